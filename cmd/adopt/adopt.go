@@ -0,0 +1,45 @@
+package adopt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the Command used to invoke the adopt logic
+func Cmd() *cobra.Command {
+	toolNames := tools.BuiltinNames()
+	adoptCmd := &cobra.Command{
+		Use:   fmt.Sprintf("adopt [%s] <path>", strings.Join(toolNames, "|")),
+		Args:  cobra.ExactArgs(2),
+		Short: "Manage an existing, manually installed executable",
+		Long:  "Imports an existing executable, such as one copied into ~/bin by hand, into the directory backplane-tools manages, and links it as the installed version of the tool. The original executable is left in place. Adopted executables are assigned a synthetic version, so they are replaced by the next 'backplane-tools upgrade'.",
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return toolNames, cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveDefault
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			return Adopt(args[0], args[1])
+		},
+	}
+	return adoptCmd
+}
+
+// Adopt imports the executable at the provided path as the installed version of the named tool
+func Adopt(toolName, path string) error {
+	tool, found := tools.GetMap()[toolName]
+	if !found {
+		return fmt.Errorf("failed to locate '%s' in list of supported tools", toolName)
+	}
+	version, err := tools.Adopt(tool, path)
+	if err != nil {
+		return fmt.Errorf("failed to adopt '%s' as %s: %w", path, toolName, err)
+	}
+	fmt.Printf("Adopted '%s' as %s version %s\n", path, toolName, version)
+	fmt.Printf("It will be replaced by the latest release the next time %s is upgraded\n", toolName)
+	return nil
+}
@@ -0,0 +1,77 @@
+package bundle
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/bundle"
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the Command used to manage bundles
+func Cmd() *cobra.Command {
+	bundleCmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Manage bundles of tools for offline installation",
+		Long:  "Bundles package tools into a single archive, so that they can be installed on machines without internet access using 'backplane-tools install --from-bundle'.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	bundleCmd.AddCommand(createCmd())
+	return bundleCmd
+}
+
+// createCmd returns the Command used to create bundles
+func createCmd() *cobra.Command {
+	var (
+		toolNames []string
+		goos      string
+		goarch    string
+		output    string
+	)
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a bundle of tools",
+		Long:  "Downloads and verifies one or more tools for the given OS and architecture, and writes them to a bundle which can be installed offline.",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return Create(toolNames, goos, goarch, output)
+		},
+	}
+	createCmd.Flags().StringSliceVar(&toolNames, "tools", []string{"all"}, fmt.Sprintf("Tools to include in the bundle: one or more of all|%s", strings.Join(tools.BuiltinNames(), "|")))
+	createCmd.Flags().StringVar(&goos, "os", runtime.GOOS, "Operating system to download tools for")
+	createCmd.Flags().StringVar(&goarch, "arch", runtime.GOARCH, "Architecture to download tools for")
+	createCmd.Flags().StringVarP(&output, "output", "o", "backplane-tools-bundle.tar", "Path to write the bundle to")
+	return createCmd
+}
+
+// Create writes a bundle containing the provided tools to the output path
+func Create(toolNames []string, goos, goarch, output string) error {
+	toolMap := tools.GetMap()
+	bundleList := []tools.Tool{}
+	if utils.Contains(toolNames, "all") {
+		for _, tool := range toolMap {
+			bundleList = append(bundleList, tool)
+		}
+	} else {
+		for _, toolName := range toolNames {
+			t, found := toolMap[toolName]
+			if !found {
+				return fmt.Errorf("failed to locate '%s' in list of supported tools", toolName)
+			}
+			bundleList = append(bundleList, t)
+		}
+	}
+
+	fmt.Printf("Creating bundle for %s/%s\n", goos, goarch)
+	err := bundle.Create(bundleList, goos, goarch, output)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the Command used to invoke the config logic
+func Cmd() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Args:  cobra.NoArgs,
+		Short: "View and change backplane-tools' settings",
+		Long:  fmt.Sprintf("Views and changes the settings in backplane-tools' configuration file, '%s'. Settings are identified by their path within the file, separated by dots, such as 'http.limitRate' or 'tools.oc.checksum'. Changes are validated before they're written, so that the file is never left holding unsupported values.", config.Path),
+		// The configuration file is loaded before other commands run, and can't be repaired with this command if it's
+		// invalid. Settings are instead read from the file by each subcommand
+		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	configCmd.AddCommand(getCmd())
+	configCmd.AddCommand(setCmd())
+	configCmd.AddCommand(listCmd())
+	configCmd.AddCommand(editCmd())
+	return configCmd
+}
+
+func getCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get KEY",
+		Args:  cobra.ExactArgs(1),
+		Short: "Print the value of a setting",
+		Long:  "Prints the value of the setting with the provided key. Lists and groups of settings are printed in YAML's flow style. An error is returned if the setting isn't set in the configuration file.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			return Get(args[0])
+		},
+	}
+}
+
+func setCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set KEY VALUE",
+		Args:  cobra.ExactArgs(2),
+		Short: "Change the value of a setting",
+		Long:  "Changes the value of the setting with the provided key, adding it to the configuration file if it isn't already set. Values are parsed as YAML, so lists can be provided in flow style, such as '[\"--verbose\"]'. Comments and other settings in the file are preserved.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			return Set(args[0], args[1])
+		},
+	}
+}
+
+func listCmd() *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Args:  cobra.NoArgs,
+		Short: "List the settings in the configuration file",
+		Long:  "Lists every setting in the configuration file, along with its value. Settings which aren't set in the file use their default values, and aren't listed.",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return List(output)
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: 'text' or 'json'")
+	return cmd
+}
+
+func editCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Args:  cobra.NoArgs,
+		Short: "Edit the configuration file",
+		Long:  "Opens the configuration file in the editor named by $VISUAL or $EDITOR, defaulting to 'vi'. Changes are only saved once the edited file is valid: if it isn't, the error is printed and the file may be re-opened to correct it.",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return Edit()
+		},
+	}
+}
+
+// Get prints the value of the provided setting
+func Get(key string) error {
+	doc, err := config.ReadDocument(config.Path)
+	if err != nil {
+		return err
+	}
+	value, found, err := doc.Get(key)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("'%s' is not set in the configuration file", key)
+	}
+	fmt.Println(value)
+	return nil
+}
+
+// Set changes the value of the provided setting, then writes the configuration file
+func Set(key, value string) error {
+	doc, err := config.ReadDocument(config.Path)
+	if err != nil {
+		return err
+	}
+	err = doc.Set(key, value)
+	if err != nil {
+		return err
+	}
+	err = doc.Write(config.Path)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Set '%s' to '%s'\n", key, value)
+	return nil
+}
+
+// List prints every setting in the configuration file in the given output format
+func List(output string) error {
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unsupported output format '%s': must be one of 'text' or 'json'", output)
+	}
+	doc, err := config.ReadDocument(config.Path)
+	if err != nil {
+		return err
+	}
+	settings, err := doc.Settings()
+	if err != nil {
+		return err
+	}
+
+	if output == "json" {
+		data, err := json.MarshalIndent(settings, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode settings: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	if len(settings) == 0 {
+		fmt.Printf("No settings in '%s': all settings use their default values\n", config.Path)
+		return nil
+	}
+	for _, setting := range settings {
+		fmt.Printf("%s=%s\n", setting.Key, setting.Value)
+	}
+	return nil
+}
+
+// Edit opens the configuration file in the user's editor. The file is only replaced once the changes made are valid
+func Edit() error {
+	original, err := os.ReadFile(config.Path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read configuration file '%s': %w", config.Path, err)
+	}
+	configDir := filepath.Dir(config.Path)
+	err = os.MkdirAll(configDir, os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create configuration directory '%s': %w", configDir, err)
+	}
+
+	// Edit a copy alongside the configuration file, so that it can replace the original in a single rename
+	tmpFile, err := os.CreateTemp(configDir, "config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary configuration file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer func() {
+		_ = os.Remove(tmpPath)
+	}()
+	_, err = tmpFile.Write(original)
+	if err != nil {
+		_ = tmpFile.Close()
+		return fmt.Errorf("failed to write temporary configuration file '%s': %w", tmpPath, err)
+	}
+	err = tmpFile.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close temporary configuration file '%s': %w", tmpPath, err)
+	}
+
+	for {
+		err = runEditor(tmpPath)
+		if err != nil {
+			return err
+		}
+		edited, err := os.ReadFile(tmpPath)
+		if err != nil {
+			return fmt.Errorf("failed to read edited configuration file '%s': %w", tmpPath, err)
+		}
+		if string(edited) == string(original) {
+			fmt.Println("No changes made")
+			return nil
+		}
+		_, err = config.Parse(edited)
+		if err == nil {
+			break
+		}
+		if !utils.IsInteractive() {
+			return fmt.Errorf("changes were not saved: %w", err)
+		}
+		fmt.Printf("The edited configuration is invalid: %v\n", err)
+		answer, promptErr := utils.Prompt("Re-open the editor to correct it? [Y/n] ")
+		if promptErr != nil {
+			return promptErr
+		}
+		if strings.HasPrefix(strings.ToLower(answer), "n") {
+			return fmt.Errorf("changes were not saved: %w", err)
+		}
+	}
+
+	err = os.Rename(tmpPath, config.Path)
+	if err != nil {
+		return fmt.Errorf("failed to replace configuration file '%s': %w", config.Path, err)
+	}
+	fmt.Printf("Saved changes to '%s'\n", config.Path)
+	return nil
+}
+
+// runEditor opens the file at the provided path in the user's editor, and waits for it to exit
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("failed to run editor '%s': %w", editor, err)
+	}
+	return nil
+}
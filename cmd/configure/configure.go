@@ -0,0 +1,62 @@
+package configure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the Command used to invoke the configure logic
+func Cmd() *cobra.Command {
+	toolNames := tools.BuiltinNames()
+	configureCmd := &cobra.Command{
+		Use:       fmt.Sprintf("configure [all|%s]", strings.Join(toolNames, "|")),
+		Args:      cobra.OnlyValidArgs,
+		ValidArgs: append(toolNames, "all"),
+		Short:     "Configure an installed tool",
+		Long:      "Performs the post-installation setup for one or more installed tools, such as creating their configuration files. Configuration runs automatically after each install; this command can be used to retry it, and is safe to run repeatedly: existing settings are never overwritten. If no specific tools are provided, all installed tools are configured.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			return Configure(args)
+		},
+	}
+	return configureCmd
+}
+
+// Configure runs the configuration phase for the provided tools
+func Configure(args []string) error {
+	var listTools []tools.Tool
+	if len(args) == 0 || utils.Contains(args, "all") {
+		var err error
+		listTools, err = tools.ListInstalled()
+		if err != nil {
+			return err
+		}
+	} else {
+		toolMap := tools.GetMap()
+
+		listTools = []tools.Tool{}
+		for _, toolName := range args {
+			t, found := toolMap[toolName]
+			if !found {
+				return fmt.Errorf("failed to locate '%s' in list of supported tools", toolName)
+			}
+			installed, err := t.Installed()
+			if err != nil {
+				return fmt.Errorf("failed to determine if '%s' has been installed: %w", toolName, err)
+			}
+			if !installed {
+				return fmt.Errorf("'%s' is not installed: run 'backplane-tools install %s' first", toolName, toolName)
+			}
+			listTools = append(listTools, t)
+		}
+	}
+
+	err := tools.Configure(listTools)
+	if err != nil {
+		return fmt.Errorf("failed to configure tools: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/openshift/backplane-tools/pkg/manifest"
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the Command used to invoke the diff logic
+func Cmd() *cobra.Command {
+	var (
+		output   string
+		exitCode bool
+	)
+	diffCmd := &cobra.Command{
+		Use:   "diff <manifest>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Compare installed tools against a manifest",
+		Long:  "Compares the installed tools against the provided manifest, reporting tools the manifest expects which are not installed, tools installed at a different version than expected, and installed tools the manifest does not list. The manifest is a YAML or JSON file mapping each expected tool's name to its version under the 'tools' key. A version of '*' (or an empty version) accepts any installed version, while 'latest' expects the latest version available.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			different, err := Diff(args[0], output)
+			if err != nil {
+				return err
+			}
+			if exitCode && different {
+				os.Exit(1)
+			}
+			return nil
+		},
+	}
+	diffCmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: 'text' or 'json'")
+	diffCmd.Flags().BoolVar(&exitCode, "exit-code", false, "Exit with status 1 if the installed tools differ from the manifest")
+	return diffCmd
+}
+
+// Diff prints the differences between the installed tools and the manifest at the provided path, in the given
+// output format. different is true if any differences were found
+func Diff(path, output string) (different bool, err error) {
+	if output != "text" && output != "json" {
+		return false, fmt.Errorf("unsupported output format '%s': must be one of 'text' or 'json'", output)
+	}
+	m, err := manifest.Read(path)
+	if err != nil {
+		return false, err
+	}
+
+	toolMap := tools.GetMap()
+	for name, version := range m.Tools {
+		if version != manifest.LatestVersion {
+			continue
+		}
+		tool, found := toolMap[name]
+		if !found {
+			return false, fmt.Errorf("manifest expects the latest version of '%s', which is not a supported tool", name)
+		}
+		latest, err := tool.LatestVersion()
+		if err != nil {
+			return false, fmt.Errorf("failed to determine latest version of %s: %w", name, err)
+		}
+		m.Tools[name] = latest
+	}
+
+	installedTools, err := tools.ListInstalled()
+	if err != nil {
+		return false, err
+	}
+	installed := map[string]string{}
+	for _, t := range installedTools {
+		version, err := t.InstalledVersion()
+		if err != nil {
+			return false, fmt.Errorf("failed to determine version for '%s': %w", t.Name(), err)
+		}
+		installed[t.Name()] = version
+	}
+
+	d := manifest.Compare(m, installed)
+	if output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(d)
+		if err != nil {
+			return false, fmt.Errorf("failed to print differences: %w", err)
+		}
+		return !d.Empty(), nil
+	}
+
+	if d.Empty() {
+		fmt.Println("Installed tools match the manifest")
+		return false, nil
+	}
+	for _, missing := range d.Missing {
+		fmt.Printf("- %s: not installed, expected %s\n", missing.Tool, describeVersion(missing.Expected))
+	}
+	for _, changed := range d.Changed {
+		fmt.Printf("~ %s: %s installed, expected %s\n", changed.Tool, changed.Installed, changed.Expected)
+	}
+	for _, extra := range d.Extra {
+		fmt.Printf("+ %s: %s installed, not in manifest\n", extra.Tool, extra.Installed)
+	}
+	return true, nil
+}
+
+// describeVersion describes the version expected by the manifest
+func describeVersion(version string) string {
+	if version == "" || version == manifest.AnyVersion {
+		return "any version"
+	}
+	return version
+}
@@ -0,0 +1,50 @@
+package exec
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the Command used to invoke the exec logic
+func Cmd() *cobra.Command {
+	toolNames := tools.BuiltinNames()
+	execCmd := &cobra.Command{
+		Use:     fmt.Sprintf("exec [%s][@version] -- [args...]", strings.Join(toolNames, "|")),
+		Args:    cobra.MinimumNArgs(1),
+		Short:   "Run a specific installed version of a tool",
+		Long:    "Runs the requested installed version of a tool with the provided arguments, without changing which version is linked as the latest. If no version is provided, the currently installed version is run. Arguments intended for the tool should follow '--', so they aren't interpreted by backplane-tools.",
+		Example: "  backplane-tools exec oc@4.12.50 -- get nodes",
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return toolNames, cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveDefault
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			code, err := Exec(args[0], args[1:])
+			if err != nil {
+				return err
+			}
+			if code != 0 {
+				os.Exit(code)
+			}
+			return nil
+		},
+	}
+	return execCmd
+}
+
+// Exec runs the tool identified by the provided 'tool[@version]' reference with the given arguments, returning
+// the tool's exit code
+func Exec(ref string, args []string) (int, error) {
+	toolName, version, _ := strings.Cut(ref, "@")
+	tool, found := tools.GetMap()[toolName]
+	if !found {
+		return 1, fmt.Errorf("failed to locate '%s' in list of supported tools", toolName)
+	}
+	return tools.Exec(tool, version, args)
+}
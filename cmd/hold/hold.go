@@ -0,0 +1,38 @@
+package hold
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the Command used to invoke the hold logic
+func Cmd() *cobra.Command {
+	toolNames := tools.BuiltinNames()
+	holdCmd := &cobra.Command{
+		Use:       fmt.Sprintf("hold [%s]", strings.Join(toolNames, "|")),
+		Args:      cobra.MatchAll(cobra.MinimumNArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: toolNames,
+		Short:     "Prevent a tool from being upgraded",
+		Long:      "Holds one or more tools at their currently installed version: held tools are skipped by 'backplane-tools upgrade', even if a newer version is available. Use 'backplane-tools unhold' to allow upgrades again.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			return Hold(args)
+		},
+	}
+	return holdCmd
+}
+
+// Hold prevents the provided tools from being upgraded
+func Hold(args []string) error {
+	for _, toolName := range args {
+		err := base.Hold(toolName)
+		if err != nil {
+			return fmt.Errorf("failed to hold '%s': %w", toolName, err)
+		}
+		fmt.Printf("%s will not be upgraded until it is unheld\n", toolName)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package initialize
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/openshift/backplane-tools/cmd/install"
+	"github.com/openshift/backplane-tools/cmd/login"
+	"github.com/openshift/backplane-tools/pkg/sources/github"
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the Command used to invoke the init logic
+func Cmd() *cobra.Command {
+	var (
+		useDefaults bool
+		profile     string
+	)
+	initCmd := &cobra.Command{
+		Use:   "init",
+		Args:  cobra.NoArgs,
+		Short: "Set up backplane-tools for the first time",
+		Long:  fmt.Sprintf("Walks through setting up backplane-tools: choosing a profile of tools to install, logging in to GitHub, adding the tools to your $PATH, and installing them. The profiles are '%s'. Pass --defaults to accept the default for every step without prompting, such as when provisioning a machine: the '%s' profile is installed, GitHub login is skipped, and your shell's startup file is updated.", strings.Join(tools.ProfileNames, "', '"), tools.ProfileCore),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return Init(profile, useDefaults)
+		},
+	}
+	initCmd.Flags().BoolVar(&useDefaults, "defaults", false, "Accept the default for every step, rather than prompting")
+	initCmd.Flags().StringVar(&profile, "profile", "", fmt.Sprintf("The profile of tools to install: '%s'. Prompted for unless --defaults is provided", strings.Join(tools.ProfileNames, "', '")))
+	return initCmd
+}
+
+// Init sets up backplane-tools, installing the tools in the provided profile. The user is prompted for each choice,
+// unless useDefaults is true
+func Init(profile string, useDefaults bool) error {
+	if !useDefaults && !utils.IsInteractive() {
+		return errors.New("init prompts for input, but isn't running in a terminal: pass --defaults to accept the default for every step")
+	}
+
+	fmt.Println(utils.Info("Step 1 of 4: choose the tools to install"))
+	var err error
+	if profile == "" {
+		profile = tools.ProfileCore
+		if !useDefaults {
+			profile, err = chooseProfile()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	profileTools, err := tools.Profile(profile)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Using the '%s' profile\n", profile)
+
+	fmt.Println()
+	fmt.Println(utils.Info("Step 2 of 4: authenticate with GitHub"))
+	setupGitHub(useDefaults)
+
+	fmt.Println()
+	fmt.Println(utils.Info("Step 3 of 4: add the tools to your $PATH"))
+	err = setupPath(useDefaults)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(utils.Info("Step 4 of 4: install the tools"))
+	names := []string{}
+	for _, tool := range profileTools {
+		names = append(names, tool.Name())
+	}
+	err = install.Install(names, true)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(utils.Success("backplane-tools is set up. Run 'backplane-tools upgrade' to keep your tools up to date"))
+	return nil
+}
+
+// chooseProfile prompts the user to choose a profile, until they choose one that exists
+func chooseProfile() (string, error) {
+	for _, name := range tools.ProfileNames {
+		profileTools, err := tools.Profile(name)
+		if err != nil {
+			return "", err
+		}
+		names := []string{}
+		for _, tool := range profileTools {
+			names = append(names, tool.Name())
+		}
+		fmt.Printf("  %s: %s\n", name, strings.Join(names, ", "))
+	}
+	for {
+		answer, err := utils.Prompt(fmt.Sprintf("Profile [%s]: ", tools.ProfileCore))
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			return tools.ProfileCore, nil
+		}
+		if utils.Contains(tools.ProfileNames, answer) {
+			return answer, nil
+		}
+		fmt.Printf("Unknown profile '%s': choose one of '%s'\n", answer, strings.Join(tools.ProfileNames, "', '"))
+	}
+}
+
+// setupGitHub offers to log in to GitHub, unless a token is already available. Failing to log in doesn't prevent
+// tools from being installed, so failures are reported rather than returned
+func setupGitHub(useDefaults bool) {
+	if github.Authenticated() {
+		fmt.Println("Found an existing GitHub token, which will be used to authenticate with GitHub")
+		return
+	}
+	fmt.Println("Requests to GitHub are rate limited, and anonymous requests are limited the most. Logging in avoids failed installs when many tools are installed at once")
+	if useDefaults {
+		fmt.Println("Skipping login. Run 'backplane-tools login github' to log in later")
+		return
+	}
+	answer, err := utils.Prompt("Log in to GitHub now? [y/N] ")
+	if err != nil || !strings.HasPrefix(strings.ToLower(answer), "y") {
+		fmt.Println("Skipping login. Run 'backplane-tools login github' to log in later")
+		return
+	}
+	err = login.Login("github", false)
+	if err != nil {
+		fmt.Printf("WARNING: %v. Continuing without logging in\n", err)
+	}
+}
+
+// setupPath adds the latest directory to the $PATH in the startup file of the user's shell, unless it's already
+// there
+func setupPath(useDefaults bool) error {
+	if utils.Contains(filepath.SplitList(os.Getenv("PATH")), base.LatestDir) {
+		fmt.Printf("'%s' is already in your $PATH\n", base.LatestDir)
+		return nil
+	}
+	startupFile, line := shellStartupFile(base.LatestDir)
+	contents, err := os.ReadFile(startupFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read '%s': %w", startupFile, err)
+	}
+	if strings.Contains(string(contents), base.LatestDir) {
+		fmt.Printf("'%s' already adds '%s' to your $PATH. Restart your shell for it to take effect\n", startupFile, base.LatestDir)
+		return nil
+	}
+
+	if !useDefaults {
+		answer, err := utils.Prompt(fmt.Sprintf("Add '%s' to your $PATH in '%s'? [Y/n] ", base.LatestDir, startupFile))
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(strings.ToLower(answer), "n") {
+			fmt.Printf("Skipping. To use the installed tools, add the following line to your shell's startup file:\n  %s\n", line)
+			return nil
+		}
+	}
+
+	err = os.MkdirAll(filepath.Dir(startupFile), os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create directory '%s': %w", filepath.Dir(startupFile), err)
+	}
+	file, err := os.OpenFile(startupFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, os.FileMode(0o644))
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %w", startupFile, err)
+	}
+	defer func() {
+		closeErr := file.Close()
+		if closeErr != nil {
+			fmt.Fprintf(os.Stderr, "failed to close file '%s': %v\n", startupFile, closeErr)
+		}
+	}()
+	_, err = fmt.Fprintf(file, "\n# Added by 'backplane-tools init'\n%s\n", line)
+	if err != nil {
+		return fmt.Errorf("failed to write '%s': %w", startupFile, err)
+	}
+	fmt.Printf("Added '%s' to your $PATH in '%s'. Restart your shell for the change to take effect\n", base.LatestDir, startupFile)
+	return nil
+}
+
+// shellStartupFile returns the startup file of the user's shell, as determined by $SHELL, along with the line added
+// to it to place the provided directory at the start of the $PATH. Unrecognized shells use '~/.profile'
+func shellStartupFile(dir string) (path string, line string) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "~"
+	}
+	export := fmt.Sprintf("export PATH=\"%s:$PATH\"", dir)
+	switch filepath.Base(os.Getenv("SHELL")) {
+	case "zsh":
+		zdotdir := os.Getenv("ZDOTDIR")
+		if zdotdir == "" {
+			zdotdir = home
+		}
+		return filepath.Join(zdotdir, ".zshrc"), export
+	case "bash":
+		// macOS' Terminal starts login shells, which read .bash_profile rather than .bashrc
+		if runtime.GOOS == "darwin" {
+			return filepath.Join(home, ".bash_profile"), export
+		}
+		return filepath.Join(home, ".bashrc"), export
+	case "fish":
+		configDir := os.Getenv("XDG_CONFIG_HOME")
+		if configDir == "" {
+			configDir = filepath.Join(home, ".config")
+		}
+		return filepath.Join(configDir, "fish", "config.fish"), fmt.Sprintf("fish_add_path --prepend \"%s\"", dir)
+	}
+	return filepath.Join(home, ".profile"), export
+}
@@ -4,29 +4,56 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/openshift/backplane-tools/pkg/bundle"
+	"github.com/openshift/backplane-tools/pkg/policy"
 	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
 	"github.com/openshift/backplane-tools/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
 // Cmd returns the Command used to invoke the installation logic
 func Cmd() *cobra.Command {
-	toolNames := tools.Names()
+	var (
+		fromBundle       string
+		fromFile         string
+		version          string
+		sha256           string
+		skipDependencies bool
+	)
+	toolNames := tools.BuiltinNames()
 	installCmd := &cobra.Command{
-		Use:       fmt.Sprintf("install [all|%s]", strings.Join(toolNames, "|")),
-		Args:      cobra.OnlyValidArgs,
+		Use: fmt.Sprintf("install [all|%s]", strings.Join(toolNames, "|")),
+		Args: func(_ *cobra.Command, args []string) error {
+			return tools.ValidateArgs(args, "all")
+		},
 		ValidArgs: append(toolNames, "all"),
 		Short:     "Install a new tool",
 		Long:      "Installs one or more tools from the given list. It's valid to specify multiple tools: in this case, all tools provided will be installed. If no specific tools are provided, all are installed by default.",
 		RunE: func(_ *cobra.Command, args []string) error {
-			return Install(args)
+			if fromBundle != "" {
+				return InstallFromBundle(fromBundle, args)
+			}
+			if fromFile != "" {
+				return InstallFromFile(args, base.LocalFile{Path: fromFile, Version: version, SHA256: sha256})
+			}
+			return Install(args, !skipDependencies)
 		},
 	}
+	installCmd.Flags().StringVar(&fromBundle, "from-bundle", "", "Install tools from a bundle created by 'backplane-tools bundle create', without accessing the network")
+	installCmd.Flags().StringVar(&fromFile, "from-file", "", "Install a single tool from a release asset on the local filesystem, without accessing the network. Requires --version")
+	installCmd.Flags().StringVar(&version, "version", "", "The version the tool provided via --from-file is installed as")
+	installCmd.Flags().StringVar(&sha256, "sha256", "", "The expected sha256 checksum of the file provided via --from-file")
+	installCmd.MarkFlagsMutuallyExclusive("from-bundle", "from-file")
+	installCmd.MarkFlagsRequiredTogether("from-file", "version")
+	installCmd.Flags().BoolVar(&skipDependencies, "skip-dependencies", false, "Don't install the tools the requested tools depend upon, if they're missing")
+	installCmd.Flags().BoolVar(&policy.Override, "allow-unapproved", false, "Install versions which aren't approved by your organization's policy. Each is reported loudly")
 	return installCmd
 }
 
-// run installs the tools specified by the provided positional args
-func Install(args []string) error {
+// Install installs the tools specified by the provided positional args. If withDependencies is true, any missing
+// tools they depend upon are installed as well
+func Install(args []string, withDependencies bool) error {
 	fmt.Println("Installing the following tools:")
 	toolMap := tools.GetMap()
 	installList := []tools.Tool{}
@@ -40,6 +67,17 @@ func Install(args []string) error {
 			installList = append(installList, toolMap[toolName])
 		}
 	}
+	if withDependencies {
+		var err error
+		installList, err = tools.WithDependencies(installList)
+		if err != nil {
+			return err
+		}
+	}
+	installList, err := tools.Order(installList)
+	if err != nil {
+		return err
+	}
 	for _, tool := range installList {
 		latestversion, err := tool.LatestVersion()
 		if err != nil {
@@ -48,7 +86,49 @@ func Install(args []string) error {
 		fmt.Printf("- %s %s\n", tool.Name(), latestversion)
 	}
 
-	err := tools.Install(installList)
+	err = tools.Install(installList)
+	if err != nil {
+		return fmt.Errorf("failed to install tools: %w", err)
+	}
+	return nil
+}
+
+// InstallFromBundle installs the tools specified by the provided positional args from the bundle at the given path.
+// If no specific tools are provided, every tool in the bundle is installed
+func InstallFromBundle(path string, args []string) error {
+	if utils.Contains(args, "all") {
+		args = []string{}
+	}
+	installed, err := bundle.Install(path, args)
+	if err != nil {
+		return fmt.Errorf("failed to install tools from bundle: %w", err)
+	}
+
+	toolMap := tools.GetMap()
+	configureList := []tools.Tool{}
+	for _, toolName := range installed {
+		fmt.Println(utils.Success(fmt.Sprintf("Successfully installed %s", toolName)))
+		if t, found := toolMap[toolName]; found {
+			configureList = append(configureList, t)
+		}
+	}
+	return tools.Configure(configureList)
+}
+
+// InstallFromFile installs the single tool specified by the provided positional args from the given local file. The
+// tool's dependencies are not installed, as they can't be retrieved without accessing the network
+func InstallFromFile(args []string, file base.LocalFile) error {
+	if len(args) != 1 || args[0] == "all" {
+		return fmt.Errorf("exactly one tool must be specified when installing from a local file")
+	}
+	tool := tools.GetMap()[args[0]]
+	err := tools.UseLocalFile(tool, file)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Installing %s %s from '%s'\n", tool.Name(), file.Version, file.Path)
+
+	err = tools.Install([]tools.Tool{tool})
 	if err != nil {
 		return fmt.Errorf("failed to install tools: %w", err)
 	}
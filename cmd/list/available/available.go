@@ -2,12 +2,16 @@ package available
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/openshift/backplane-tools/pkg/sources"
 	"github.com/openshift/backplane-tools/pkg/tools"
 	"github.com/spf13/cobra"
 )
 
 func Cmd() *cobra.Command {
+	var showSources bool
 	availableCmd := &cobra.Command{
 		Use:     "available",
 		Args:    cobra.NoArgs,
@@ -15,13 +19,14 @@ func Cmd() *cobra.Command {
 		Short:   "List available tools for install",
 		Long:    "List tools that are available to install with backplane-tools",
 		RunE: func(_ *cobra.Command, _ []string) error {
-			return List()
+			return List(showSources)
 		},
 	}
+	availableCmd.Flags().BoolVar(&showSources, "sources", false, "Include where each tool is installed from, and what the source supports")
 	return availableCmd
 }
 
-func List() error {
+func List(showSources bool) error {
 	fmt.Println("The following tools are available for install:")
 
 	toolMap := tools.GetMap()
@@ -30,7 +35,36 @@ func List() error {
 		if err != nil {
 			return fmt.Errorf("failed to determine version for '%s': %w", t.Name(), err)
 		}
-		fmt.Printf("- %s %s\n", t.Name(), version)
+		if !showSources {
+			fmt.Printf("- %s %s\n", t.Name(), version)
+			continue
+		}
+		fmt.Printf("- %s %s (%s)\n", t.Name(), version, describeSource(t))
 	}
 	return nil
 }
+
+// describeSource returns where the tool is installed from, along with the optional capabilities the source supports
+func describeSource(t tools.Tool) string {
+	src, found := tools.SourceOf(t)
+	if !found {
+		return "unknown source"
+	}
+	capabilities := sources.Discover(src)
+	supported := []string{}
+	for name, ok := range map[string]bool{
+		"latest":    capabilities.ResolveLatest,
+		"versions":  capabilities.ResolveVersion,
+		"artifacts": capabilities.ListArtifacts,
+		"verify":    capabilities.VerificationHints,
+	} {
+		if ok {
+			supported = append(supported, name)
+		}
+	}
+	sort.Strings(supported)
+	if len(supported) == 0 {
+		return src.String()
+	}
+	return fmt.Sprintf("%s; supports: %s", src.String(), strings.Join(supported, ", "))
+}
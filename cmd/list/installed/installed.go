@@ -2,8 +2,10 @@ package installed
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/openshift/backplane-tools/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -23,8 +25,12 @@ func Cmd() *cobra.Command {
 func List() error {
 	toolMap := tools.GetMap()
 
+	names := utils.Keys(toolMap)
+	sort.Strings(names)
+
 	fmt.Println("Currently installed tools:")
-	for _, t := range toolMap {
+	for _, name := range names {
+		t := toolMap[name]
 		installed, err := t.Installed()
 		if err != nil {
 			return fmt.Errorf("failed to determine if '%s' has been installed: %w", t.Name(), err)
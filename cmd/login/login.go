@@ -0,0 +1,100 @@
+package login
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/sources/github"
+	"github.com/spf13/cobra"
+)
+
+// services lists the services backplane-tools can store credentials for
+var services = []string{"github"}
+
+// Cmd returns the Command used to invoke the login logic
+func Cmd() *cobra.Command {
+	var withToken bool
+	loginCmd := &cobra.Command{
+		Use:       fmt.Sprintf("login [%s]", strings.Join(services, "|")),
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: services,
+		Short:     "Store credentials used to download tools",
+		Long:      "Stores a token used to authenticate with the provided service in the operating system's keyring. Authenticated requests to GitHub are subject to much higher rate limits than anonymous ones. The token is prompted for, unless --with-token is provided. A token set in $GH_TOKEN or $GITHUB_TOKEN takes precedence over the stored token.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			return Login(args[0], withToken)
+		},
+	}
+	loginCmd.Flags().BoolVar(&withToken, "with-token", false, "Read the token from stdin rather than prompting for it")
+	return loginCmd
+}
+
+// Login stores a token for the provided service in the keyring. If withToken is true, the token is read from stdin,
+// otherwise the user is prompted for it
+func Login(service string, withToken bool) error {
+	if service != "github" {
+		return fmt.Errorf("unsupported service '%s'", service)
+	}
+
+	var (
+		token string
+		err   error
+	)
+	if withToken {
+		token, err = readLine(os.Stdin)
+	} else {
+		fmt.Println("Create a personal access token at https://github.com/settings/tokens. No scopes are required to download tools from public repositories")
+		token, err = promptSecret("GitHub token: ")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read token: %w", err)
+	}
+	if token == "" {
+		return errors.New("no token provided")
+	}
+
+	user, err := github.Login(token)
+	if err != nil {
+		return fmt.Errorf("failed to log in to GitHub: %w", err)
+	}
+	fmt.Printf("Logged in to GitHub as %s. The token has been stored in your keyring\n", user)
+	return nil
+}
+
+// promptSecret prints the provided prompt and reads a line from stdin without echoing it to the terminal
+func promptSecret(prompt string) (string, error) {
+	fmt.Print(prompt)
+	// Echo is disabled via stty, rather than the terminal's ioctls, as these differ between platforms. If stdin
+	// isn't a terminal, stty fails and the input is read as-is
+	err := stty("-echo")
+	if err == nil {
+		defer func() {
+			fmt.Println()
+			err := stty("echo")
+			if err != nil {
+				fmt.Printf("WARNING: failed to re-enable terminal echo: %v. Run 'stty echo' to restore it\n", err)
+			}
+		}()
+	}
+	return readLine(os.Stdin)
+}
+
+// stty applies the provided setting to the terminal attached to stdin
+func stty(setting string) error {
+	cmd := exec.Command("stty", setting)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
+// readLine returns the first line read from the provided reader, with surrounding whitespace removed
+func readLine(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,42 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the Command used to migrate installed tools to the XDG directory layout
+func Cmd() *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Args:  cobra.NoArgs,
+		Short: "Migrate installed tools to the XDG directory layout",
+		Long:  fmt.Sprintf("Relocates tools installed in '%s' to '%s', moves the records backplane-tools keeps about them to '%s', and updates the links in the latest directory to match.", base.LegacyInstallDir, base.XDGInstallDir, base.XDGStateDir),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return Migrate()
+		},
+	}
+	return migrateCmd
+}
+
+// Migrate relocates tools installed using the legacy layout to the XDG layout
+func Migrate() error {
+	if config.Get().Layout == config.LayoutLegacy {
+		return errors.New("the legacy layout has been explicitly configured: update the 'layout' setting in your configuration before migrating")
+	}
+
+	fmt.Printf("Migrating tools from '%s' to '%s'\n", base.LegacyInstallDir, base.XDGInstallDir)
+	err := base.MigrateToXDG()
+	if err != nil {
+		return fmt.Errorf("failed to migrate tools: %w", err)
+	}
+	fmt.Println("Successfully migrated tools")
+	fmt.Println()
+	fmt.Printf("Update your $PATH to replace '%s' with '%s'\n", filepath.Join(base.LegacyInstallDir, "latest"), filepath.Join(base.XDGInstallDir, "latest"))
+	return nil
+}
@@ -0,0 +1,73 @@
+package ocmplugins
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the Command used to invoke the ocm plugin logic
+func Cmd() *cobra.Command {
+	var output string
+	ocmPluginsCmd := &cobra.Command{
+		Use:   "ocm-plugins",
+		Args:  cobra.NoArgs,
+		Short: "Check the health of tools installed as ocm plugins",
+		Long:  "Lists the tools managed by backplane-tools which are ocm plugins, such as backplane-cli ('ocm backplane'), and checks that ocm is able to invoke each installed plugin: ocm must be installed, and the plugin must be published in the latest directory as 'ocm-<plugin>', refer to the plugin's installed executable, and be the first executable of that name in the $PATH.",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return OCMPlugins(output)
+		},
+	}
+	ocmPluginsCmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: 'text' or 'json'")
+	return ocmPluginsCmd
+}
+
+// OCMPlugins prints the status of each ocm plugin in the given output format. An error is returned if any installed
+// plugin can't be invoked through ocm
+func OCMPlugins(output string) error {
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unsupported output format '%s': must be one of 'text' or 'json'", output)
+	}
+	statuses, err := tools.CheckOCMPlugins()
+	if err != nil {
+		return err
+	}
+
+	unhealthy := []string{}
+	for _, status := range statuses {
+		if status.Installed && !status.Healthy() {
+			unhealthy = append(unhealthy, status.Tool)
+		}
+	}
+
+	if output == "json" {
+		data, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode ocm plugin statuses: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Println("ocm plugins:")
+		for _, status := range statuses {
+			switch {
+			case !status.Installed:
+				fmt.Printf("- %s (%s): not installed\n", status.Plugin, status.Tool)
+			case status.Healthy():
+				fmt.Printf("- %s (%s): ok, at %s\n", status.Plugin, status.Tool, status.Path)
+			default:
+				fmt.Printf("- %s (%s): UNHEALTHY\n", status.Plugin, status.Tool)
+				for _, problem := range status.Problems {
+					fmt.Printf("  - %s\n", problem)
+				}
+			}
+		}
+	}
+
+	if len(unhealthy) > 0 {
+		return fmt.Errorf("ocm is unable to invoke the plugins provided by: %s", strings.Join(unhealthy, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package promptstatus
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/spf13/cobra"
+)
+
+// refreshTimeout bounds how long a background refresh is assumed to take. Another isn't started within this time of
+// the previous one starting, so that prompts rendered in quick succession don't each start one
+const refreshTimeout = 10 * time.Minute
+
+// Cmd returns the Command used to invoke the prompt-status logic
+func Cmd() *cobra.Command {
+	var (
+		refresh bool
+		maxAge  time.Duration
+	)
+	promptStatusCmd := &cobra.Command{
+		Use:   "prompt-status",
+		Args:  cobra.NoArgs,
+		Short: "Print how many tools are outdated, for use in shell prompts",
+		Long:  "Prints how many installed tools are outdated, such as '2 tools outdated', or nothing if all are up to date. Only the latest versions recorded by the most recent check are used, so this is fast enough to run from a shell prompt (ie - PS1, or a starship custom module). When the recorded versions are older than --max-age, a check is started in the background to refresh them. Alternatively, run 'backplane-tools prompt-status --refresh' on a schedule, such as from cron, and pass '--max-age 0' to disable background checks. Held tools aren't counted.",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if refresh {
+				return Refresh()
+			}
+			return PromptStatus(maxAge)
+		},
+	}
+	promptStatusCmd.Flags().BoolVar(&refresh, "refresh", false, "Retrieve the latest version of each installed tool from its source and record them, rather than printing the status")
+	promptStatusCmd.Flags().DurationVar(&maxAge, "max-age", 24*time.Hour, "How old the recorded versions can become before they're refreshed in the background. 0 disables background refreshes")
+	return promptStatusCmd
+}
+
+// Refresh records the latest version of each installed tool, for later use by PromptStatus
+func Refresh() error {
+	return tools.RefreshLatestVersions()
+}
+
+// PromptStatus prints how many tools are outdated according to the recorded latest versions. If the recorded versions
+// are older than maxAge, they're refreshed in the background for the next time the status is printed
+func PromptStatus(maxAge time.Duration) error {
+	outdated, checkedAt, err := tools.CachedOutdated()
+	if err != nil {
+		return err
+	}
+	if maxAge > 0 && time.Since(checkedAt) > maxAge {
+		startRefresh()
+	}
+
+	switch len(outdated) {
+	case 0:
+	case 1:
+		fmt.Println("1 tool outdated")
+	default:
+		fmt.Printf("%d tools outdated\n", len(outdated))
+	}
+	return nil
+}
+
+// startRefresh runs 'prompt-status --refresh' in the background, unless one was started recently. Failures are
+// ignored, since anything printed would end up in the user's prompt
+func startRefresh() {
+	latest, err := base.ReadLatestVersions()
+	if err != nil || time.Since(latest.RefreshStartedAt) < refreshTimeout {
+		return
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return
+	}
+	latest.RefreshStartedAt = time.Now().UTC()
+	err = base.WriteLatestVersions(latest)
+	if err != nil {
+		return
+	}
+
+	// The refresh's output is discarded, and it isn't waited on, so that the prompt isn't delayed
+	cmd := exec.Command(self, "prompt-status", "--refresh")
+	err = cmd.Start()
+	if err != nil {
+		return
+	}
+	_ = cmd.Process.Release()
+}
@@ -10,10 +10,12 @@ import (
 )
 
 func Cmd() *cobra.Command {
-	toolNames := tools.Names()
+	toolNames := tools.BuiltinNames()
 	removeCmd := &cobra.Command{
-		Use:       fmt.Sprintf("remove [all|%s]", strings.Join(toolNames, "|")),
-		Args:      cobra.OnlyValidArgs,
+		Use: fmt.Sprintf("remove [all|%s]", strings.Join(toolNames, "|")),
+		Args: func(_ *cobra.Command, args []string) error {
+			return tools.ValidateArgs(args, "all")
+		},
 		ValidArgs: append(toolNames, "all"),
 		Short:     "Remove a tool",
 		Long:      "Removes one or more tools from the given list. It's valid to specify multiple tools: in this case, all tools provided will be removed. If 'all' is explicitly passed, then the entire tool directory will be removed, providing a clean slate for reinstall. If no specific tools are provided, no action is taken",
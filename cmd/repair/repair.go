@@ -0,0 +1,39 @@
+package repair
+
+import (
+	"fmt"
+
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the Command used to invoke the repair logic
+func Cmd() *cobra.Command {
+	repairCmd := &cobra.Command{
+		Use:   "repair",
+		Args:  cobra.NoArgs,
+		Short: "Repair tools left incomplete by an interrupted install, upgrade, or removal",
+		Long:  "Detects installs, upgrades, and removals which were interrupted - such as by a crash or power loss - and returns the affected tools to a consistent state: interrupted installs and upgrades are rolled back to the previously installed version, while interrupted removals are completed. Files in the latest directory which are missing or refer to removed versions are also repaired.",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return Repair()
+		},
+	}
+	return repairCmd
+}
+
+// Repair returns all tools to a consistent state, reporting the actions taken
+func Repair() error {
+	actions, err := tools.Repair()
+	for _, action := range actions {
+		fmt.Printf("- %s\n", action)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to repair tools: %w", err)
+	}
+	if len(actions) == 0 {
+		fmt.Println("Nothing to repair: all tools are consistent")
+		return nil
+	}
+	fmt.Println("Successfully repaired tools")
+	return nil
+}
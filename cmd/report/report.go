@@ -0,0 +1,42 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/openshift/backplane-tools/pkg/support"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the Command used to invoke the report logic
+func Cmd() *cobra.Command {
+	var output string
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Args:  cobra.NoArgs,
+		Short: "Collect a support bundle for bug reports",
+		Long:  "Collects the state of backplane-tools into a gzipped tarball which can be attached to bug reports. The bundle contains the build and platform information, the configuration with tokens and passwords redacted, the state files - including the report of the most recent installation run and the journal of incomplete changes - a listing of the install directory, and the target of each link in the latest directory. No tool executables are included.",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return Report(output)
+		},
+	}
+	reportCmd.Flags().StringVarP(&output, "output", "o", "", "The path to write the bundle to. Defaults to 'backplane-tools-report-<timestamp>.tar.gz' in the current directory")
+	return reportCmd
+}
+
+// Report writes a support bundle to the provided path, or to a timestamped file in the current directory if empty
+func Report(output string) error {
+	if output == "" {
+		output = fmt.Sprintf("backplane-tools-report-%s.tar.gz", time.Now().UTC().Format("20060102T150405Z"))
+	}
+	entries, err := support.Write(output)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Wrote support bundle to '%s', containing:\n", output)
+	for _, entry := range entries {
+		fmt.Printf("- %s\n", entry)
+	}
+	fmt.Println("Please review its contents before attaching it to a bug report")
+	return nil
+}
@@ -0,0 +1,51 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/openshift/backplane-tools/pkg/inventory"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the Command used to print the inventory of installed artifacts
+func Cmd() *cobra.Command {
+	sbomCmd := &cobra.Command{
+		Use:   "sbom [tool...]",
+		Short: "Print an inventory of installed artifacts",
+		Long:  "Prints a JSON inventory of every artifact backplane-tools has installed, including where each was downloaded from, its sha256 digest, and how it was verified. If one or more tools are provided, only their artifacts are printed.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			return SBOM(args)
+		},
+	}
+	return sbomCmd
+}
+
+// SBOM prints the inventory of installed artifacts, optionally limited to the provided tools
+func SBOM(args []string) error {
+	inv, err := inventory.Read(base.InventoryPath)
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		artifacts := []inventory.Artifact{}
+		for _, artifact := range inv.Artifacts {
+			if utils.Contains(args, artifact.Tool) {
+				artifacts = append(artifacts, artifact)
+			}
+		}
+		inv.Artifacts = artifacts
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(inv)
+	if err != nil {
+		return fmt.Errorf("failed to print inventory: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package search
+
+import (
+	"fmt"
+
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the Command used to invoke the search logic
+func Cmd() *cobra.Command {
+	searchCmd := &cobra.Command{
+		Use:   "search <term>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Search the tools available for install",
+		Long:  "Searches the names and descriptions of the tools backplane-tools can manage - including those provided by plugins - for the provided term, ignoring case. Use this to discover whether a utility can be managed by backplane-tools before installing it by hand.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			return Search(args[0])
+		},
+	}
+	return searchCmd
+}
+
+// Search prints the tools matching the provided term
+func Search(term string) error {
+	matches := tools.Search(term)
+	if len(matches) == 0 {
+		fmt.Printf("No tools match '%s'. Run 'backplane-tools list available' to see every tool which can be installed\n", term)
+		return nil
+	}
+	for _, t := range matches {
+		description := tools.DescriptionOf(t)
+		if description == "" {
+			fmt.Printf("- %s\n", t.Name())
+			continue
+		}
+		fmt.Printf("- %s: %s\n", t.Name(), description)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/openshift/backplane-tools/pkg/serve"
+	"github.com/spf13/cobra"
+)
+
+// shutdownTimeout bounds how long in-flight requests are given to complete once the server is stopped
+const shutdownTimeout = 30 * time.Second
+
+// Cmd returns the Command used to invoke the serve logic
+func Cmd() *cobra.Command {
+	var (
+		listen       string
+		cacheDir     string
+		allowedHosts []string
+		tlsCert      string
+		tlsKey       string
+	)
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Args:  cobra.NoArgs,
+		Short: "Share downloads with other machines",
+		Long:  "Runs until interrupted, serving as a caching proxy for other machines running backplane-tools: clients whose 'http.cacheServer' setting refers to this server make every request through it. Assets retrieved for one client are cached on disk, and served to subsequent clients without downloading them again. Cached assets are verified against the checksum calculated when they were retrieved before they're served, and clients continue to verify every asset against its published checksums. Clients make requests with credentials, such as a GitHub token, directly rather than through the server, so that private assets and credentials are never shared. Clients only accept servers using HTTPS, unless they're on the loopback interface: provide a certificate with --tls-cert and --tls-key, or serve behind a TLS-terminating proxy. Only the hosts tools are installed from are proxied; others may be allowed with --allow-host.",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return Serve(listen, cacheDir, allowedHosts, tlsCert, tlsKey)
+		},
+	}
+	serveCmd.Flags().StringVar(&listen, "listen", ":8080", "The address to listen on")
+	serveCmd.Flags().StringVar(&cacheDir, "cache-dir", defaultCacheDir(), "The directory to cache assets in")
+	serveCmd.Flags().StringSliceVar(&allowedHosts, "allow-host", []string{}, "Additional hosts to proxy, such as a GitHub Enterprise server. May be repeated")
+	serveCmd.Flags().StringVar(&tlsCert, "tls-cert", "", "The PEM-encoded certificate to serve HTTPS with. Requires --tls-key")
+	serveCmd.Flags().StringVar(&tlsKey, "tls-key", "", "The PEM-encoded private key of the certificate provided via --tls-cert")
+	serveCmd.MarkFlagsRequiredTogether("tls-cert", "tls-key")
+	return serveCmd
+}
+
+// defaultCacheDir returns the directory assets are cached in when no other is provided
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "backplane-tools", "serve")
+}
+
+// Serve proxies requests from other machines until the process is interrupted or terminated. If tlsCert and tlsKey
+// are provided, requests are served over HTTPS
+func Serve(listen, cacheDir string, allowedHosts []string, tlsCert, tlsKey string) error {
+	if cacheDir == "" {
+		return fmt.Errorf("failed to determine the directory to cache assets in: provide one with --cache-dir")
+	}
+	err := os.MkdirAll(cacheDir, os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create cache directory '%s': %w", cacheDir, err)
+	}
+
+	server := &http.Server{
+		Addr:              listen,
+		Handler:           serve.NewServer(cacheDir, append(serve.DefaultAllowedHosts, allowedHosts...)),
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Serving on '%s', caching assets in '%s'\n", listen, cacheDir)
+	if tlsCert != "" {
+		err = server.ListenAndServeTLS(tlsCert, tlsKey)
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve: %w", err)
+	}
+	fmt.Println("Stopped serving")
+	return nil
+}
@@ -0,0 +1,187 @@
+package trust
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// ToolKeys describes the keys trusted to sign a tool's assets
+type ToolKeys struct {
+	Tool string            `json:"tool"`
+	Keys []base.TrustedKey `json:"keys"`
+}
+
+// Cmd returns the Command used to invoke the trust logic
+func Cmd() *cobra.Command {
+	trustCmd := &cobra.Command{
+		Use:   "trust",
+		Args:  cobra.NoArgs,
+		Short: "Manage the keys trusted to sign tools",
+		Long:  "Manages the GPG keys trusted to sign the assets of tools verified using signatures: butane's release assets, and the checksum files oc and crc are verified against. backplane-tools pins the fingerprints of each tool's signing keys, and only ever verifies signatures against those keys: keys retrieved while installing a tool are discarded unless they're trusted. Trusted keys are cached locally once retrieved. When a tool's maintainers begin signing with a new key, confirm the key belongs to them, then trust it with 'backplane-tools trust add'.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	trustCmd.AddCommand(listCmd())
+	trustCmd.AddCommand(addCmd())
+	trustCmd.AddCommand(removeCmd())
+	return trustCmd
+}
+
+func listCmd() *cobra.Command {
+	var output string
+	signedNames := names(tools.BuiltinSignedTools())
+	cmd := &cobra.Command{
+		Use:       fmt.Sprintf("list [%s]", strings.Join(signedNames, "|")),
+		Args:      cobra.OnlyValidArgs,
+		ValidArgs: signedNames,
+		Short:     "List the keys trusted to sign tools",
+		Long:      "Lists the keys trusted to sign each tool's assets, or only those of the provided tools. Keys are either pinned by backplane-tools or added by the user, and are cached once they've been retrieved.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			return List(args, output)
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: 'text' or 'json'")
+	return cmd
+}
+
+func addCmd() *cobra.Command {
+	var keyFile string
+	signedNames := names(tools.BuiltinSignedTools())
+	cmd := &cobra.Command{
+		Use:       fmt.Sprintf("add [%s] FINGERPRINT", strings.Join(signedNames, "|")),
+		Args:      cobra.ExactArgs(2),
+		ValidArgs: signedNames,
+		Short:     "Trust a key to sign a tool",
+		Long:      "Trusts the key with the provided fingerprint to sign the tool's assets. Only trust keys you've confirmed belong to the tool's maintainers. The key is retrieved from where the tool's keys are published when next needed, unless it's provided with --key-file.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			return Add(args[0], args[1], keyFile)
+		},
+	}
+	cmd.Flags().StringVar(&keyFile, "key-file", "", "A file containing the key, armored or binary, to cache instead of retrieving it")
+	return cmd
+}
+
+func removeCmd() *cobra.Command {
+	signedNames := names(tools.BuiltinSignedTools())
+	return &cobra.Command{
+		Use:       fmt.Sprintf("remove [%s] FINGERPRINT", strings.Join(signedNames, "|")),
+		Aliases:   []string{"rm"},
+		Args:      cobra.ExactArgs(2),
+		ValidArgs: signedNames,
+		Short:     "Stop trusting a key to sign a tool",
+		Long:      "Stops trusting the key with the provided fingerprint to sign the tool's assets, whether it was pinned by backplane-tools or added by the user, and removes it from the local cache.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			return Remove(args[0], args[1])
+		},
+	}
+}
+
+// names returns the names of the provided tools
+func names(signed []tools.Tool) []string {
+	signedNames := []string{}
+	for _, t := range signed {
+		signedNames = append(signedNames, t.Name())
+	}
+	return signedNames
+}
+
+// pinnedKeys returns the fingerprints of the keys backplane-tools pins to sign the named tool
+func pinnedKeys(name string) ([]string, error) {
+	t, found := tools.GetMap()[name]
+	if !found {
+		return []string{}, fmt.Errorf("failed to locate '%s' in list of supported tools", name)
+	}
+	pinned, found := tools.SigningKeys(t)
+	if !found {
+		return []string{}, fmt.Errorf("'%s' is not verified using GPG signatures", name)
+	}
+	return pinned, nil
+}
+
+// List prints the keys trusted to sign each of the named tools, or every tool verified using GPG signatures if none
+// are named, in the given output format
+func List(args []string, output string) error {
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unsupported output format '%s': must be one of 'text' or 'json'", output)
+	}
+	if len(args) == 0 {
+		args = names(tools.SignedTools())
+	}
+	results := []ToolKeys{}
+	for _, name := range args {
+		pinned, err := pinnedKeys(name)
+		if err != nil {
+			return err
+		}
+		keys, err := base.TrustedKeys(name, pinned)
+		if err != nil {
+			return err
+		}
+		results = append(results, ToolKeys{Tool: name, Keys: keys})
+	}
+
+	if output == "json" {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode trusted keys: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, result := range results {
+		fmt.Printf("%s:\n", result.Tool)
+		if len(result.Keys) == 0 {
+			fmt.Println("  No keys are trusted")
+			continue
+		}
+		for _, key := range result.Keys {
+			origin := "added"
+			if key.Pinned {
+				origin = "pinned"
+			}
+			cached := "not yet retrieved"
+			if key.Cached {
+				cached = strings.Join(key.UserIDs, ", ")
+			}
+			fmt.Printf("- %s (%s): %s\n", key.Fingerprint, origin, cached)
+		}
+	}
+	return nil
+}
+
+// Add trusts the key with the provided fingerprint to sign the named tool. If keyFile is provided, the key is cached
+// from it
+func Add(name, fingerprint, keyFile string) error {
+	pinned, err := pinnedKeys(name)
+	if err != nil {
+		return err
+	}
+	err = base.Trust(name, fingerprint, pinned, keyFile)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Trusted key %s to sign %s\n", utils.NormalizeFingerprint(fingerprint), name)
+	return nil
+}
+
+// Remove stops trusting the key with the provided fingerprint to sign the named tool
+func Remove(name, fingerprint string) error {
+	pinned, err := pinnedKeys(name)
+	if err != nil {
+		return err
+	}
+	err = base.Distrust(name, fingerprint, pinned)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed key %s from the keys trusted to sign %s\n", utils.NormalizeFingerprint(fingerprint), name)
+	return nil
+}
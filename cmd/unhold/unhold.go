@@ -0,0 +1,38 @@
+package unhold
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the Command used to invoke the unhold logic
+func Cmd() *cobra.Command {
+	toolNames := tools.BuiltinNames()
+	unholdCmd := &cobra.Command{
+		Use:       fmt.Sprintf("unhold [%s]", strings.Join(toolNames, "|")),
+		Args:      cobra.MatchAll(cobra.MinimumNArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: toolNames,
+		Short:     "Allow a held tool to be upgraded",
+		Long:      "Releases the hold placed on one or more tools by 'backplane-tools hold', so that they are upgraded by 'backplane-tools upgrade' again.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			return Unhold(args)
+		},
+	}
+	return unholdCmd
+}
+
+// Unhold allows the provided tools to be upgraded
+func Unhold(args []string) error {
+	for _, toolName := range args {
+		err := base.Unhold(toolName)
+		if err != nil {
+			return fmt.Errorf("failed to unhold '%s': %w", toolName, err)
+		}
+		fmt.Printf("%s will be upgraded\n", toolName)
+	}
+	return nil
+}
@@ -1,33 +1,43 @@
 package upgrade
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/policy"
 	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
 	"github.com/openshift/backplane-tools/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
 // Cmd returns the Command used to invoke the upgrade logic
 func Cmd() *cobra.Command {
-	toolNames := tools.Names()
+	var allowMajor bool
+	toolNames := tools.BuiltinNames()
 	upgradeCmd := &cobra.Command{
-		Use:       fmt.Sprintf("upgrade [all|%s]", strings.Join(toolNames, "|")),
-		Aliases:   []string{"update"},
-		Args:      cobra.OnlyValidArgs,
+		Use:     fmt.Sprintf("upgrade [all|%s]", strings.Join(toolNames, "|")),
+		Aliases: []string{"update"},
+		Args: func(_ *cobra.Command, args []string) error {
+			return tools.ValidateArgs(args, "all")
+		},
 		ValidArgs: append(toolNames, "all"),
 		Short:     "Upgrade an existing tool",
 		Long:      "Upgrades one or more tools from the provided list. It's valid to specify multiple tools: in this case, all tools provided will be upgraded. If no specific tools are provided, all are (installed and) upgraded by default.",
 		RunE: func(_ *cobra.Command, args []string) error {
-			return Upgrade(args)
+			return Upgrade(args, allowMajor)
 		},
 	}
+	upgradeCmd.Flags().BoolVar(&allowMajor, "allow-major", false, "Upgrade tools across major versions, which may introduce breaking changes. By default, tools are only upgraded within their installed major version")
+	upgradeCmd.Flags().BoolVar(&policy.Override, "allow-unapproved", false, "Install versions which aren't approved by your organization's policy. Each is reported loudly")
 	return upgradeCmd
 }
 
-// Upgrade upgrades the provided tools to their latest versions
-func Upgrade(args []string) error {
+// Upgrade upgrades the provided tools to their latest versions. Unless allowMajor is true or the user's configuration
+// allows it, tools are only upgraded to the newest release within their installed major version
+func Upgrade(args []string, allowMajor bool) error {
 	var listTools []tools.Tool
 	if len(args) == 0 || utils.Contains(args, "all") {
 		// If user explicitly passes 'all' or doesn't specify which tools to install,
@@ -51,19 +61,61 @@ func Upgrade(args []string) error {
 		}
 	}
 
+	verify := config.Get().VerifyBeforeUpgrade
 	fmt.Println("Upgrading the following tools: ")
-	upgradeList := []tools.Tool{}
+	candidates := []tools.Tool{}
 	for _, t := range listTools {
-		latestVersion, err := t.LatestVersion()
+		held, err := base.Held(t.Name())
 		if err != nil {
-			return fmt.Errorf("failed to determine version for '%s': %w", t.Name(), err)
+			return fmt.Errorf("failed to determine if '%s' is held: %w", t.Name(), err)
+		}
+		if held {
+			fmt.Printf("- %s\n", utils.Warning(fmt.Sprintf("%s is held and will not be upgraded. Run 'backplane-tools unhold %s' to allow upgrades", t.Name(), t.Name())))
+			continue
 		}
+		candidates = append(candidates, t)
+	}
+
+	// Looking up each tool's latest version requires a round trip to its source, so they're looked up concurrently
+	latestVersions, lookupErrs := tools.LatestVersions(candidates)
+	failed := []error{}
+	upgradeList := []tools.Tool{}
+	for i, t := range candidates {
+		if lookupErrs[i] != nil {
+			fmt.Printf("- %s\n", utils.Warning(fmt.Sprintf("%v. %s will not be upgraded", lookupErrs[i], t.Name())))
+			failed = append(failed, lookupErrs[i])
+			continue
+		}
+		latestVersion := latestVersions[i]
 		installedVersion, err := t.InstalledVersion()
 		if err != nil {
 			return fmt.Errorf("failed to determine version for '%s': %w", t.Name(), err)
 		}
+
+		// Tools whose files were modified after installation are reinstalled, even if they're up to date
+		modified := false
+		if verify {
+			err := tools.CheckIntegrity(t)
+			if err != nil && !errors.Is(err, base.ErrNoDigest) {
+				fmt.Printf("- %s\n", utils.Warning(fmt.Sprintf("WARNING: %v. %s will be reinstalled", err, t.Name())))
+				modified = true
+			}
+		}
+
 		if installedVersion == latestVersion {
+			if modified {
+				upgradeList = append(upgradeList, t)
+				continue
+			}
 			fmt.Printf("- %s is already installed with latest version %s and will not be upgraded\n", t.Name(), latestVersion)
+		} else if !allowMajor && !config.Get().MajorUpgradesAllowed(t.Name()) && tools.CrossesMajor(t, installedVersion, latestVersion) {
+			upgraded, err := upgradeWithinMajor(t, installedVersion, latestVersion, modified)
+			if err != nil {
+				return err
+			}
+			if upgraded {
+				upgradeList = append(upgradeList, t)
+			}
 		} else {
 			upgradeList = append(upgradeList, t)
 			fmt.Printf("- %s %s -> %s\n", t.Name(), installedVersion, latestVersion)
@@ -74,5 +126,38 @@ func Upgrade(args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to upgrade tools: %w", err)
 	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to check some tools for upgrades: %w", errors.Join(failed...))
+	}
 	return nil
 }
+
+// upgradeWithinMajor targets the newest release of the provided tool within its installed major version, rather than
+// the latest version, which is a major upgrade. If no newer release exists within the major version, modified tools
+// are targeted at their installed version so that they're reinstalled; upgraded is false if the tool won't be installed
+func upgradeWithinMajor(t tools.Tool, installedVersion, latestVersion string, modified bool) (upgraded bool, err error) {
+	hint := fmt.Sprintf("Run 'backplane-tools upgrade --allow-major %s' to upgrade to %s", t.Name(), latestVersion)
+	version, found, err := tools.NewestWithinMajor(t, installedVersion)
+	if err != nil {
+		return false, fmt.Errorf("failed to determine versions of '%s': %w", t.Name(), err)
+	}
+	if !found && modified {
+		err = tools.TargetVersion(t, installedVersion)
+		if err != nil {
+			fmt.Printf("- %s\n", utils.Warning(fmt.Sprintf("%s can't be reinstalled at %s, and %s is a major version upgrade which will not be performed. %s", t.Name(), installedVersion, latestVersion, hint)))
+			return false, nil
+		}
+		fmt.Printf("- %s %s will be reinstalled (%s)\n", t.Name(), installedVersion, utils.Warning(fmt.Sprintf("%s is a major version upgrade. %s", latestVersion, hint)))
+		return true, nil
+	}
+	if !found {
+		fmt.Printf("- %s\n", utils.Warning(fmt.Sprintf("%s %s -> %s is a major version upgrade and will not be performed. %s", t.Name(), installedVersion, latestVersion, hint)))
+		return false, nil
+	}
+	err = tools.TargetVersion(t, version)
+	if err != nil {
+		return false, err
+	}
+	fmt.Printf("- %s %s -> %s (%s)\n", t.Name(), installedVersion, version, utils.Warning(fmt.Sprintf("%s is a major version upgrade. %s", latestVersion, hint)))
+	return true, nil
+}
@@ -0,0 +1,84 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the Command used to invoke the verify logic
+func Cmd() *cobra.Command {
+	var reinstall bool
+	toolNames := tools.BuiltinNames()
+	verifyCmd := &cobra.Command{
+		Use:       fmt.Sprintf("verify [all|%s]", strings.Join(toolNames, "|")),
+		Args:      cobra.OnlyValidArgs,
+		ValidArgs: append(toolNames, "all"),
+		Short:     "Verify installed tools have not been modified",
+		Long:      "Compares the executables of one or more installed tools against the digests recorded when they were installed, reporting any that have been replaced or modified outside of backplane-tools. If no specific tools are provided, all installed tools are verified.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			return Verify(args, reinstall)
+		},
+	}
+	verifyCmd.Flags().BoolVar(&reinstall, "reinstall", false, "Reinstall any tools which have been modified")
+	return verifyCmd
+}
+
+// Verify checks the provided tools for modifications, optionally reinstalling those which have been modified
+func Verify(args []string, reinstall bool) error {
+	var listTools []tools.Tool
+	if len(args) == 0 || utils.Contains(args, "all") {
+		var err error
+		listTools, err = tools.ListInstalled()
+		if err != nil {
+			return err
+		}
+	} else {
+		toolMap := tools.GetMap()
+
+		listTools = []tools.Tool{}
+		for _, toolName := range args {
+			t, found := toolMap[toolName]
+			if !found {
+				return fmt.Errorf("failed to locate '%s' in list of supported tools", toolName)
+			}
+			listTools = append(listTools, t)
+		}
+	}
+
+	modified := []tools.Tool{}
+	modifiedNames := []string{}
+	for _, t := range listTools {
+		err := tools.CheckIntegrity(t)
+		switch {
+		case err == nil:
+			fmt.Printf("- %s: ok\n", t.Name())
+		case errors.Is(err, base.ErrNoDigest):
+			fmt.Printf("- %s: unknown (%v: reinstall the tool to record one)\n", t.Name(), err)
+		default:
+			fmt.Printf("- %s: MODIFIED (%v)\n", t.Name(), err)
+			modified = append(modified, t)
+			modifiedNames = append(modifiedNames, t.Name())
+		}
+	}
+
+	if len(modified) == 0 {
+		return nil
+	}
+	if !reinstall {
+		fmt.Println()
+		fmt.Printf("WARNING: %d tool(s) have been modified since they were installed. Reinstall them by running 'backplane-tools verify --reinstall %s'\n", len(modified), strings.Join(modifiedNames, " "))
+		return fmt.Errorf("detected modified tools: %s", strings.Join(modifiedNames, ", "))
+	}
+
+	err := tools.Install(modified)
+	if err != nil {
+		return fmt.Errorf("failed to reinstall modified tools: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/openshift/backplane-tools/pkg/buildinfo"
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/spf13/cobra"
+)
+
+// ToolVersion describes the version of an installed tool
+type ToolVersion struct {
+	// Tool is the tool's name
+	Tool string `json:"tool"`
+
+	// Installed is the version linked into the latest directory, as determined by the directory it's installed in
+	Installed string `json:"installed"`
+
+	// Reported is the version the tool's executable reports itself as, if it's able to
+	Reported string `json:"reported,omitempty"`
+
+	// Error describes why the tool's versions couldn't be determined, if they couldn't
+	Error string `json:"error,omitempty"`
+}
+
+// Report describes the running build of backplane-tools and, optionally, the versions of each installed tool
+type Report struct {
+	buildinfo.Info
+
+	// Tools lists the versions of each installed tool
+	Tools []ToolVersion `json:"tools,omitempty"`
+}
+
+// Cmd returns the Command used to invoke the version logic
+func Cmd() *cobra.Command {
+	var (
+		all    bool
+		output string
+	)
+	versionCmd := &cobra.Command{
+		Use:   "version",
+		Args:  cobra.NoArgs,
+		Short: "Print version information",
+		Long:  "Prints the version, commit, and build date of backplane-tools, along with the Go version it was built with. With --all, the version of each installed tool is printed as well: both the version it's installed as, and the version its executable reports itself as. The two differ when an executable has been replaced outside of backplane-tools.",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return Version(all, output)
+		},
+	}
+	versionCmd.Flags().BoolVar(&all, "all", false, "Also print the version of each installed tool")
+	versionCmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: 'text' or 'json'")
+	return versionCmd
+}
+
+// Version prints the build information of backplane-tools in the given output format. If all is true, the versions of
+// each installed tool are printed as well
+func Version(all bool, output string) error {
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unsupported output format '%s': must be one of 'text' or 'json'", output)
+	}
+	report := Report{Info: buildinfo.Get()}
+	if all {
+		var err error
+		report.Tools, err = toolVersions()
+		if err != nil {
+			return err
+		}
+	}
+
+	if output == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode version information: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("backplane-tools %s\n", report.Version)
+	fmt.Printf("Commit: %s\n", report.Commit)
+	fmt.Printf("Built: %s\n", report.Date)
+	fmt.Printf("Go version: %s\n", report.GoVersion)
+	fmt.Printf("Platform: %s\n", report.Platform)
+	if !all {
+		return nil
+	}
+	fmt.Println()
+	if len(report.Tools) == 0 {
+		fmt.Println("No tools are installed")
+		return nil
+	}
+	fmt.Println("Installed tools:")
+	for _, v := range report.Tools {
+		switch {
+		case v.Error != "":
+			fmt.Printf("- %s %s (failed to determine version: %s)\n", v.Tool, v.Installed, v.Error)
+		case v.Reported == "":
+			fmt.Printf("- %s %s (does not report its version)\n", v.Tool, v.Installed)
+		default:
+			fmt.Printf("- %s %s (reports %s)\n", v.Tool, v.Installed, v.Reported)
+		}
+	}
+	return nil
+}
+
+// toolVersions returns the versions of each installed tool, sorted by name. Failing to determine a tool's versions
+// is recorded in its result, rather than preventing the others from being reported
+func toolVersions() ([]ToolVersion, error) {
+	installed, err := tools.ListInstalled()
+	if err != nil {
+		return []ToolVersion{}, err
+	}
+	sort.Slice(installed, func(i, j int) bool {
+		return installed[i].Name() < installed[j].Name()
+	})
+
+	versions := []ToolVersion{}
+	for _, t := range installed {
+		v := ToolVersion{Tool: t.Name()}
+		v.Installed, err = t.InstalledVersion()
+		if err != nil {
+			v.Error = err.Error()
+			versions = append(versions, v)
+			continue
+		}
+		v.Reported, _, err = tools.ReportedVersion(t)
+		if err != nil {
+			v.Error = err.Error()
+		}
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
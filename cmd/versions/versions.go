@@ -0,0 +1,73 @@
+package versions
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the Command used to invoke the versions logic
+func Cmd() *cobra.Command {
+	toolNames := tools.BuiltinNames()
+	versionsCmd := &cobra.Command{
+		Use:       fmt.Sprintf("versions [%s]", strings.Join(toolNames, "|")),
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: toolNames,
+		Short:     "List the versions of a tool available for install",
+		Long:      "Lists the versions of a tool published by its source, from oldest to newest. Versions which are installed are marked with '(installed)', and the version currently linked into the latest directory is marked with '*'.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			return Versions(args[0])
+		},
+	}
+	return versionsCmd
+}
+
+// Versions prints the published versions of the named tool, marking those which are installed
+func Versions(toolName string) error {
+	tool, found := tools.GetMap()[toolName]
+	if !found {
+		return fmt.Errorf("failed to locate '%s' in list of supported tools", toolName)
+	}
+	published, found, err := tools.ListVersions(tool)
+	if !found {
+		return fmt.Errorf("%s does not support listing its versions", toolName)
+	}
+	if err != nil {
+		return err
+	}
+
+	installedVersions, err := base.InstalledVersions(toolName)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	active := ""
+	installed, err := tool.Installed()
+	if err == nil && installed {
+		active, _ = tool.InstalledVersion()
+	}
+
+	// Installed versions are listed even if they're no longer published
+	for _, version := range installedVersions {
+		if !utils.Contains(published, version) {
+			published = append(published, version)
+		}
+	}
+	for _, version := range published {
+		marker := " "
+		if version == active {
+			marker = "*"
+		}
+		if utils.Contains(installedVersions, version) {
+			fmt.Printf("%s %s (installed)\n", marker, version)
+			continue
+		}
+		fmt.Printf("%s %s\n", marker, version)
+	}
+	return nil
+}
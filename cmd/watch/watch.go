@@ -0,0 +1,137 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/openshift/backplane-tools/cmd/upgrade"
+	"github.com/openshift/backplane-tools/pkg/report"
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/spf13/cobra"
+)
+
+// minInterval bounds how often upgrades can be checked for, to avoid exhausting the sources' rate limits
+const minInterval = 5 * time.Minute
+
+// Cmd returns the Command used to invoke the watch logic
+func Cmd() *cobra.Command {
+	var (
+		interval time.Duration
+		logFile  string
+	)
+	watchCmd := &cobra.Command{
+		Use:   "watch",
+		Args:  cobra.NoArgs,
+		Short: "Keep installed tools up to date until stopped",
+		Long:  "Runs until interrupted, upgrading installed tools whenever a new release is published. Every --interval, the installed tools are upgraded exactly as 'backplane-tools upgrade' would: held tools aren't upgraded, and tools are only upgraded within their installed major version unless the configuration allows otherwise. Each check is logged with a timestamp, along with the tools it changed. Failed checks are logged, and retried at the next interval.",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return Watch(interval, logFile)
+		},
+	}
+	watchCmd.Flags().DurationVar(&interval, "interval", 6*time.Hour, "How long to wait between checks for upgrades")
+	watchCmd.Flags().StringVar(&logFile, "log-file", "", "A file to append the log of what changed to, in addition to printing it")
+	return watchCmd
+}
+
+// Watch upgrades the installed tools every interval until the process is interrupted or terminated. If logFile is
+// provided, the outcome of each check is appended to it
+func Watch(interval time.Duration, logFile string) error {
+	if interval < minInterval {
+		return fmt.Errorf("invalid interval '%s': must be at least %s", interval, minInterval)
+	}
+	log, err := newLogger(logFile)
+	if err != nil {
+		return err
+	}
+	defer log.close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.printf("Checking for upgrades every %s", interval)
+	for {
+		check(log)
+		log.printf("Next check at %s", time.Now().Add(interval).Format(time.RFC3339))
+		select {
+		case <-ctx.Done():
+			log.printf("Stopping")
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// check upgrades the installed tools, and logs what changed
+func check(log *logger) {
+	// Rather than reusing the versions retrieved by the previous check, retrieve them again from each tool's source
+	tools.Reload()
+	startedAt := time.Now().UTC()
+	upgradeErr := upgrade.Upgrade([]string{}, false)
+	if upgradeErr != nil {
+		log.printf("Failed to check for upgrades: %v", upgradeErr)
+	}
+
+	// A report is written whenever tools are installed, so if it predates this check, nothing was changed
+	run, found, err := report.Read(base.ReportPath)
+	if err != nil {
+		log.printf("WARNING: %v", err)
+		return
+	}
+	if !found || run.StartedAt.Before(startedAt) || len(run.Results) == 0 {
+		if upgradeErr == nil {
+			log.printf("All tools are up to date")
+		}
+		return
+	}
+	for _, result := range run.Results {
+		switch {
+		case !result.Success:
+			log.printf("Failed to upgrade %s: %s", result.Tool, result.Error)
+		case result.Action == report.ActionUpgrade:
+			log.printf("Upgraded %s from %s to %s", result.Tool, result.VersionBefore, result.VersionAfter)
+		case result.Action == report.ActionInstall:
+			log.printf("Installed %s %s", result.Tool, result.VersionAfter)
+		default:
+			log.printf("Reinstalled %s %s", result.Tool, result.VersionAfter)
+		}
+	}
+}
+
+// logger prints timestamped messages, optionally appending them to a file as well
+type logger struct {
+	file *os.File
+}
+
+// newLogger returns a logger appending to the provided file, if any
+func newLogger(path string) (*logger, error) {
+	if path == "" {
+		return &logger{}, nil
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, os.FileMode(0o644))
+	if err != nil {
+		return &logger{}, fmt.Errorf("failed to open log file '%s': %w", path, err)
+	}
+	return &logger{file: file}, nil
+}
+
+func (l *logger) printf(format string, args ...any) {
+	line := fmt.Sprintf("%s %s\n", time.Now().Format(time.RFC3339), fmt.Sprintf(format, args...))
+	fmt.Print(line)
+	if l.file != nil {
+		_, err := l.file.WriteString(line)
+		if err != nil {
+			fmt.Printf("WARNING: failed to write to log file '%s': %v\n", l.file.Name(), err)
+		}
+	}
+}
+
+func (l *logger) close() {
+	if l.file != nil {
+		_ = l.file.Close()
+	}
+}
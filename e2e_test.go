@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/openshift/backplane-tools/pkg/utils/test"
+)
+
+// newHarness opens a Harness routing GitHub's API to the returned server, and isolates the user's home directory
+func newHarness(t *testing.T) (*test.Harness, *test.GithubServer) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	h, err := test.NewHarness()
+	if err != nil {
+		t.Fatalf("failed to create harness: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = h.Close()
+	})
+	gh := test.NewGithubServer()
+	h.Route("api.github.com", gh)
+	return h, gh
+}
+
+// yqAsset returns the name of yq's release asset for the target platform
+func yqAsset() string {
+	return fmt.Sprintf("yq_%s_%s", utils.TargetOS, utils.TargetArch)
+}
+
+// publishYq publishes a release of yq at tag, whose executable reports the release's version, and whose checksum
+// file lists the digests of checksummed
+func publishYq(gh *test.GithubServer, tag string, checksummed []byte) {
+	executable := []byte(fmt.Sprintf("#!/bin/sh\necho 'yq (https://github.com/mikefarah/yq/) version %s'\n", tag))
+	if checksummed == nil {
+		checksummed = executable
+	}
+	gh.AddRelease("mikefarah", "yq", tag, map[string][]byte{
+		yqAsset():   executable,
+		"checksums": test.SHA256Sums(map[string][]byte{yqAsset(): checksummed}),
+	})
+}
+
+// run executes backplane-tools with the provided arguments, returning its output and exit status
+func run(t *testing.T, h *test.Harness, args ...string) (test.Output, int) {
+	t.Helper()
+	out, err := h.Run(&cmd, args...)
+	if err == nil {
+		return out, 0
+	}
+	code, _ := exitCode(err)
+	return out, code
+}
+
+// tree returns the install root's contents
+func tree(t *testing.T, h *test.Harness) []string {
+	t.Helper()
+	entries, err := h.Tree()
+	if err != nil {
+		t.Fatalf("failed to list install root: %v", err)
+	}
+	return entries
+}
+
+// yqLink returns the tree entry of yq's link in the latest directory, if any
+func yqLink(t *testing.T, h *test.Harness) (string, bool) {
+	t.Helper()
+	for _, entry := range tree(t, h) {
+		if strings.HasPrefix(entry, filepath.Join("latest", "yq")+" -> ") {
+			return entry, true
+		}
+	}
+	return "", false
+}
+
+func TestInstall(t *testing.T) {
+	h, gh := newHarness(t)
+	publishYq(gh, "v4.40.5", nil)
+
+	out, code := run(t, h, "install", "yq")
+	if code != 0 {
+		t.Fatalf("install exited with status %d:\n%s%s", code, out.Stdout, out.Stderr)
+	}
+	link, found := yqLink(t, h)
+	if !found {
+		t.Fatalf("expected yq to be linked, install root contains:\n%s", strings.Join(tree(t, h), "\n"))
+	}
+	expectedTarget := filepath.Join(base.InstallDir, "yq", "v4.40.5", yqAsset())
+	if !strings.HasSuffix(link, " -> "+expectedTarget) {
+		t.Errorf("expected yq to link to '%s', got '%s'", expectedTarget, link)
+	}
+}
+
+func TestInstallFailures(t *testing.T) {
+	tests := []struct {
+		name         string
+		publish      func(gh *test.GithubServer)
+		args         []string
+		expectedCode int
+	}{
+		{
+			name: "checksum mismatch",
+			publish: func(gh *test.GithubServer) {
+				publishYq(gh, "v4.40.5", []byte("tampered"))
+			},
+			args:         []string{"install", "yq"},
+			expectedCode: 4,
+		},
+		{
+			name: "no asset for the platform",
+			publish: func(gh *test.GithubServer) {
+				gh.AddRelease("mikefarah", "yq", "v4.40.5", map[string][]byte{"checksums": test.SHA256Sums(nil)})
+			},
+			args:         []string{"install", "yq"},
+			expectedCode: 5,
+		},
+		{
+			name:         "unknown tool",
+			publish:      func(_ *test.GithubServer) {},
+			args:         []string{"install", "not-a-tool"},
+			expectedCode: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, gh := newHarness(t)
+			tt.publish(gh)
+
+			out, code := run(t, h, tt.args...)
+			if code != tt.expectedCode {
+				t.Errorf("expected exit status %d, got %d:\n%s%s", tt.expectedCode, code, out.Stdout, out.Stderr)
+			}
+			if link, found := yqLink(t, h); found {
+				t.Errorf("expected nothing to be linked after a failed installation, found '%s'", link)
+			}
+		})
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	h, gh := newHarness(t)
+	publishYq(gh, "v4.40.5", nil)
+	out, code := run(t, h, "install", "yq")
+	if code != 0 {
+		t.Fatalf("install exited with status %d:\n%s%s", code, out.Stdout, out.Stderr)
+	}
+
+	publishYq(gh, "v4.41.0", nil)
+	out, code = run(t, h, "upgrade", "yq")
+	if code != 0 {
+		t.Fatalf("upgrade exited with status %d:\n%s%s", code, out.Stdout, out.Stderr)
+	}
+	link, found := yqLink(t, h)
+	expectedTarget := filepath.Join(base.InstallDir, "yq", "v4.41.0", yqAsset())
+	if !found || !strings.HasSuffix(link, " -> "+expectedTarget) {
+		t.Errorf("expected yq to link to '%s' once upgraded, got '%s'", expectedTarget, link)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	h, gh := newHarness(t)
+	publishYq(gh, "v4.40.5", nil)
+	out, code := run(t, h, "install", "yq")
+	if code != 0 {
+		t.Fatalf("install exited with status %d:\n%s%s", code, out.Stdout, out.Stderr)
+	}
+
+	out, code = run(t, h, "remove", "yq")
+	if code != 0 {
+		t.Fatalf("remove exited with status %d:\n%s%s", code, out.Stdout, out.Stderr)
+	}
+	for _, entry := range tree(t, h) {
+		if strings.HasPrefix(entry, "yq") || strings.HasPrefix(entry, filepath.Join("latest", "yq")) {
+			t.Errorf("expected yq to be removed, found '%s'", entry)
+		}
+	}
+}
+
+func TestUpgradeReinstallsModifiedToolWithinMajor(t *testing.T) {
+	h, gh := newHarness(t)
+	publishYq(gh, "v4.40.5", nil)
+	out, code := run(t, h, "install", "yq")
+	if code != 0 {
+		t.Fatalf("install exited with status %d:\n%s%s", code, out.Stdout, out.Stderr)
+	}
+	executablePath := filepath.Join(base.InstallDir, "yq", "v4.40.5", yqAsset())
+	err := os.Chmod(executablePath, 0o755)
+	if err == nil {
+		err = os.WriteFile(executablePath, []byte("#!/bin/sh\necho modified\n"), 0o755)
+	}
+	if err != nil {
+		t.Fatalf("failed to modify yq: %v", err)
+	}
+
+	// The only newer release is a major upgrade, which isn't allowed, so the modified version is reinstalled
+	publishYq(gh, "v5.0.0", nil)
+	err = h.WriteConfig("verifyBeforeUpgrade: true\n")
+	if err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	out, code = run(t, h, "upgrade", "yq")
+	if code != 0 {
+		t.Fatalf("upgrade exited with status %d:\n%s%s", code, out.Stdout, out.Stderr)
+	}
+	link, found := yqLink(t, h)
+	if !found || !strings.HasSuffix(link, " -> "+executablePath) {
+		t.Errorf("expected yq to remain linked to '%s', got '%s'", executablePath, link)
+	}
+	contents, err := os.ReadFile(executablePath)
+	if err != nil {
+		t.Fatalf("failed to read yq: %v", err)
+	}
+	if !strings.Contains(string(contents), "version v4.40.5") {
+		t.Errorf("expected the modified executable to be reinstalled, got:\n%s", contents)
+	}
+}
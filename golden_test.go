@@ -0,0 +1,46 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift/backplane-tools/pkg/utils/test"
+)
+
+// compareGolden compares the normalized output of a command against the golden file of the provided name
+func compareGolden(t *testing.T, h *test.Harness, name string, out test.Output) {
+	t.Helper()
+	actual := h.Normalize("stdout:\n" + out.Stdout + "stderr:\n" + out.Stderr)
+	err := test.CompareGolden(filepath.Join("testdata", "golden", name+".golden"), actual)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGoldenOutput(t *testing.T) {
+	h, gh := newHarness(t)
+	publishYq(gh, "v4.40.5", nil)
+	t.Setenv("PATH", filepath.Join(h.Dir, "latest"))
+
+	// Each command is run in turn against the same install root
+	steps := []struct {
+		name    string
+		publish string
+		args    []string
+	}{
+		{name: "install", args: []string{"install", "yq"}},
+		{name: "list", args: []string{"list", "installed"}},
+		{name: "upgrade", publish: "v4.41.0", args: []string{"upgrade", "yq"}},
+		{name: "remove", args: []string{"remove", "yq"}},
+	}
+	for _, step := range steps {
+		if step.publish != "" {
+			publishYq(gh, step.publish, nil)
+		}
+		out, code := run(t, h, step.args...)
+		if code != 0 {
+			t.Fatalf("%s exited with status %d:\n%s%s", step.name, code, out.Stdout, out.Stderr)
+		}
+		compareGolden(t, h, step.name, out)
+	}
+}
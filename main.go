@@ -1,37 +1,202 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"os"
 
+	"github.com/openshift/backplane-tools/cmd/adopt"
+	"github.com/openshift/backplane-tools/cmd/bundle"
+	cmdconfig "github.com/openshift/backplane-tools/cmd/config"
+	"github.com/openshift/backplane-tools/cmd/configure"
+	"github.com/openshift/backplane-tools/cmd/diff"
+	"github.com/openshift/backplane-tools/cmd/exec"
+	"github.com/openshift/backplane-tools/cmd/hold"
+	"github.com/openshift/backplane-tools/cmd/initialize"
 	"github.com/openshift/backplane-tools/cmd/install"
 	"github.com/openshift/backplane-tools/cmd/list"
+	"github.com/openshift/backplane-tools/cmd/login"
+	"github.com/openshift/backplane-tools/cmd/migrate"
+	"github.com/openshift/backplane-tools/cmd/ocmplugins"
+	"github.com/openshift/backplane-tools/cmd/promptstatus"
 	"github.com/openshift/backplane-tools/cmd/remove"
+	"github.com/openshift/backplane-tools/cmd/repair"
+	"github.com/openshift/backplane-tools/cmd/report"
+	"github.com/openshift/backplane-tools/cmd/sbom"
+	"github.com/openshift/backplane-tools/cmd/search"
+	"github.com/openshift/backplane-tools/cmd/serve"
+	"github.com/openshift/backplane-tools/cmd/trust"
+	"github.com/openshift/backplane-tools/cmd/unhold"
 	"github.com/openshift/backplane-tools/cmd/upgrade"
+	"github.com/openshift/backplane-tools/cmd/verify"
+	"github.com/openshift/backplane-tools/cmd/version"
+	"github.com/openshift/backplane-tools/cmd/versions"
+	"github.com/openshift/backplane-tools/cmd/watch"
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
+// noColor disables colored output, regardless of whether stdout is a terminal
+var noColor bool
+
+// limitRate caps the combined throughput of all downloads, overriding the 'http.limitRate' setting
+var limitRate string
+
+// offline prevents any network requests, so that only cached metadata and assets are used
+var offline bool
+
+// configErr is the error encountered while applying the user's configuration, if any
+var configErr error
+
 var cmd = cobra.Command{
 	Use:   "backplane-tools",
 	Short: "An OpenShift tool manager",
 	Long:  "This applications manages the tools needed to interact with OpenShift clusters",
 	RunE:  help,
+	PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+		return configErr
+	},
 }
 
 func help(cmd *cobra.Command, _ []string) error {
 	return cmd.Help()
 }
 
+// loadConfig reads the user's configuration file before any subcommand runs, so that invalid settings are reported up
+// front. It's run before the subcommand's arguments are validated, since validating them loads the user's catalog and
+// plugins, which must honor the configuration
+func loadConfig() error {
+	if noColor {
+		utils.DisableColor()
+	}
+	utils.Offline = offline
+	err := config.Load()
+	if err != nil {
+		return err
+	}
+	utils.TargetLibc = config.Get().Libc
+	utils.WorldReadable = config.Get().FilesWorldReadable()
+	rate := config.Get().DownloadRateLimit()
+	if limitRate != "" {
+		rate, err = utils.ParseRate(limitRate)
+		if err != nil {
+			return fmt.Errorf("--limit-rate: %w", err)
+		}
+	}
+	utils.SetDownloadRateLimit(rate)
+	return configureHTTP(config.Get())
+}
+
+// configureHTTP applies the user's TLS and connection settings to every HTTP request made by backplane-tools
+func configureHTTP(c *config.Config) error {
+	if c.TLS.InsecureSkipVerify {
+		fmt.Println("WARNING: TLS certificate verification is disabled by the 'tls.insecureSkipVerify' setting. Downloaded tools may have been tampered with")
+	}
+	if c.Mirror.InsecureHTTP {
+		fmt.Println("WARNING: mirror.openshift.com is accessed over plain HTTP per the 'mirror.insecureHTTP' setting. Downloaded tools may have been tampered with")
+	}
+	err := utils.SetCacheServer(c.HTTP.CacheServer)
+	if err != nil {
+		return err
+	}
+	hosts := map[string]utils.TransportSettings{}
+	for host, settings := range c.HTTP.Hosts {
+		hosts[host] = transportSettings(settings)
+	}
+	return utils.ConfigureTransport(utils.TLSOptions{
+		CABundle:           c.TLS.CABundle,
+		ClientCertificate:  c.TLS.ClientCertificate,
+		ClientKey:          c.TLS.ClientKey,
+		InsecureSkipVerify: c.TLS.InsecureSkipVerify,
+	}, transportSettings(c.HTTP.HTTPSettings), hosts)
+}
+
+// transportSettings converts the user's connection settings for use by utils.ConfigureTransport
+func transportSettings(settings config.HTTPSettings) utils.TransportSettings {
+	return utils.TransportSettings{
+		ConnectTimeout:      settings.ConnectTimeout,
+		ReadTimeout:         settings.ReadTimeout,
+		KeepAlive:           settings.KeepAlive,
+		MaxIdleConnsPerHost: settings.MaxIdleConnsPerHost,
+	}
+}
+
 // Add subcommands
 func init() {
+	cobra.OnInitialize(func() {
+		configErr = loadConfig()
+	})
+	cmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output. Output is also uncolored when it isn't a terminal, or when $NO_COLOR is set")
+	cmd.PersistentFlags().StringVar(&limitRate, "limit-rate", "", "Cap the combined throughput of all downloads, in bytes per second, such as '500K' or '2M'. Overrides the 'http.limitRate' setting")
+	cmd.PersistentFlags().BoolVar(&offline, "offline", false, "Make no network requests, using the release metadata and assets cached by earlier runs instead. Only tools installed from GitHub releases are cached")
+	cmd.AddCommand(adopt.Cmd())
+	cmd.AddCommand(bundle.Cmd())
+	cmd.AddCommand(cmdconfig.Cmd())
+	cmd.AddCommand(configure.Cmd())
+	cmd.AddCommand(diff.Cmd())
+	cmd.AddCommand(exec.Cmd())
+	cmd.AddCommand(hold.Cmd())
+	cmd.AddCommand(initialize.Cmd())
 	cmd.AddCommand(install.Cmd())
 	cmd.AddCommand(list.Cmd())
+	cmd.AddCommand(login.Cmd())
+	cmd.AddCommand(migrate.Cmd())
+	cmd.AddCommand(ocmplugins.Cmd())
+	cmd.AddCommand(promptstatus.Cmd())
 	cmd.AddCommand(remove.Cmd())
+	cmd.AddCommand(repair.Cmd())
+	cmd.AddCommand(report.Cmd())
+	cmd.AddCommand(sbom.Cmd())
+	cmd.AddCommand(search.Cmd())
+	cmd.AddCommand(serve.Cmd())
+	cmd.AddCommand(trust.Cmd())
+	cmd.AddCommand(unhold.Cmd())
 	cmd.AddCommand(upgrade.Cmd())
+	cmd.AddCommand(verify.Cmd())
+	cmd.AddCommand(version.Cmd())
+	cmd.AddCommand(versions.Cmd())
+	cmd.AddCommand(watch.Cmd())
+}
+
+// failure describes how backplane-tools exits when a command fails with an error in a given category
+type failure struct {
+	err  error
+	code int
+	hint string
+}
+
+// failures lists the categories of error with a dedicated exit code, so that scripts can react to them. Commands
+// failing for any other reason exit with status 1
+var failures = []failure{
+	{err: utils.ErrAssetNotFound, code: 3, hint: "The release or file may not have been published yet, or may have been removed"},
+	{err: utils.ErrChecksumMismatch, code: 4, hint: "The download may have been corrupted or tampered with. Retry, and report the problem if it persists"},
+	{err: utils.ErrUnsupportedPlatform, code: 5, hint: "The tool isn't published for this operating system and architecture"},
+	{err: utils.ErrRateLimited, code: 6, hint: "Wait a while before retrying"},
+	{err: utils.ErrNetwork, code: 7, hint: "Check your network connection and proxy settings, then retry"},
+	{err: utils.ErrNotApproved, code: 8, hint: "Install a version approved by your organization's policy, or ask for this one to be approved"},
+}
+
+// exitCode returns the status backplane-tools exits with when a command fails with err, along with a hint
+// describing how to resolve the failure, if any
+func exitCode(err error) (code int, hint string) {
+	for _, f := range failures {
+		if errors.Is(err, f.err) {
+			return f.code, f.hint
+		}
+	}
+	return 1, ""
 }
 
 func main() {
 	err := cmd.Execute()
 	if err != nil {
-		log.Fatalf("Error executing command: %v", err)
+		log.Printf("Error executing command: %v", err)
+		code, hint := exitCode(err)
+		if hint != "" {
+			log.Println(hint)
+		}
+		os.Exit(code)
 	}
 }
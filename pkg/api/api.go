@@ -0,0 +1,205 @@
+/*
+api provides a stable interface for embedding backplane-tools' tool management within other applications.
+
+The types and functions exported by this package are supported across releases of backplane-tools. Other
+packages within this module are considered internal implementation details and may change without notice.
+*/
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/openshift/backplane-tools/pkg/sources/base/url"
+	"github.com/openshift/backplane-tools/pkg/sources/cloud.google.com/storage"
+	"github.com/openshift/backplane-tools/pkg/sources/github"
+	"github.com/openshift/backplane-tools/pkg/sources/openshift.com/mirror"
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// Tool defines the operations available for each tool backplane-tools manages
+type Tool = tools.Tool
+
+// The following errors categorize why an operation failed. Errors returned by this package wrap the matching
+// category, if any, so that callers can check for it using errors.Is
+var (
+	// ErrAssetNotFound indicates a release, asset, or file which was expected to be published could not be found
+	ErrAssetNotFound = utils.ErrAssetNotFound
+
+	// ErrChecksumMismatch indicates a file's contents differ from its published checksum or digest
+	ErrChecksumMismatch = utils.ErrChecksumMismatch
+
+	// ErrUnsupportedPlatform indicates a tool isn't published for the target operating system or architecture
+	ErrUnsupportedPlatform = utils.ErrUnsupportedPlatform
+
+	// ErrRateLimited indicates a server refused a request because too many have been made
+	ErrRateLimited = utils.ErrRateLimited
+
+	// ErrNetwork indicates a request failed before a response was received
+	ErrNetwork = utils.ErrNetwork
+)
+
+// GithubSource retrieves releases and assets from a GitHub repository
+type GithubSource = github.Source
+
+// MirrorSource retrieves files from mirror.openshift.com
+type MirrorSource = mirror.Source
+
+// URLSource retrieves files from an arbitrary HTTP server
+type URLSource = url.Source
+
+// StorageSource retrieves objects from a Google Cloud Storage bucket
+type StorageSource = storage.Source
+
+// NewGithubSource creates a GithubSource for the given repository
+func NewGithubSource(owner, repo string) *GithubSource {
+	return github.NewSource(owner, repo)
+}
+
+// NewMirrorSource creates a MirrorSource
+func NewMirrorSource() *MirrorSource {
+	return mirror.NewSource()
+}
+
+// NewURLSource creates a URLSource which builds its requests off of the provided base URL
+func NewURLSource(baseURL string) *URLSource {
+	return url.NewSource(baseURL)
+}
+
+// NewStorageSource creates a StorageSource for the given bucket
+func NewStorageSource(bucketName string) (*StorageSource, error) {
+	return storage.NewSource(bucketName)
+}
+
+// InstallDir returns the root directory all tools are installed into
+func InstallDir() string {
+	return base.InstallDir
+}
+
+// LatestDir returns the directory containing links to the latest version of each installed tool
+func LatestDir() string {
+	return base.LatestDir
+}
+
+// ToolNames returns the names of all tools available for management, in alphabetical order
+func ToolNames() []string {
+	names := tools.Names()
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the tools matching the provided names. An error is returned if any name
+// does not correspond to a known tool
+func Lookup(names ...string) ([]Tool, error) {
+	toolMap := tools.GetMap()
+	found := make([]Tool, 0, len(names))
+	for _, name := range names {
+		t, ok := toolMap[name]
+		if !ok {
+			return []Tool{}, fmt.Errorf("failed to locate '%s' in list of supported tools", name)
+		}
+		found = append(found, t)
+	}
+	return found, nil
+}
+
+// Installed returns all tools currently installed on the local machine
+func Installed() ([]Tool, error) {
+	return tools.ListInstalled()
+}
+
+// Install installs the latest version of each named tool
+func Install(names ...string) error {
+	installList, err := Lookup(names...)
+	if err != nil {
+		return err
+	}
+	return tools.Install(installList)
+}
+
+// Upgrade installs the latest version of each named tool whose installed version is out of date.
+// Tools which have not been installed are installed. Held tools are skipped
+func Upgrade(names ...string) error {
+	toolList, err := Lookup(names...)
+	if err != nil {
+		return err
+	}
+
+	candidates := []Tool{}
+	for _, t := range toolList {
+		held, err := base.Held(t.Name())
+		if err != nil {
+			return fmt.Errorf("failed to determine if '%s' is held: %w", t.Name(), err)
+		}
+		if !held {
+			candidates = append(candidates, t)
+		}
+	}
+
+	// Looking up each tool's latest version requires a round trip to its source, so they're looked up concurrently
+	latestVersions, lookupErrs := tools.LatestVersions(candidates)
+	failed := []error{}
+	upgradeList := []Tool{}
+	for i, t := range candidates {
+		if lookupErrs[i] != nil {
+			failed = append(failed, lookupErrs[i])
+			continue
+		}
+		outdated, err := outdatedAgainst(t, latestVersions[i])
+		if err != nil {
+			return err
+		}
+		if outdated {
+			upgradeList = append(upgradeList, t)
+		}
+	}
+	err = tools.Install(upgradeList)
+	if err != nil {
+		return err
+	}
+	return errors.Join(failed...)
+}
+
+// Outdated returns true if the provided tool is not installed, or if the installed version differs
+// from the latest version available
+func Outdated(t Tool) (bool, error) {
+	latestVersion, err := t.LatestVersion()
+	if err != nil {
+		return false, fmt.Errorf("failed to determine latest version for '%s': %w", t.Name(), err)
+	}
+	return outdatedAgainst(t, latestVersion)
+}
+
+// outdatedAgainst returns true if the provided tool is not installed, or if the installed version differs from the
+// provided latest version
+func outdatedAgainst(t Tool, latestVersion string) (bool, error) {
+	installed, err := t.Installed()
+	if err != nil {
+		return false, fmt.Errorf("failed to determine if '%s' has been installed: %w", t.Name(), err)
+	}
+	if !installed {
+		return true, nil
+	}
+	installedVersion, err := t.InstalledVersion()
+	if err != nil {
+		return false, fmt.Errorf("failed to determine installed version for '%s': %w", t.Name(), err)
+	}
+	return installedVersion != latestVersion, nil
+}
+
+// Remove uninstalls each named tool
+func Remove(names ...string) error {
+	removeList, err := Lookup(names...)
+	if err != nil {
+		return err
+	}
+	return tools.Remove(removeList)
+}
+
+// RemoveAll removes every tool, along with the installation directory
+func RemoveAll() error {
+	return tools.RemoveInstallDir()
+}
@@ -0,0 +1,64 @@
+/*
+buildinfo provides the capability to describe how the running build of backplane-tools was produced
+*/
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// The following are set at build time via '-ldflags -X'. When unset - such as for builds made with 'go build' or
+// 'go install' - they're derived from the information Go embeds in the binary, if available
+var (
+	// Version is the release backplane-tools was built from
+	Version string
+
+	// Commit is the git commit backplane-tools was built from
+	Commit string
+
+	// Date is when backplane-tools was built, or when its commit was made
+	Date string
+)
+
+// unknown is reported for any build information which can't be determined
+const unknown = "unknown"
+
+// Info describes the running build of backplane-tools
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+}
+
+// Get returns the build information of the running backplane-tools binary
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if embedded, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && embedded.Main.Version != "(devel)" {
+			info.Version = embedded.Main.Version
+		}
+		for _, setting := range embedded.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.Date == "":
+				info.Date = setting.Value
+			}
+		}
+	}
+	for _, field := range []*string{&info.Version, &info.Commit, &info.Date} {
+		if *field == "" {
+			*field = unknown
+		}
+	}
+	return info
+}
@@ -0,0 +1,426 @@
+/*
+bundle provides the capability to package tools into a portable archive, so that they can be installed on
+machines without internet access.
+
+A bundle is an uncompressed tar archive. Its first entry is a manifest describing the bundle's contents, followed
+by the directories of each bundled tool, as they would be laid out under the installation directory.
+*/
+package bundle
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/openshift/backplane-tools/pkg/inventory"
+	"github.com/openshift/backplane-tools/pkg/policy"
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+const (
+	// manifestName is the name of the manifest entry within a bundle
+	manifestName = "manifest.json"
+
+	// toolsPrefix is the directory within a bundle containing each tool's files
+	toolsPrefix = "tools"
+
+	// maxScriptSize is the largest file inspected for references to the staging directory when installing a bundle
+	maxScriptSize = 1 << 20
+)
+
+// Manifest describes the contents of a bundle
+type Manifest struct {
+	// OS is the operating system the bundled tools were downloaded for
+	OS string `json:"os"`
+
+	// Arch is the architecture the bundled tools were downloaded for
+	Arch string `json:"arch"`
+
+	// CreatedAt is the time the bundle was created
+	CreatedAt time.Time `json:"createdAt"`
+
+	// StagingDir is the directory the tools were installed into while creating the bundle. Scripts
+	// referring to this directory are rewritten to refer to the installation directory when the bundle is installed
+	StagingDir string `json:"stagingDir"`
+
+	// Links maps the name of each file published into the latest directory to the executable it
+	// refers to, relative to the installation directory
+	Links map[string]string `json:"links"`
+
+	// Artifacts lists every file downloaded while creating the bundle
+	Artifacts []inventory.Artifact `json:"artifacts"`
+}
+
+// Tools returns the sorted names of the tools contained within the bundle
+func (m Manifest) Tools() []string {
+	names := []string{}
+	for _, artifact := range m.Artifacts {
+		if !utils.Contains(names, artifact.Tool) {
+			names = append(names, artifact.Tool)
+		}
+	}
+	for _, target := range m.Links {
+		name := strings.SplitN(target, "/", 2)[0]
+		if !utils.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Create downloads and verifies the provided tools for the given OS and architecture, and writes them
+// to a bundle at the output path
+func Create(toolList []tools.Tool, goos, goarch, output string) error {
+	stagingDir, err := os.MkdirTemp("", "backplane-tools-bundle-")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer func() {
+		removeErr := os.RemoveAll(stagingDir)
+		if removeErr != nil {
+			fmt.Printf("WARNING: failed to remove staging directory '%s': %v\n", stagingDir, removeErr)
+		}
+	}()
+
+	utils.TargetOS = goos
+	utils.TargetArch = goarch
+	base.SetRoot(stagingDir)
+	err = os.MkdirAll(base.LatestDir, os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	bundled := []string{}
+	for _, tool := range toolList {
+		fmt.Println()
+		fmt.Println(utils.Info(fmt.Sprintf("Downloading %s", tool.Name())))
+		err = tool.Install()
+		if err != nil {
+			fmt.Println(utils.Failure(fmt.Sprintf("Encountered error while downloading %s: %v", tool.Name(), err)))
+			fmt.Println(utils.Warning("Skipping..."))
+			continue
+		}
+		fmt.Println(utils.Success(fmt.Sprintf("Successfully downloaded %s", tool.Name())))
+		bundled = append(bundled, tool.Name())
+	}
+	if len(bundled) == 0 {
+		return fmt.Errorf("failed to download any tools")
+	}
+
+	manifest, err := buildManifest(stagingDir, goos, goarch)
+	if err != nil {
+		return err
+	}
+	err = write(output, stagingDir, bundled, manifest)
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Printf("Wrote bundle containing %s to '%s'\n", strings.Join(bundled, ", "), output)
+	return nil
+}
+
+// buildManifest describes the tools installed in the staging directory
+func buildManifest(stagingDir, goos, goarch string) (Manifest, error) {
+	manifest := Manifest{
+		OS:         goos,
+		Arch:       goarch,
+		CreatedAt:  time.Now().UTC(),
+		StagingDir: stagingDir,
+		Links:      map[string]string{},
+	}
+
+	links, err := base.Links()
+	if err != nil {
+		return manifest, err
+	}
+	for linkPath, target := range links {
+		relTarget, err := filepath.Rel(stagingDir, target)
+		if err != nil {
+			return manifest, fmt.Errorf("failed to determine location of '%s' within the staging directory: %w", target, err)
+		}
+		manifest.Links[filepath.Base(linkPath)] = filepath.ToSlash(relTarget)
+	}
+
+	inv, err := inventory.Read(base.InventoryPath)
+	if err != nil {
+		return manifest, err
+	}
+	manifest.Artifacts = inv.Artifacts
+	return manifest, nil
+}
+
+// write creates a bundle at the output path, containing the manifest followed by the directories of the named tools
+func write(output, stagingDir string, toolNames []string, manifest Manifest) (err error) {
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle '%s': %w", output, err)
+	}
+	defer func() {
+		closeErr := file.Close()
+		if closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close bundle '%s': %w", output, closeErr)
+		}
+	}()
+	tw := tar.NewWriter(file)
+	defer func() {
+		closeErr := tw.Close()
+		if closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to finish writing bundle '%s': %w", output, closeErr)
+		}
+	}()
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bundle manifest: %w", err)
+	}
+	err = tw.WriteHeader(&tar.Header{
+		Name:    manifestName,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: manifest.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write bundle manifest: %w", err)
+	}
+	_, err = tw.Write(data)
+	if err != nil {
+		return fmt.Errorf("failed to write bundle manifest: %w", err)
+	}
+
+	for _, name := range toolNames {
+		err = addDir(tw, stagingDir, filepath.Join(stagingDir, name))
+		if err != nil {
+			return fmt.Errorf("failed to add '%s' to bundle: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// addDir writes the contents of the provided directory to the tar archive, naming each entry relative to root
+func addDir(tw *tar.Writer, root, dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		var linkTarget string
+		if info.Mode()&os.ModeSymlink != 0 {
+			linkTarget, err = os.Readlink(path)
+			if err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, linkTarget)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(toolsPrefix, relPath))
+		err = tw.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			closeErr := file.Close()
+			if closeErr != nil {
+				fmt.Fprintf(os.Stderr, "failed to close file '%s': %v\n", path, closeErr)
+			}
+		}()
+		_, err = io.Copy(tw, file)
+		return err
+	})
+}
+
+// Install installs the tools contained in the bundle at the provided path, without accessing the network.
+// If any tool names are provided, only those tools are installed. Nothing is installed if any tool's version isn't
+// approved by the user's organization. Returns the names of the tools installed
+func Install(path string, toolNames []string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle '%s': %w", path, err)
+	}
+	defer func() {
+		closeErr := file.Close()
+		if closeErr != nil {
+			fmt.Fprintf(os.Stderr, "failed to close bundle '%s': %v\n", path, closeErr)
+		}
+	}()
+	tr := tar.NewReader(file)
+
+	manifest, err := readManifest(tr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle '%s': %w", path, err)
+	}
+	if manifest.OS != runtime.GOOS || manifest.Arch != runtime.GOARCH {
+		return nil, fmt.Errorf("bundle '%s' was created for %s/%s, but this system is %s/%s", path, manifest.OS, manifest.Arch, runtime.GOOS, runtime.GOARCH)
+	}
+	if len(toolNames) == 0 {
+		toolNames = manifest.Tools()
+	}
+	for _, name := range toolNames {
+		if !utils.Contains(manifest.Tools(), name) {
+			return nil, fmt.Errorf("bundle '%s' does not contain '%s'", path, name)
+		}
+	}
+
+	policyErrs := []error{}
+	for _, artifact := range manifest.Artifacts {
+		if !utils.Contains(toolNames, artifact.Tool) {
+			continue
+		}
+		_, err = policy.Check(artifact.Tool, artifact.Version)
+		if err != nil {
+			policyErrs = append(policyErrs, err)
+		}
+	}
+	err = errors.Join(policyErrs...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range []string{base.InstallDir, base.LatestDir, base.StateDir} {
+		err = os.MkdirAll(dir, os.FileMode(0o755))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create directory '%s': %w", dir, err)
+		}
+	}
+
+	err = extract(tr, toolNames, manifest.StagingDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract bundle '%s': %w", path, err)
+	}
+
+	// Verify the extracted artifacts are exactly those downloaded when the bundle was created
+	for _, artifact := range manifest.Artifacts {
+		if !utils.Contains(toolNames, artifact.Tool) {
+			continue
+		}
+		artifactPath := filepath.Join(base.InstallDir, artifact.Tool, artifact.Version, artifact.Name)
+		sum, err := utils.Sha256sum(artifactPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify bundled artifact: %w", err)
+		}
+		if sum != artifact.SHA256 {
+			return nil, fmt.Errorf("bundled artifact '%s' does not match the bundle's manifest: %w: expected sha256 '%s', got '%s'", artifactPath, utils.ErrChecksumMismatch, artifact.SHA256, sum)
+		}
+		artifact.InstalledAt = time.Now().UTC()
+		err = inventory.Record(base.InventoryPath, artifact)
+		if err != nil {
+			fmt.Printf("WARNING: failed to record '%s' in inventory: %v\n", artifactPath, err)
+		}
+	}
+
+	for name, target := range manifest.Links {
+		if !utils.Contains(toolNames, strings.SplitN(target, "/", 2)[0]) {
+			continue
+		}
+		err = base.Link(filepath.Join(base.InstallDir, filepath.FromSlash(target)), filepath.Join(base.LatestDir, name))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return toolNames, nil
+}
+
+// readManifest reads the manifest from the first entry in the bundle
+func readManifest(tr *tar.Reader) (Manifest, error) {
+	manifest := Manifest{}
+	header, err := tr.Next()
+	if err != nil {
+		return manifest, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if header.Name != manifestName {
+		return manifest, fmt.Errorf("expected first entry to be '%s', got '%s'", manifestName, header.Name)
+	}
+	data, err := io.ReadAll(tr)
+	if err != nil {
+		return manifest, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return manifest, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// extract writes the files belonging to the named tools into the installation directory. Scripts referring to
+// the staging directory the bundle was created in are rewritten to refer to the installation directory instead
+func extract(tr *tar.Reader, toolNames []string, stagingDir string) error {
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		relPath, found := strings.CutPrefix(header.Name, toolsPrefix+"/")
+		if !found || !utils.Contains(toolNames, strings.SplitN(relPath, "/", 2)[0]) {
+			continue
+		}
+		dest, err := utils.SafeJoin(base.InstallDir, filepath.FromSlash(relPath))
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(dest, os.FileMode(header.Mode).Perm())
+		case tar.TypeSymlink:
+			err = os.Remove(dest)
+			if err == nil || errors.Is(err, os.ErrNotExist) {
+				err = os.Symlink(header.Linkname, dest)
+			}
+		case tar.TypeReg:
+			err = extractFile(tr, header, dest, stagingDir)
+		default:
+			err = fmt.Errorf("unsupported entry type '%c' for '%s'", header.Typeflag, header.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// extractFile writes the current entry in the archive to dest, relocating any references to the staging directory
+// within scripts
+func extractFile(tr *tar.Reader, header *tar.Header, dest, stagingDir string) error {
+	err := os.MkdirAll(filepath.Dir(dest), os.FileMode(0o755))
+	if err != nil {
+		return err
+	}
+	var reader io.Reader = tr
+	if header.Size <= maxScriptSize {
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(string(data), "#!") {
+			data = []byte(strings.ReplaceAll(string(data), stagingDir+string(os.PathSeparator), base.InstallDir+string(os.PathSeparator)))
+		}
+		reader = strings.NewReader(string(data))
+	}
+	return utils.WriteFile(reader, dest, os.FileMode(header.Mode).Perm())
+}
@@ -0,0 +1,206 @@
+/*
+catalog provides the capability to manage tools defined in a signed catalog, published separately from backplane-tools,
+so that new tools can be managed without a new release of backplane-tools
+*/
+package catalog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/signed"
+	"github.com/openshift/backplane-tools/pkg/sources/github"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/openshift/backplane-tools/pkg/verify"
+)
+
+// toolName matches the names catalog tools may be given, so that they're safe to use as directory names
+var toolName = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Catalog lists tools defined outside of backplane-tools
+type Catalog struct {
+	// Tools lists the tools defined by the catalog
+	Tools []Entry `yaml:"tools" json:"tools"`
+}
+
+// Entry declaratively defines a tool installed from GitHub releases, using the same AssetSpec as tools built into
+// backplane-tools
+type Entry struct {
+	// Name is the name the tool is managed as
+	Name string `yaml:"name" json:"name"`
+
+	// Description describes what the tool does
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+
+	// Executable is the name of the tool's executable. Defaults to Name
+	Executable string `yaml:"executable,omitempty" json:"executable,omitempty"`
+
+	// Repository is the 'owner/repo' the tool's releases are published to
+	Repository string `yaml:"repository" json:"repository"`
+
+	// URL is the http(s) URL of the GitHub Enterprise server hosting the repository. Defaults to github.com
+	URL string `yaml:"url,omitempty" json:"url,omitempty"`
+
+	// Include lists terms the tool asset's name must contain
+	Include []string `yaml:"include,omitempty" json:"include,omitempty"`
+
+	// Exclude lists terms the tool asset's name must not contain
+	Exclude []string `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+
+	// Prefer lists terms used to choose between several assets matching the local system
+	Prefer []string `yaml:"prefer,omitempty" json:"prefer,omitempty"`
+
+	// Archive is how the tool asset is packaged: 'tar.gz', 'zip', 'auto', 'optional', or empty for executables
+	Archive base.ArchiveType `yaml:"archive,omitempty" json:"archive,omitempty"`
+
+	// BinaryPath is the location of the executable within the extracted archive
+	BinaryPath string `yaml:"binaryPath,omitempty" json:"binaryPath,omitempty"`
+
+	// Verification describes how the tool asset's integrity is verified
+	Verification Verification `yaml:"verification,omitempty" json:"verification,omitempty"`
+}
+
+// Verification describes the release asset used to verify a tool asset. See base.VerificationSpec
+type Verification struct {
+	Method        base.VerifyMethod `yaml:"method,omitempty" json:"method,omitempty"`
+	Terms         []string          `yaml:"terms,omitempty" json:"terms,omitempty"`
+	Pattern       string            `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	AssetSuffixes []string          `yaml:"assetSuffixes,omitempty" json:"assetSuffixes,omitempty"`
+	MatchSystem   bool              `yaml:"matchSystem,omitempty" json:"matchSystem,omitempty"`
+	KeyURL        string            `yaml:"keyURL,omitempty" json:"keyURL,omitempty"`
+	Fingerprints  []string          `yaml:"fingerprints,omitempty" json:"fingerprints,omitempty"`
+	Format        verify.Format     `yaml:"format,omitempty" json:"format,omitempty"`
+}
+
+// Validate ensures the entry defines a tool which can be installed
+func (e Entry) Validate() error {
+	if !toolName.MatchString(e.Name) {
+		return fmt.Errorf("invalid name '%s': must contain only lowercase letters, digits, '.', '_', and '-'", e.Name)
+	}
+	if e.Executable != "" && (e.Executable == "." || e.Executable == ".." || strings.ContainsAny(e.Executable, `/\`)) {
+		return fmt.Errorf("tool '%s': invalid executable '%s': must be a file name", e.Name, e.Executable)
+	}
+	owner, repo, found := strings.Cut(e.Repository, "/")
+	if !found || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return fmt.Errorf("tool '%s': invalid repository '%s': must be 'owner/repo'", e.Name, e.Repository)
+	}
+	switch e.Archive {
+	case base.ArchiveNone, base.ArchiveTarGz, base.ArchiveZip, base.ArchiveAuto, base.ArchiveOptional:
+	default:
+		return fmt.Errorf("tool '%s': unsupported archive '%s': must be one of '%s', '%s', '%s', '%s', or empty", e.Name, e.Archive, base.ArchiveTarGz, base.ArchiveZip, base.ArchiveAuto, base.ArchiveOptional)
+	}
+	switch e.Verification.Method {
+	case "", base.VerifyChecksum, base.VerifyReleaseNotes:
+	case base.VerifyGPG:
+		if e.Verification.KeyURL == "" || len(e.Verification.Fingerprints) == 0 {
+			return fmt.Errorf("tool '%s': keyURL and fingerprints must be set to verify signatures", e.Name)
+		}
+	default:
+		return fmt.Errorf("tool '%s': unsupported verification method '%s': must be one of '%s', '%s', or '%s'", e.Name, e.Verification.Method, base.VerifyChecksum, base.VerifyGPG, base.VerifyReleaseNotes)
+	}
+	if e.Verification.Pattern != "" {
+		_, err := regexp.Compile(e.Verification.Pattern)
+		if err != nil {
+			return fmt.Errorf("tool '%s': invalid verification pattern: %w", e.Name, err)
+		}
+	}
+	return nil
+}
+
+// NewTool returns the tool defined by the entry
+func (e Entry) NewTool() (*base.Github, error) {
+	owner, repo, _ := strings.Cut(e.Repository, "/")
+	source := github.NewSource(owner, repo)
+	if e.URL != "" {
+		var err error
+		source, err = github.NewEnterpriseSource(owner, repo, e.URL)
+		if err != nil {
+			return nil, fmt.Errorf("tool '%s': %w", e.Name, err)
+		}
+	}
+	executable := e.Executable
+	if executable == "" {
+		executable = e.Name
+	}
+	t := &base.Github{
+		Default: base.NewDefaultWithExecutable(e.Name, executable),
+		Source:  source,
+		Spec: &base.AssetSpec{
+			Include:    e.Include,
+			Exclude:    e.Exclude,
+			Prefer:     e.Prefer,
+			Archive:    e.Archive,
+			BinaryPath: e.BinaryPath,
+			Verification: base.VerificationSpec{
+				Method:        e.Verification.Method,
+				Terms:         e.Verification.Terms,
+				Pattern:       e.Verification.Pattern,
+				AssetSuffixes: e.Verification.AssetSuffixes,
+				MatchSystem:   e.Verification.MatchSystem,
+				KeyURL:        e.Verification.KeyURL,
+				Fingerprints:  e.Verification.Fingerprints,
+				Format:        e.Verification.Format,
+			},
+		},
+	}
+	t.SetDescription(e.Description)
+	return t, nil
+}
+
+// Parse parses and validates the provided catalog. Both YAML and JSON are accepted
+func Parse(data []byte) (Catalog, error) {
+	c := Catalog{}
+	err := yaml.Unmarshal(data, &c)
+	if err != nil {
+		return Catalog{}, fmt.Errorf("failed to parse catalog: %w", err)
+	}
+	names := map[string]bool{}
+	for _, entry := range c.Tools {
+		err = entry.Validate()
+		if err != nil {
+			return Catalog{}, err
+		}
+		if names[entry.Name] {
+			return Catalog{}, fmt.Errorf("tool '%s' is defined more than once", entry.Name)
+		}
+		names[entry.Name] = true
+	}
+	return c, nil
+}
+
+// Load returns the tools defined by the catalog configured by the user, if any. The catalog is retrieved at most once
+// a day, and is only used once its signature has been verified. If the catalog can't be retrieved, the copy
+// previously retrieved is used instead
+func Load(settings config.Catalog) ([]*base.Github, error) {
+	if settings.URL == "" {
+		return []*base.Github{}, nil
+	}
+	data, err := signed.Read(signed.Document{
+		Kind:     "catalog",
+		Settings: settings.SignedDocument,
+		Validate: func(data []byte) error {
+			_, err := Parse(data)
+			return err
+		},
+	})
+	if err != nil {
+		return []*base.Github{}, err
+	}
+	c, err := Parse(data)
+	if err != nil {
+		return []*base.Github{}, fmt.Errorf("invalid catalog '%s': %w", settings.URL, err)
+	}
+	tools := []*base.Github{}
+	for _, entry := range c.Tools {
+		t, err := entry.NewTool()
+		if err != nil {
+			return []*base.Github{}, fmt.Errorf("invalid catalog '%s': %w", settings.URL, err)
+		}
+		tools = append(tools, t)
+	}
+	return tools, nil
+}
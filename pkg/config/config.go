@@ -0,0 +1,777 @@
+/*
+config provides the capability for users to customize backplane-tools' behavior via a configuration file
+*/
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// Path is the location of the configuration file
+var Path = func() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		panic(fmt.Errorf("failed to retrieve user config dir: %w", err))
+	}
+	return filepath.Join(configDir, "backplane-tools", "config.yaml")
+}()
+
+// ProvenancePolicy determines how SLSA provenance attestations are handled when installing a tool
+type ProvenancePolicy string
+
+const (
+	// ProvenanceOff skips provenance verification entirely
+	ProvenanceOff ProvenancePolicy = "off"
+	// ProvenanceWarn verifies provenance when available, but only warns when verification fails
+	ProvenanceWarn ProvenancePolicy = "warn"
+	// ProvenanceEnforce requires provenance to be published and successfully verified, including its signatures
+	ProvenanceEnforce ProvenancePolicy = "enforce"
+)
+
+// CodeSignaturePolicy determines how the macOS code signatures of installed executables are handled
+type CodeSignaturePolicy string
+
+const (
+	// CodeSignatureOff skips code signature verification entirely
+	CodeSignatureOff CodeSignaturePolicy = "off"
+	// CodeSignatureWarn verifies code signatures, but only warns when verification fails
+	CodeSignatureWarn CodeSignaturePolicy = "warn"
+	// CodeSignatureEnforce requires executables to be validly signed and notarized
+	CodeSignatureEnforce CodeSignaturePolicy = "enforce"
+)
+
+// ChecksumPolicy determines how checksum mismatches are handled when installing a tool
+type ChecksumPolicy string
+
+const (
+	// ChecksumStrict aborts installation when an asset's checksum cannot be verified
+	ChecksumStrict ChecksumPolicy = "strict"
+	// ChecksumWarn installs the asset anyway, warning when its checksum cannot be verified
+	ChecksumWarn ChecksumPolicy = "warn"
+	// ChecksumSkip does not verify checksums at all
+	ChecksumSkip ChecksumPolicy = "skip"
+)
+
+// LinkMode determines how each tool's executable is published into the latest directory
+type LinkMode string
+
+const (
+	// LinkModeSymlink publishes executables as symlinks to the latest installed version
+	LinkModeSymlink LinkMode = "symlink"
+	// LinkModeShim publishes executables as small scripts which select the version to execute at invocation time
+	LinkModeShim LinkMode = "shim"
+	// LinkModeHardlink publishes executables as hardlinks to the latest installed version
+	LinkModeHardlink LinkMode = "hardlink"
+	// LinkModeCopy publishes executables as copies of the latest installed version
+	LinkModeCopy LinkMode = "copy"
+)
+
+// Layout determines where on the local filesystem tools and state are stored
+type Layout string
+
+const (
+	// LayoutLegacy stores tools and state in $HOME/.local/bin/backplane
+	LayoutLegacy Layout = "legacy"
+	// LayoutXDG stores tools in $XDG_DATA_HOME/backplane-tools and state in $XDG_STATE_HOME/backplane-tools
+	LayoutXDG Layout = "xdg"
+)
+
+// Config defines the user-provided settings for backplane-tools
+type Config struct {
+	// Provenance is the default provenance policy applied to all tools
+	Provenance ProvenancePolicy `yaml:"provenance,omitempty"`
+
+	// Checksum is the default checksum policy applied to all tools
+	Checksum ChecksumPolicy `yaml:"checksum,omitempty"`
+
+	// CodeSignature is the default policy applied to the macOS code signatures of all tools' executables
+	CodeSignature CodeSignaturePolicy `yaml:"codeSignature,omitempty"`
+
+	// ClearQuarantine determines whether the macOS quarantine attribute is removed from installed
+	// executables. Defaults to true
+	ClearQuarantine *bool `yaml:"clearQuarantine,omitempty"`
+
+	// Permissions determines the permissions given to installed files
+	Permissions Permissions `yaml:"permissions,omitempty"`
+
+	// Layout determines where tools are installed. If unset, the XDG layout is used if tools have already
+	// been installed there, and the legacy layout is used otherwise
+	Layout Layout `yaml:"layout,omitempty"`
+
+	// LinkMode determines how executables are published into the latest directory. Defaults to LinkModeSymlink
+	LinkMode LinkMode `yaml:"linkMode,omitempty"`
+
+	// VerifyBeforeUpgrade determines whether installed executables are checked for modifications before
+	// upgrading. Modified tools are reinstalled, even if they are already up to date
+	VerifyBeforeUpgrade bool `yaml:"verifyBeforeUpgrade,omitempty"`
+
+	// AllowMajorUpgrades determines whether tools are upgraded across major versions, which may introduce breaking
+	// changes. When false, tools are only upgraded to the newest release within their installed major version
+	AllowMajorUpgrades bool `yaml:"allowMajorUpgrades,omitempty"`
+
+	// SmokeTest determines whether each tool's executable is run with a harmless command, such as '--version', once
+	// installed. Installs whose executable fails to run or doesn't report the installed version are rolled back
+	SmokeTest bool `yaml:"smokeTest,omitempty"`
+
+	// Libc overrides the C library tools are installed for on Linux: either 'glibc' or 'musl'. When unset, it's
+	// detected from the local system
+	Libc string `yaml:"libc,omitempty"`
+
+	// Hooks defines commands to run around the installation of every tool
+	Hooks Hooks `yaml:"hooks,omitempty"`
+
+	// Telemetry determines whether anonymized installation metrics are reported
+	Telemetry Telemetry `yaml:"telemetry,omitempty"`
+
+	// GoogleCloudStorage determines how tools are retrieved from Google Cloud Storage buckets
+	GoogleCloudStorage GoogleCloudStorage `yaml:"googleCloudStorage,omitempty"`
+
+	// TLS customizes how TLS connections to the servers tools are downloaded from are established
+	TLS TLS `yaml:"tls,omitempty"`
+
+	// HTTP tunes the connections made to the servers tools are downloaded from
+	HTTP HTTP `yaml:"http,omitempty"`
+
+	// Mirror determines how mirror.openshift.com is accessed
+	Mirror Mirror `yaml:"mirror,omitempty"`
+
+	// OC determines which OpenShift clients are installed
+	OC OC `yaml:"oc,omitempty"`
+
+	// BackplaneCLI seeds backplane-cli's configuration file
+	BackplaneCLI BackplaneCLI `yaml:"backplaneCLI,omitempty"`
+
+	// GitHub determines how GitHub repositories are accessed
+	GitHub GitHub `yaml:"github,omitempty"`
+
+	// Catalog defines where tools which aren't built into backplane-tools are defined
+	Catalog Catalog `yaml:"catalog,omitempty"`
+
+	// Policy defines where the manifest of the tools and versions approved for installation is published
+	Policy Policy `yaml:"policy,omitempty"`
+
+	// Credentials contains the basic auth credentials sent to servers hosting tools, keyed by the server's host
+	// name. Hosts without credentials here use those in the user's netrc file, if any
+	Credentials map[string]Credentials `yaml:"credentials,omitempty"`
+
+	// Tools contains settings specific to individual tools, keyed by the tool's name.
+	// Any value set here takes precedence over the global setting of the same name
+	Tools map[string]Tool `yaml:"tools,omitempty"`
+}
+
+// Tool defines the user-provided settings for an individual tool
+type Tool struct {
+	// Provenance is the provenance policy applied to this tool
+	Provenance ProvenancePolicy `yaml:"provenance,omitempty"`
+
+	// Checksum is the checksum policy applied to this tool
+	Checksum ChecksumPolicy `yaml:"checksum,omitempty"`
+
+	// CodeSignature is the policy applied to the macOS code signatures of this tool's executables
+	CodeSignature CodeSignaturePolicy `yaml:"codeSignature,omitempty"`
+
+	// Hooks defines commands to run around the installation of this tool. These are run after
+	// any globally defined hooks
+	Hooks Hooks `yaml:"hooks,omitempty"`
+
+	// Aliases lists additional names the tool's executable is published under in the latest directory
+	Aliases []string `yaml:"aliases,omitempty"`
+
+	// AllowMajorUpgrades determines whether this tool is upgraded across major versions
+	AllowMajorUpgrades *bool `yaml:"allowMajorUpgrades,omitempty"`
+
+	// SmokeTest determines whether this tool's executable is run with a harmless command once installed
+	SmokeTest *bool `yaml:"smokeTest,omitempty"`
+
+	// PreferAssets lists terms used to choose between several release assets matching the local system. An asset
+	// containing an earlier term is preferred over one which doesn't
+	PreferAssets []string `yaml:"preferAssets,omitempty"`
+
+	// Source replaces the GitHub repository this tool is retrieved from
+	Source *SourceOverride `yaml:"source,omitempty"`
+}
+
+// SourceOverride replaces the GitHub repository a built-in tool is retrieved from, such as with a fork publishing
+// patched builds, or with a mirror hosted on a GitHub Enterprise server
+type SourceOverride struct {
+	// Repository is the 'owner/repo' the tool's releases are retrieved from. Defaults to the tool's usual repository
+	Repository string `yaml:"repository,omitempty"`
+
+	// URL is the http(s) URL of the GitHub Enterprise server hosting the repository. Defaults to github.com
+	URL string `yaml:"url,omitempty"`
+}
+
+// SignedDocument defines where a document published with a detached GPG signature is retrieved from, and the keys
+// trusted to sign it
+type SignedDocument struct {
+	// URL is the http(s) URL the document is published at. Documents are YAML or JSON
+	URL string `yaml:"url,omitempty"`
+
+	// SignatureURL is the http(s) URL of the document's detached GPG signature. Defaults to URL with '.asc' appended
+	SignatureURL string `yaml:"signatureURL,omitempty"`
+
+	// KeyURL is the http(s) URL the keys which sign the document are published at
+	KeyURL string `yaml:"keyURL,omitempty"`
+
+	// Fingerprints pins the keys trusted to sign the document. Documents which aren't signed by one of these keys
+	// are never used
+	Fingerprints []string `yaml:"fingerprints,omitempty"`
+}
+
+// Catalog defines a signed catalog of tools to manage in addition to those built into backplane-tools, so that new
+// tools can be added without a new release of backplane-tools
+type Catalog struct {
+	SignedDocument `yaml:",inline"`
+}
+
+// Policy defines a signed manifest of the tools, and the versions of each, an organization approves. When set,
+// installing any other tool or version is refused
+type Policy struct {
+	SignedDocument `yaml:",inline"`
+}
+
+// Telemetry defines where anonymized installation metrics are reported. Telemetry is disabled by default
+type Telemetry struct {
+	// Enabled opts into reporting metrics
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// Endpoint is the http(s) URL metrics are sent to
+	Endpoint string `yaml:"endpoint,omitempty"`
+}
+
+// GoogleCloudStorage defines how Google Cloud Storage buckets are accessed
+type GoogleCloudStorage struct {
+	// Endpoint is the http(s) URL of the JSON API of a server used in place of storage.googleapis.com,
+	// such as a proxy implementing the same API
+	Endpoint string `yaml:"endpoint,omitempty"`
+
+	// Authenticated sends the application default credentials created by 'gcloud auth application-default login'
+	// with each request. Buckets are accessed anonymously by default
+	Authenticated bool `yaml:"authenticated,omitempty"`
+}
+
+// TLS defines additional trust and credentials used when establishing TLS connections
+type TLS struct {
+	// CABundle is the path to a PEM-encoded file of certificate authorities trusted in addition to the system's,
+	// such as that of a corporate proxy
+	CABundle string `yaml:"caBundle,omitempty"`
+
+	// ClientCertificate is the path to a PEM-encoded certificate presented to servers requiring client authentication
+	ClientCertificate string `yaml:"clientCertificate,omitempty"`
+
+	// ClientKey is the path to the PEM-encoded private key of ClientCertificate
+	ClientKey string `yaml:"clientKey,omitempty"`
+
+	// InsecureSkipVerify disables verification of servers' certificates. This is dangerous: any download may be
+	// tampered with
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify,omitempty"`
+}
+
+// Mirror defines how mirror.openshift.com is accessed
+type Mirror struct {
+	// InsecureHTTP retrieves files from mirror.openshift.com over plain HTTP rather than HTTPS. This is dangerous:
+	// both the downloaded files and the checksums they're verified against may be tampered with in transit
+	InsecureHTTP bool `yaml:"insecureHTTP,omitempty"`
+}
+
+// Permissions defines the permissions given to installed files. Regardless of these settings, executables and
+// directories are given 0755, other files 0644, and the user's umask is applied
+type Permissions struct {
+	// WorldReadable allows installed files to be read by users other than their owner. When false, installed files
+	// are only accessible by their owner. Defaults to true
+	WorldReadable *bool `yaml:"worldReadable,omitempty"`
+}
+
+// OC defines which OpenShift clients are installed in addition to the 'oc' tool
+type OC struct {
+	// Channels lists additional mirror.openshift.com channels to install clients from, such as 'candidate' or
+	// 'stable-4.15'. Each is managed as a separate tool, named 'oc-<channel>' - omitting the 'stable-' prefix
+	// of versioned stable channels - whose executable is linked under the same name
+	Channels []string `yaml:"channels,omitempty"`
+}
+
+// BackplaneCLI defines the settings written to backplane-cli's configuration file when it's installed. Settings left
+// empty here are prompted for, if they're also missing from backplane-cli's configuration file
+type BackplaneCLI struct {
+	// ProxyURL is the http(s) URL of the proxy backplane-cli connects to backplane through
+	ProxyURL string `yaml:"proxyURL,omitempty"`
+
+	// SessionDir is the directory backplane-cli stores sessions in
+	SessionDir string `yaml:"sessionDir,omitempty"`
+}
+
+// HTTP defines the timeouts and connection reuse applied to HTTP requests
+type HTTP struct {
+	// HTTPSettings apply to every server, unless overridden in Hosts
+	HTTPSettings `yaml:",inline"`
+
+	// Hosts contains settings specific to individual servers, keyed by the server's host name. Any value set here
+	// takes precedence over the global setting of the same name
+	Hosts map[string]HTTPSettings `yaml:"hosts,omitempty"`
+
+	// LimitRate caps the combined throughput of all downloads, in bytes per second, optionally suffixed with 'K',
+	// 'M', or 'G'. Downloads are unlimited when unset
+	LimitRate string `yaml:"limitRate,omitempty"`
+
+	// CacheServer is the https URL of a 'backplane-tools serve' instance every request is made through, so that
+	// assets retrieved by other machines are shared rather than downloaded again. Plain http is only accepted for
+	// servers on the loopback interface
+	CacheServer string `yaml:"cacheServer,omitempty"`
+}
+
+// HTTPSettings tunes the connections made to a server. Unset values use backplane-tools' defaults
+type HTTPSettings struct {
+	// ConnectTimeout bounds how long establishing a connection, including the TLS handshake, may take
+	ConnectTimeout time.Duration `yaml:"connectTimeout,omitempty"`
+
+	// ReadTimeout bounds how long the server may go without sending any data before the request is abandoned.
+	// Downloads may take longer than this, as long as data continues to arrive
+	ReadTimeout time.Duration `yaml:"readTimeout,omitempty"`
+
+	// KeepAlive is the interval between keep-alive probes sent on open connections
+	KeepAlive time.Duration `yaml:"keepAlive,omitempty"`
+
+	// MaxIdleConnsPerHost bounds the number of idle connections kept open for reuse
+	MaxIdleConnsPerHost int `yaml:"maxIdleConnsPerHost,omitempty"`
+}
+
+// GitHub defines how GitHub repositories are accessed
+type GitHub struct {
+	// Repositories contains the tokens used to access individual repositories, such as private repositories
+	// tools are installed from. Each is keyed by 'owner/repo', or by 'owner' to apply to every repository of a
+	// user or organization. Repositories without a token here use the token found by 'backplane-tools login github'
+	// or the gh CLI, if any
+	Repositories map[string]GitHubCredentials `yaml:"repositories,omitempty"`
+}
+
+// GitHubCredentials defines the token used to access a GitHub repository
+type GitHubCredentials struct {
+	// Token is a personal access token able to read the repository's contents. For private repositories, classic
+	// tokens require the 'repo' scope, and fine-grained tokens require read access to the repository's contents
+	Token string `yaml:"token,omitempty"`
+
+	// TokenEnv is the name of an environment variable containing the token, so that secrets need not be stored
+	// in the configuration file
+	TokenEnv string `yaml:"tokenEnv,omitempty"`
+}
+
+// Credentials defines the basic auth credentials used to access a server
+type Credentials struct {
+	// Username is the user to authenticate as
+	Username string `yaml:"username"`
+
+	// Password is the user's password or API token. If unset, PasswordEnv is used instead
+	Password string `yaml:"password,omitempty"`
+
+	// PasswordEnv is the name of an environment variable containing the user's password or API token, so that
+	// secrets need not be stored in the configuration file
+	PasswordEnv string `yaml:"passwordEnv,omitempty"`
+}
+
+// Hooks defines shell commands to run before and after a tool is installed or upgraded
+type Hooks struct {
+	// PreInstall commands are run before the tool is installed. If any fail, the tool is not installed
+	PreInstall []string `yaml:"preInstall,omitempty"`
+
+	// PostInstall commands are run after the tool is successfully installed
+	PostInstall []string `yaml:"postInstall,omitempty"`
+}
+
+var cfg *Config
+
+// githubRepository matches the 'owner' or 'owner/repo' keys of GitHub repository settings
+var githubRepository = regexp.MustCompile(`^[A-Za-z0-9-]+(/[A-Za-z0-9._-]+)?$`)
+
+// ocChannel matches the names of the channels OpenShift clients are published to on mirror.openshift.com
+var ocChannel = regexp.MustCompile(`^[a-z]+(-[0-9]+\.[0-9]+)?$`)
+
+// Load reads the configuration file, replacing any configuration previously loaded.
+// A missing configuration file is not considered an error
+func Load() error {
+	loaded, err := Read(Path)
+	if err != nil {
+		return err
+	}
+	cfg = loaded
+	return nil
+}
+
+// Get returns the current configuration, loading it from disk if it has not been loaded yet.
+// If the configuration file cannot be loaded, a warning is printed and the default configuration is used
+func Get() *Config {
+	if cfg == nil {
+		err := Load()
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "WARNING: %v. Using default configuration\n", err)
+			cfg = &Config{}
+		}
+	}
+	return cfg
+}
+
+// Read parses the configuration file at the provided path. If no file exists, the default configuration is returned
+func Read(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return &Config{}, fmt.Errorf("failed to read configuration file '%s': %w", path, err)
+	}
+
+	c, err := Parse(data)
+	if err != nil {
+		return &Config{}, fmt.Errorf("invalid configuration file '%s': %w", path, err)
+	}
+	return c, nil
+}
+
+// Parse parses and validates the provided configuration
+func Parse(data []byte) (*Config, error) {
+	c := &Config{}
+	err := yaml.Unmarshal(data, c)
+	if err != nil {
+		return &Config{}, fmt.Errorf("failed to parse configuration: %w", err)
+	}
+	err = c.Validate()
+	if err != nil {
+		return &Config{}, err
+	}
+	return c, nil
+}
+
+// Validate ensures all settings in the configuration hold supported values
+func (c *Config) Validate() error {
+	err := validateProvenance(c.Provenance)
+	if err != nil {
+		return err
+	}
+	err = validateCodeSignature(c.CodeSignature)
+	if err != nil {
+		return err
+	}
+	err = validateChecksum(c.Checksum)
+	if err != nil {
+		return err
+	}
+	switch c.Layout {
+	case "", LayoutLegacy, LayoutXDG:
+	default:
+		return fmt.Errorf("unsupported layout '%s': must be one of '%s' or '%s'", c.Layout, LayoutLegacy, LayoutXDG)
+	}
+	switch c.Libc {
+	case "", "glibc", "musl":
+	default:
+		return fmt.Errorf("unsupported libc '%s': must be one of 'glibc' or 'musl'", c.Libc)
+	}
+	switch c.LinkMode {
+	case "", LinkModeSymlink, LinkModeShim, LinkModeHardlink, LinkModeCopy:
+	default:
+		return fmt.Errorf("unsupported link mode '%s': must be one of '%s', '%s', '%s', or '%s'", c.LinkMode, LinkModeSymlink, LinkModeShim, LinkModeHardlink, LinkModeCopy)
+	}
+	if c.Telemetry.Enabled && !strings.HasPrefix(c.Telemetry.Endpoint, "https://") && !strings.HasPrefix(c.Telemetry.Endpoint, "http://") {
+		return fmt.Errorf("telemetry is enabled, but endpoint '%s' is not an http(s) URL", c.Telemetry.Endpoint)
+	}
+	if proxyURL := c.BackplaneCLI.ProxyURL; proxyURL != "" && !strings.HasPrefix(proxyURL, "https://") && !strings.HasPrefix(proxyURL, "http://") {
+		return fmt.Errorf("backplaneCLI: proxyURL '%s' is not an http(s) URL", proxyURL)
+	}
+	if endpoint := c.GoogleCloudStorage.Endpoint; endpoint != "" && !strings.HasPrefix(endpoint, "https://") && !strings.HasPrefix(endpoint, "http://") {
+		return fmt.Errorf("google cloud storage endpoint '%s' is not an http(s) URL", endpoint)
+	}
+	if (c.TLS.ClientCertificate == "") != (c.TLS.ClientKey == "") {
+		return fmt.Errorf("tls: clientCertificate and clientKey must be set together")
+	}
+	for _, channel := range c.OC.Channels {
+		if !ocChannel.MatchString(channel) || channel == "stable" {
+			return fmt.Errorf("oc: invalid channel '%s': must be a channel such as 'candidate' or 'stable-4.15', other than 'stable'", channel)
+		}
+	}
+	err = validateHTTPSettings(c.HTTP.HTTPSettings)
+	if err != nil {
+		return fmt.Errorf("http: %w", err)
+	}
+	for host, settings := range c.HTTP.Hosts {
+		err = validateHTTPSettings(settings)
+		if err != nil {
+			return fmt.Errorf("http settings for '%s': %w", host, err)
+		}
+	}
+	_, err = utils.ParseRate(c.HTTP.LimitRate)
+	if err != nil {
+		return fmt.Errorf("http: %w", err)
+	}
+	if server := c.HTTP.CacheServer; server != "" {
+		_, err = utils.ParseCacheServer(server)
+		if err != nil {
+			return fmt.Errorf("http: %w", err)
+		}
+	}
+	err = validateSignedDocument(c.Catalog.SignedDocument)
+	if err != nil {
+		return fmt.Errorf("catalog: %w", err)
+	}
+	err = validateSignedDocument(c.Policy.SignedDocument)
+	if err != nil {
+		return fmt.Errorf("policy: %w", err)
+	}
+	for host, creds := range c.Credentials {
+		if creds.Username == "" {
+			return fmt.Errorf("credentials for '%s': username must be set", host)
+		}
+		if creds.Password != "" && creds.PasswordEnv != "" {
+			return fmt.Errorf("credentials for '%s': only one of password and passwordEnv may be set", host)
+		}
+	}
+	for repository, creds := range c.GitHub.Repositories {
+		if !githubRepository.MatchString(repository) {
+			return fmt.Errorf("github: invalid repository '%s': must be 'owner/repo' or 'owner'", repository)
+		}
+		if (creds.Token == "") == (creds.TokenEnv == "") {
+			return fmt.Errorf("github: repository '%s': exactly one of token and tokenEnv must be set", repository)
+		}
+	}
+	for name, t := range c.Tools {
+		err = validateProvenance(t.Provenance)
+		if err != nil {
+			return fmt.Errorf("tool '%s': %w", name, err)
+		}
+		err = validateCodeSignature(t.CodeSignature)
+		if err != nil {
+			return fmt.Errorf("tool '%s': %w", name, err)
+		}
+		err = validateChecksum(t.Checksum)
+		if err != nil {
+			return fmt.Errorf("tool '%s': %w", name, err)
+		}
+		for _, alias := range t.Aliases {
+			if alias == "" || alias == "." || alias == ".." || strings.ContainsAny(alias, `/\`) {
+				return fmt.Errorf("tool '%s': invalid alias '%s': must be a file name", name, alias)
+			}
+		}
+		if t.Source != nil {
+			err = validateSourceOverride(*t.Source)
+			if err != nil {
+				return fmt.Errorf("tool '%s': %w", name, err)
+			}
+		}
+		for _, term := range t.PreferAssets {
+			if strings.TrimSpace(term) == "" {
+				return fmt.Errorf("tool '%s': preferAssets must not contain empty terms", name)
+			}
+		}
+	}
+	return nil
+}
+
+func validateSourceOverride(o SourceOverride) error {
+	if o.Repository == "" && o.URL == "" {
+		return fmt.Errorf("source: at least one of repository and url must be set")
+	}
+	if o.Repository != "" && (!strings.Contains(o.Repository, "/") || !githubRepository.MatchString(o.Repository)) {
+		return fmt.Errorf("source: invalid repository '%s': must be of the form 'owner/repo'", o.Repository)
+	}
+	if o.URL != "" && !strings.HasPrefix(o.URL, "https://") && !strings.HasPrefix(o.URL, "http://") {
+		return fmt.Errorf("source: url '%s' is not an http(s) URL", o.URL)
+	}
+	return nil
+}
+
+func validateSignedDocument(c SignedDocument) error {
+	if c.URL == "" {
+		if c.SignatureURL != "" || c.KeyURL != "" || len(c.Fingerprints) > 0 {
+			return fmt.Errorf("url must be set")
+		}
+		return nil
+	}
+	for name, value := range map[string]string{"url": c.URL, "signatureURL": c.SignatureURL, "keyURL": c.KeyURL} {
+		if value != "" && !strings.HasPrefix(value, "https://") && !strings.HasPrefix(value, "http://") {
+			return fmt.Errorf("%s '%s' is not an http(s) URL", name, value)
+		}
+	}
+	if c.KeyURL == "" || len(c.Fingerprints) == 0 {
+		return fmt.Errorf("keyURL and fingerprints must be set, so that the signature can be verified")
+	}
+	for _, fingerprint := range c.Fingerprints {
+		if !utils.ValidFingerprint(utils.NormalizeFingerprint(fingerprint)) {
+			return fmt.Errorf("invalid fingerprint '%s'", fingerprint)
+		}
+	}
+	return nil
+}
+
+func validateCodeSignature(p CodeSignaturePolicy) error {
+	switch p {
+	case "", CodeSignatureOff, CodeSignatureWarn, CodeSignatureEnforce:
+		return nil
+	default:
+		return fmt.Errorf("unsupported code signature policy '%s': must be one of '%s', '%s', or '%s'", p, CodeSignatureOff, CodeSignatureWarn, CodeSignatureEnforce)
+	}
+}
+
+func validateProvenance(p ProvenancePolicy) error {
+	switch p {
+	case "", ProvenanceOff, ProvenanceWarn, ProvenanceEnforce:
+		return nil
+	default:
+		return fmt.Errorf("unsupported provenance policy '%s': must be one of '%s', '%s', or '%s'", p, ProvenanceOff, ProvenanceWarn, ProvenanceEnforce)
+	}
+}
+
+func validateHTTPSettings(s HTTPSettings) error {
+	if s.ConnectTimeout < 0 || s.ReadTimeout < 0 || s.KeepAlive < 0 {
+		return fmt.Errorf("timeouts and keep-alive intervals must not be negative")
+	}
+	if s.MaxIdleConnsPerHost < 0 {
+		return fmt.Errorf("maxIdleConnsPerHost must not be negative")
+	}
+	return nil
+}
+
+func validateChecksum(p ChecksumPolicy) error {
+	switch p {
+	case "", ChecksumStrict, ChecksumWarn, ChecksumSkip:
+		return nil
+	default:
+		return fmt.Errorf("unsupported checksum policy '%s': must be one of '%s', '%s', or '%s'", p, ChecksumStrict, ChecksumWarn, ChecksumSkip)
+	}
+}
+
+// GetLinkMode returns the method used to publish executables into the latest directory
+func (c *Config) GetLinkMode() LinkMode {
+	if c.LinkMode == "" {
+		return LinkModeSymlink
+	}
+	return c.LinkMode
+}
+
+// ShouldClearQuarantine returns true if the macOS quarantine attribute should be removed from installed executables
+func (c *Config) ShouldClearQuarantine() bool {
+	return c.ClearQuarantine == nil || *c.ClearQuarantine
+}
+
+// FilesWorldReadable returns true if installed files may be read by users other than their owner
+func (c *Config) FilesWorldReadable() bool {
+	return c.Permissions.WorldReadable == nil || *c.Permissions.WorldReadable
+}
+
+// DownloadRateLimit returns the combined throughput downloads are capped to, in bytes per second, or 0 if downloads are
+// unlimited
+func (c *Config) DownloadRateLimit() int64 {
+	rate, _ := utils.ParseRate(c.HTTP.LimitRate)
+	return rate
+}
+
+// MajorUpgradesAllowed returns true if the named tool may be upgraded across major versions
+func (c *Config) MajorUpgradesAllowed(tool string) bool {
+	if allowed := c.Tools[tool].AllowMajorUpgrades; allowed != nil {
+		return *allowed
+	}
+	return c.AllowMajorUpgrades
+}
+
+// AssetPreferences returns the terms used to choose between several release assets of the named tool
+func (c *Config) AssetPreferences(tool string) []string {
+	return c.Tools[tool].PreferAssets
+}
+
+// SmokeTestEnabled returns true if the named tool's executable should be smoke tested once installed
+func (c *Config) SmokeTestEnabled(tool string) bool {
+	if enabled := c.Tools[tool].SmokeTest; enabled != nil {
+		return *enabled
+	}
+	return c.SmokeTest
+}
+
+// ProvenancePolicy returns the provenance policy applied to the named tool
+func (c *Config) ProvenancePolicy(tool string) ProvenancePolicy {
+	if p := c.Tools[tool].Provenance; p != "" {
+		return p
+	}
+	if c.Provenance != "" {
+		return c.Provenance
+	}
+	return ProvenanceOff
+}
+
+// CodeSignaturePolicy returns the code signature policy applied to the named tool
+func (c *Config) CodeSignaturePolicy(tool string) CodeSignaturePolicy {
+	if p := c.Tools[tool].CodeSignature; p != "" {
+		return p
+	}
+	if c.CodeSignature != "" {
+		return c.CodeSignature
+	}
+	return CodeSignatureOff
+}
+
+// ChecksumPolicy returns the checksum policy applied to the named tool
+func (c *Config) ChecksumPolicy(tool string) ChecksumPolicy {
+	if p := c.Tools[tool].Checksum; p != "" {
+		return p
+	}
+	if c.Checksum != "" {
+		return c.Checksum
+	}
+	return ChecksumStrict
+}
+
+// ToolHooks returns the hooks applied to the named tool: global hooks first, followed by the tool's own
+func (c *Config) ToolHooks(tool string) Hooks {
+	toolHooks := c.Tools[tool].Hooks
+	return Hooks{
+		PreInstall:  append(append([]string{}, c.Hooks.PreInstall...), toolHooks.PreInstall...),
+		PostInstall: append(append([]string{}, c.Hooks.PostInstall...), toolHooks.PostInstall...),
+	}
+}
+
+// ToolAliases returns the additional names the named tool's executable is published under
+func (c *Config) ToolAliases(tool string) []string {
+	return append([]string{}, c.Tools[tool].Aliases...)
+}
+
+// HostCredentials returns the username and password configured for the named host. found is false if none are configured
+func (c *Config) HostCredentials(host string) (username, password string, found bool) {
+	creds, found := c.Credentials[host]
+	if !found {
+		return "", "", false
+	}
+	if creds.PasswordEnv != "" {
+		return creds.Username, os.Getenv(creds.PasswordEnv), true
+	}
+	return creds.Username, creds.Password, true
+}
+
+// SourceOverride returns the source configured to replace the named tool's usual GitHub repository. found is false if
+// none is configured
+func (c *Config) SourceOverride(tool string) (override SourceOverride, found bool) {
+	if o := c.Tools[tool].Source; o != nil {
+		return *o, true
+	}
+	return SourceOverride{}, false
+}
+
+// GitHubToken returns the token configured for the provided GitHub repository, or for its owner. found is false if
+// neither has a token configured
+func (c *Config) GitHubToken(owner, repo string) (token string, found bool) {
+	creds, found := c.GitHub.Repositories[owner+"/"+repo]
+	if !found {
+		creds, found = c.GitHub.Repositories[owner]
+	}
+	if !found {
+		return "", false
+	}
+	if creds.TokenEnv != "" {
+		return os.Getenv(creds.TokenEnv), true
+	}
+	return creds.Token, true
+}
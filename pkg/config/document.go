@@ -0,0 +1,356 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Setting is a single value set in the configuration file
+type Setting struct {
+	// Key identifies the setting, such as 'http.limitRate' or 'tools.oc.checksum'
+	Key string `json:"key"`
+
+	// Value is the setting's value, as written in the configuration file. Lists are written in YAML's flow style
+	Value string `json:"value"`
+}
+
+// Document is the configuration file, parsed with its comments and layout preserved, so that individual settings can
+// be modified without rewriting the rest of the file
+type Document struct {
+	root yaml.Node
+}
+
+// ReadDocument parses the configuration file at the provided path. If no file exists, an empty Document is returned
+func ReadDocument(path string) (*Document, error) {
+	d := &Document{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return d, nil
+	}
+	if err != nil {
+		return d, fmt.Errorf("failed to read configuration file '%s': %w", path, err)
+	}
+	err = yaml.Unmarshal(data, &d.root)
+	if err != nil {
+		return d, fmt.Errorf("failed to parse configuration file '%s': %w", path, err)
+	}
+	return d, nil
+}
+
+// Write validates the Document, then writes it to the provided path. The file is only accessible by its owner, as it
+// may contain credentials
+func (d *Document) Write(path string) error {
+	data, err := d.Bytes()
+	if err != nil {
+		return err
+	}
+	_, err = Parse(data)
+	if err != nil {
+		return fmt.Errorf("refusing to write invalid configuration: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(path), os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create configuration directory '%s': %w", filepath.Dir(path), err)
+	}
+	err = os.WriteFile(path, data, os.FileMode(0o600))
+	if err != nil {
+		return fmt.Errorf("failed to write configuration file '%s': %w", path, err)
+	}
+	return nil
+}
+
+// Bytes encodes the Document as YAML
+func (d *Document) Bytes() ([]byte, error) {
+	if d.root.Kind == 0 {
+		return []byte{}, nil
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	err := encoder.Encode(&d.root)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	err = encoder.Close()
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Get returns the value of the setting with the provided key, encoded as YAML. found is false if the setting isn't
+// set in the Document
+func (d *Document) Get(key string) (value string, found bool, err error) {
+	path, err := ResolveKey(key)
+	if err != nil {
+		return "", false, err
+	}
+	node := d.mapping()
+	for _, segment := range path {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return "", false, nil
+		}
+		node = lookup(node, segment)
+	}
+	if node == nil {
+		return "", false, nil
+	}
+	value, err = encodeValue(node)
+	return value, true, err
+}
+
+// Set replaces the value of the setting with the provided key, adding it if it isn't already set. The value is parsed
+// as YAML, so that lists - such as '[musl, amd64]' - can be provided as well as scalars
+func (d *Document) Set(key, value string) error {
+	path, err := ResolveKey(key)
+	if err != nil {
+		return err
+	}
+	valueNode, err := decodeValue(value)
+	if err != nil {
+		return fmt.Errorf("failed to parse value for '%s': %w", key, err)
+	}
+
+	if d.root.Kind == 0 {
+		d.root = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	node := d.mapping()
+	if node == nil || node.Kind != yaml.MappingNode {
+		return fmt.Errorf("the configuration file does not contain a mapping of settings")
+	}
+	for i, segment := range path {
+		child := lookup(node, segment)
+		if i == len(path)-1 {
+			if child != nil {
+				valueNode.LineComment = child.LineComment
+				*child = *valueNode
+				return nil
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: segment}, valueNode)
+			return nil
+		}
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: segment}, child)
+		}
+		if child.Kind != yaml.MappingNode {
+			return fmt.Errorf("'%s' is not a group of settings", strings.Join(path[:i+1], "."))
+		}
+		node = child
+	}
+	return nil
+}
+
+// Settings returns every setting in the Document, sorted by key
+func (d *Document) Settings() ([]Setting, error) {
+	settings := []Setting{}
+	node := d.mapping()
+	if node == nil {
+		return settings, nil
+	}
+	err := flatten(node, "", &settings)
+	if err != nil {
+		return []Setting{}, err
+	}
+	sort.Slice(settings, func(i, j int) bool {
+		return settings[i].Key < settings[j].Key
+	})
+	return settings, nil
+}
+
+// mapping returns the top-level mapping of the Document, or nil if the Document is empty
+func (d *Document) mapping() *yaml.Node {
+	if d.root.Kind != yaml.DocumentNode || len(d.root.Content) == 0 {
+		return nil
+	}
+	return d.root.Content[0]
+}
+
+// lookup returns the value of the provided key within a mapping node, or nil if it isn't set
+func lookup(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// flatten appends the leaves of the provided node to settings, keyed by their dotted path beneath prefix
+func flatten(node *yaml.Node, prefix string, settings *[]Setting) error {
+	if node.Kind == yaml.MappingNode && len(node.Content) > 0 {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			err := flatten(node.Content[i+1], key, settings)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	value, err := encodeValue(node)
+	if err != nil {
+		return err
+	}
+	*settings = append(*settings, Setting{Key: prefix, Value: value})
+	return nil
+}
+
+// encodeValue returns the provided node as it would be written in the configuration file. Scalars are returned as
+// is, and collections are written in YAML's flow style, so that they fit on a single line
+func encodeValue(node *yaml.Node) (string, error) {
+	if node.Kind == yaml.ScalarNode {
+		return node.Value, nil
+	}
+	flow := *node
+	flow.Style = yaml.FlowStyle
+	flow.HeadComment, flow.LineComment, flow.FootComment = "", "", ""
+	data, err := yaml.Marshal(&flow)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode value: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// decodeValue parses the provided value as YAML. Empty values are treated as empty strings
+func decodeValue(value string) (*yaml.Node, error) {
+	var doc yaml.Node
+	err := yaml.Unmarshal([]byte(value), &doc)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	}
+	return doc.Content[0], nil
+}
+
+// ResolveKey splits a dotted key, such as 'http.hosts.mirror.openshift.com.readTimeout', into the path of keys
+// leading to the setting within the configuration file. Keys are validated against the settings backplane-tools
+// supports. Names chosen by the user - such as host names, which may themselves contain dots - are matched so that
+// the rest of the key names a setting beneath them
+func ResolveKey(key string) ([]string, error) {
+	if strings.TrimSpace(key) == "" {
+		return []string{}, fmt.Errorf("no setting provided")
+	}
+	return resolvePath(reflect.TypeOf(Config{}), strings.Split(key, "."), "")
+}
+
+// resolvePath resolves the provided segments of a key against the given type, where prefix is the portion of the key
+// already resolved
+func resolvePath(t reflect.Type, segments []string, prefix string) ([]string, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if len(segments) == 0 {
+		return []string{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		name, fieldType, found := findSetting(t, segments[0])
+		if !found {
+			return []string{}, fmt.Errorf("unknown setting '%s'. Supported settings%s are: %s", joinKey(prefix, segments[0]), describePrefix(prefix), strings.Join(settingNames(t), ", "))
+		}
+		rest, err := resolvePath(fieldType, segments[1:], joinKey(prefix, name))
+		if err != nil {
+			return []string{}, err
+		}
+		return append([]string{name}, rest...), nil
+	case reflect.Map:
+		elem := t.Elem()
+		for elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct || len(segments) == 1 {
+			return []string{strings.Join(segments, ".")}, nil
+		}
+		var err error
+		for i := 1; i < len(segments); i++ {
+			name := strings.Join(segments[:i], ".")
+			var rest []string
+			rest, err = resolvePath(elem, segments[i:], joinKey(prefix, name))
+			if err == nil {
+				return append([]string{name}, rest...), nil
+			}
+		}
+		// Report the error against the longest name, as the final segment is the one most likely to be mistyped
+		return []string{}, err
+	}
+	return []string{}, fmt.Errorf("'%s' is a single setting, and has no settings beneath it", prefix)
+}
+
+// findSetting returns the name and type of the field of the provided struct type which stores the named setting.
+// Names are matched regardless of case, and fields inlined into the struct are searched as well
+func findSetting(t reflect.Type, name string) (string, reflect.Type, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tagName, inline := yamlName(field)
+		if inline {
+			found, fieldType, ok := findSetting(field.Type, name)
+			if ok {
+				return found, fieldType, true
+			}
+			continue
+		}
+		if tagName != "" && strings.EqualFold(tagName, name) {
+			return tagName, field.Type, true
+		}
+	}
+	return "", nil, false
+}
+
+// settingNames returns the names of the settings stored by the provided struct type
+func settingNames(t reflect.Type) []string {
+	names := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, inline := yamlName(field)
+		if inline {
+			names = append(names, settingNames(field.Type)...)
+			continue
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// yamlName returns the name the provided field is stored under in the configuration file, and whether its fields
+// are inlined into those of the enclosing struct
+func yamlName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("yaml")
+	if tag == "-" || !field.IsExported() {
+		return "", false
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	return name, strings.Contains(options, "inline")
+}
+
+// joinKey appends name to the dotted key prefix
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// describePrefix describes where the settings listed in an error are found
+func describePrefix(prefix string) string {
+	if prefix == "" {
+		return ""
+	}
+	return fmt.Sprintf(" under '%s'", prefix)
+}
@@ -0,0 +1,60 @@
+/*
+hooks provides the capability to run user-defined commands around the installation of a tool
+*/
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// Phase identifies when a hook is run
+type Phase string
+
+const (
+	// PreInstall hooks run before a tool is installed
+	PreInstall Phase = "pre-install"
+	// PostInstall hooks run after a tool is successfully installed
+	PostInstall Phase = "post-install"
+)
+
+const (
+	// ToolEnvVar holds the name of the tool being installed
+	ToolEnvVar = "BACKPLANE_TOOLS_HOOK_TOOL"
+	// PhaseEnvVar holds the phase the hook is being run in
+	PhaseEnvVar = "BACKPLANE_TOOLS_HOOK_PHASE"
+	// OldVersionEnvVar holds the version of the tool installed prior to this run. Empty if the tool was not installed
+	OldVersionEnvVar = "BACKPLANE_TOOLS_HOOK_OLD_VERSION"
+	// NewVersionEnvVar holds the version of the tool being installed
+	NewVersionEnvVar = "BACKPLANE_TOOLS_HOOK_NEW_VERSION"
+)
+
+// Context describes the installation a hook is being run for
+type Context struct {
+	Tool       string
+	OldVersion string
+	NewVersion string
+}
+
+// Run executes each command in order using the system shell, stopping at the first failure. The hook's context is
+// passed to each command via environment variables
+func Run(phase Phase, commands []string, ctx Context) error {
+	for _, command := range commands {
+		fmt.Printf("Running %s hook: %s\n", phase, command)
+		cmd := exec.Command("/bin/sh", "-c", command)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		cmd.Env = append(os.Environ(),
+			fmt.Sprintf("%s=%s", ToolEnvVar, ctx.Tool),
+			fmt.Sprintf("%s=%s", PhaseEnvVar, phase),
+			fmt.Sprintf("%s=%s", OldVersionEnvVar, ctx.OldVersion),
+			fmt.Sprintf("%s=%s", NewVersionEnvVar, ctx.NewVersion),
+		)
+		err := cmd.Run()
+		if err != nil {
+			return fmt.Errorf("%s hook '%s' failed: %w", phase, command, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+/*
+httpfixture provides the capability to record HTTP traffic to a file, and to replay it later without network access.
+Replacing utils.HTTPTransport with a recording Transport captures real responses from GitHub, mirror.openshift.com,
+and Google Cloud Storage; replaying them allows sources and tools to be exercised deterministically, and catches
+changes to the shape of upstream responses when fixtures are re-recorded
+*/
+package httpfixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Mode determines whether a Transport records or replays traffic
+type Mode string
+
+const (
+	// ModeRecord performs each request against the network, recording the response
+	ModeRecord Mode = "record"
+	// ModeReplay answers each request with a previously recorded response, without network access
+	ModeReplay Mode = "replay"
+)
+
+// RecordEnvVar is the environment variable which, when set to "true", causes Open to record fixtures against the
+// network instead of replaying them
+const RecordEnvVar = "BACKPLANE_TOOLS_RECORD_FIXTURES"
+
+// omittedHeaders are response headers which are never recorded
+var omittedHeaders = []string{"Set-Cookie"}
+
+// Cassette holds the interactions recorded by a Transport
+type Cassette struct {
+	// Interactions are the recorded requests and their responses, in the order they were made
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single request and the response it received
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request identifies a recorded request. Request headers and bodies are not recorded, so that credentials are
+// never written to fixtures
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// Response is a recorded response
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       []byte      `json:"body,omitempty"`
+}
+
+// Transport is an http.RoundTripper which records or replays interactions
+type Transport struct {
+	mode Mode
+	path string
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	replayed []bool
+}
+
+// NewRecorder returns a Transport which performs requests using next, and records them to be saved at path
+func NewRecorder(path string, next http.RoundTripper) *Transport {
+	return &Transport{
+		mode: ModeRecord,
+		path: path,
+		next: next,
+	}
+}
+
+// NewReplayer returns a Transport which answers requests using the interactions saved at path
+func NewReplayer(path string) (*Transport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture '%s': %w", path, err)
+	}
+	t := &Transport{
+		mode: ModeReplay,
+		path: path,
+	}
+	err = json.Unmarshal(data, &t.cassette)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse fixture '%s': %w", path, err)
+	}
+	t.replayed = make([]bool, len(t.cassette.Interactions))
+	return t, nil
+}
+
+// Open returns a Transport recording to path if RecordEnvVar is set to "true", or replaying the interactions saved at
+// path otherwise. Recording Transports perform requests over the network using http.DefaultTransport
+func Open(path string) (*Transport, error) {
+	if os.Getenv(RecordEnvVar) == "true" {
+		return NewRecorder(path, http.DefaultTransport), nil
+	}
+	return NewReplayer(path)
+}
+
+// RoundTrip records or replays the provided request, according to the Transport's mode
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.mode == ModeReplay {
+		return t.replay(req)
+	}
+	return t.record(req)
+}
+
+// replay answers the request with the first recorded response to an identical request which hasn't yet been replayed.
+// Identical requests are therefore answered in the order they were recorded
+func (t *Transport) replay(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, interaction := range t.cassette.Interactions {
+		if t.replayed[i] || interaction.Request.Method != req.Method || interaction.Request.URL != req.URL.String() {
+			continue
+		}
+		t.replayed[i] = true
+		return interaction.Response.toHTTP(req), nil
+	}
+	return nil, fmt.Errorf("no recorded response for %s '%s' in fixture '%s'", req.Method, req.URL, t.path)
+}
+
+// record performs the request and records its response
+func (t *Transport) record(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	closeErr := resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if closeErr != nil {
+		return nil, fmt.Errorf("failed to close response body: %w", closeErr)
+	}
+
+	header := resp.Header.Clone()
+	for _, name := range omittedHeaders {
+		header.Del(name)
+	}
+	recorded := Response{
+		StatusCode: resp.StatusCode,
+		Header:     header,
+		Body:       body,
+	}
+
+	t.mu.Lock()
+	t.cassette.Interactions = append(t.cassette.Interactions, Interaction{
+		Request:  Request{Method: req.Method, URL: req.URL.String()},
+		Response: recorded,
+	})
+	t.mu.Unlock()
+	return recorded.toHTTP(req), nil
+}
+
+// Unreplayed returns the recorded requests which have not been replayed. This allows callers to detect when
+// fewer requests were made than expected. Recording Transports have replayed nothing, and return no requests
+func (t *Transport) Unreplayed() []Request {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	requests := []Request{}
+	if t.mode != ModeReplay {
+		return requests
+	}
+	for i, interaction := range t.cassette.Interactions {
+		if !t.replayed[i] {
+			requests = append(requests, interaction.Request)
+		}
+	}
+	return requests
+}
+
+// Save writes the recorded interactions to the Transport's path. Replaying Transports have nothing to save
+func (t *Transport) Save() error {
+	if t.mode != ModeRecord {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	data, err := json.MarshalIndent(t.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+	err = os.WriteFile(t.path, data, os.FileMode(0o644))
+	if err != nil {
+		return fmt.Errorf("failed to write fixture '%s': %w", t.path, err)
+	}
+	return nil
+}
+
+// toHTTP converts the recorded response into a response to the provided request
+func (r Response) toHTTP(req *http.Request) *http.Response {
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,115 @@
+/*
+inventory provides the capability to record which artifacts backplane-tools has downloaded and installed
+*/
+package inventory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// Artifact describes a single file downloaded while installing a tool
+type Artifact struct {
+	// Tool is the name of the tool the artifact was installed for
+	Tool string `json:"tool"`
+
+	// Version is the version of the tool the artifact belongs to
+	Version string `json:"version"`
+
+	// Name is the artifact's file name
+	Name string `json:"name"`
+
+	// SourceURL is the location the artifact was downloaded from
+	SourceURL string `json:"sourceURL"`
+
+	// SHA256 is the sha256sum of the artifact, as downloaded
+	SHA256 string `json:"sha256"`
+
+	// Verification describes how the artifact's integrity was verified before it was installed
+	Verification string `json:"verification"`
+
+	// InstalledAt is the time the artifact was installed
+	InstalledAt time.Time `json:"installedAt"`
+}
+
+// Inventory contains all artifacts currently installed on the local machine
+type Inventory struct {
+	Artifacts []Artifact `json:"artifacts"`
+}
+
+// Read parses the inventory file at the provided path. If no file exists, an empty inventory is returned
+func Read(path string) (Inventory, error) {
+	inv := Inventory{Artifacts: []Artifact{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return inv, nil
+	}
+	if err != nil {
+		return inv, fmt.Errorf("failed to read inventory file '%s': %w", path, err)
+	}
+	err = json.Unmarshal(data, &inv)
+	if err != nil {
+		return inv, fmt.Errorf("failed to parse inventory file '%s': %w", path, err)
+	}
+	return inv, nil
+}
+
+// Write stores the inventory at the provided path
+func Write(path string, inv Inventory) error {
+	sort.SliceStable(inv.Artifacts, func(i, j int) bool {
+		if inv.Artifacts[i].Tool != inv.Artifacts[j].Tool {
+			return inv.Artifacts[i].Tool < inv.Artifacts[j].Tool
+		}
+		return inv.Artifacts[i].Version < inv.Artifacts[j].Version
+	})
+	data, err := json.MarshalIndent(inv, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode inventory: %w", err)
+	}
+	err = os.WriteFile(path, data, os.FileMode(0o644))
+	if err != nil {
+		return fmt.Errorf("failed to write inventory file '%s': %w", path, err)
+	}
+	return nil
+}
+
+// Record adds the provided artifact to the inventory file at the given path. Any artifact previously
+// recorded with the same tool, version, and name is replaced
+func Record(path string, artifact Artifact) error {
+	inv, err := Read(path)
+	if err != nil {
+		return err
+	}
+	artifacts := []Artifact{artifact}
+	for _, existing := range inv.Artifacts {
+		if existing.Tool == artifact.Tool && existing.Version == artifact.Version && existing.Name == artifact.Name {
+			continue
+		}
+		artifacts = append(artifacts, existing)
+	}
+	inv.Artifacts = artifacts
+	return Write(path, inv)
+}
+
+// Forget removes all artifacts recorded for the provided tool from the inventory file at the given path
+func Forget(path, tool string) error {
+	inv, err := Read(path)
+	if err != nil {
+		return err
+	}
+	artifacts := []Artifact{}
+	for _, existing := range inv.Artifacts {
+		if existing.Tool != tool {
+			artifacts = append(artifacts, existing)
+		}
+	}
+	if len(artifacts) == len(inv.Artifacts) {
+		return nil
+	}
+	inv.Artifacts = artifacts
+	return Write(path, inv)
+}
@@ -0,0 +1,151 @@
+/*
+journal provides the capability to record changes to installed tools as transactions, so that changes interrupted
+by a crash or power loss can be detected and repaired
+*/
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Operation describes the change a transaction makes to a tool
+type Operation string
+
+const (
+	// OperationInstall installs or upgrades a tool
+	OperationInstall Operation = "install"
+	// OperationRemove removes a tool
+	OperationRemove Operation = "remove"
+)
+
+// Transaction records a change to a tool which has begun, but not yet been committed
+type Transaction struct {
+	// ID uniquely identifies the transaction
+	ID string `json:"id"`
+
+	// Tool is the name of the tool being changed
+	Tool string `json:"tool"`
+
+	// Operation is the change being made to the tool
+	Operation Operation `json:"operation"`
+
+	// StartedAt is the time the transaction began
+	StartedAt time.Time `json:"startedAt"`
+
+	// Versions lists the versions of the tool which were installed when the transaction began
+	Versions []string `json:"versions"`
+
+	// Links maps each file in the latest directory referring to the tool when the transaction began to its target
+	Links map[string]string `json:"links"`
+
+	// Steps lists the progress made by the transaction, in the order it was made
+	Steps []Step `json:"steps"`
+}
+
+// Step records a single change made during a transaction
+type Step struct {
+	// Description describes the change
+	Description string `json:"description"`
+
+	// At is the time the change was made
+	At time.Time `json:"at"`
+}
+
+// Journal contains every transaction which has not been committed
+type Journal struct {
+	Transactions []Transaction `json:"transactions"`
+}
+
+// Read parses the journal file at the provided path. If no file exists, an empty journal is returned
+func Read(path string) (Journal, error) {
+	j := Journal{Transactions: []Transaction{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return j, nil
+	}
+	if err != nil {
+		return j, fmt.Errorf("failed to read journal file '%s': %w", path, err)
+	}
+	err = json.Unmarshal(data, &j)
+	if err != nil {
+		return j, fmt.Errorf("failed to parse journal file '%s': %w", path, err)
+	}
+	return j, nil
+}
+
+// Write stores the journal at the provided path. The file is replaced atomically and flushed to disk, so that the
+// journal remains readable if the machine crashes while it's being written
+func Write(path string, j Journal) error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode journal: %w", err)
+	}
+	tmpPath := filepath.Join(filepath.Dir(path), fmt.Sprintf(".%s.%d.tmp", filepath.Base(path), os.Getpid()))
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(0o644))
+	if err != nil {
+		return fmt.Errorf("failed to write journal file '%s': %w", path, err)
+	}
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write journal file '%s': %w", path, err)
+	}
+	return nil
+}
+
+// Begin adds the provided transaction to the journal file at the given path
+func Begin(path string, tx Transaction) error {
+	j, err := Read(path)
+	if err != nil {
+		return err
+	}
+	j.Transactions = append(j.Transactions, tx)
+	return Write(path, j)
+}
+
+// AddStep records progress made by the identified transaction in the journal file at the given path
+func AddStep(path, id, description string) error {
+	j, err := Read(path)
+	if err != nil {
+		return err
+	}
+	for i := range j.Transactions {
+		if j.Transactions[i].ID == id {
+			j.Transactions[i].Steps = append(j.Transactions[i].Steps, Step{Description: description, At: time.Now().UTC()})
+			return Write(path, j)
+		}
+	}
+	return fmt.Errorf("transaction '%s' not found in journal", id)
+}
+
+// Commit removes the identified transaction from the journal file at the given path, marking it as complete.
+// Committing a transaction which is not in the journal is not an error
+func Commit(path, id string) error {
+	j, err := Read(path)
+	if err != nil {
+		return err
+	}
+	transactions := []Transaction{}
+	for _, tx := range j.Transactions {
+		if tx.ID != id {
+			transactions = append(transactions, tx)
+		}
+	}
+	j.Transactions = transactions
+	return Write(path, j)
+}
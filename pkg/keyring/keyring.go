@@ -0,0 +1,99 @@
+/*
+keyring provides the capability to store secrets, such as API tokens, in the operating system's keyring: the login
+keychain on macOS, and the Secret Service (ie - GNOME Keyring or KWallet) on Linux.
+
+The keyring is accessed using the platform's command line client - 'security' on macOS, and 'secret-tool' on Linux -
+so that no cgo or D-Bus bindings are required.
+*/
+package keyring
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Service identifies the secrets stored by backplane-tools within the keyring
+const Service = "backplane-tools"
+
+// ErrNotFound indicates no secret is stored for the requested account
+var ErrNotFound = errors.New("secret not found in keyring")
+
+// notFoundExitCodeDarwin is returned by 'security' when the requested item does not exist
+const notFoundExitCodeDarwin = 44
+
+// Get returns the secret stored for the provided account. ErrNotFound is returned if none is stored, or if the
+// system has no keyring available
+func Get(account string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", Service, "-a", account, "-w")
+	case "linux":
+		cmd = exec.Command("secret-tool", "lookup", "service", Service, "account", account)
+	default:
+		return "", ErrNotFound
+	}
+	if cmd.Err != nil {
+		// The keyring client isn't installed
+		return "", ErrNotFound
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			switch {
+			case runtime.GOOS == "darwin" && exitErr.ExitCode() == notFoundExitCodeDarwin:
+				return "", ErrNotFound
+			case runtime.GOOS == "linux" && exitErr.ExitCode() == 1 && stderr.Len() == 0:
+				// secret-tool exits with 1 and prints nothing when the secret does not exist
+				return "", ErrNotFound
+			}
+		}
+		return "", fmt.Errorf("failed to read '%s' from keyring: %w: %s", account, err, strings.TrimSpace(stderr.String()))
+	}
+	secret := strings.TrimRight(string(out), "\r\n")
+	if secret == "" {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+// Set stores the secret for the provided account, replacing any secret previously stored for it. The secret is
+// passed to the keyring client via stdin, so that it isn't visible in the system's process list
+func Set(account, secret string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// 'security -i' reads commands from stdin. The secret is quoted, as the command is tokenized like a shell would
+		cmd = exec.Command("security", "-i")
+		cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", quote(Service), quote(account), quote(secret)))
+	case "linux":
+		cmd = exec.Command("secret-tool", "store", "--label", fmt.Sprintf("%s: %s", Service, account), "service", Service, "account", account)
+		cmd.Stdin = strings.NewReader(secret)
+	default:
+		return fmt.Errorf("storing secrets in the keyring is not supported on %s", runtime.GOOS)
+	}
+	if cmd.Err != nil {
+		return fmt.Errorf("failed to locate the keyring client '%s': %w", cmd.Args[0], cmd.Err)
+	}
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to store '%s' in keyring: %w: %s", account, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// quote wraps the provided value in double quotes, escaping any characters 'security -i' would interpret
+func quote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
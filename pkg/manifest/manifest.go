@@ -0,0 +1,100 @@
+/*
+manifest provides the capability to describe the tools, and their versions, a machine is expected to have installed,
+and to compare a machine's installed tools against that description
+*/
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// AnyVersion indicates any installed version of the tool satisfies the manifest. An empty version is
+	// equivalent
+	AnyVersion = "*"
+	// LatestVersion indicates the tool is expected to be installed at the latest version available
+	LatestVersion = "latest"
+)
+
+// Manifest describes the tools expected to be installed
+type Manifest struct {
+	// Tools maps the name of each expected tool to its expected version
+	Tools map[string]string `yaml:"tools" json:"tools"`
+}
+
+// Read parses the manifest file at the provided path. Both YAML and JSON are accepted
+func Read(path string) (Manifest, error) {
+	m := Manifest{Tools: map[string]string{}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return m, fmt.Errorf("failed to read manifest '%s': %w", path, err)
+	}
+	err = yaml.Unmarshal(data, &m)
+	if err != nil {
+		return m, fmt.Errorf("failed to parse manifest '%s': %w", path, err)
+	}
+	if m.Tools == nil {
+		m.Tools = map[string]string{}
+	}
+	return m, nil
+}
+
+// Difference describes a single tool whose installed state does not match the manifest
+type Difference struct {
+	// Tool is the tool's name
+	Tool string `json:"tool"`
+
+	// Expected is the version expected by the manifest. Empty for tools not listed in the manifest
+	Expected string `json:"expected,omitempty"`
+
+	// Installed is the version currently installed. Empty for tools which are not installed
+	Installed string `json:"installed,omitempty"`
+}
+
+// Diff describes how a machine's installed tools differ from a manifest
+type Diff struct {
+	// Missing lists tools expected by the manifest which are not installed
+	Missing []Difference `json:"missing"`
+
+	// Changed lists tools which are installed, but at a different version than expected
+	Changed []Difference `json:"changed"`
+
+	// Extra lists tools which are installed, but not listed in the manifest
+	Extra []Difference `json:"extra"`
+}
+
+// Empty returns true if the installed tools match the manifest
+func (d Diff) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Changed) == 0 && len(d.Extra) == 0
+}
+
+// Compare returns the differences between the manifest and the installed tools, provided as a map of each
+// installed tool's name to its version. Expected versions must already be resolved: LatestVersion should be
+// replaced with the version it refers to. Differences are sorted by tool name
+func Compare(m Manifest, installed map[string]string) Diff {
+	d := Diff{Missing: []Difference{}, Changed: []Difference{}, Extra: []Difference{}}
+	for tool, expected := range m.Tools {
+		version, found := installed[tool]
+		switch {
+		case !found:
+			d.Missing = append(d.Missing, Difference{Tool: tool, Expected: expected})
+		case expected != "" && expected != AnyVersion && expected != version:
+			d.Changed = append(d.Changed, Difference{Tool: tool, Expected: expected, Installed: version})
+		}
+	}
+	for tool, version := range installed {
+		if _, found := m.Tools[tool]; !found {
+			d.Extra = append(d.Extra, Difference{Tool: tool, Installed: version})
+		}
+	}
+	for _, differences := range [][]Difference{d.Missing, d.Changed, d.Extra} {
+		sort.Slice(differences, func(i, j int) bool {
+			return differences[i].Tool < differences[j].Tool
+		})
+	}
+	return d
+}
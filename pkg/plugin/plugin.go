@@ -0,0 +1,250 @@
+/*
+plugin provides the capability for third-party executables to manage tools on behalf of backplane-tools.
+
+A plugin is any executable whose name begins with 'backplane-tools-plugin-', located either on the user's
+$PATH or within the plugin directory. The remainder of the executable's name is used as the name of the tool
+it manages. backplane-tools communicates with plugins by invoking them with a single JSON-encoded Request
+written to stdin, and expects a single JSON-encoded Response to be written to stdout. Anything the plugin
+writes to stderr is passed through to the user.
+*/
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+)
+
+const (
+	// Prefix is the naming convention plugin executables must follow in order to be discovered
+	Prefix = "backplane-tools-plugin-"
+
+	// CommandDescribe requests the plugin return information about the tool it manages
+	CommandDescribe = "describe"
+	// CommandLatestVersion requests the plugin return the latest version of its tool available for install
+	CommandLatestVersion = "latest-version"
+	// CommandInstall requests the plugin install the latest version of its tool
+	CommandInstall = "install"
+	// CommandConfigure requests the plugin configure its tool after installation
+	CommandConfigure = "configure"
+	// CommandRemove requests the plugin clean up any files it owns outside of its tool directory
+	CommandRemove = "remove"
+)
+
+// queryTimeout bounds how long a plugin may take to respond to 'describe' and 'latest-version', which are run for
+// every plugin whenever the list of tools is built
+var queryTimeout = 10 * time.Second
+
+// actionTimeout bounds how long a plugin may take to install, configure, or remove its tool
+var actionTimeout = 30 * time.Minute
+
+// Dir returns the directory searched for plugins in addition to $PATH
+func Dir() string {
+	return filepath.Join(base.InstallDir, "plugins")
+}
+
+// Request is the message sent to a plugin on stdin
+type Request struct {
+	// Command is the action the plugin should take
+	Command string `json:"command"`
+
+	// ToolDir is the directory the plugin should install its tool's versioned directories into
+	ToolDir string `json:"toolDir"`
+
+	// LatestDir is the directory the plugin should link its tool's executable into
+	LatestDir string `json:"latestDir"`
+}
+
+// Response is the message a plugin returns on stdout
+type Response struct {
+	// Executable is the name of the executable the plugin links into the latest directory.
+	// Only used when responding to a 'describe' command
+	Executable string `json:"executable,omitempty"`
+
+	// Description briefly describes what the tool does. Only used when responding to a 'describe' command
+	Description string `json:"description,omitempty"`
+
+	// Version is the latest version of the tool. Only used when responding to a 'latest-version' command
+	Version string `json:"version,omitempty"`
+
+	// Error describes why the plugin was unable to complete the requested command
+	Error string `json:"error,omitempty"`
+}
+
+// Tool implements the interface to manage a tool via an external plugin
+type Tool struct {
+	base.Default
+
+	// path is the location of the plugin's executable
+	path string
+
+	// latestVersion caches the version reported by the plugin, so it's only asked once per run
+	latestVersion string
+}
+
+// New initializes a Tool driven by the plugin located at the provided path
+func New(path string) (*Tool, error) {
+	name := strings.TrimPrefix(filepath.Base(path), Prefix)
+	if name == "" {
+		return &Tool{}, fmt.Errorf("plugin '%s' does not define a tool name", path)
+	}
+	t := &Tool{
+		Default: base.NewDefault(name),
+		path:    path,
+	}
+
+	resp, err := t.call(CommandDescribe)
+	if err != nil {
+		return &Tool{}, err
+	}
+	if resp.Executable != "" {
+		t.Default = base.NewDefaultWithExecutable(name, resp.Executable)
+	}
+	t.SetDescription(resp.Description)
+	return t, nil
+}
+
+// Path returns the location of the plugin's executable
+func (t *Tool) Path() string {
+	return t.path
+}
+
+// Install requests the plugin install the latest version of its tool
+func (t *Tool) Install() error {
+	_, err := t.call(CommandInstall)
+	return err
+}
+
+// Configure requests the plugin configure its tool
+func (t *Tool) Configure() error {
+	_, err := t.call(CommandConfigure)
+	return err
+}
+
+// Remove requests the plugin clean up after itself, then removes the tool's directory and symlink
+func (t *Tool) Remove() error {
+	_, err := t.call(CommandRemove)
+	if err != nil {
+		return err
+	}
+	return t.Default.Remove()
+}
+
+// CleanOrphans does nothing: plugins manage the contents of their tool directory themselves
+func (t *Tool) CleanOrphans() ([]string, error) {
+	return []string{}, nil
+}
+
+// LatestVersion requests the plugin report the latest version of its tool available for install
+func (t *Tool) LatestVersion() (string, error) {
+	if t.latestVersion != "" {
+		return t.latestVersion, nil
+	}
+	resp, err := t.call(CommandLatestVersion)
+	if err != nil {
+		return "", err
+	}
+	if resp.Version == "" {
+		return "", fmt.Errorf("plugin '%s' did not return a version", t.path)
+	}
+	t.latestVersion = resp.Version
+	return t.latestVersion, nil
+}
+
+// call invokes the plugin with the provided command and decodes its response
+func (t *Tool) call(command string) (Response, error) {
+	req := Request{
+		Command:   command,
+		ToolDir:   t.ToolDir(),
+		LatestDir: base.LatestDir,
+	}
+	input, err := json.Marshal(req)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to encode request for plugin '%s': %w", t.path, err)
+	}
+
+	timeout := actionTimeout
+	if command == CommandDescribe || command == CommandLatestVersion {
+		timeout = queryTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, t.path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	// Processes started by the plugin may hold its stdout open after it's been killed
+	cmd.WaitDelay = time.Second
+	runErr := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return Response{}, fmt.Errorf("plugin '%s' did not %s within %s", t.path, command, timeout)
+	}
+
+	resp := Response{}
+	if stdout.Len() > 0 {
+		err = json.Unmarshal(stdout.Bytes(), &resp)
+		if err != nil {
+			return Response{}, fmt.Errorf("failed to decode response from plugin '%s': %w", t.path, err)
+		}
+	}
+	if resp.Error != "" {
+		return Response{}, fmt.Errorf("plugin '%s' failed to %s: %s", t.path, command, resp.Error)
+	}
+	if runErr != nil {
+		return Response{}, fmt.Errorf("failed to execute plugin '%s': %w", t.path, runErr)
+	}
+	return resp, nil
+}
+
+// Discover searches the plugin directory and $PATH for plugin executables. When multiple plugins share
+// the same name, the first one found takes precedence, with the plugin directory being searched first
+func Discover() []string {
+	dirs := []string{Dir()}
+	userPath, found := os.LookupEnv("PATH")
+	if found {
+		dirs = append(dirs, filepath.SplitList(userPath)...)
+	}
+
+	seen := map[string]bool{}
+	plugins := []string{}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			// Missing or unreadable directories on the $PATH are common, and shouldn't prevent discovery
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if !strings.HasPrefix(name, Prefix) || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, name)
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, path)
+		}
+	}
+	return plugins
+}
+
+// isExecutable returns true if the provided path refers to a regular file with any execute bit set
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return info.Mode().IsRegular() && info.Mode().Perm()&0o111 != 0
+}
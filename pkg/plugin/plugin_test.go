@@ -0,0 +1,95 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+)
+
+// fakePlugin writes a plugin script named after tool to dir. The script appends each command it receives to the
+// returned path, then runs body
+func fakePlugin(t *testing.T, dir, tool, body string) (path string, callsPath string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are faked using shell scripts")
+	}
+	callsPath = filepath.Join(t.TempDir(), "calls")
+	script := "#!/bin/sh\nread -r request\necho \"$request\" >> '" + callsPath + "'\n" + body + "\n"
+	path = filepath.Join(dir, Prefix+tool)
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatalf("failed to write plugin: %v", err)
+	}
+	return path, callsPath
+}
+
+// calls returns the number of times the plugin recording to callsPath was asked to run command
+func calls(t *testing.T, callsPath, command string) int {
+	t.Helper()
+	recorded, err := os.ReadFile(callsPath)
+	if err != nil {
+		t.Fatalf("failed to read plugin calls: %v", err)
+	}
+	return strings.Count(string(recorded), `"command":"`+command+`"`)
+}
+
+func TestLatestVersionIsCached(t *testing.T) {
+	path, callsPath := fakePlugin(t, t.TempDir(), "example", `case "$request" in
+*latest-version*) echo '{"version": "v1.2.3"}' ;;
+*) echo '{"description": "An example tool"}' ;;
+esac`)
+	tool, err := New(path)
+	if err != nil {
+		t.Fatalf("failed to initialize plugin: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		version, err := tool.LatestVersion()
+		if err != nil {
+			t.Fatalf("failed to retrieve latest version: %v", err)
+		}
+		if version != "v1.2.3" {
+			t.Errorf("expected version 'v1.2.3', got '%s'", version)
+		}
+	}
+	if n := calls(t, callsPath, CommandLatestVersion); n != 1 {
+		t.Errorf("expected the plugin to be asked for its latest version once, got %d", n)
+	}
+}
+
+func TestUnresponsivePluginTimesOut(t *testing.T) {
+	original := queryTimeout
+	queryTimeout = 100 * time.Millisecond
+	t.Cleanup(func() { queryTimeout = original })
+
+	path, _ := fakePlugin(t, t.TempDir(), "example", "exec sleep 10")
+	start := time.Now()
+	_, err := New(path)
+	if err == nil || !strings.Contains(err.Error(), "did not describe within") {
+		t.Errorf("expected the plugin to time out, got '%v'", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the plugin to be stopped once it timed out, but it ran for %s", elapsed)
+	}
+}
+
+func TestDiscoverUsesInstallDir(t *testing.T) {
+	root := t.TempDir()
+	base.SetRoot(root)
+	if Dir() != filepath.Join(root, "plugins") {
+		t.Errorf("expected the plugin directory to be under '%s', got '%s'", root, Dir())
+	}
+
+	if err := os.MkdirAll(Dir(), 0o755); err != nil {
+		t.Fatalf("failed to create plugin directory: %v", err)
+	}
+	path, _ := fakePlugin(t, Dir(), "example", `echo '{}'`)
+	t.Setenv("PATH", "")
+	discovered := Discover()
+	if len(discovered) != 1 || discovered[0] != path {
+		t.Errorf("expected '%s' to be discovered, got %v", path, discovered)
+	}
+}
@@ -0,0 +1,157 @@
+/*
+policy provides the capability to restrict the tools, and the versions of each, installed by backplane-tools to those
+approved in a signed manifest published by the user's organization
+*/
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/signed"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// Override allows tools and versions the policy doesn't approve to be installed anyway. Each is reported loudly
+var Override bool
+
+// Policy lists the tools an organization approves for installation, and the versions of each
+type Policy struct {
+	// Tools maps the name of each approved tool to its approved versions. Tools not listed are not approved
+	Tools map[string]Versions `yaml:"tools" json:"tools"`
+}
+
+// Versions lists approved versions of a tool. Each is either a version, or a glob pattern matching several - such as
+// '4.15.*', or '*' to approve every version. A single version may be provided in place of a list
+type Versions []string
+
+// UnmarshalYAML parses either a single version or a list of versions
+func (v *Versions) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*v = Versions{node.Value}
+		return nil
+	}
+	var versions []string
+	err := node.Decode(&versions)
+	if err != nil {
+		return err
+	}
+	*v = versions
+	return nil
+}
+
+// Parse parses and validates the provided policy. Both YAML and JSON are accepted
+func Parse(data []byte) (Policy, error) {
+	p := Policy{}
+	err := yaml.Unmarshal(data, &p)
+	if err != nil {
+		return Policy{}, fmt.Errorf("failed to parse policy: %w", err)
+	}
+	if p.Tools == nil {
+		p.Tools = map[string]Versions{}
+	}
+	for tool, versions := range p.Tools {
+		if len(versions) == 0 {
+			return Policy{}, fmt.Errorf("tool '%s': no versions are approved: list at least one, or remove the tool", tool)
+		}
+		for _, version := range versions {
+			_, err = path.Match(version, "")
+			if strings.TrimSpace(version) == "" || err != nil {
+				return Policy{}, fmt.Errorf("tool '%s': invalid version '%s': must be a version or glob pattern", tool, version)
+			}
+		}
+	}
+	return p, nil
+}
+
+// Approves returns true if the policy approves installing the named tool at the provided version. Versions are
+// matched regardless of any leading 'v'
+func (p Policy) Approves(tool, version string) bool {
+	version = strings.TrimPrefix(version, "v")
+	for _, approved := range p.Tools[tool] {
+		matched, err := path.Match(strings.TrimPrefix(approved, "v"), version)
+		if err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Load returns the policy configured by the user, or nil if none is configured. The policy is retrieved at most once
+// a day, and is only used once its signature has been verified. If it can't be retrieved, the copy previously
+// retrieved is used instead
+func Load(settings config.Policy) (*Policy, error) {
+	if settings.URL == "" {
+		return nil, nil
+	}
+	data, err := signed.Read(signed.Document{
+		Kind:     "policy",
+		Settings: settings.SignedDocument,
+		Validate: func(data []byte) error {
+			_, err := Parse(data)
+			return err
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy '%s': %w", settings.URL, err)
+	}
+	return &p, nil
+}
+
+// current returns the policy configured by the user, loading it the first time it's needed
+var current = sync.OnceValues(func() (*Policy, error) {
+	return Load(config.Get().Policy)
+})
+
+// Enabled returns true if the user has configured a policy
+func Enabled() bool {
+	return config.Get().Policy.URL != ""
+}
+
+// Check determines whether the named tool may be installed at the provided version. If the policy doesn't approve
+// it, an error wrapping utils.ErrNotApproved is returned - unless Override is set, in which case a warning is printed
+// and overridden is true instead. Every version is approved when no policy is configured
+func Check(tool, version string) (overridden bool, err error) {
+	if !Enabled() {
+		return false, nil
+	}
+	p, err := current()
+	if err != nil {
+		if !Override {
+			return false, fmt.Errorf("failed to load the policy approving tools for installation: %w", err)
+		}
+		warn(fmt.Sprintf("installing %s %s without checking it against your organization's policy, which could not be loaded: %v", tool, version, err))
+		return true, nil
+	}
+	if p.Approves(tool, version) {
+		return false, nil
+	}
+	if !Override {
+		return false, fmt.Errorf("%w by the policy published at '%s': %s %s. Approved versions of %s: %s", utils.ErrNotApproved, config.Get().Policy.URL, tool, version, tool, describe(p.Tools[tool]))
+	}
+	warn(fmt.Sprintf("installing %s %s, which is NOT approved by your organization's policy published at '%s', because the policy was overridden", tool, version, config.Get().Policy.URL))
+	return true, nil
+}
+
+// describe lists the provided approved versions
+func describe(versions Versions) string {
+	if len(versions) == 0 {
+		return "none"
+	}
+	return "'" + strings.Join(versions, "', '") + "'"
+}
+
+// warn reports that the policy was overridden, so that it stands out from the rest of the output
+func warn(message string) {
+	fmt.Fprintln(os.Stderr, utils.Failure("WARNING: "+message))
+}
@@ -0,0 +1,88 @@
+/*
+report provides the capability to record the outcome of an installation run in a machine-readable format, so that
+automation and support bundles don't need to parse console output
+*/
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Action describes what an installation did to a tool
+type Action string
+
+const (
+	// ActionInstall indicates the tool was not previously installed
+	ActionInstall Action = "install"
+	// ActionUpgrade indicates a different version of the tool was previously installed
+	ActionUpgrade Action = "upgrade"
+	// ActionReinstall indicates the same version of the tool was previously installed
+	ActionReinstall Action = "reinstall"
+)
+
+// Result records the outcome of installing a single tool
+type Result struct {
+	Tool            string `json:"tool"`
+	Action          Action `json:"action"`
+	VersionBefore   string `json:"versionBefore,omitempty"`
+	VersionAfter    string `json:"versionAfter,omitempty"`
+	Success         bool   `json:"success"`
+	Error           string `json:"error,omitempty"`
+	DurationMS      int64  `json:"durationMs"`
+	BytesDownloaded int64  `json:"bytesDownloaded"`
+	Unapproved      bool   `json:"unapproved,omitempty"`
+}
+
+// Report records the outcome of a single installation run
+type Report struct {
+	StartedAt       time.Time `json:"startedAt"`
+	FinishedAt      time.Time `json:"finishedAt"`
+	DurationMS      int64     `json:"durationMs"`
+	BytesDownloaded int64     `json:"bytesDownloaded"`
+	Results         []Result  `json:"results"`
+}
+
+// NewAction determines the Action taken given the tool's version before and after installation
+func NewAction(before, after string) Action {
+	switch before {
+	case "":
+		return ActionInstall
+	case after:
+		return ActionReinstall
+	default:
+		return ActionUpgrade
+	}
+}
+
+// Write stores the report at the provided path, replacing any previous report
+func Write(path string, r Report) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	err = os.WriteFile(path, data, os.FileMode(0o644))
+	if err != nil {
+		return fmt.Errorf("failed to write report '%s': %w", path, err)
+	}
+	return nil
+}
+
+// Read returns the report stored at the provided path. found is false if no report has been stored
+func Read(path string) (r Report, found bool, err error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Report{}, false, nil
+	}
+	if err != nil {
+		return Report{}, false, fmt.Errorf("failed to read report '%s': %w", path, err)
+	}
+	err = json.Unmarshal(data, &r)
+	if err != nil {
+		return Report{}, false, fmt.Errorf("failed to parse report '%s': %w", path, err)
+	}
+	return r, true, nil
+}
@@ -0,0 +1,423 @@
+/*
+serve provides the capability to share downloads between machines. A Server retrieves the files requested by clients
+configured to fetch through it, caches the assets among them on disk, and serves cached assets to subsequent clients
+without contacting upstream again
+*/
+package serve
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// DefaultAllowedHosts are the hosts a Server retrieves files from: those the tools built into backplane-tools are
+// installed from
+var DefaultAllowedHosts = []string{
+	"api.github.com",
+	"github.com",
+	"objects.githubusercontent.com",
+	"release-assets.githubusercontent.com",
+	"mirror.openshift.com",
+	"storage.googleapis.com",
+	"awscli.amazonaws.com",
+	"pypi.org",
+	"files.pythonhosted.org",
+	"formulae.brew.sh",
+	"ghcr.io",
+	"pkg-containers.githubusercontent.com",
+	"fedoraproject.org",
+}
+
+// hopHeaders are the headers which only apply to a single connection, and are never relayed
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// uncachedTypes are the media types of responses which are never cached, as they describe releases - which may
+// change - rather than containing assets
+var uncachedTypes = []string{"application/json", "text/html", "application/xml", "text/xml"}
+
+// signingParameters are the query parameters of Azure shared access signatures, which GitHub signs the URLs of
+// release assets with. S3 and Google Cloud Storage signatures are identified by their 'X-Amz-' and 'X-Goog-' prefixes
+var signingParameters = []string{"sp", "sv", "sr", "spr", "st", "se", "sig", "skoid", "sktid", "skt", "ske", "sks", "skv", "rscd", "rsct", "jwt"}
+
+// entry describes an asset stored in the cache
+type entry struct {
+	// URL is the URL the asset was retrieved from
+	URL string `json:"url"`
+
+	// ContentType is the media type upstream served the asset with
+	ContentType string `json:"contentType,omitempty"`
+
+	// Size is the asset's size, in bytes
+	Size int64 `json:"size"`
+
+	// SHA256 is the asset's checksum, calculated when it was retrieved. Cached assets are verified against it
+	// before they're served
+	SHA256 string `json:"sha256"`
+
+	// Retrieved is when the asset was retrieved
+	Retrieved time.Time `json:"retrieved"`
+}
+
+// Server relays requests from clients to upstream servers, caching the assets it retrieves
+type Server struct {
+	// CacheDir is the directory assets are cached in
+	CacheDir string
+
+	// AllowedHosts are the hosts files are retrieved from. Requests for any other host are refused, so that the
+	// Server can't be used to reach arbitrary servers on its network
+	AllowedHosts []string
+
+	// locks serializes retrieval of each asset, so that concurrent requests for an uncached asset only retrieve it once
+	locks sync.Map
+
+	// redirects records the cache keys of the signed URLs requests through the Server were redirected to. Signed
+	// URLs are only cached once such a request has led to them, since others may have been issued to clients which
+	// retrieved private assets directly, with their credentials
+	redirects sync.Map
+}
+
+// NewServer returns a Server caching assets in the provided directory
+func NewServer(cacheDir string, allowedHosts []string) *Server {
+	return &Server{
+		CacheDir:     cacheDir,
+		AllowedHosts: allowedHosts,
+	}
+}
+
+// ServeHTTP handles a single request from a client
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != utils.CacheServerPath {
+		http.NotFound(w, req)
+		return
+	}
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		http.Error(w, fmt.Sprintf("unsupported method '%s'", req.Method), http.StatusMethodNotAllowed)
+		return
+	}
+	upstream, err := url.Parse(req.URL.Query().Get("url"))
+	if err != nil || (upstream.Scheme != "https" && upstream.Scheme != "http") || upstream.Host == "" {
+		http.Error(w, "the 'url' parameter must be an http(s) URL", http.StatusBadRequest)
+		return
+	}
+	if !utils.Contains(s.AllowedHosts, upstream.Hostname()) {
+		http.Error(w, fmt.Sprintf("'%s' is not an allowed host", upstream.Hostname()), http.StatusForbidden)
+		return
+	}
+	// Clients make requests with credentials directly, so credentials are never relayed upstream on their behalf
+	if req.Header.Get("Authorization") != "" || req.Header.Get("Cookie") != "" {
+		http.Error(w, "requests made with credentials must be sent directly, rather than through the cache server", http.StatusBadRequest)
+		return
+	}
+
+	if !cacheable(req) {
+		s.relay(w, req, upstream)
+		return
+	}
+	key := cacheKey(upstream)
+	if _, redirected := s.redirects.Load(key); signed(upstream) && !redirected {
+		s.relay(w, req, upstream)
+		return
+	}
+	lock, _ := s.locks.LoadOrStore(key, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	served, err := s.serveCached(w, key)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: discarding cached copy of '%s': %v\n", upstream.Redacted(), err)
+		s.evict(key)
+	}
+	if served {
+		fmt.Printf("%s %s (cached)\n", req.Method, upstream.Redacted())
+		return
+	}
+	s.retrieve(w, req, upstream, key)
+}
+
+// cacheable returns true if the response to the provided request may be cached and shared between clients
+func cacheable(req *http.Request) bool {
+	return req.Method == http.MethodGet && req.Header.Get("Range") == ""
+}
+
+// cacheableResponse returns true if the provided response contains an asset, rather than describing a release
+func cacheableResponse(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return !utils.Contains(uncachedTypes, mediaType)
+}
+
+// signed returns true if the provided URL carries query parameters signing it
+func signed(u *url.URL) bool {
+	for name := range u.Query() {
+		if isSigningParameter(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSigningParameter returns true if the named query parameter signs the URL it's part of
+func isSigningParameter(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasPrefix(lower, "x-amz-") || strings.HasPrefix(lower, "x-goog-") || utils.Contains(signingParameters, lower)
+}
+
+// cacheKey identifies the asset at the provided URL in the cache. Query parameters which sign the URL - as added by
+// GitHub, S3, and Google Cloud Storage when redirecting to an asset - change with every request, and are ignored
+func cacheKey(u *url.URL) string {
+	query := u.Query()
+	for name := range query {
+		if isSigningParameter(name) {
+			query.Del(name)
+		}
+	}
+	keyed := *u
+	keyed.RawQuery = query.Encode()
+	keyed.Fragment = ""
+	sum := sha256.Sum256([]byte(keyed.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// paths returns the locations of the cached asset with the provided key, and of the entry describing it
+func (s *Server) paths(key string) (assetPath, entryPath string) {
+	dir := filepath.Join(s.CacheDir, key[:2])
+	return filepath.Join(dir, key), filepath.Join(dir, key+".json")
+}
+
+// serveCached serves the cached asset with the provided key, if any. The asset is only served once it's been verified
+// against the checksum calculated when it was retrieved. served is false if the asset isn't cached, or fails
+// verification
+func (s *Server) serveCached(w http.ResponseWriter, key string) (served bool, err error) {
+	assetPath, entryPath := s.paths(key)
+	data, err := os.ReadFile(entryPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read cache entry '%s': %w", entryPath, err)
+	}
+	e := entry{}
+	err = json.Unmarshal(data, &e)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse cache entry '%s': %w", entryPath, err)
+	}
+	sum, err := utils.Sha256sum(assetPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to calculate checksum of cached asset '%s': %w", assetPath, err)
+	}
+	if sum != e.SHA256 {
+		return false, fmt.Errorf("%w: cached asset '%s' has checksum '%s', expected '%s'", utils.ErrChecksumMismatch, assetPath, sum, e.SHA256)
+	}
+
+	file, err := os.Open(assetPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to open cached asset '%s': %w", assetPath, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	writeEntryHeaders(w, e)
+	w.Header().Set("X-Cache", "HIT")
+	w.WriteHeader(http.StatusOK)
+	_, err = io.Copy(w, file)
+	if err != nil {
+		// The response has already begun, so the client is left to notice the truncated download
+		fmt.Fprintf(os.Stderr, "WARNING: failed to serve cached copy of '%s': %v\n", e.URL, err)
+	}
+	return true, nil
+}
+
+// retrieve retrieves the asset at the provided URL, caches it if possible, then serves it
+func (s *Server) retrieve(w http.ResponseWriter, req *http.Request, upstream *url.URL, key string) {
+	resp, err := s.do(req, upstream)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if !cacheableResponse(resp) {
+		s.recordRedirect(resp, upstream)
+		writeResponse(w, resp)
+		fmt.Printf("%s %s (%d)\n", req.Method, upstream.Redacted(), resp.StatusCode)
+		return
+	}
+
+	e, err := s.store(resp, upstream, key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	fmt.Printf("%s %s (retrieved %d bytes)\n", req.Method, upstream.Redacted(), e.Size)
+	_, err = s.serveCached(w, key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// recordRedirect records the signed URL the provided response redirects to, if any, so that the asset it refers to
+// may be cached
+func (s *Server) recordRedirect(resp *http.Response, upstream *url.URL) {
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		return
+	}
+	location, err := upstream.Parse(resp.Header.Get("Location"))
+	if err != nil || !signed(location) {
+		return
+	}
+	s.redirects.Store(cacheKey(location), struct{}{})
+}
+
+// relay passes the request to upstream, and the response back to the client, without caching it
+func (s *Server) relay(w http.ResponseWriter, req *http.Request, upstream *url.URL) {
+	resp, err := s.do(req, upstream)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	writeResponse(w, resp)
+	fmt.Printf("%s %s (%d)\n", req.Method, upstream.Redacted(), resp.StatusCode)
+}
+
+// do performs the client's request against upstream. Redirects are returned to the client rather than followed, so
+// that the client requests their destination through the Server as well
+func (s *Server) do(req *http.Request, upstream *url.URL) (*http.Response, error) {
+	upstreamReq, err := http.NewRequestWithContext(req.Context(), req.Method, upstream.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for '%s': %w", upstream.Redacted(), err)
+	}
+	copyHeaders(upstreamReq.Header, req.Header)
+	// Responses are decompressed as they're retrieved, so that assets are cached as upstream published them
+	upstreamReq.Header.Del("Accept-Encoding")
+	// Requests are made directly, rather than through any cache server configured, so that a Server never makes
+	// requests through itself
+	client := &http.Client{
+		Transport: utils.HTTPTransport,
+		CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(upstreamReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve '%s': %w", upstream.Redacted(), err)
+	}
+	return resp, nil
+}
+
+// store writes the asset in the provided response to the cache
+func (s *Server) store(resp *http.Response, upstream *url.URL, key string) (entry, error) {
+	assetPath, entryPath := s.paths(key)
+	dir := filepath.Dir(assetPath)
+	err := os.MkdirAll(dir, os.FileMode(0o755))
+	if err != nil {
+		return entry{}, fmt.Errorf("failed to create cache directory '%s': %w", dir, err)
+	}
+	tmpFile, err := os.CreateTemp(dir, key+"-*")
+	if err != nil {
+		return entry{}, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmpFile, hash), utils.ExpectSize(utils.CountDownload(resp.Body), resp.ContentLength))
+	closeErr := tmpFile.Close()
+	if err != nil {
+		return entry{}, fmt.Errorf("failed to retrieve '%s': %w", upstream.Redacted(), err)
+	}
+	if closeErr != nil {
+		return entry{}, fmt.Errorf("failed to write '%s': %w", tmpFile.Name(), closeErr)
+	}
+
+	e := entry{
+		URL:         upstream.Redacted(),
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        size,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+		Retrieved:   time.Now().UTC(),
+	}
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return entry{}, fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	err = os.Rename(tmpFile.Name(), assetPath)
+	if err != nil {
+		return entry{}, fmt.Errorf("failed to store '%s': %w", assetPath, err)
+	}
+	// The entry is written last, so that assets are never served before they've been completely written
+	err = os.WriteFile(entryPath, data, os.FileMode(0o644))
+	if err != nil {
+		return entry{}, fmt.Errorf("failed to write cache entry '%s': %w", entryPath, err)
+	}
+	return e, nil
+}
+
+// evict removes the asset with the provided key from the cache
+func (s *Server) evict(key string) {
+	assetPath, entryPath := s.paths(key)
+	_ = os.Remove(entryPath)
+	_ = os.Remove(assetPath)
+}
+
+// writeEntryHeaders sets the response headers describing the provided cached asset
+func writeEntryHeaders(w http.ResponseWriter, e entry) {
+	if e.ContentType != "" {
+		w.Header().Set("Content-Type", e.ContentType)
+	}
+	w.Header().Set("Content-Length", strconv.FormatInt(e.Size, 10))
+	w.Header().Set("X-Checksum-Sha256", e.SHA256)
+}
+
+// writeResponse relays the provided upstream response to the client
+func writeResponse(w http.ResponseWriter, resp *http.Response) {
+	copyHeaders(w.Header(), resp.Header)
+	w.Header().Set("X-Cache", "MISS")
+	w.WriteHeader(resp.StatusCode)
+	_, err := io.Copy(w, resp.Body)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: failed to relay response from '%s': %v\n", resp.Request.URL.Redacted(), err)
+	}
+}
+
+// copyHeaders copies the end-to-end headers in src to dst
+func copyHeaders(dst, src http.Header) {
+	for name, values := range src {
+		if utils.Contains(hopHeaders, http.CanonicalHeaderKey(name)) {
+			continue
+		}
+		for _, value := range values {
+			dst.Add(name, value)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+func TestServerRejectsCredentials(t *testing.T) {
+	upstreamRequests := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		upstreamRequests++
+		_, _ = w.Write([]byte("asset"))
+	}))
+	defer upstream.Close()
+	server := httptest.NewServer(NewServer(t.TempDir(), []string{"127.0.0.1"}))
+	defer server.Close()
+
+	for _, header := range []string{"Authorization", "Cookie"} {
+		req, err := http.NewRequest(http.MethodGet, server.URL+utils.CacheServerPath+"?url="+url.QueryEscape(upstream.URL+"/asset"), nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		req.Header.Set(header, "secret")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected a request with the '%s' header to be rejected, got status %d", header, resp.StatusCode)
+		}
+	}
+	if upstreamRequests != 0 {
+		t.Errorf("expected no requests to be relayed upstream, got %d", upstreamRequests)
+	}
+}
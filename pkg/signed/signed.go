@@ -0,0 +1,237 @@
+/*
+signed provides the capability to retrieve documents published with a detached GPG signature, such as catalogs of
+additional tools, and to cache them once their signature has been verified
+*/
+package signed
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// refreshInterval is how long a retrieved document is used before it's retrieved again
+const refreshInterval = 24 * time.Hour
+
+// Document describes a signed document to retrieve
+type Document struct {
+	// Kind describes what the document is, such as 'catalog'. It's used in messages, and names the files the
+	// document is stored in once retrieved
+	Kind string
+
+	// Settings defines where the document and its signature are published, and the keys trusted to sign it
+	Settings config.SignedDocument
+
+	// Validate rejects documents which can't be used, so that they never replace the copy previously retrieved.
+	// May be nil
+	Validate func(data []byte) error
+}
+
+// Read returns the contents of the document, once its signature has been verified. The document is retrieved at most
+// once per refreshInterval. If it can't be retrieved, the copy previously retrieved is used instead
+func Read(d Document) ([]byte, error) {
+	dir := d.cacheDir()
+	documentPath := filepath.Join(dir, d.Kind)
+	signaturePath := filepath.Join(dir, d.Kind+".sig")
+
+	info, err := os.Stat(documentPath)
+	cached := err == nil
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return []byte{}, fmt.Errorf("failed to check for cached %s '%s': %w", d.Kind, documentPath, err)
+	}
+	if !cached || time.Since(info.ModTime()) > refreshInterval {
+		err = d.refresh(dir)
+		if err != nil {
+			if !cached {
+				return []byte{}, err
+			}
+			fmt.Fprintf(os.Stderr, "WARNING: %v. Using the %s retrieved %s ago\n", err, d.Kind, time.Since(info.ModTime()).Round(time.Minute))
+		}
+	}
+
+	keyRing, err := d.keys(dir)
+	if err != nil {
+		return []byte{}, err
+	}
+	err = utils.VerifyGPGSignature(documentPath, signaturePath, keyRing)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed to verify %s '%s': %w", d.Kind, d.Settings.URL, err)
+	}
+	data, err := os.ReadFile(documentPath)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed to read cached %s '%s': %w", d.Kind, documentPath, err)
+	}
+	return data, nil
+}
+
+// cacheDir returns the directory the document is stored in once retrieved. Each URL is stored separately, so that
+// a previously retrieved document is never used in place of a newly configured one
+func (d Document) cacheDir() string {
+	sum := sha256.Sum256([]byte(d.Settings.URL))
+	return filepath.Join(base.StateDir, d.Kind+"s", hex.EncodeToString(sum[:])[:16])
+}
+
+// refresh retrieves the document and its signature into the provided directory. The previously retrieved document
+// is only replaced once the new document has been verified
+func (d Document) refresh(dir string) error {
+	signatureURL := d.Settings.SignatureURL
+	if signatureURL == "" {
+		signatureURL = d.Settings.URL + ".asc"
+	}
+	data, err := fetch(d.Settings.URL)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve %s: %w", d.Kind, err)
+	}
+	signature, err := fetch(signatureURL)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve %s signature: %w", d.Kind, err)
+	}
+
+	err = os.MkdirAll(dir, os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create %s directory '%s': %w", d.Kind, dir, err)
+	}
+	tmpDir, err := os.MkdirTemp(dir, "refresh-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+	tmpDocument := filepath.Join(tmpDir, d.Kind)
+	tmpSignature := filepath.Join(tmpDir, d.Kind+".sig")
+	for path, content := range map[string][]byte{tmpDocument: data, tmpSignature: signature} {
+		err = os.WriteFile(path, content, os.FileMode(0o644))
+		if err != nil {
+			return fmt.Errorf("failed to write '%s': %w", path, err)
+		}
+	}
+
+	keyRing, err := d.keys(dir)
+	if err != nil {
+		return err
+	}
+	err = utils.VerifyGPGSignature(tmpDocument, tmpSignature, keyRing)
+	if err != nil {
+		return fmt.Errorf("failed to verify %s '%s': %w", d.Kind, d.Settings.URL, err)
+	}
+	if d.Validate != nil {
+		err = d.Validate(data)
+		if err != nil {
+			return fmt.Errorf("invalid %s '%s': %w", d.Kind, d.Settings.URL, err)
+		}
+	}
+
+	// Replace the signature first: a document left paired with a newer signature fails verification, rather than
+	// being used unverified
+	err = os.Rename(tmpSignature, filepath.Join(dir, d.Kind+".sig"))
+	if err != nil {
+		return fmt.Errorf("failed to store %s signature: %w", d.Kind, err)
+	}
+	err = os.Rename(tmpDocument, filepath.Join(dir, d.Kind))
+	if err != nil {
+		return fmt.Errorf("failed to store %s: %w", d.Kind, err)
+	}
+	return nil
+}
+
+// keys returns the keys trusted to sign the document. Keys are retrieved from the configured keyURL the first time
+// they're needed, and cached alongside the document. Keys whose fingerprints aren't pinned by the user are discarded
+func (d Document) keys(dir string) (openpgp.EntityList, error) {
+	fingerprints := []string{}
+	for _, fingerprint := range d.Settings.Fingerprints {
+		fingerprints = append(fingerprints, utils.NormalizeFingerprint(fingerprint))
+	}
+	keyRingPath := filepath.Join(dir, "keyring.gpg")
+
+	keyRing := openpgp.EntityList{}
+	data, err := os.ReadFile(keyRingPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return openpgp.EntityList{}, fmt.Errorf("failed to read %s keyring '%s': %w", d.Kind, keyRingPath, err)
+	}
+	if err == nil {
+		keyRing, err = utils.ReadKeyRing(bytes.NewReader(data))
+		if err != nil {
+			return openpgp.EntityList{}, fmt.Errorf("failed to read %s keyring '%s': %w", d.Kind, keyRingPath, err)
+		}
+	}
+	keyRing = filterKeys(keyRing, fingerprints)
+	if len(keyRing) == len(fingerprints) {
+		return keyRing, nil
+	}
+
+	fetched, err := utils.FetchKeyRing(d.Settings.KeyURL)
+	if err != nil {
+		if len(keyRing) > 0 {
+			fmt.Fprintf(os.Stderr, "WARNING: failed to retrieve the keys trusted to sign the %s: %v\n", d.Kind, err)
+			return keyRing, nil
+		}
+		return openpgp.EntityList{}, fmt.Errorf("failed to retrieve the keys trusted to sign the %s: %w", d.Kind, err)
+	}
+	keyRing = filterKeys(fetched, fingerprints)
+	if len(keyRing) == 0 {
+		return openpgp.EntityList{}, fmt.Errorf("none of the keys published at '%s' are trusted to sign the %s", d.Settings.KeyURL, d.Kind)
+	}
+	var buf bytes.Buffer
+	for _, key := range keyRing {
+		err = key.Serialize(&buf)
+		if err != nil {
+			return openpgp.EntityList{}, fmt.Errorf("failed to encode key %s: %w", utils.Fingerprint(key), err)
+		}
+	}
+	err = os.MkdirAll(dir, os.FileMode(0o755))
+	if err != nil {
+		return openpgp.EntityList{}, fmt.Errorf("failed to create %s directory '%s': %w", d.Kind, dir, err)
+	}
+	err = os.WriteFile(keyRingPath, buf.Bytes(), os.FileMode(0o644))
+	if err != nil {
+		return openpgp.EntityList{}, fmt.Errorf("failed to write %s keyring '%s': %w", d.Kind, keyRingPath, err)
+	}
+	return keyRing, nil
+}
+
+// filterKeys returns the keys whose fingerprint is one of those provided
+func filterKeys(keys openpgp.EntityList, fingerprints []string) openpgp.EntityList {
+	filtered := openpgp.EntityList{}
+	for _, key := range keys {
+		if utils.Contains(fingerprints, utils.Fingerprint(key)) {
+			filtered = append(filtered, key)
+		}
+	}
+	return filtered
+}
+
+// fetch returns the contents of the file at the provided URL
+func fetch(url string) ([]byte, error) {
+	resp, err := utils.HTTPClient().Get(url)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed to GET '%s': %w", url, err)
+	}
+	defer func() {
+		closeErr := resp.Body.Close()
+		if closeErr != nil {
+			fmt.Printf("WARNING: failed to close response body: %v\n", closeErr)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return []byte{}, fmt.Errorf("failed to retrieve '%s': %w", url, utils.UnexpectedStatus(resp.StatusCode))
+	}
+	data, err := io.ReadAll(utils.CountDownload(resp.Body))
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed to read '%s': %w", url, err)
+	}
+	return data, nil
+}
@@ -1,21 +1,57 @@
 package aws
 
 import (
-	"net/http"
+	"fmt"
+	"net/url"
 	"path/filepath"
 
+	"github.com/openshift/backplane-tools/pkg/sources"
 	"github.com/openshift/backplane-tools/pkg/utils"
 )
 
+// BaseURL is the location the aws cli's installers are published to
+const BaseURL = "https://awscli.amazonaws.com"
+
+// Source retrieves the aws cli's installers
+type Source struct{}
+
+// NewSource creates a Source
+func NewSource() *Source {
+	return &Source{}
+}
+
+// String describes the source
+func (s Source) String() string {
+	return BaseURL
+}
+
+// Download retrieves the artifact into dir. Artifacts without a URL are located by name within BaseURL
+func (s Source) Download(artifact sources.Artifact, dir string) (string, error) {
+	artifactURL := artifact.URL
+	if artifactURL == "" {
+		var err error
+		artifactURL, err = url.JoinPath(BaseURL, artifact.Name)
+		if err != nil {
+			return "", fmt.Errorf("failed to build URL: %w", err)
+		}
+	}
+	filePath := filepath.Join(dir, filepath.Base(artifact.Name))
+	return filePath, download(artifactURL, filePath)
+}
+
 func DownloadAWSCLIRelease(url string, fileExtension string, dir string) error {
+	return download(url, filepath.Join(dir, "aws-cli"+fileExtension))
+}
+
+// download retrieves the file at url, storing it at filePath
+func download(url, filePath string) error {
 	// Make the HTTP request to download the release
-	response, err := http.Get(url)
+	response, err := utils.HTTPClient().Get(url)
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
 	// Create the output file
-	filePath := filepath.Join(dir, "aws-cli"+fileExtension)
-	return utils.WriteFile(response.Body, filePath, 0o755)
+	return utils.WriteFile(utils.ExpectSize(utils.CountDownload(response.Body), response.ContentLength), filePath, utils.DataMode)
 }
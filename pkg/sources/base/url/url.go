@@ -8,9 +8,10 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"os"
 	"path/filepath"
 
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/sources"
 	"github.com/openshift/backplane-tools/pkg/utils"
 )
 
@@ -35,9 +36,45 @@ func (s Source) DownloadFile(path, dir string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to build URL: %w", err)
 	}
-	resp, err := http.Get(url)
+	_, fileName := filepath.Split(path)
+	filePath := filepath.Join(dir, fileName)
+	return filePath, download(url, filePath)
+}
+
+// get requests the file at url, authenticating using the credentials configured for its host, or those in the
+// user's netrc file. It is the caller's responsibility to close the response body
+func get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for '%s': %w", url, err)
+	}
+	username, password, found := config.Get().HostCredentials(req.URL.Hostname())
+	if !found {
+		username, password, found, err = utils.NetrcCredentials(req.URL.Hostname())
+		if err != nil {
+			fmt.Printf("WARNING: %v\n", err)
+		}
+	}
+	if found {
+		req.SetBasicAuth(username, password)
+	}
+
+	resp, err := utils.HTTPClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GET '%s': %w", url, err)
+	}
+	if resp.StatusCode == http.StatusUnauthorized && !found {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("'%s' requires authentication: add credentials for '%s' to the configuration file or to %s", url, req.URL.Hostname(), utils.NetrcPath())
+	}
+	return resp, nil
+}
+
+// download retrieves the file at url, storing it at filePath
+func download(url, filePath string) error {
+	resp, err := get(url)
 	if err != nil {
-		return "", fmt.Errorf("failed to GET '%s': %w", url, err)
+		return err
 	}
 	defer func() {
 		closeErr := resp.Body.Close()
@@ -46,16 +83,14 @@ func (s Source) DownloadFile(path, dir string) (string, error) {
 		}
 	}()
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("received non-%d status code: %d", http.StatusOK, resp.StatusCode)
+		return utils.UnexpectedStatus(resp.StatusCode)
 	}
 
-	_, fileName := filepath.Split(path)
-	filePath := filepath.Join(dir, fileName)
-	err = utils.WriteFile(resp.Body, filePath, os.FileMode(0o755))
+	err = utils.WriteFile(utils.ExpectSize(utils.CountDownload(resp.Body), resp.ContentLength), filePath, utils.DataMode)
 	if err != nil {
-		return "", fmt.Errorf("failed to create file '%s': %w", filePath, err)
+		return fmt.Errorf("failed to create file '%s': %w", filePath, err)
 	}
-	return filePath, nil
+	return nil
 }
 
 // GetFileContents returns the contents of the specified file without storing it locally.
@@ -65,12 +100,13 @@ func (s Source) GetFileContents(path string) (io.ReadCloser, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to build URL: %w", err)
 	}
-	resp, err := http.Get(url)
+	resp, err := get(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to GET '%s': %w", url, err)
+		return nil, err
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("received non-%d status code: %d", http.StatusOK, resp.StatusCode)
+		_ = resp.Body.Close()
+		return nil, utils.UnexpectedStatus(resp.StatusCode)
 	}
 	return resp.Body, nil
 }
@@ -79,3 +115,18 @@ func (s Source) GetFileContents(path string) (io.ReadCloser, error) {
 func (s Source) BuildURL(path string) (string, error) {
 	return url.JoinPath(s.BaseURL, path)
 }
+
+// String describes the source
+func (s Source) String() string {
+	return s.BaseURL
+}
+
+// Download retrieves the artifact into dir. Artifacts without a URL are located by treating their name as a path
+// relative to the source's BaseURL
+func (s Source) Download(artifact sources.Artifact, dir string) (string, error) {
+	if artifact.URL == "" {
+		return s.DownloadFile(artifact.Name, dir)
+	}
+	filePath := filepath.Join(dir, filepath.Base(artifact.Name))
+	return filePath, download(artifact.URL, filePath)
+}
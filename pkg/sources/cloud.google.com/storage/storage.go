@@ -4,13 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 
 	"cloud.google.com/go/storage"
+	"github.com/openshift/backplane-tools/pkg/sources"
 	"github.com/openshift/backplane-tools/pkg/utils"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
@@ -22,9 +27,65 @@ type Source struct {
 	client storage.Client
 }
 
+// Options customizes how a Source accesses its bucket
+type Options struct {
+	// Endpoint is the base URL of the JSON API of a server used in place of storage.googleapis.com, such as an
+	// emulator or proxy implementing the same API (ie - "http://localhost:4443/storage/v1/"). If empty, the default
+	// endpoint is used
+	Endpoint string
+
+	// HTTPClient performs the Source's requests. If nil, the client returned by utils.HTTPClient is used
+	HTTPClient *http.Client
+
+	// Authenticated sends the application default credentials (ie - those created by
+	// 'gcloud auth application-default login') with each request. Public buckets are accessed anonymously by default
+	Authenticated bool
+}
+
 // NewSource creates a Source given the google cloud bucket's name
 func NewSource(bucketName string) (*Source, error) {
-	client, err := storage.NewClient(context.TODO(), option.WithoutAuthentication())
+	return NewSourceWithOptions(bucketName, Options{})
+}
+
+// NewSourceWithEndpoint creates a Source retrieving the google cloud bucket from a server other than
+// storage.googleapis.com, such as an emulator implementing the same API. The endpoint is the base URL of
+// the server's JSON API (ie - "http://localhost:4443/storage/v1/"). If empty, the default endpoint is used
+func NewSourceWithEndpoint(bucketName, endpoint string) (*Source, error) {
+	return NewSourceWithOptions(bucketName, Options{Endpoint: endpoint})
+}
+
+// NewSourceWithOptions creates a Source accessing the google cloud bucket as described by the provided Options
+func NewSourceWithOptions(bucketName string, options Options) (*Source, error) {
+	httpClient := options.HTTPClient
+	if httpClient == nil {
+		httpClient = utils.HTTPClient()
+	}
+	if options.Authenticated {
+		// The provided client is used as-is, so credentials must be added by its transport
+		tokenSource, err := google.DefaultTokenSource(context.TODO(), storage.ScopeReadOnly)
+		if err != nil {
+			return &Source{}, fmt.Errorf("failed to find application default credentials: %w", err)
+		}
+		base := httpClient.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		httpClient = &http.Client{
+			Transport:     &oauth2.Transport{Source: tokenSource, Base: base},
+			CheckRedirect: httpClient.CheckRedirect,
+			Jar:           httpClient.Jar,
+			Timeout:       httpClient.Timeout,
+		}
+	}
+
+	opts := []option.ClientOption{option.WithHTTPClient(httpClient)}
+	if !options.Authenticated {
+		opts = append(opts, option.WithoutAuthentication())
+	}
+	if options.Endpoint != "" {
+		opts = append(opts, option.WithEndpoint(options.Endpoint))
+	}
+	client, err := storage.NewClient(context.TODO(), opts...)
 	if err != nil {
 		return &Source{}, err
 	}
@@ -39,30 +100,72 @@ func (s *Source) bucket() *storage.BucketHandle {
 	return s.client.Bucket(s.bucketName)
 }
 
+// MaxListedObjects bounds the number of objects a single listing may return, so that an overly broad prefix fails
+// quickly rather than iterating an entire bucket
+const MaxListedObjects = 10000
+
+// versionInName matches the first dot-separated version embedded in an object's name or prefix (ie - "457.0.0" in
+// "google-cloud-cli-457.0.0-linux-x86_64.tar.gz", or "457" in "google-cloud-cli-457.")
+var versionInName = regexp.MustCompile(`\d+(\.\d+)*`)
+
 // ListObjects fetches all objects in the Source's bucket matching the provided prefix
 // Objects are returned in lexigraphical order
 func (s *Source) ListObjects(prefix string) ([]*storage.ObjectAttrs, error) {
+	objs, _, err := s.list(prefix, "")
+	return objs, err
+}
+
+// ListPrefixes returns the distinct prefixes of the names of the objects matching the provided prefix, up to and
+// including the first occurrence of delimiter after the prefix. This allows a bucket to be explored without listing
+// each of its objects (ie - listing "google-cloud-cli-" with the delimiter "." returns a prefix for each major version)
+func (s *Source) ListPrefixes(prefix, delimiter string) ([]string, error) {
+	_, prefixes, err := s.list(prefix, delimiter)
+	return prefixes, err
+}
+
+// list fetches the objects matching the provided prefix and, when a delimiter is provided, the prefixes grouping the
+// objects whose names contain it
+func (s *Source) list(prefix, delimiter string) ([]*storage.ObjectAttrs, []string, error) {
 	query := storage.Query{
-		Prefix: prefix,
+		Prefix:    prefix,
+		Delimiter: delimiter,
 	}
 	err := query.SetAttrSelection([]string{"Name"})
 	if err != nil {
-		return []*storage.ObjectAttrs{}, fmt.Errorf("failed to set attribute selection for query: %w", err)
+		return []*storage.ObjectAttrs{}, []string{}, fmt.Errorf("failed to set attribute selection for query: %w", err)
 	}
 	it := s.bucket().Objects(context.TODO(), &query)
 
 	objs := []*storage.ObjectAttrs{}
+	prefixes := []string{}
 	for {
 		attrs, err := it.Next()
 		if errors.Is(err, iterator.Done) {
 			break
 		}
 		if err != nil {
-			return []*storage.ObjectAttrs{}, fmt.Errorf("error while listing bucket objects: %w", err)
+			return []*storage.ObjectAttrs{}, []string{}, fmt.Errorf("error while listing bucket objects: %w", err)
+		}
+		if len(objs)+len(prefixes) >= MaxListedObjects {
+			return []*storage.ObjectAttrs{}, []string{}, fmt.Errorf("listing objects with prefix '%s' in bucket '%s' returned more than %d results", prefix, s.bucketName, MaxListedObjects)
+		}
+		if attrs.Prefix != "" {
+			prefixes = append(prefixes, attrs.Prefix)
+			continue
 		}
 		objs = append(objs, attrs)
 	}
-	return objs, nil
+	return objs, prefixes, nil
+}
+
+// ObjectAttrs fetches the complete attributes of the named object, including the hashes published for its contents.
+// Listing objects only retrieves their names
+func (s *Source) ObjectAttrs(name string) (*storage.ObjectAttrs, error) {
+	attrs, err := s.bucket().Object(name).Attrs(context.TODO())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve attributes of object '%s' in bucket '%s': %w", name, s.bucketName, err)
+	}
+	return attrs, nil
 }
 
 func (s *Source) DownloadObject(obj *storage.ObjectAttrs, dir string) error {
@@ -84,12 +187,12 @@ func (s *Source) DownloadObject(obj *storage.ObjectAttrs, dir string) error {
 		}
 	}()
 
-	err = file.Chmod(0o755)
+	err = file.Chmod(utils.FileMode(utils.DataMode))
 	if err != nil {
 		return fmt.Errorf("failed to set permission on '%s': %w", filePath, err)
 	}
 
-	_, err = file.ReadFrom(objReader)
+	_, err = file.ReadFrom(utils.ExpectSize(utils.CountDownload(objReader), objReader.Attrs.Size))
 	if err != nil {
 		return fmt.Errorf("failed to read object '%s' from bucket '%s': %w", obj.Name, s.bucketName, err)
 	}
@@ -97,7 +200,7 @@ func (s *Source) DownloadObject(obj *storage.ObjectAttrs, dir string) error {
 }
 
 // FindObjectsForOS searches the provided list of objects and returns the subset, if any, whose name
-// contains references to the local system's OS, as defined by runtime.GOOS, in addition to
+// contains references to the target OS, as defined by utils.TargetOS, in addition to
 // any well-known alternative names for the architecture
 func (s *Source) FindObjectsForOS(objs []*storage.ObjectAttrs) []*storage.ObjectAttrs {
 	matches := []*storage.ObjectAttrs{}
@@ -110,7 +213,7 @@ func (s *Source) FindObjectsForOS(objs []*storage.ObjectAttrs) []*storage.Object
 }
 
 // FindObjectsForArch searches the provided list of objects and returns the subset, if any, whose name
-// contains references to the local system's architecture, as defined by runtime.GOARCH, in addition to
+// contains references to the target architecture, as defined by utils.TargetArch, in addition to
 // any well-known alternative names for the architecture
 func (s *Source) FindObjectsForArch(objs []*storage.ObjectAttrs) []*storage.ObjectAttrs {
 	matches := []*storage.ObjectAttrs{}
@@ -123,23 +226,53 @@ func (s *Source) FindObjectsForArch(objs []*storage.ObjectAttrs) []*storage.Obje
 }
 
 // FindObjectsForArchAndOS searches the provided list of assets and returns the subset, if any, matching
-// the local architecture and OS, as defined by runtime.GOARCH and runtime.GOOS, respectively. In addition
+// the target architecture and OS, as defined by utils.TargetArch and utils.TargetOS, respectively. In addition
 // to these values, well-known alternatives are also used when searching.
 func (s *Source) FindObjectsForArchAndOS(objs []*storage.ObjectAttrs) []*storage.ObjectAttrs {
 	return s.FindObjectsForOS(s.FindObjectsForArch(objs))
 }
 
+// FindLatest returns the object with the greatest version embedded in its name, or nil if no objects are provided.
+// Versions are compared numerically, so that "100.0.0" is newer than "99.0.0". Objects with the same version, or
+// without a version, are ordered by name
 func (s *Source) FindLatest(objs []*storage.ObjectAttrs) *storage.ObjectAttrs {
-	names := []string{}
-	for _, obj := range objs {
-		names = append(names, obj.Name)
+	if len(objs) == 0 {
+		return nil
 	}
-	sort.Strings(names)
-	latest := names[len(names)-1]
-	for _, obj := range objs {
-		if obj.Name == latest {
-			return obj
-		}
+	sorted := append([]*storage.ObjectAttrs{}, objs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return CompareNames(sorted[i].Name, sorted[j].Name) < 0
+	})
+	return sorted[len(sorted)-1]
+}
+
+// CompareNames compares the versions embedded in the provided object names or prefixes, returning -1 if a is older
+// than b, 1 if a is newer than b, and 0 if they are the same. Names with the same version, or without a version,
+// are compared lexicographically
+func CompareNames(a, b string) int {
+	aVersion, bVersion := versionInName.FindString(a), versionInName.FindString(b)
+	switch {
+	case aVersion == "" && bVersion != "":
+		return -1
+	case aVersion != "" && bVersion == "":
+		return 1
+	}
+	if c := utils.CompareVersions(aVersion, bVersion); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// String describes the source
+func (s *Source) String() string {
+	return "gs://" + s.bucketName
+}
+
+// Download retrieves the object with the artifact's name into dir
+func (s *Source) Download(artifact sources.Artifact, dir string) (string, error) {
+	err := s.DownloadObject(&storage.ObjectAttrs{Name: artifact.Name}, dir)
+	if err != nil {
+		return "", err
 	}
-	panic(fmt.Sprintf("cannot find previously present object %s. All storage objects: %#v", latest, objs))
+	return filepath.Join(dir, artifact.Name), nil
 }
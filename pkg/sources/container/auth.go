@@ -0,0 +1,162 @@
+package container
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// authFiles returns the locations searched for registry credentials, in order of preference. These are the files
+// written by 'podman login' and 'docker login'
+func authFiles() []string {
+	files := []string{}
+	if path := os.Getenv("REGISTRY_AUTH_FILE"); path != "" {
+		files = append(files, path)
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		files = append(files, filepath.Join(dir, "containers", "auth.json"))
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		files = append(files, filepath.Join(dir, "containers", "auth.json"))
+	}
+	if dir, err := os.UserHomeDir(); err == nil {
+		files = append(files, filepath.Join(dir, ".docker", "config.json"))
+	}
+	return files
+}
+
+// credentials returns the username and password stored for the registry by 'podman login' or 'docker login'. If
+// none are stored, empty strings are returned and the registry is accessed anonymously
+func credentials(registry string) (string, string) {
+	for _, path := range authFiles() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				fmt.Printf("WARNING: failed to read registry credentials from '%s': %v\n", path, err)
+			}
+			continue
+		}
+		config := struct {
+			Auths map[string]struct {
+				Auth string `json:"auth"`
+			} `json:"auths"`
+		}{}
+		err = json.Unmarshal(data, &config)
+		if err != nil {
+			fmt.Printf("WARNING: failed to parse registry credentials from '%s': %v\n", path, err)
+			continue
+		}
+		for host, entry := range config.Auths {
+			if normalizeRegistry(host) != registry {
+				continue
+			}
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				continue
+			}
+			username, password, found := strings.Cut(string(decoded), ":")
+			if found {
+				return username, password
+			}
+		}
+	}
+	return "", ""
+}
+
+// normalizeRegistry converts the keys used in credential files - which may be URLs - to registry host names
+func normalizeRegistry(host string) string {
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+	if host == "index.docker.io" || host == "docker.io" {
+		return dockerHubRegistry
+	}
+	return host
+}
+
+// authenticate obtains authorization for the request in response to the registry's challenge, returning the value
+// of the Authorization header to retry with
+func (s *Source) authenticate(challenge string) (string, error) {
+	username, password := credentials(s.Registry)
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if username == "" {
+			return "", fmt.Errorf("registry '%s' requires authentication: log in using 'podman login %s'", s.Registry, s.Registry)
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password)), nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("unsupported authentication scheme '%s' for registry '%s'", scheme, s.Registry)
+	}
+
+	values := parseChallenge(params)
+	realm := values["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("registry '%s' did not specify where to obtain a token", s.Registry)
+	}
+	query := url.Values{}
+	if service := values["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull", s.Repository))
+	req, err := http.NewRequest(http.MethodGet, realm+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+	resp, err := utils.HTTPClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request token from '%s': %w", realm, err)
+	}
+	defer func() {
+		closeErr := resp.Body.Close()
+		if closeErr != nil {
+			fmt.Printf("WARNING: failed to close response body: %v\n", closeErr)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		if username == "" {
+			return "", fmt.Errorf("registry '%s' refused anonymous access (status %d): log in using 'podman login %s'", s.Registry, resp.StatusCode, s.Registry)
+		}
+		return "", fmt.Errorf("registry '%s' refused the stored credentials: received status %d", s.Registry, resp.StatusCode)
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode token from '%s': %w", realm, err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	return "Bearer " + token.Token, nil
+}
+
+// parseChallenge parses the comma-separated, quoted parameters of a WWW-Authenticate header
+func parseChallenge(params string) map[string]string {
+	values := map[string]string{}
+	for params != "" {
+		var key, value string
+		key, params, _ = strings.Cut(strings.TrimLeft(params, ", "), "=")
+		if strings.HasPrefix(params, `"`) {
+			value, params, _ = strings.Cut(params[1:], `"`)
+		} else {
+			value, params, _ = strings.Cut(params, ",")
+		}
+		values[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+	return values
+}
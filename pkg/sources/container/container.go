@@ -0,0 +1,418 @@
+/*
+container provides the capability for tools to retrieve files from within container images
+*/
+package container
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/sources"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// dockerHubRegistry serves images referenced without a registry
+const dockerHubRegistry = "registry-1.docker.io"
+
+const (
+	mediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerList     = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeOCIManifest    = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeOCIIndex       = "application/vnd.oci.image.index.v1+json"
+)
+
+// maxManifestSize bounds the size of the manifests and configs read from a registry
+const maxManifestSize = 4 << 20
+
+// maxLinks bounds the number of symlinks followed when extracting a file
+const maxLinks = 10
+
+// Source objects retrieve files from a container image
+type Source struct {
+	// Registry is the host serving the image
+	Registry string
+	// Repository is the image's name within the registry
+	Repository string
+	// Reference is the tag or digest of the image
+	Reference string
+	// Platform selects the image from multi-platform images, formatted as <os>/<architecture>. Defaults to
+	// linux and the target architecture, as defined by utils.TargetArch
+	Platform string
+
+	// authorization is the value of the Authorization header sent to the registry
+	authorization string
+}
+
+// Image describes a single-platform image
+type Image struct {
+	// Digest is the digest of the image's manifest
+	Digest string
+	// Labels contains the labels set on the image
+	Labels map[string]string
+
+	layers []descriptor
+}
+
+// descriptor refers to content stored in a registry
+type descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+	Platform  *struct {
+		OS           string `json:"os"`
+		Architecture string `json:"architecture"`
+	} `json:"platform,omitempty"`
+}
+
+// manifest is an image manifest or, for multi-platform images, an index of manifests
+type manifest struct {
+	MediaType string       `json:"mediaType"`
+	Config    descriptor   `json:"config"`
+	Layers    []descriptor `json:"layers"`
+	Manifests []descriptor `json:"manifests"`
+}
+
+// NewSource creates a Source for the provided image reference (ie - "registry.redhat.io/openshift4/ose-cli:latest").
+// Images without a registry are retrieved from Docker Hub, and images without a tag or digest use the 'latest' tag
+func NewSource(image string) (*Source, error) {
+	s := &Source{Registry: dockerHubRegistry}
+	name, digest, isDigest := strings.Cut(image, "@")
+	s.Reference = digest
+	if !isDigest {
+		s.Reference = "latest"
+		slash := strings.LastIndex(name, "/")
+		if colon := strings.LastIndex(name, ":"); colon > slash {
+			name, s.Reference = name[:colon], name[colon+1:]
+		}
+	}
+
+	first, rest, found := strings.Cut(name, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		s.Registry = normalizeRegistry(first)
+		name = rest
+	} else if !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	s.Repository = name
+	if s.Repository == "" || s.Reference == "" {
+		return nil, fmt.Errorf("invalid image reference '%s'", image)
+	}
+	return s, nil
+}
+
+// platform returns the os and architecture of the image selected from multi-platform images
+func (s *Source) platform() (string, string) {
+	if s.Platform != "" {
+		goos, arch, _ := strings.Cut(s.Platform, "/")
+		return goos, arch
+	}
+	return "linux", utils.TargetArch
+}
+
+// get retrieves the named manifest or blob from the image's repository, authenticating if required.
+// It is the caller's responsibility to close the returned body
+func (s *Source) get(kind, reference string, accept ...string) (*http.Response, error) {
+	url := fmt.Sprintf("https://%s/v2/%s/%s/%s", s.Registry, s.Repository, kind, reference)
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request for '%s': %w", url, err)
+		}
+		if len(accept) > 0 {
+			req.Header.Set("Accept", strings.Join(accept, ", "))
+		}
+		if s.authorization != "" {
+			req.Header.Set("Authorization", s.authorization)
+		}
+		resp, err := utils.HTTPClient().Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to GET '%s': %w", url, err)
+		}
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+		_ = resp.Body.Close()
+
+		challenge := resp.Header.Get("WWW-Authenticate")
+		if resp.StatusCode != http.StatusUnauthorized || challenge == "" || attempt > 0 {
+			return nil, fmt.Errorf("received non-%d status code from '%s': %d", http.StatusOK, url, resp.StatusCode)
+		}
+		s.authorization, err = s.authenticate(challenge)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// getJSON decodes the named manifest or blob into v, returning its digest
+func (s *Source) getJSON(kind, reference string, v any, accept ...string) (string, error) {
+	resp, err := s.get(kind, reference, accept...)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		closeErr := resp.Body.Close()
+		if closeErr != nil {
+			fmt.Printf("WARNING: failed to close response body: %v\n", closeErr)
+		}
+	}()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s '%s': %w", strings.TrimSuffix(kind, "s"), reference, err)
+	}
+	sum := sha256.Sum256(data)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	if strings.HasPrefix(reference, "sha256:") && reference != digest {
+		return "", fmt.Errorf("%w for %s '%s': got digest '%s'", utils.ErrChecksumMismatch, strings.TrimSuffix(kind, "s"), reference, digest)
+	}
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode %s '%s': %w", strings.TrimSuffix(kind, "s"), reference, err)
+	}
+	return digest, nil
+}
+
+// FetchImage retrieves the manifest and configuration of the image. For multi-platform images, the image matching
+// the Source's Platform is selected
+func (s *Source) FetchImage() (*Image, error) {
+	m := manifest{}
+	digest, err := s.getJSON("manifests", s.Reference, &m, mediaTypeOCIIndex, mediaTypeDockerList, mediaTypeOCIManifest, mediaTypeDockerManifest)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.MediaType == mediaTypeOCIIndex || m.MediaType == mediaTypeDockerList || (m.MediaType == "" && len(m.Manifests) > 0) {
+		wantOS, wantArch := s.platform()
+		selected := ""
+		for _, entry := range m.Manifests {
+			if entry.Platform != nil && entry.Platform.OS == wantOS && entry.Platform.Architecture == wantArch {
+				selected = entry.Digest
+				break
+			}
+		}
+		if selected == "" {
+			return nil, fmt.Errorf("image '%s' is not available for %s/%s", s, wantOS, wantArch)
+		}
+		m = manifest{}
+		digest, err = s.getJSON("manifests", selected, &m, mediaTypeOCIManifest, mediaTypeDockerManifest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	config := struct {
+		Config struct {
+			Labels map[string]string `json:"Labels"`
+		} `json:"config"`
+	}{}
+	_, err = s.getJSON("blobs", m.Config.Digest, &config)
+	if err != nil {
+		return nil, err
+	}
+	return &Image{Digest: digest, Labels: config.Config.Labels, layers: m.Layers}, nil
+}
+
+// Version describes the image's version using its 'version' and 'release' labels, as set on Red Hat images. Images
+// without a version label are identified by their digest
+func (i *Image) Version() string {
+	version := i.Labels["version"]
+	if release := i.Labels["release"]; version != "" && release != "" {
+		version = version + "-" + release
+	}
+	if version == "" || strings.ContainsAny(version, `/\`) || version == "." || version == ".." {
+		return strings.TrimPrefix(i.Digest, "sha256:")[:12]
+	}
+	return version
+}
+
+// Extract copies the file at the provided path within the image into dir, returning the location of the copy.
+// The file is located by searching the image's layers from the top down, following any symlinks within the image.
+// The digest of each layer read is verified
+func (s *Source) Extract(image *Image, filePath, dir string) (string, error) {
+	target := cleanPath(filePath)
+	// The copy is named after the requested path, rather than the file any symlinks resolve to
+	dest := filepath.Join(dir, path.Base(target))
+	for links := 0; links <= maxLinks; links++ {
+		var linkTarget string
+		for i := len(image.layers) - 1; i >= 0; i-- {
+			result, err := s.searchLayer(image.layers[i], target, dest)
+			if err != nil {
+				return "", err
+			}
+			if result.extracted {
+				return dest, nil
+			}
+			if result.deleted {
+				return "", fmt.Errorf("'%s' was removed from image '%s'", filePath, s)
+			}
+			if result.link != "" {
+				linkTarget = result.link
+				break
+			}
+		}
+		if linkTarget == "" {
+			return "", fmt.Errorf("%w: '%s' not found in image '%s'", utils.ErrAssetNotFound, filePath, s)
+		}
+		target = linkTarget
+	}
+	return "", fmt.Errorf("too many symlinks while resolving '%s' in image '%s'", filePath, s)
+}
+
+// searchResult describes the outcome of searching a single layer for a file
+type searchResult struct {
+	// extracted is true if the file was found and copied
+	extracted bool
+	// deleted is true if the layer removes the file
+	deleted bool
+	// link is the path the file is a symlink to, if it's a symlink
+	link string
+}
+
+// searchLayer searches the layer for the file at target, copying it to dest if found
+func (s *Source) searchLayer(layer descriptor, target, dest string) (searchResult, error) {
+	resp, err := s.get("blobs", layer.Digest)
+	if err != nil {
+		return searchResult{}, err
+	}
+	defer func() {
+		closeErr := resp.Body.Close()
+		if closeErr != nil {
+			fmt.Printf("WARNING: failed to close response body: %v\n", closeErr)
+		}
+	}()
+	hasher := sha256.New()
+	blob := io.TeeReader(utils.ExpectSize(utils.CountDownload(resp.Body), layer.Size), hasher)
+
+	format := utils.ArchiveFormatGzip
+	switch {
+	case strings.HasSuffix(layer.MediaType, "+zstd") || strings.HasSuffix(layer.MediaType, ".zstd"):
+		format = utils.ArchiveFormatZstd
+	case strings.HasSuffix(layer.MediaType, ".tar") || strings.HasSuffix(layer.MediaType, "layer.v1.tar"):
+		format = utils.ArchiveFormatTar
+	}
+	contents, err := utils.Decompress(format, blob)
+	if err != nil {
+		return searchResult{}, fmt.Errorf("failed to decompress layer '%s': %w", layer.Digest, err)
+	}
+
+	result, err := searchTar(tar.NewReader(contents), target, dest)
+	closeErr := contents.Close()
+	if err != nil {
+		return searchResult{}, fmt.Errorf("failed to read layer '%s': %w", layer.Digest, err)
+	}
+	if closeErr != nil {
+		return searchResult{}, fmt.Errorf("failed to decompress layer '%s': %w", layer.Digest, closeErr)
+	}
+
+	// Read any remaining data so the layer's digest can be verified
+	_, err = io.Copy(io.Discard, blob)
+	if err != nil {
+		return searchResult{}, fmt.Errorf("failed to read layer '%s': %w", layer.Digest, err)
+	}
+	err = verifyDigest(layer.Digest, hasher)
+	if err != nil {
+		if result.extracted {
+			_ = os.Remove(dest)
+		}
+		return searchResult{}, err
+	}
+	return result, nil
+}
+
+// searchTar reads the layer's entries, copying target to dest if found
+func searchTar(arc *tar.Reader, target, dest string) (searchResult, error) {
+	result := searchResult{}
+	for {
+		header, err := arc.Next()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return result, err
+		}
+		name := cleanPath(header.Name)
+
+		// Whiteouts remove a file, or everything within a directory, from the layers below
+		dir, base := path.Split(name)
+		dir = strings.TrimSuffix(dir, "/")
+		if base == ".wh..wh..opq" && (dir == "" || strings.HasPrefix(target, dir+"/")) {
+			result.deleted = true
+		}
+		if strings.HasPrefix(base, ".wh.") && path.Join(dir, strings.TrimPrefix(base, ".wh.")) == target {
+			result.deleted = true
+		}
+		if name != target {
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeReg:
+			err = utils.WriteFile(arc, dest, utils.ExecutableMode)
+			if err != nil {
+				return result, err
+			}
+			return searchResult{extracted: true}, nil
+		case tar.TypeSymlink:
+			link := header.Linkname
+			if !path.IsAbs(link) {
+				link = path.Join(path.Dir(target), link)
+			}
+			return searchResult{link: cleanPath(link)}, nil
+		case tar.TypeLink:
+			return searchResult{link: cleanPath(header.Linkname)}, nil
+		default:
+			return result, fmt.Errorf("'%s' is not a regular file", target)
+		}
+	}
+}
+
+// verifyDigest compares the digest to the hash of the data read
+func verifyDigest(digest string, hasher hash.Hash) error {
+	actual := "sha256:" + hex.EncodeToString(hasher.Sum(nil))
+	if actual != digest {
+		return fmt.Errorf("%w for layer: expected digest '%s', got '%s'", utils.ErrChecksumMismatch, digest, actual)
+	}
+	return nil
+}
+
+// cleanPath converts the path to the form used within layers: relative to the image's root
+func cleanPath(p string) string {
+	return strings.TrimPrefix(path.Clean("/"+p), "/")
+}
+
+// String describes the source
+func (s *Source) String() string {
+	if strings.HasPrefix(s.Reference, "sha256:") {
+		return fmt.Sprintf("%s/%s@%s", s.Registry, s.Repository, s.Reference)
+	}
+	return fmt.Sprintf("%s/%s:%s", s.Registry, s.Repository, s.Reference)
+}
+
+// ResolveLatest returns the version of the image the Source refers to
+func (s *Source) ResolveLatest() (string, error) {
+	image, err := s.FetchImage()
+	if err != nil {
+		return "", err
+	}
+	return image.Version(), nil
+}
+
+// Download extracts the file whose path within the image is the artifact's name into dir
+func (s *Source) Download(artifact sources.Artifact, dir string) (string, error) {
+	image, err := s.FetchImage()
+	if err != nil {
+		return "", err
+	}
+	return s.Extract(image, artifact.Name, dir)
+}
@@ -0,0 +1,132 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v51/github"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// maxCachedAssets bounds how many release assets are cached per repository. The least recently downloaded assets
+// are removed first
+const maxCachedAssets = 10
+
+// assetCacheDir returns the directory the repository's release assets are cached in, or an empty string if nothing
+// is cached
+func (s Source) assetCacheDir() string {
+	if CacheDir == "" {
+		return ""
+	}
+	// Match the directory release metadata is cached in, which is named after the API's host name
+	host := s.Host
+	if host == "" {
+		host = apiHost
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	return filepath.Join(CacheDir, host, s.Owner, s.Repo, "assets")
+}
+
+// assetCachePath returns the path the provided asset is cached at, or an empty string if nothing is cached
+func (s Source) assetCachePath(asset *github.ReleaseAsset) string {
+	dir := s.assetCacheDir()
+	if dir == "" || asset.GetName() != filepath.Base(asset.GetName()) {
+		return ""
+	}
+	return filepath.Join(dir, strconv.FormatInt(asset.GetID(), 10), asset.GetName())
+}
+
+// cacheAsset stores a copy of the downloaded asset, so that it can be reused when GitHub is unavailable. Failures
+// are reported, but don't fail the download
+func (s Source) cacheAsset(asset *github.ReleaseAsset, filePath string) {
+	cachePath := s.assetCachePath(asset)
+	if cachePath == "" {
+		return
+	}
+	err := os.MkdirAll(filepath.Dir(cachePath), os.FileMode(0o755))
+	if err == nil {
+		err = copyAsset(filePath, cachePath)
+	}
+	if err == nil {
+		// Mark the asset as recently downloaded, even if it was already cached
+		now := time.Now()
+		err = os.Chtimes(filepath.Dir(cachePath), now, now)
+	}
+	if err != nil {
+		fmt.Printf("WARNING: failed to cache GitHub release asset '%s': %v\n", asset.GetName(), err)
+		return
+	}
+	s.pruneAssets()
+}
+
+// pruneAssets removes the least recently downloaded assets from the repository's cache, until at most
+// maxCachedAssets remain
+func (s Source) pruneAssets() {
+	dir := s.assetCacheDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) <= maxCachedAssets {
+		return
+	}
+	modified := map[string]time.Time{}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		modified[entry.Name()] = info.ModTime()
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return modified[entries[i].Name()].After(modified[entries[j].Name()])
+	})
+	for _, entry := range entries[maxCachedAssets:] {
+		err = os.RemoveAll(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			fmt.Printf("WARNING: failed to remove cached GitHub release asset '%s': %v\n", entry.Name(), err)
+		}
+	}
+}
+
+// cachedAsset copies the cached copy of the provided asset to filePath. found is false if the asset isn't cached
+func (s Source) cachedAsset(asset *github.ReleaseAsset, filePath string) (found bool, err error) {
+	cachePath := s.assetCachePath(asset)
+	if cachePath == "" {
+		return false, nil
+	}
+	info, err := os.Stat(cachePath)
+	if err != nil || (asset.GetSize() != 0 && info.Size() != int64(asset.GetSize())) {
+		return false, nil
+	}
+	fmt.Printf("WARNING: GitHub is unavailable, using the copy of release asset '%s' downloaded %s ago. It will still be verified before it's installed\n", asset.GetName(), time.Since(info.ModTime()).Round(time.Minute))
+	return true, copyAsset(cachePath, filePath)
+}
+
+// copyAsset copies the file at src to dst
+func copyAsset(src, dst string) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %w", src, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	return utils.WriteFile(file, dst, utils.DataMode)
+}
+
+// isUnavailable determines whether the provided error indicates GitHub couldn't be reached, or couldn't serve the
+// request: either due to a server error, or because the rate limit was exceeded
+func isUnavailable(err error) bool {
+	if errors.Is(err, utils.ErrNetwork) || errors.Is(err, utils.ErrRateLimited) {
+		return true
+	}
+	var responseErr *github.ErrorResponse
+	return errors.As(err, &responseErr) && responseErr.Response != nil && responseErr.Response.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,141 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/google/go-github/v51/github"
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/keyring"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// host is the GitHub server tools are retrieved from, and the account its token is stored under in the keyring
+const host = "github.com"
+
+// apiHost serves GitHub's API. Tokens are only sent to this host: release assets are downloaded by following a
+// redirect to a pre-signed URL, which rejects requests carrying any other authentication
+const apiHost = "api.github.com"
+
+// tokenEnvVars are the environment variables consulted for a GitHub token before the keyring
+var tokenEnvVars = []string{"GH_TOKEN", "GITHUB_TOKEN"}
+
+// token returns the token used to authenticate requests to GitHub, or an empty string if none is available. A token
+// set in the environment is preferred, followed by one stored in the keyring by 'backplane-tools login github', and
+// finally the gh CLI's token. The token is only looked up once, as the keyring is accessed via a separate command
+var token = sync.OnceValue(lookupToken)
+
+// lookupToken finds the token used to authenticate requests to GitHub, as described by token
+func lookupToken() string {
+	for _, envVar := range tokenEnvVars {
+		if value := os.Getenv(envVar); value != "" {
+			return value
+		}
+	}
+	value, err := keyring.Get(host)
+	if err == nil {
+		return value
+	}
+	if !errors.Is(err, keyring.ErrNotFound) {
+		fmt.Printf("WARNING: %v\n", err)
+	}
+	value, _ = auth.TokenForHost(host)
+	return value
+}
+
+// resetToken discards the token previously looked up, so that it's looked up again when next needed
+func resetToken() {
+	token = sync.OnceValue(lookupToken)
+}
+
+// Authenticated returns true if a token is available to authenticate requests to github.com
+func Authenticated() bool {
+	return token() != ""
+}
+
+// authTransport authenticates each request with the token returned by its token func, if any. The token is retrieved
+// when the first request is made, rather than when the client is created, so that commands which never contact
+// GitHub don't access the keyring
+type authTransport struct {
+	// host is the host serving the API tokens are sent to
+	host  string
+	token func() string
+	next  http.RoundTripper
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Hostname() != t.host {
+		return t.next.RoundTrip(req)
+	}
+	if token := t.token(); token != "" {
+		// RoundTrippers must not modify the request they're provided
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// newClient returns a client for GitHub's API, authenticated with the token returned by the provided func
+func newClient(token func() string) *github.Client {
+	tc := utils.HTTPClient()
+	tc.Transport = &authTransport{
+		host:  apiHost,
+		token: token,
+		next:  &cacheTransport{next: &rateLimitTransport{next: tc.Transport}},
+	}
+	return github.NewClient(tc)
+}
+
+// newEnterpriseClient returns a client for the API of the GitHub Enterprise server at the provided URL, authenticated
+// with the token returned by the provided func
+func newEnterpriseClient(serverURL *url.URL, token func() string) (*github.Client, error) {
+	tc := utils.HTTPClient()
+	tc.Transport = &authTransport{
+		host:  serverURL.Hostname(),
+		token: token,
+		next:  &cacheTransport{next: &rateLimitTransport{next: tc.Transport}},
+	}
+	return github.NewEnterpriseClient(serverURL.String(), serverURL.String(), tc)
+}
+
+// enterpriseRepositoryToken returns a func retrieving the token used to access the provided repository on a GitHub
+// Enterprise server. Only tokens configured for the repository are used, as tokens for github.com aren't valid there
+func enterpriseRepositoryToken(owner, repo string) func() string {
+	return func() string {
+		value, _ := config.Get().GitHubToken(owner, repo)
+		return value
+	}
+}
+
+// repositoryToken returns a func retrieving the token used to access the provided repository: the token configured
+// for the repository, if any, or the token used for GitHub generally
+func repositoryToken(owner, repo string) func() string {
+	return func() string {
+		if value, found := config.Get().GitHubToken(owner, repo); found {
+			return value
+		}
+		return token()
+	}
+}
+
+// Login validates the provided token against GitHub and stores it in the keyring, so that it's used to authenticate
+// future requests. The login of the user the token belongs to is returned
+func Login(token string) (string, error) {
+	user, _, err := newClient(func() string { return token }).Users.Get(context.TODO(), "")
+	if err != nil {
+		return "", fmt.Errorf("failed to validate token: %w", describeRateLimit(err))
+	}
+	err = keyring.Set(host, token)
+	if err != nil {
+		return "", err
+	}
+	// Look the token up again, so that requests made later in this process use the stored token
+	resetToken()
+	return user.GetLogin(), nil
+}
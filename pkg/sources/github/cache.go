@@ -0,0 +1,239 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheDir is the directory GitHub release and tag metadata is cached in. Responses are revalidated using
+// conditional requests, which GitHub doesn't count against the rate limit when unchanged. If empty, no responses
+// are cached
+var CacheDir = func() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "backplane-tools", "github")
+}()
+
+// cacheMu serializes access to the cache files
+var cacheMu sync.Mutex
+
+// staleWarnings records the cache files a stale response has been reported for, so that each repository is only
+// reported once
+var staleWarnings sync.Map
+
+// cachedResponse is a response stored in the cache
+type cachedResponse struct {
+	// ETag identifies the version of the response
+	ETag string `json:"etag"`
+	// Header contains the response's headers
+	Header http.Header `json:"header"`
+	// Body contains the response's body
+	Body []byte `json:"body"`
+	// Cached is when the response was last retrieved or revalidated
+	Cached time.Time `json:"cached,omitempty"`
+}
+
+// cacheTransport caches the responses to release and tag requests, keyed by repository. Cached responses are
+// revalidated using their ETag and reused when unchanged, or when GitHub is unavailable
+type cacheTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip performs the request, using the cached response where possible
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path, found := cachePath(req)
+	if !found {
+		return t.next.RoundTrip(req)
+	}
+	key := req.URL.String()
+	cached, found := readCache(path)[key]
+
+	if found && cached.ETag != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+	resp, err := t.next.RoundTrip(req)
+	if found && unavailable(resp, err) {
+		reason := describeUnavailable(resp, err)
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		if _, warned := staleWarnings.LoadOrStore(path, true); !warned {
+			fmt.Printf("WARNING: GitHub is unavailable, using release metadata for %s%s, which may be stale: %s\n", strings.TrimSuffix(strings.TrimPrefix(path, CacheDir+string(filepath.Separator)), ".json"), describeAge(cached.Cached), reason)
+		}
+		return cached.response(req), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if found && resp.StatusCode == http.StatusNotModified {
+		_ = resp.Body.Close()
+		cached.Cached = time.Now()
+		err = writeCache(path, key, cached)
+		if err != nil {
+			fmt.Printf("WARNING: failed to cache GitHub response: %v\n", err)
+		}
+		revalidated := cached.response(req)
+		// Report the current rate limit, rather than the one in effect when the response was cached
+		for name, values := range resp.Header {
+			if strings.HasPrefix(name, "X-Ratelimit-") {
+				revalidated.Header[name] = values
+			}
+		}
+		return revalidated, nil
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") == "" {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	header := resp.Header.Clone()
+	for name := range header {
+		if strings.HasPrefix(name, "X-Ratelimit-") {
+			header.Del(name)
+		}
+	}
+	err = writeCache(path, key, cachedResponse{ETag: resp.Header.Get("ETag"), Header: header, Body: body, Cached: time.Now()})
+	if err != nil {
+		fmt.Printf("WARNING: failed to cache GitHub response: %v\n", err)
+	}
+	return resp, nil
+}
+
+// unavailable determines whether the request failed because GitHub couldn't be reached, or couldn't serve it: either
+// due to a server error, or because the rate limit was exceeded
+func unavailable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError, resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode == http.StatusForbidden:
+		return resp.Header.Get("X-RateLimit-Remaining") == "0" || resp.Header.Get("Retry-After") != ""
+	}
+	return false
+}
+
+// describeUnavailable describes why GitHub was unable to serve the request
+func describeUnavailable(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		return "rate limit exceeded"
+	}
+	return "unexpected status " + resp.Status
+}
+
+// describeAge describes how long ago a response was cached. Responses cached before their age was recorded are
+// described without one
+func describeAge(cached time.Time) string {
+	if cached.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf(" cached %s ago", time.Since(cached).Round(time.Minute))
+}
+
+// response rebuilds the cached response as a reply to the provided request
+func (c cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        c.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(c.Body)),
+		ContentLength: int64(len(c.Body)),
+		Request:       req,
+	}
+}
+
+// cachePath returns the cache file for the repository the request refers to. Only requests for release and
+// tag metadata are cached; release asset downloads are not
+func cachePath(req *http.Request) (string, bool) {
+	if CacheDir == "" || req.Method != http.MethodGet || strings.Contains(req.URL.Path, "/releases/assets/") {
+		return "", false
+	}
+	// Paths have the form /repos/<owner>/<repo>/<releases|tags>[/...], beneath /api/v3 on GitHub Enterprise servers
+	segments := strings.Split(strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, "/api/v3"), "/"), "/")
+	if len(segments) < 4 || segments[0] != "repos" || (segments[3] != "releases" && segments[3] != "tags") {
+		return "", false
+	}
+	owner, repo := segments[1], segments[2]
+	if owner == "" || repo == "" || owner == ".." || repo == ".." || owner == "." || repo == "." {
+		return "", false
+	}
+	return filepath.Join(CacheDir, req.URL.Hostname(), owner, repo+".json"), true
+}
+
+// readCache returns the cached responses in the given file. Unreadable caches are treated as empty
+func readCache(path string) map[string]cachedResponse {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	return loadCache(path)
+}
+
+// loadCache reads the cached responses in the given file. cacheMu must be held
+func loadCache(path string) map[string]cachedResponse {
+	entries := map[string]cachedResponse{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			fmt.Printf("WARNING: failed to read GitHub cache '%s': %v\n", path, err)
+		}
+		return entries
+	}
+	err = json.Unmarshal(data, &entries)
+	if err != nil {
+		fmt.Printf("WARNING: ignoring invalid GitHub cache '%s': %v\n", path, err)
+		return map[string]cachedResponse{}
+	}
+	return entries
+}
+
+// writeCache stores the response in the given file, replacing any previous response for the same key. The lock is
+// held from reading the file until it's replaced, so that concurrent lookups don't discard each other's responses
+func writeCache(path, key string, entry cachedResponse) error {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	entries := loadCache(path)
+	entries[key] = entry
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(path), os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create cache directory '%s': %w", filepath.Dir(path), err)
+	}
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, data, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to write '%s': %w", tmp, err)
+	}
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return fmt.Errorf("failed to replace '%s': %w", path, err)
+	}
+	return nil
+}
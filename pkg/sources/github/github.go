@@ -5,14 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
-	"github.com/cli/go-gh/v2/pkg/auth"
 	"github.com/google/go-github/v51/github"
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/sources"
 	"github.com/openshift/backplane-tools/pkg/utils"
-	"golang.org/x/oauth2"
 )
 
 type Source struct {
@@ -22,39 +24,50 @@ type Source struct {
 	// Repo specifies the repository of the tool
 	Repo string
 
+	// Host is the GitHub server hosting the repository. Empty for github.com
+	Host string
+
 	// client is used to interact with GitHub
 	client *github.Client
 }
 
 func NewSource(owner, repo string) *Source {
-	token, _ := auth.TokenForHost("github.com")
-	var tc *http.Client
-	if token != "" {
-		ctx := context.Background()
-		ts := oauth2.StaticTokenSource(
-			&oauth2.Token{AccessToken: token},
-		)
-		tc = oauth2.NewClient(ctx, ts)
-	} else {
-		tc = nil
-	}
 	tool := &Source{
 		Owner:  owner,
 		Repo:   repo,
-		client: github.NewClient(tc),
+		client: newClient(repositoryToken(owner, repo)),
 	}
 	return tool
 }
 
+// NewEnterpriseSource returns a source retrieving the tool from a repository hosted on the GitHub Enterprise server at
+// the provided http(s) URL
+func NewEnterpriseSource(owner, repo, serverURL string) (*Source, error) {
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub Enterprise URL '%s': %w", serverURL, err)
+	}
+	client, err := newEnterpriseClient(parsed, enterpriseRepositoryToken(owner, repo))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for GitHub Enterprise server '%s': %w", serverURL, err)
+	}
+	return &Source{
+		Owner:  owner,
+		Repo:   repo,
+		Host:   parsed.Host,
+		client: client,
+	}, nil
+}
+
 // ListReleases returns all releases of the tool from GitHub
 func (s Source) ListReleases(opts *github.ListOptions) ([]*github.RepositoryRelease, error) {
 	releases, response, err := s.client.Repositories.ListReleases(context.TODO(), s.Owner, s.Repo, opts)
 	if err != nil {
-		return []*github.RepositoryRelease{}, err
+		return []*github.RepositoryRelease{}, s.describeError(err)
 	}
 	err = github.CheckResponse(response.Response)
 	if err != nil {
-		return []*github.RepositoryRelease{}, err
+		return []*github.RepositoryRelease{}, s.describeError(err)
 	}
 	return releases, nil
 }
@@ -63,11 +76,11 @@ func (s Source) ListReleases(opts *github.ListOptions) ([]*github.RepositoryRele
 func (s Source) FetchRelease(releaseID int64) (*github.RepositoryRelease, error) {
 	release, response, err := s.client.Repositories.GetRelease(context.TODO(), s.Owner, s.Repo, releaseID)
 	if err != nil {
-		return &github.RepositoryRelease{}, err
+		return &github.RepositoryRelease{}, s.describeError(err)
 	}
 	err = github.CheckResponse(response.Response)
 	if err != nil {
-		return &github.RepositoryRelease{}, err
+		return &github.RepositoryRelease{}, s.describeError(err)
 	}
 	return release, nil
 }
@@ -76,22 +89,34 @@ func (s Source) FetchRelease(releaseID int64) (*github.RepositoryRelease, error)
 func (s Source) FetchLatestRelease() (*github.RepositoryRelease, error) {
 	release, response, err := s.client.Repositories.GetLatestRelease(context.TODO(), s.Owner, s.Repo)
 	if err != nil {
-		return &github.RepositoryRelease{}, err
+		return &github.RepositoryRelease{}, s.describeError(err)
 	}
 	err = github.CheckResponse(response.Response)
 	if err != nil {
-		return &github.RepositoryRelease{}, err
+		return &github.RepositoryRelease{}, s.describeError(err)
 	}
 	return release, nil
 }
 
-// FetchTags returns the latest tag
+// ListTags returns the repository's tags from GitHub, most recent first
+func (s Source) ListTags(opts *github.ListOptions) ([]*github.RepositoryTag, error) {
+	tags, response, err := s.client.Repositories.ListTags(context.TODO(), s.Owner, s.Repo, opts)
+	if err != nil {
+		return []*github.RepositoryTag{}, s.describeError(err)
+	}
+	err = github.CheckResponse(response.Response)
+	if err != nil {
+		return []*github.RepositoryTag{}, s.describeError(err)
+	}
+	return tags, nil
+}
+
 // FetchLatestTag returns the latest tag
 func (s Source) FetchLatestTag() (string, error) {
 	ctx := context.Background()
 	tags, _, err := s.client.Repositories.ListTags(ctx, s.Owner, s.Repo, nil)
 	if err != nil {
-		return "", err
+		return "", s.describeError(err)
 	}
 	if len(tags) > 0 {
 		return *tags[0].Name, nil
@@ -116,12 +141,35 @@ func (s Source) DownloadReleaseAssets(assets []*github.ReleaseAsset, dir string)
 	return errors.Join(downloadErrors...)
 }
 
+// downloadReleaseAsset downloads the provided asset to the given directory. Downloaded assets are cached, and the
+// cached copy is used instead when GitHub is unavailable
 func (s Source) downloadReleaseAsset(asset *github.ReleaseAsset, dir string) error {
+	filePath := filepath.Join(dir, asset.GetName())
+	err := s.fetchReleaseAsset(asset, filePath)
+	if err == nil {
+		s.cacheAsset(asset, filePath)
+		return nil
+	}
+	if !isUnavailable(err) {
+		return err
+	}
+	found, cacheErr := s.cachedAsset(asset, filePath)
+	if !found {
+		return err
+	}
+	if cacheErr != nil {
+		return fmt.Errorf("%w. The cached copy could not be used either: %w", err, cacheErr)
+	}
+	return nil
+}
+
+// fetchReleaseAsset downloads the provided asset from GitHub to filePath
+func (s Source) fetchReleaseAsset(asset *github.ReleaseAsset, filePath string) error {
 	// Per the documentation for this method (https://pkg.go.dev/github.com/google/go-github/v51/github#RepositoriesService.DownloadReleaseAsset),
 	// a redirectURL will not be returned if an http.Client is provided for the followRedirectsClient argument.
 	reader, _, err := s.client.Repositories.DownloadReleaseAsset(context.TODO(), s.Owner, s.Repo, asset.GetID(), s.client.Client())
 	if err != nil {
-		return err
+		return s.describeError(err)
 	}
 	defer func() {
 		err = reader.Close()
@@ -129,13 +177,21 @@ func (s Source) downloadReleaseAsset(asset *github.ReleaseAsset, dir string) err
 			panic(fmt.Sprintf("failed to close reader from GitHub asset '%s'", asset.GetName()))
 		}
 	}()
-	filePath := filepath.Join(dir, asset.GetName())
+	return utils.WriteFile(utils.ExpectSize(utils.CountDownload(reader), int64(asset.GetSize())), filePath, utils.DataMode)
+}
 
-	return utils.WriteFile(reader, filePath, 0o755)
+// describeError describes the provided error returned by GitHub's API. Requests for private repositories the user
+// isn't authorized to access fail as though the repository doesn't exist, so how to authenticate is described as well
+func (s Source) describeError(err error) error {
+	var responseErr *github.ErrorResponse
+	if errors.As(err, &responseErr) && responseErr.Response != nil && responseErr.Response.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %w. If %s/%s is a private repository, configure a token able to read it under 'github.repositories' in %s", utils.ErrAssetNotFound, err, s.Owner, s.Repo, config.Path)
+	}
+	return describeRateLimit(err)
 }
 
 // FindAssetsForOS searches the provided list of assets and returns the subset, if any, matching
-// the local OS as defined by runtime.GOOS, as well as any well-known alternative names for the OS
+// the target OS as defined by utils.TargetOS, as well as any well-known alternative names for the OS
 func FindAssetsForOS(assets []*github.ReleaseAsset) []*github.ReleaseAsset {
 	matches := []*github.ReleaseAsset{}
 	for _, asset := range assets {
@@ -147,23 +203,69 @@ func FindAssetsForOS(assets []*github.ReleaseAsset) []*github.ReleaseAsset {
 }
 
 // FindAssetsForArch searches the provided list of assets and returns the subset, if any, matching
-// the local architecture as defined by runtime.GOARCH, as well as well-known alternative names for the
-// architecture
+// the target architecture as defined by utils.TargetArch, as well as well-known alternative names for the
+// architecture. If no assets are built specifically for the target architecture, universal assets built for
+// every architecture are returned instead, so assets should be filtered by OS beforehand
 func FindAssetsForArch(assets []*github.ReleaseAsset) []*github.ReleaseAsset {
 	matches := []*github.ReleaseAsset{}
+	universal := []*github.ReleaseAsset{}
 	for _, asset := range assets {
-		if utils.ContainsAny(strings.ToLower(asset.GetName()), utils.GetArchAliases()) {
+		name := strings.ToLower(asset.GetName())
+		switch {
+		case utils.ContainsAny(name, utils.GetArchAliases()):
 			matches = append(matches, asset)
+		case utils.ContainsAny(name, utils.GetUniversalArchAliases()):
+			universal = append(universal, asset)
 		}
 	}
+	if len(matches) == 0 {
+		return universal
+	}
 	return matches
 }
 
 // FindAssetsForArchAndOS searches the provided list of assets and returns the subset, if any, matching
-// the local architecture and OS, as defined by runtime.GOARCH and runtime.GOOS, respectively.
+// the target architecture and OS, as defined by utils.TargetArch and utils.TargetOS, respectively.
 // In addition to these values, well-known alternatives are also used when searching.
 func FindAssetsForArchAndOS(assets []*github.ReleaseAsset) []*github.ReleaseAsset {
-	return FindAssetsForOS(FindAssetsForArch(assets))
+	return FindAssetsForLibc(FindAssetsForArch(FindAssetsForOS(assets)))
+}
+
+// FindAssetsForLibc filters the provided slice of assets down to those suitable for the target C library, as
+// defined by utils.Libc, when assets are published for several. Assets built against the target C library are
+// preferred, followed by assets which don't name one. Assets built against another C library are only returned if
+// nothing else is available, as they're unlikely to run
+func FindAssetsForLibc(assets []*github.ReleaseAsset) []*github.ReleaseAsset {
+	libc := utils.Libc()
+	if libc == "" {
+		return assets
+	}
+	otherAliases := []string{}
+	for _, other := range []string{utils.LibcGlibc, utils.LibcMusl} {
+		if other != libc {
+			otherAliases = append(otherAliases, utils.GetLibcAliases(other)...)
+		}
+	}
+
+	matching := []*github.ReleaseAsset{}
+	unmarked := []*github.ReleaseAsset{}
+	for _, asset := range assets {
+		name := strings.ToLower(asset.GetName())
+		switch {
+		case utils.ContainsAny(name, utils.GetLibcAliases(libc)):
+			matching = append(matching, asset)
+		case !utils.ContainsAny(name, otherAliases):
+			unmarked = append(unmarked, asset)
+		}
+	}
+	switch {
+	case len(matching) > 0:
+		return matching
+	case len(unmarked) > 0:
+		return unmarked
+	default:
+		return assets
+	}
 }
 
 // FindAssetMatching searches the provided slice of assets for entries whose Name matches the given pattern.
@@ -181,7 +283,7 @@ func FindAssetsMatching(pattern string, assets []*github.ReleaseAsset) ([]*githu
 		}
 	}
 	if len(matches) == 0 {
-		return []*github.ReleaseAsset{}, fmt.Errorf("failed to find asset matching '%s'", pattern)
+		return []*github.ReleaseAsset{}, fmt.Errorf("failed to find asset matching '%s': %w", pattern, utils.ErrAssetNotFound)
 	}
 	return matches, nil
 }
@@ -209,3 +311,81 @@ func FindAssetsExcluding(terms []string, assets []*github.ReleaseAsset) []*githu
 	}
 	return matches
 }
+
+// String describes the source
+func (s Source) String() string {
+	host := s.Host
+	if host == "" {
+		host = "github.com"
+	}
+	return fmt.Sprintf("%s/%s/%s", host, s.Owner, s.Repo)
+}
+
+// FetchReleaseByTag returns the release of the tool with the provided tag from GitHub
+func (s Source) FetchReleaseByTag(tag string) (*github.RepositoryRelease, error) {
+	release, response, err := s.client.Repositories.GetReleaseByTag(context.TODO(), s.Owner, s.Repo, tag)
+	if err != nil {
+		return &github.RepositoryRelease{}, s.describeError(err)
+	}
+	err = github.CheckResponse(response.Response)
+	if err != nil {
+		return &github.RepositoryRelease{}, s.describeError(err)
+	}
+	return release, nil
+}
+
+// ResolveLatest returns the tag of the latest release
+func (s Source) ResolveLatest() (string, error) {
+	release, err := s.FetchLatestRelease()
+	if err != nil {
+		return "", err
+	}
+	return release.GetTagName(), nil
+}
+
+// ResolveVersion returns the tag of the release with the provided tag, if it exists
+func (s Source) ResolveVersion(version string) (string, error) {
+	release, err := s.FetchReleaseByTag(version)
+	if err != nil {
+		return "", err
+	}
+	return release.GetTagName(), nil
+}
+
+// ListArtifacts returns the assets of the release with the provided tag
+func (s Source) ListArtifacts(version string) ([]sources.Artifact, error) {
+	release, err := s.FetchReleaseByTag(version)
+	if err != nil {
+		return []sources.Artifact{}, err
+	}
+	artifacts := []sources.Artifact{}
+	for _, asset := range release.Assets {
+		artifacts = append(artifacts, sources.Artifact{
+			Name: asset.GetName(),
+			ID:   strconv.FormatInt(asset.GetID(), 10),
+			URL:  asset.GetBrowserDownloadURL(),
+			Size: int64(asset.GetSize()),
+		})
+	}
+	return artifacts, nil
+}
+
+// VerificationHints identifies the checksum, signature, and provenance assets among the provided artifacts
+func (s Source) VerificationHints(artifacts []sources.Artifact) []sources.Hint {
+	return sources.HintsFromNames(artifacts)
+}
+
+// Download retrieves the release asset identified by the artifact's ID into dir
+func (s Source) Download(artifact sources.Artifact, dir string) (string, error) {
+	id, err := strconv.ParseInt(artifact.ID, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid release asset ID '%s' for '%s': %w", artifact.ID, artifact.Name, err)
+	}
+	size := int(artifact.Size)
+	asset := &github.ReleaseAsset{ID: &id, Name: &artifact.Name, Size: &size}
+	err = s.downloadReleaseAsset(asset, dir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, artifact.Name), nil
+}
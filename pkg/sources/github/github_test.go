@@ -0,0 +1,174 @@
+package github
+
+import (
+	"errors"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/google/go-github/v51/github"
+	"github.com/openshift/backplane-tools/pkg/httpfixture"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// fuzzAssetNames seed the asset selection fuzz targets with names resembling those published by the tools
+// backplane-tools installs, including names mentioning several platforms
+var fuzzAssetNames = []string{
+	"osdctl_0.30.0_Linux_x86_64.tar.gz",
+	"osdctl_0.30.0_Darwin_arm64.tar.gz",
+	"yq_darwin_amd64.tar.gz",
+	"rosa-darwin-universal.tar.gz",
+	"tool-v1.0.0-darwin-linux-amd64",
+	"tool_linux_musl_arm64.zip",
+	"checksums.txt",
+	"",
+	"ツール_linux_amd64",
+}
+
+// assetsNamed returns release assets with the provided names
+func assetsNamed(names ...string) []*github.ReleaseAsset {
+	assets := []*github.ReleaseAsset{}
+	for _, name := range names {
+		assets = append(assets, &github.ReleaseAsset{Name: github.String(name)})
+	}
+	return assets
+}
+
+// isSubset returns true if every asset in subset is one of the provided assets
+func isSubset(subset, assets []*github.ReleaseAsset) bool {
+	for _, s := range subset {
+		found := false
+		for _, asset := range assets {
+			if s == asset {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func FuzzFindAssetsForArchAndOS(f *testing.F) {
+	for i, name := range fuzzAssetNames {
+		f.Add(name, fuzzAssetNames[(i+1)%len(fuzzAssetNames)])
+	}
+	f.Fuzz(func(t *testing.T, first, second string) {
+		assets := assetsNamed(first, second)
+		for _, find := range []func([]*github.ReleaseAsset) []*github.ReleaseAsset{FindAssetsForOS, FindAssetsForArch, FindAssetsForLibc, FindAssetsForArchAndOS} {
+			matches := find(assets)
+			if !isSubset(matches, assets) {
+				t.Errorf("selected assets which weren't provided")
+			}
+		}
+	})
+}
+
+func FuzzFindAssetsContainingAndExcluding(f *testing.F) {
+	for _, name := range fuzzAssetNames {
+		f.Add(name, "darwin")
+		f.Add(name, "amd64")
+	}
+	f.Fuzz(func(t *testing.T, name, term string) {
+		assets := assetsNamed(name)
+		containing := FindAssetsContaining([]string{term}, assets)
+		excluding := FindAssetsExcluding([]string{term}, assets)
+		if len(containing)+len(excluding) != len(assets) {
+			t.Errorf("asset '%s' was selected %d times by term '%s', expected once", name, len(containing)+len(excluding), term)
+		}
+	})
+}
+
+func FuzzFindAssetsMatching(f *testing.F) {
+	for _, name := range fuzzAssetNames {
+		f.Add(name, `.*\.tar\.gz$`)
+	}
+	f.Fuzz(func(t *testing.T, name, pattern string) {
+		assets := assetsNamed(name)
+		// Arbitrary patterns must only fail to compile, rather than panic
+		matches, err := FindAssetsMatching(pattern, assets)
+		if err == nil && !isSubset(matches, assets) {
+			t.Errorf("selected assets which weren't provided")
+		}
+
+		// GitHub's API only returns names which are valid UTF-8, which regular expressions require
+		if !utf8.ValidString(name) {
+			return
+		}
+		matches, err = FindAssetsMatching("^"+regexp.QuoteMeta(name)+"$", assets)
+		if err != nil || len(matches) != 1 {
+			t.Errorf("failed to select asset '%s' by its name: %v", name, err)
+		}
+	})
+}
+
+// replaySource returns a Source for mikefarah/yq whose requests are answered by the named fixture in testdata/fixtures.
+// Fixtures are recorded without a token, and the cache is disabled, so that each request reaches the fixture exactly
+// as it was recorded. Set httpfixture.RecordEnvVar to "true" to re-record them
+func replaySource(t *testing.T, fixture string) *Source {
+	t.Helper()
+	transport, err := httpfixture.Open(filepath.Join("testdata", "fixtures", fixture+".json"))
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	originalTransport, originalCacheDir := utils.HTTPTransport, CacheDir
+	utils.HTTPTransport, CacheDir = transport, ""
+	t.Cleanup(func() {
+		utils.HTTPTransport, CacheDir = originalTransport, originalCacheDir
+		for _, req := range transport.Unreplayed() {
+			t.Errorf("expected %s '%s' to be requested", req.Method, req.URL)
+		}
+		err := transport.Save()
+		if err != nil {
+			t.Errorf("failed to save fixture: %v", err)
+		}
+	})
+	return &Source{
+		Owner:  "mikefarah",
+		Repo:   "yq",
+		client: newClient(func() string { return "" }),
+	}
+}
+
+func TestResolveLatest(t *testing.T) {
+	s := replaySource(t, "latest-release")
+	tag, err := s.ResolveLatest()
+	if err != nil {
+		t.Fatalf("failed to resolve latest release: %v", err)
+	}
+	if tag != "v4.40.5" {
+		t.Errorf("expected latest release 'v4.40.5', got '%s'", tag)
+	}
+}
+
+func TestListArtifacts(t *testing.T) {
+	s := replaySource(t, "release-assets")
+	artifacts, err := s.ListArtifacts("v4.40.5")
+	if err != nil {
+		t.Fatalf("failed to list artifacts: %v", err)
+	}
+	if len(artifacts) != 7 {
+		t.Errorf("expected 7 artifacts, got %d", len(artifacts))
+	}
+	for _, artifact := range artifacts {
+		if artifact.Name != "yq_linux_amd64" {
+			continue
+		}
+		if artifact.ID != "138442888" || artifact.Size != 9367704 || artifact.URL != "https://github.com/mikefarah/yq/releases/download/v4.40.5/yq_linux_amd64" {
+			t.Errorf("artifact 'yq_linux_amd64' was parsed incorrectly: %+v", artifact)
+		}
+		return
+	}
+	t.Errorf("expected artifact 'yq_linux_amd64' to be listed")
+}
+
+func TestResolveMissingVersion(t *testing.T) {
+	s := replaySource(t, "missing-release")
+	_, err := s.ResolveVersion("v0.0.0")
+	if !errors.Is(err, utils.ErrAssetNotFound) {
+		t.Errorf("expected error '%v', got '%v'", utils.ErrAssetNotFound, err)
+	}
+}
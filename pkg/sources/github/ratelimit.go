@@ -0,0 +1,125 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v51/github"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+const (
+	// maxRateLimitRetries bounds how many times a rate limited request is retried
+	maxRateLimitRetries = 3
+	// maxRateLimitWait bounds how long a single retry waits. Requests which can't be retried within this time
+	// fail immediately, rather than stalling the installation
+	maxRateLimitWait = 60 * time.Second
+	// defaultSecondaryWait is how long to wait when a secondary rate limit doesn't specify when to retry
+	defaultSecondaryWait = 10 * time.Second
+)
+
+// sleep pauses before retrying a rate limited request
+var sleep = time.Sleep
+
+// rateLimitTransport retries requests which trip GitHub's rate limits, when the limit resets soon enough to be
+// worth waiting for
+type rateLimitTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip performs the request, backing off and retrying while it's rate limited
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil || attempt >= maxRateLimitRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+		wait, limited := retryAfter(resp, attempt)
+		if !limited || wait > maxRateLimitWait {
+			return resp, nil
+		}
+
+		// Drain the response so the connection can be reused
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		fmt.Printf("WARNING: GitHub rate limit reached, retrying in %s\n", wait.Round(time.Second))
+		sleep(wait)
+
+		if req.GetBody != nil {
+			req.Body, err = req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// retryAfter determines whether the response indicates the request was rate limited and, if so, how long to wait
+// before retrying it
+func retryAfter(resp *http.Response, attempt int) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	// Secondary rate limits specify when to retry
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+
+	// Primary rate limits report when the quota resets
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		return time.Until(time.Unix(reset, 0)) + time.Second, true
+	}
+
+	// Secondary rate limits without a Retry-After header are identified by their message, and retried with
+	// exponential backoff
+	if resp.StatusCode == http.StatusTooManyRequests || strings.Contains(strings.ToLower(peekBody(resp)), "secondary rate limit") {
+		return defaultSecondaryWait << attempt, true
+	}
+	return 0, false
+}
+
+// peekBody returns the start of the response's body, without consuming it
+func peekBody(resp *http.Response) string {
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(strings.NewReader(string(data)), resp.Body), resp.Body}
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// describeRateLimit adds the remaining quota and reset time to rate limit errors, along with how to raise the limit.
+// Other errors are returned unchanged
+func describeRateLimit(err error) error {
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		rate := rateLimitErr.Rate
+		return fmt.Errorf("GitHub API rate limit exceeded: %d of %d requests remaining, resetting at %s (in %s). Authenticate with 'backplane-tools login github' to raise the limit: %w: %w",
+			rate.Remaining, rate.Limit, rate.Reset.Local().Format(time.Kitchen), time.Until(rate.Reset.Time).Round(time.Second), utils.ErrRateLimited, err)
+	}
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		retry := "later"
+		if abuseErr.RetryAfter != nil {
+			retry = "in " + abuseErr.RetryAfter.Round(time.Second).String()
+		}
+		return fmt.Errorf("GitHub API secondary rate limit exceeded, retry %s: %w: %w", retry, utils.ErrRateLimited, err)
+	}
+	return err
+}
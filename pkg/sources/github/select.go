@@ -0,0 +1,120 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v51/github"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// packageSuffixes identify assets which are system packages, rather than archives or executables. Packages match the
+// local system, but can't be installed by backplane-tools
+var packageSuffixes = []string{".rpm", ".deb", ".apk", ".pkg", ".msi", ".dmg", ".snap", ".appimage"}
+
+// metadataSuffixes identify assets which describe another asset, such as checksums and signatures
+var metadataSuffixes = []string{".sha256", ".sha256sum", ".sha512", ".sha512sum", ".md5", ".sig", ".asc", ".pem", ".crt", ".sbom", ".spdx", ".spdx.json", ".intoto.jsonl", ".txt"}
+
+// assetKind ranks assets by how suitable their format is for installation: archives and executables are preferred
+// over packages, which are preferred over metadata
+func assetKind(name string) int {
+	switch {
+	case hasAnySuffix(name, metadataSuffixes):
+		return 0
+	case hasAnySuffix(name, packageSuffixes):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// archExactness ranks assets naming the target architecture precisely (ie - 'arm64') above those matching it only
+// via a loose alias (ie - 'arm', which is also used for 32-bit builds)
+func archExactness(name string) int {
+	if utils.ContainsAny(name, utils.GetExactArchAliases()) {
+		return 1
+	}
+	return 0
+}
+
+// libcPreference ranks assets built against the target C library, as defined by utils.Libc, above those built
+// against another
+func libcPreference(name string) int {
+	if utils.Libc() == utils.LibcMusl {
+		if strings.Contains(name, "musl") {
+			return 1
+		}
+		return 0
+	}
+	if strings.Contains(name, "musl") {
+		return 0
+	}
+	return 1
+}
+
+// scoreAsset returns the provided asset's score as a list of criteria, in order of precedence: the asset's kind, how
+// exactly it names the target architecture, its C library, and finally whether it contains each of the preferred terms
+func scoreAsset(asset *github.ReleaseAsset, prefer []string) []int {
+	name := strings.ToLower(asset.GetName())
+	score := []int{assetKind(name), archExactness(name), libcPreference(name)}
+	for _, term := range prefer {
+		if strings.Contains(name, strings.ToLower(term)) {
+			score = append(score, 1)
+		} else {
+			score = append(score, 0)
+		}
+	}
+	return score
+}
+
+// compareScores compares two scores criterion by criterion, returning a positive number if a is preferred over b,
+// a negative number if b is preferred over a, and 0 if neither is preferred
+func compareScores(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+	return 0
+}
+
+// SelectAsset returns the most suitable of the provided assets, each of which has already been found to match the
+// local system. Archives and executables are preferred over packages, exact architecture names over loose aliases,
+// and builds for the target C library over others. Remaining ties are broken by the preferred terms, in order: an asset containing
+// an earlier term is preferred over one which doesn't. An error is returned if no asset is clearly the most suitable
+func SelectAsset(assets []*github.ReleaseAsset, prefer []string) (*github.ReleaseAsset, error) {
+	if len(assets) == 0 {
+		return nil, fmt.Errorf("no assets found matching system spec: %w", utils.ErrAssetNotFound)
+	}
+
+	best := []*github.ReleaseAsset{assets[0]}
+	bestScore := scoreAsset(assets[0], prefer)
+	for _, asset := range assets[1:] {
+		score := scoreAsset(asset, prefer)
+		switch c := compareScores(score, bestScore); {
+		case c > 0:
+			best = []*github.ReleaseAsset{asset}
+			bestScore = score
+		case c == 0:
+			best = append(best, asset)
+		}
+	}
+	if len(best) > 1 {
+		names := []string{}
+		for _, asset := range best {
+			names = append(names, asset.GetName())
+		}
+		return nil, fmt.Errorf("unable to choose between the assets matching system spec: '%s' are equally suitable", strings.Join(names, "', '"))
+	}
+	return best[0], nil
+}
+
+// hasAnySuffix returns true if the provided string ends with any of the given suffixes
+func hasAnySuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package mirror
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// hrefPattern matches the targets of the links in a directory listing
+var hrefPattern = regexp.MustCompile(`(?i)href\s*=\s*"([^"]+)"`)
+
+// maxListingSize bounds the size of the directory listings read from the server
+const maxListingSize = 16 << 20
+
+// ListDirectory returns the names of the entries in the directory at slug, as published in the server's directory
+// listing. The names of subdirectories end with '/'
+func (s Source) ListDirectory(slug string) ([]string, error) {
+	dirSlug := strings.TrimSuffix(slug, "/") + "/"
+	listing, err := s.GetFileContents(dirSlug)
+	if err != nil {
+		return []string{}, fmt.Errorf("failed to retrieve directory listing for '%s': %w", dirSlug, err)
+	}
+	defer func() {
+		closeErr := listing.Close()
+		if closeErr != nil {
+			fmt.Printf("WARNING: failed to close response body: %v\n", closeErr)
+		}
+	}()
+	data, err := io.ReadAll(io.LimitReader(listing, maxListingSize))
+	if err != nil {
+		return []string{}, fmt.Errorf("failed to read directory listing for '%s': %w", dirSlug, err)
+	}
+
+	dirURL, err := s.BuildURL(dirSlug)
+	if err != nil {
+		return []string{}, fmt.Errorf("failed to build URL: %w", err)
+	}
+	return parseListing(string(data), dirURL)
+}
+
+// parseListing returns the entries linked to by the directory listing of the directory at dirURL. Links are resolved
+// relative to the directory, and only those referring to its direct children are returned
+func parseListing(listing, dirURL string) ([]string, error) {
+	base, err := url.Parse(dirURL)
+	if err != nil {
+		return []string{}, fmt.Errorf("failed to parse URL '%s': %w", dirURL, err)
+	}
+	dirPath := path.Clean(base.Path)
+
+	entries := []string{}
+	for _, match := range hrefPattern.FindAllStringSubmatch(listing, -1) {
+		ref, err := url.Parse(match[1])
+		if err != nil || ref.RawQuery != "" || ref.Fragment != "" {
+			continue
+		}
+		resolved := base.ResolveReference(ref)
+		if resolved.Host != base.Host {
+			continue
+		}
+		parent, name := path.Split(path.Clean(resolved.Path))
+		if path.Clean(parent) != dirPath || name == "" || name == "." || name == ".." {
+			continue
+		}
+		if strings.HasSuffix(resolved.Path, "/") {
+			name += "/"
+		}
+		if !utils.Contains(entries, name) {
+			entries = append(entries, name)
+		}
+	}
+	sort.Strings(entries)
+	return entries, nil
+}
+
+// ListVersions returns the versions published in the directory at slug, where each version is a subdirectory named
+// after it (ie - "4.15.3/"). Entries which aren't versions, such as channel directories, are ignored. Versions are
+// returned from oldest to newest
+func (s Source) ListVersions(slug string) ([]string, error) {
+	entries, err := s.ListDirectory(slug)
+	if err != nil {
+		return []string{}, err
+	}
+	versions := []string{}
+	for _, entry := range entries {
+		name, isDir := strings.CutSuffix(entry, "/")
+		if isDir && utils.IsVersion(name) {
+			versions = append(versions, name)
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return utils.CompareVersions(versions[i], versions[j]) < 0
+	})
+	return versions, nil
+}
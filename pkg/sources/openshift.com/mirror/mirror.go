@@ -4,11 +4,17 @@ mirror provides the capability for tools to retrieve files from mirror.openshift
 package mirror
 
 import (
+	"github.com/openshift/backplane-tools/pkg/config"
 	"github.com/openshift/backplane-tools/pkg/sources/base/url"
 )
 
 const (
-	baseURL string = "http://mirror.openshift.com"
+	// BaseURL is the location of mirror.openshift.com
+	BaseURL string = "https://mirror.openshift.com"
+
+	// InsecureBaseURL is the location of mirror.openshift.com over plain HTTP, used only when the user explicitly
+	// opts into it
+	InsecureBaseURL string = "http://mirror.openshift.com"
 )
 
 // Source objects retrieve files from a mirror server
@@ -17,8 +23,18 @@ type Source struct {
 	*url.Source
 }
 
-// NewSource creates a Source
+// NewSource creates a Source retrieving files from mirror.openshift.com over HTTPS, unless the user's configuration
+// requires plain HTTP
 func NewSource() *Source {
+	if config.Get().Mirror.InsecureHTTP {
+		return NewSourceWithURL(InsecureBaseURL)
+	}
+	return NewSourceWithURL(BaseURL)
+}
+
+// NewSourceWithURL creates a Source retrieving files from a server other than mirror.openshift.com, which
+// serves the same directory structure
+func NewSourceWithURL(baseURL string) *Source {
 	s := &Source{
 		Source: url.NewSource(baseURL),
 	}
@@ -0,0 +1,78 @@
+package mirror
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openshift/backplane-tools/pkg/httpfixture"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// slug is the directory the client's versions are published to
+const slug = "/pub/openshift-v4/amd64/clients/ocp/"
+
+// replaySource returns a Source for mirror.openshift.com whose requests are answered by the named fixture in
+// testdata/fixtures. Set httpfixture.RecordEnvVar to "true" to re-record it
+func replaySource(t *testing.T, fixture string) *Source {
+	t.Helper()
+	transport, err := httpfixture.Open(filepath.Join("testdata", "fixtures", fixture+".json"))
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	original := utils.HTTPTransport
+	utils.HTTPTransport = transport
+	t.Cleanup(func() {
+		utils.HTTPTransport = original
+		for _, req := range transport.Unreplayed() {
+			t.Errorf("expected %s '%s' to be requested", req.Method, req.URL)
+		}
+		err := transport.Save()
+		if err != nil {
+			t.Errorf("failed to save fixture: %v", err)
+		}
+	})
+	return NewSourceWithURL(BaseURL)
+}
+
+func TestListVersions(t *testing.T) {
+	s := replaySource(t, "list-versions")
+	versions, err := s.ListVersions(slug)
+	if err != nil {
+		t.Fatalf("failed to list versions: %v", err)
+	}
+	// Channel directories, the parent directory, and the listing's sort links aren't versions
+	expected := []string{"4.9.0", "4.15.2", "4.15.3"}
+	if !reflect.DeepEqual(versions, expected) {
+		t.Errorf("expected versions %v, got %v", expected, versions)
+	}
+}
+
+func TestGetFileContents(t *testing.T) {
+	s := replaySource(t, "release-info")
+	contents, err := s.GetFileContents(slug + "stable/release.txt")
+	if err != nil {
+		t.Fatalf("failed to retrieve release info: %v", err)
+	}
+	defer func() {
+		_ = contents.Close()
+	}()
+	data, err := io.ReadAll(contents)
+	if err != nil {
+		t.Fatalf("failed to read release info: %v", err)
+	}
+	if !strings.Contains(string(data), "Name:           4.15.3") {
+		t.Errorf("expected release info to name version '4.15.3', got:\n%s", data)
+	}
+}
+
+func TestGetMissingFile(t *testing.T) {
+	s := replaySource(t, "missing-file")
+	_, err := s.GetFileContents(slug + "4.0.0/release.txt")
+	if !errors.Is(err, utils.ErrAssetNotFound) {
+		t.Errorf("expected error '%v', got '%v'", utils.ErrAssetNotFound, err)
+	}
+}
@@ -0,0 +1,204 @@
+/*
+pypi provides the capability for tools to retrieve Python packages from the Python Package Index
+*/
+package pypi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/sources"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// BaseURL is the location of the Python Package Index
+const BaseURL string = "https://pypi.org"
+
+const (
+	// PackageTypeWheel identifies files containing a built distribution
+	PackageTypeWheel = "bdist_wheel"
+	// PackageTypeSdist identifies files containing a source distribution
+	PackageTypeSdist = "sdist"
+)
+
+// Source objects retrieve a package from the Python Package Index
+type Source struct {
+	// Package is the name of the package on PyPI
+	Package string
+
+	// baseURL is the location of the package index
+	baseURL string
+}
+
+// Release describes a published version of a package
+type Release struct {
+	Info struct {
+		// Version is the release's version
+		Version string `json:"version"`
+	} `json:"info"`
+
+	// Files lists the distributions published for the release
+	Files []File `json:"urls"`
+}
+
+// File is a distribution published for a release
+type File struct {
+	// Filename is the distribution's file name
+	Filename string `json:"filename"`
+	// URL is the location the distribution can be downloaded from
+	URL string `json:"url"`
+	// PackageType identifies whether the distribution is a wheel or an sdist
+	PackageType string `json:"packagetype"`
+	// Size is the distribution's size in bytes
+	Size int64 `json:"size"`
+	// Yanked is true if the distribution has been withdrawn by its publisher
+	Yanked bool `json:"yanked"`
+	// Digests contains the distribution's hashes
+	Digests struct {
+		Sha256 string `json:"sha256"`
+	} `json:"digests"`
+}
+
+// NewSource creates a Source for the named package
+func NewSource(pkg string) *Source {
+	return NewSourceWithURL(pkg, BaseURL)
+}
+
+// NewSourceWithURL creates a Source retrieving the named package from a server other than pypi.org, which
+// implements the same JSON API
+func NewSourceWithURL(pkg, baseURL string) *Source {
+	return &Source{
+		Package: pkg,
+		baseURL: baseURL,
+	}
+}
+
+// FetchLatestRelease returns the latest release of the package
+func (s Source) FetchLatestRelease() (*Release, error) {
+	return s.fetch("pypi", s.Package, "json")
+}
+
+// FetchRelease returns the release of the package with the provided version
+func (s Source) FetchRelease(version string) (*Release, error) {
+	return s.fetch("pypi", s.Package, version, "json")
+}
+
+// fetch retrieves the release described at the provided path
+func (s Source) fetch(path ...string) (*Release, error) {
+	releaseURL, err := url.JoinPath(s.baseURL, path...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build URL: %w", err)
+	}
+	resp, err := utils.HTTPClient().Get(releaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GET '%s': %w", releaseURL, err)
+	}
+	defer func() {
+		closeErr := resp.Body.Close()
+		if closeErr != nil {
+			fmt.Printf("WARNING: failed to close response body: %v\n", closeErr)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to retrieve '%s': %w", releaseURL, utils.UnexpectedStatus(resp.StatusCode))
+	}
+
+	release := &Release{}
+	err = json.NewDecoder(resp.Body).Decode(release)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode release info for '%s': %w", s.Package, err)
+	}
+	return release, nil
+}
+
+// FindDistribution returns the file which should be installed from those published for a release. Wheels which run
+// on any platform are preferred, followed by source distributions. Platform-specific wheels aren't considered, as
+// pip selects any platform-specific dependencies itself
+func FindDistribution(files []File) (File, error) {
+	for _, packageType := range []string{PackageTypeWheel, PackageTypeSdist} {
+		for _, file := range files {
+			if file.Yanked || file.PackageType != packageType {
+				continue
+			}
+			if packageType == PackageTypeWheel && !strings.HasSuffix(file.Filename, "-none-any.whl") {
+				continue
+			}
+			return file, nil
+		}
+	}
+	return File{}, fmt.Errorf("%w: no platform-independent wheel or source distribution found among %d files", utils.ErrAssetNotFound, len(files))
+}
+
+// String describes the source
+func (s Source) String() string {
+	return fmt.Sprintf("%s/project/%s", strings.TrimPrefix(strings.TrimPrefix(s.baseURL, "https://"), "http://"), s.Package)
+}
+
+// ResolveLatest returns the latest version of the package
+func (s Source) ResolveLatest() (string, error) {
+	release, err := s.FetchLatestRelease()
+	if err != nil {
+		return "", err
+	}
+	return release.Info.Version, nil
+}
+
+// ResolveVersion returns the canonical name of the provided version, if it exists
+func (s Source) ResolveVersion(version string) (string, error) {
+	release, err := s.FetchRelease(version)
+	if err != nil {
+		return "", err
+	}
+	return release.Info.Version, nil
+}
+
+// ListArtifacts returns the files published for the provided version
+func (s Source) ListArtifacts(version string) ([]sources.Artifact, error) {
+	release, err := s.FetchRelease(version)
+	if err != nil {
+		return []sources.Artifact{}, err
+	}
+	artifacts := []sources.Artifact{}
+	for _, file := range release.Files {
+		artifacts = append(artifacts, sources.Artifact{
+			Name: file.Filename,
+			URL:  file.URL,
+			Size: file.Size,
+		})
+	}
+	return artifacts, nil
+}
+
+// Download retrieves the artifact into dir
+func (s Source) Download(artifact sources.Artifact, dir string) (string, error) {
+	if artifact.URL == "" {
+		return "", fmt.Errorf("no URL provided for '%s'", artifact.Name)
+	}
+	filePath := filepath.Join(dir, filepath.Base(artifact.Name))
+	resp, err := utils.HTTPClient().Get(artifact.URL)
+	if err != nil {
+		return "", fmt.Errorf("failed to GET '%s': %w", artifact.URL, err)
+	}
+	defer func() {
+		closeErr := resp.Body.Close()
+		if closeErr != nil {
+			fmt.Printf("WARNING: failed to close response body: %v\n", closeErr)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return "", utils.UnexpectedStatus(resp.StatusCode)
+	}
+	size := artifact.Size
+	if size <= 0 {
+		size = resp.ContentLength
+	}
+	err = utils.WriteFile(utils.ExpectSize(utils.CountDownload(resp.Body), size), filePath, utils.DataMode)
+	if err != nil {
+		return "", fmt.Errorf("failed to create file '%s': %w", filePath, err)
+	}
+	return filePath, nil
+}
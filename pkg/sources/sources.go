@@ -0,0 +1,133 @@
+/*
+sources provides the capability for tools and commands to interact with the locations tools are installed from,
+without depending on the specific type of each source
+*/
+package sources
+
+import (
+	"strings"
+)
+
+// Artifact is a file published by a source
+type Artifact struct {
+	// Name is the artifact's file name
+	Name string
+
+	// ID identifies the artifact within its source, when the source addresses artifacts by something other than
+	// their name (ie - GitHub release asset IDs)
+	ID string
+
+	// URL is the location the artifact can be downloaded from, if it's directly addressable
+	URL string
+
+	// Size is the artifact's size in bytes, or 0 if unknown
+	Size int64
+}
+
+// Source is implemented by every location tools are installed from. Any further capabilities a source has are
+// discovered using Discover
+type Source interface {
+	// String describes the source for use in messages (ie - "github.com/openshift/osdctl")
+	String() string
+
+	// Download retrieves the artifact into dir, returning the path of the downloaded file
+	Download(artifact Artifact, dir string) (string, error)
+}
+
+// LatestResolver is implemented by sources able to determine the latest version they publish
+type LatestResolver interface {
+	ResolveLatest() (string, error)
+}
+
+// VersionResolver is implemented by sources able to confirm a specific version is published, returning the
+// version's canonical name
+type VersionResolver interface {
+	ResolveVersion(version string) (string, error)
+}
+
+// ArtifactLister is implemented by sources able to list the artifacts published for a version
+type ArtifactLister interface {
+	ListArtifacts(version string) ([]Artifact, error)
+}
+
+// VerificationHinter is implemented by sources which publish verification material alongside their artifacts
+type VerificationHinter interface {
+	VerificationHints(artifacts []Artifact) []Hint
+}
+
+// HintKind describes what a verification artifact contains
+type HintKind string
+
+const (
+	// HintChecksum indicates the artifact lists checksums of other artifacts
+	HintChecksum HintKind = "checksum"
+	// HintSignature indicates the artifact is a detached signature of another artifact
+	HintSignature HintKind = "signature"
+	// HintProvenance indicates the artifact contains SLSA provenance attestations
+	HintProvenance HintKind = "provenance"
+)
+
+// Hint identifies an artifact which can be used to verify other artifacts
+type Hint struct {
+	Kind     HintKind
+	Artifact Artifact
+}
+
+// Capabilities describes which optional operations a source supports
+type Capabilities struct {
+	ResolveLatest     bool
+	ResolveVersion    bool
+	ListArtifacts     bool
+	VerificationHints bool
+}
+
+// Discover returns the capabilities of the provided source
+func Discover(src Source) Capabilities {
+	_, resolveLatest := src.(LatestResolver)
+	_, resolveVersion := src.(VersionResolver)
+	_, listArtifacts := src.(ArtifactLister)
+	_, verificationHints := src.(VerificationHinter)
+	return Capabilities{
+		ResolveLatest:     resolveLatest,
+		ResolveVersion:    resolveVersion,
+		ListArtifacts:     listArtifacts,
+		VerificationHints: verificationHints,
+	}
+}
+
+// hintSuffixes identify verification artifacts by the end of their name
+var hintSuffixes = map[HintKind][]string{
+	HintProvenance: {".intoto.jsonl"},
+	HintSignature:  {".sig", ".asc", ".gpg"},
+}
+
+// hintTerms identify checksum artifacts by any part of their name
+var hintTerms = []string{"checksum", "sha256", "sha512", "sums"}
+
+// HintsFromNames identifies verification artifacts using the naming conventions common to most projects. This is
+// suitable for sources which don't otherwise describe the relationships between their artifacts
+func HintsFromNames(artifacts []Artifact) []Hint {
+	hints := []Hint{}
+	for _, artifact := range artifacts {
+		name := strings.ToLower(artifact.Name)
+		kind := HintKind("")
+		for k, suffixes := range hintSuffixes {
+			for _, suffix := range suffixes {
+				if strings.HasSuffix(name, suffix) {
+					kind = k
+				}
+			}
+		}
+		if kind == "" {
+			for _, term := range hintTerms {
+				if strings.Contains(name, term) {
+					kind = HintChecksum
+				}
+			}
+		}
+		if kind != "" {
+			hints = append(hints, Hint{Kind: kind, Artifact: artifact})
+		}
+	}
+	return hints
+}
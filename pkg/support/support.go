@@ -0,0 +1,307 @@
+/*
+support provides the capability to collect the state of a backplane-tools installation into a single archive, which
+users can attach to bug reports
+*/
+package support
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/openshift/backplane-tools/pkg/buildinfo"
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+const (
+	// redacted replaces secrets within the configuration included in the bundle
+	redacted = "REDACTED"
+
+	// maxTreeDepth bounds how deep the install directory is listed. This is deep enough to include each version of
+	// each tool and the files at the top of its directory, without listing the whole of large SDKs like gcloud's
+	maxTreeDepth = 3
+)
+
+// Platform describes the system and installation the bundle was collected from
+type Platform struct {
+	buildinfo.Info
+
+	// CollectedAt is when the bundle was created
+	CollectedAt time.Time `json:"collectedAt"`
+
+	// OS and Arch are the operating system and architecture backplane-tools is running on
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+
+	// Libc is the C library tools are installed for, on Linux
+	Libc string `json:"libc,omitempty"`
+
+	// Layout is the directory layout in use: 'xdg' or 'legacy'
+	Layout string `json:"layout"`
+
+	// InstallDir, StateDir and ConfigPath are the locations backplane-tools reads from and writes to
+	InstallDir string `json:"installDir"`
+	StateDir   string `json:"stateDir"`
+	ConfigPath string `json:"configPath"`
+
+	// Path lists the directories in the user's $PATH, in order
+	Path []string `json:"path"`
+}
+
+// Write creates a support bundle at the output path, returning the names of the entries it contains. Files which
+// don't exist, such as state which hasn't been recorded yet, are omitted from the bundle
+func Write(output string) (entries []string, err error) {
+	file, err := os.Create(output)
+	if err != nil {
+		return []string{}, fmt.Errorf("failed to create support bundle '%s': %w", output, err)
+	}
+	defer func() {
+		closeErr := file.Close()
+		if closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close support bundle '%s': %w", output, closeErr)
+		}
+	}()
+	gw := gzip.NewWriter(file)
+	defer func() {
+		closeErr := gw.Close()
+		if closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to finish writing support bundle '%s': %w", output, closeErr)
+		}
+	}()
+	tw := tar.NewWriter(gw)
+	defer func() {
+		closeErr := tw.Close()
+		if closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to finish writing support bundle '%s': %w", output, closeErr)
+		}
+	}()
+
+	now := time.Now()
+	add := func(name string, data []byte) error {
+		err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0o600,
+			Size:    int64(len(data)),
+			ModTime: now,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add '%s' to support bundle: %w", name, err)
+		}
+		_, err = tw.Write(data)
+		if err != nil {
+			return fmt.Errorf("failed to add '%s' to support bundle: %w", name, err)
+		}
+		entries = append(entries, name)
+		return nil
+	}
+
+	platform, err := json.MarshalIndent(collectPlatform(now), "", "  ")
+	if err != nil {
+		return entries, fmt.Errorf("failed to encode platform information: %w", err)
+	}
+	err = add("platform.json", platform)
+	if err != nil {
+		return entries, err
+	}
+
+	cfg, err := redactedConfig()
+	if err != nil {
+		return entries, err
+	}
+	err = add("config.yaml", cfg)
+	if err != nil {
+		return entries, err
+	}
+
+	// The state files include the report of the most recent installation run and the journal of incomplete changes,
+	// which are the closest thing backplane-tools has to logs
+	for _, path := range base.StateFilePaths() {
+		info, err := os.Stat(path)
+		if err == nil && info.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return entries, fmt.Errorf("failed to read state file '%s': %w", path, err)
+		}
+		err = add(filepath.Join("state", filepath.Base(path)), data)
+		if err != nil {
+			return entries, err
+		}
+	}
+
+	err = add("tree.txt", []byte(listTree(base.InstallDir)))
+	if err != nil {
+		return entries, err
+	}
+	err = add("links.txt", []byte(listLinks(base.LatestDir)))
+	if err != nil {
+		return entries, err
+	}
+	return entries, nil
+}
+
+// collectPlatform describes the system and installation backplane-tools is running on
+func collectPlatform(now time.Time) Platform {
+	layout := string(config.LayoutLegacy)
+	if base.UseXDG {
+		layout = string(config.LayoutXDG)
+	}
+	return Platform{
+		Info:        buildinfo.Get(),
+		CollectedAt: now.UTC(),
+		OS:          runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		Libc:        utils.Libc(),
+		Layout:      layout,
+		InstallDir:  base.InstallDir,
+		StateDir:    base.StateDir,
+		ConfigPath:  config.Path,
+		Path:        filepath.SplitList(os.Getenv("PATH")),
+	}
+}
+
+// redactedConfig returns the loaded configuration, encoded as YAML, with any tokens, passwords, and credentials
+// embedded in URLs replaced
+func redactedConfig() ([]byte, error) {
+	cfg := *config.Get()
+
+	cfg.Telemetry.Endpoint = redactURL(cfg.Telemetry.Endpoint)
+	cfg.GoogleCloudStorage.Endpoint = redactURL(cfg.GoogleCloudStorage.Endpoint)
+	cfg.BackplaneCLI.ProxyURL = redactURL(cfg.BackplaneCLI.ProxyURL)
+
+	if cfg.GitHub.Repositories != nil {
+		repositories := map[string]config.GitHubCredentials{}
+		for name, creds := range cfg.GitHub.Repositories {
+			if creds.Token != "" {
+				creds.Token = redacted
+			}
+			repositories[name] = creds
+		}
+		cfg.GitHub.Repositories = repositories
+	}
+
+	if cfg.Credentials != nil {
+		credentials := map[string]config.Credentials{}
+		for host, creds := range cfg.Credentials {
+			if creds.Password != "" {
+				creds.Password = redacted
+			}
+			credentials[host] = creds
+		}
+		cfg.Credentials = credentials
+	}
+
+	if cfg.Tools != nil {
+		tools := map[string]config.Tool{}
+		for name, tool := range cfg.Tools {
+			if tool.Source != nil {
+				source := *tool.Source
+				source.URL = redactURL(source.URL)
+				tool.Source = &source
+			}
+			tools[name] = tool
+		}
+		cfg.Tools = tools
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return []byte{}, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	return data, nil
+}
+
+// redactURL replaces the credentials embedded in the provided URL, if any
+func redactURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.User == nil {
+		return raw
+	}
+	parsed.User = url.User(redacted)
+	return parsed.String()
+}
+
+// listTree describes the contents of the provided directory, one entry per line, up to maxTreeDepth levels deep.
+// Problems reading the directory are recorded in the listing, rather than preventing the bundle from being created
+func listTree(root string) string {
+	var b strings.Builder
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			fmt.Fprintf(&b, "%s: %v\n", path, err)
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		depth := 0
+		if rel != "." {
+			depth = len(strings.Split(rel, string(os.PathSeparator)))
+		}
+
+		line := fmt.Sprintf("%s %10d %s", info.Mode(), info.Size(), rel)
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(path)
+			if err != nil {
+				target = fmt.Sprintf("<%v>", err)
+			}
+			line = fmt.Sprintf("%s -> %s", line, target)
+		}
+		if info.IsDir() && depth >= maxTreeDepth {
+			fmt.Fprintf(&b, "%s (contents omitted)\n", line)
+			return filepath.SkipDir
+		}
+		fmt.Fprintln(&b, line)
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(&b, "failed to list '%s': %v\n", root, err)
+	}
+	return b.String()
+}
+
+// listLinks describes what each entry in the provided latest directory resolves to, one entry per line
+func listLinks(latestDir string) string {
+	entries, err := os.ReadDir(latestDir)
+	if err != nil {
+		return fmt.Sprintf("failed to read '%s': %v\n", latestDir, err)
+	}
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		target, err := base.ResolveLink(filepath.Join(latestDir, name))
+		if err != nil {
+			fmt.Fprintf(&b, "%s: failed to resolve: %v\n", name, err)
+			continue
+		}
+		_, err = os.Stat(target)
+		if err != nil {
+			fmt.Fprintf(&b, "%s -> %s (%v)\n", name, target, err)
+			continue
+		}
+		fmt.Fprintf(&b, "%s -> %s\n", name, target)
+	}
+	return b.String()
+}
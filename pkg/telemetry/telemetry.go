@@ -0,0 +1,123 @@
+/*
+telemetry provides the capability to report anonymized installation metrics to a user-configured endpoint.
+
+Telemetry is disabled unless explicitly enabled in the configuration file. Reported events contain only the
+tool's name and version, the platform, how long installation took, and a coarse classification of any failure:
+no paths, usernames, hostnames, or error messages are included.
+*/
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// sendTimeout bounds how long reporting may delay the user
+const sendTimeout = 3 * time.Second
+
+// FailureClass coarsely categorizes why an installation failed
+type FailureClass string
+
+const (
+	// FailureNone indicates the installation succeeded
+	FailureNone FailureClass = ""
+	// FailureNetwork indicates an asset or release could not be retrieved
+	FailureNetwork FailureClass = "network"
+	// FailureVerification indicates a downloaded asset failed checksum, signature, or provenance verification
+	FailureVerification FailureClass = "verification"
+	// FailureFilesystem indicates a file could not be read or written locally
+	FailureFilesystem FailureClass = "filesystem"
+	// FailureUnknown indicates the failure could not be classified
+	FailureUnknown FailureClass = "unknown"
+)
+
+// Event records the outcome of installing a single tool
+type Event struct {
+	Tool       string       `json:"tool"`
+	Version    string       `json:"version,omitempty"`
+	OS         string       `json:"os"`
+	Arch       string       `json:"arch"`
+	DurationMS int64        `json:"durationMs"`
+	Success    bool         `json:"success"`
+	Failure    FailureClass `json:"failure,omitempty"`
+}
+
+// NewEvent creates an Event describing the installation of a tool, which took the provided duration and returned err
+func NewEvent(tool, version string, duration time.Duration, err error) Event {
+	return Event{
+		Tool:       tool,
+		Version:    version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		DurationMS: duration.Milliseconds(),
+		Success:    err == nil,
+		Failure:    Classify(err),
+	}
+}
+
+// Classify determines the FailureClass of the provided error. Errors are classified by the category they wrap, if
+// any, falling back to their message otherwise
+func Classify(err error) FailureClass {
+	if err == nil {
+		return FailureNone
+	}
+	switch {
+	case errors.Is(err, utils.ErrChecksumMismatch):
+		return FailureVerification
+	case errors.Is(err, utils.ErrNetwork) || errors.Is(err, utils.ErrRateLimited) || errors.Is(err, utils.ErrAssetNotFound):
+		return FailureNetwork
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return FailureNetwork
+	}
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return FailureFilesystem
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "verify") || strings.Contains(msg, "checksum") || strings.Contains(msg, "signature"):
+		return FailureVerification
+	case strings.Contains(msg, "download") || strings.Contains(msg, "fetch"):
+		return FailureNetwork
+	default:
+		return FailureUnknown
+	}
+}
+
+// Send reports the provided events to the endpoint as a single JSON document
+func Send(endpoint string, events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(struct {
+		Events []Event `json:"events"`
+	}{Events: events})
+	if err != nil {
+		return fmt.Errorf("failed to encode events: %w", err)
+	}
+
+	client := utils.HTTPClient()
+	client.Timeout = sendTimeout
+	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send events: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to send events: unexpected status '%s'", resp.Status)
+	}
+	return nil
+}
@@ -3,9 +3,7 @@ package awscli
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"runtime"
 	"strings"
 
 	"github.com/openshift/backplane-tools/pkg/sources/aws"
@@ -27,6 +25,8 @@ func New() *Tool {
 			VersionInLatestTag: true,
 		},
 	}
+	t.AddExecutables("aws_completer")
+	t.SetDescription("The AWS command line interface, for managing Amazon Web Services resources")
 	return t
 }
 
@@ -38,21 +38,20 @@ func (t *Tool) Install() error {
 	}
 
 	var (
-		awsExecDir                 string
-		awsOldInstallDir           string
-		awsBinaryFilepath          string
-		awsCompleterBinaryFilepath string
-		url                        string
-		fileExtension              string
+		awsExecDir        string
+		awsOldInstallDir  string
+		awsBinaryFilepath string
+		url               string
+		fileExtension     string
 	)
 
 	toolDir := t.ToolDir()
 	versionedDir := filepath.Join(toolDir, version)
 
-	switch runtime.GOOS {
+	switch utils.TargetOS {
 	case "linux":
 		arch := "x86_64"
-		if runtime.GOARCH == "arm64" {
+		if utils.TargetArch == "arm64" {
 			arch = "aarch64"
 		}
 		// Assign variables for Linux
@@ -66,7 +65,7 @@ func (t *Tool) Install() error {
 		url = "https://awscli.amazonaws.com/AWSCLIV2" + fileExtension
 	default:
 		// Handle unsupported operating systems
-		return fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+		return fmt.Errorf("%w: unsupported operating system: %s", utils.ErrUnsupportedPlatform, utils.TargetOS)
 	}
 
 	err = os.RemoveAll(versionedDir)
@@ -85,13 +84,14 @@ func (t *Tool) Install() error {
 		return fmt.Errorf("failed to download aws cli: %w", err)
 	}
 
-	// Unzip binary Bundle
+	// Extract binary Bundle
 	bundle := "aws-cli" + fileExtension
 	awsArchiveFilepath := filepath.Join(versionedDir, bundle)
 	awsNewInstallDir := filepath.Join(versionedDir, "aws-cli")
+	t.RecordArtifact(version, awsArchiveFilepath, url, "none")
 
 	if fileExtension == ".zip" {
-		err = utils.Unzip(awsArchiveFilepath, versionedDir)
+		err = utils.Unarchive(awsArchiveFilepath, versionedDir)
 		if err != nil {
 			return fmt.Errorf("failed to unarchive the aws-cli file '%s': %w", awsArchiveFilepath, err)
 		}
@@ -102,49 +102,21 @@ func (t *Tool) Install() error {
 			return fmt.Errorf("error renaming directory %w", err)
 		}
 	} else {
-		cmd := exec.Command("pkgutil", "--expand-full", awsArchiveFilepath, awsNewInstallDir)
-		err = cmd.Run()
+		err = utils.ExpandPkg(awsArchiveFilepath, awsNewInstallDir)
 		if err != nil {
 			return fmt.Errorf("failed to extract the aws-cli file '%s': %w", awsArchiveFilepath, err)
 		}
 	}
 	awsBinaryFilepath = filepath.Join(awsNewInstallDir, awsExecDir, "aws")
-	awsCompleterBinaryFilepath = filepath.Join(awsNewInstallDir, awsExecDir, "aws_completer")
 
 	// Link as latest
-	latestFilePath := t.SymlinkPath()
-	err = os.Remove(latestFilePath)
-	if err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to remove existing 'aws' binary at '%s': %w", base.LatestDir, err)
-	}
-
 	awsWrapperPath, err := t.createWrapper(versionedDir, awsBinaryFilepath)
 	if err != nil {
 		return fmt.Errorf("failed to create aws cli squid proxy wrapper: %w", err)
 	}
 
-	err = os.Symlink(awsWrapperPath, latestFilePath)
-	if err != nil {
-		return fmt.Errorf("failed to link new 'aws' binary to '%s': %w", base.LatestDir, err)
-	}
-
-	// Link as latest also aws_completer
-	latestCompleterFilePath := t.symlinkCompleterPath()
-	err = os.Remove(latestCompleterFilePath)
-	if err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to remove existing 'aws_completer' binary at '%s': %w", base.LatestDir, err)
-	}
-
-	err = os.Symlink(awsCompleterBinaryFilepath, latestCompleterFilePath)
-	if err != nil {
-		return fmt.Errorf("failed to link new 'aws' binary to '%s': %w", base.LatestDir, err)
-	}
-
-	return nil
-}
-
-func (t *Tool) symlinkCompleterPath() string {
-	return filepath.Join(base.LatestDir, "aws_completer")
+	// aws_completer is linked alongside the wrapper
+	return t.LinkExecutable(awsWrapperPath)
 }
 
 // Creates script that routes all aws traffic through squid proxy
@@ -177,10 +149,55 @@ fi
 	input := builder.String()
 	awsWrapperPath := filepath.Join(versionedDir, "aws")
 
-	err := os.WriteFile(awsWrapperPath, []byte(input), 0o755)
+	err := utils.WriteFile(strings.NewReader(input), awsWrapperPath, utils.ExecutableMode)
 	if err != nil {
 		return "", fmt.Errorf("failed to create exec file: %w", err)
 	}
 
 	return awsWrapperPath, nil
 }
+
+// Requirements lists the executables the wrapper script relies on, which are not managed by backplane-tools
+func (t *Tool) Requirements() []string {
+	return []string{"curl"}
+}
+
+// Configure creates empty aws config and credentials files with restrictive permissions, if they do not
+// already exist, so that profiles can be added to them with 'aws configure'
+func (t *Tool) Configure() error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve user home dir: %w", err)
+	}
+
+	files := []struct {
+		envVar      string
+		defaultPath string
+		template    string
+	}{
+		{
+			envVar:      "AWS_CONFIG_FILE",
+			defaultPath: filepath.Join(homeDir, ".aws", "config"),
+			template:    "# aws configuration, created by backplane-tools.\n# Add profiles by running 'aws configure --profile <name>'\n",
+		},
+		{
+			envVar:      "AWS_SHARED_CREDENTIALS_FILE",
+			defaultPath: filepath.Join(homeDir, ".aws", "credentials"),
+			template:    "# aws credentials, created by backplane-tools.\n# Add credentials by running 'aws configure --profile <name>'\n",
+		},
+	}
+	for _, file := range files {
+		path := file.defaultPath
+		if envPath := os.Getenv(file.envVar); envPath != "" {
+			path = envPath
+		}
+		created, err := utils.CreateFileIfNotExist(path, []byte(file.template), os.FileMode(0o600))
+		if err != nil {
+			return fmt.Errorf("failed to scaffold aws configuration: %w", err)
+		}
+		if created {
+			fmt.Printf("Created aws configuration file '%s'\n", path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package awscli_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/openshift/backplane-tools/pkg/tools/awscli"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/openshift/backplane-tools/pkg/utils/test"
+)
+
+func TestConformance(t *testing.T) {
+	if utils.TargetOS != "linux" {
+		t.Skip("the aws cli is published as an installer package for macOS")
+	}
+	executable := []byte("#!/bin/sh\necho 'aws-cli/2.15.0'\n")
+	archive, err := test.Zip(map[string][]byte{"aws/dist/aws": executable, "aws/dist/aws_completer": executable})
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	arch := "x86_64"
+	if utils.TargetArch == "arm64" {
+		arch = "aarch64"
+	}
+	// The aws cli's version is taken from its repository's tags, while the archive is published by Amazon
+	archivePath := "/awscli-exe-linux-" + arch + "-2.15.0.zip"
+	routes := map[string]http.Handler{
+		"awscli.amazonaws.com": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != archivePath {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(archive)
+		}),
+	}
+
+	violations, err := test.GithubConformance(awscli.New(), "aws", "aws-cli", "2.15.0", map[string][]byte{}, routes)
+	if err != nil {
+		t.Fatalf("failed to run conformance test: %v", err)
+	}
+	for _, violation := range violations {
+		t.Error(violation)
+	}
+}
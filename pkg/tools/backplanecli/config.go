@@ -0,0 +1,113 @@
+package backplanecli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// configEnvVar overrides the location of backplane-cli's configuration file
+const configEnvVar = "BACKPLANE_CONFIG"
+
+// setting describes a value in backplane-cli's configuration file which is managed by backplane-tools
+type setting struct {
+	// key is the setting's name within backplane-cli's configuration file
+	key string
+
+	// value is the value configured for the setting in backplane-tools' configuration, if any
+	value string
+
+	// prompt asks the user for the setting's value, if it's missing from both configuration files
+	prompt string
+}
+
+// configPath returns the location of backplane-cli's configuration file
+func configPath() (string, error) {
+	if path := os.Getenv(configEnvVar); path != "" {
+		return path, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve user home dir: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "backplane", "config.json"), nil
+}
+
+// Configure creates or refreshes backplane-cli's configuration file. Settings configured under 'backplaneCLI' in
+// backplane-tools' configuration replace those in the file, while settings missing from both are prompted for when
+// running interactively. Any other settings in the file are preserved
+func (t *Tool) Configure() error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	settings := map[string]any{}
+	existing, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return fmt.Errorf("failed to read backplane-cli configuration '%s': %w", path, err)
+	default:
+		err = json.Unmarshal(existing, &settings)
+		if err != nil {
+			return fmt.Errorf("failed to parse backplane-cli configuration '%s': %w", path, err)
+		}
+	}
+
+	c := config.Get().BackplaneCLI
+	managed := []setting{
+		{key: "proxy-url", value: c.ProxyURL, prompt: "backplane proxy URL (leave empty for none): "},
+		{key: "session-dir", value: c.SessionDir, prompt: "backplane session directory (leave empty for backplane-cli's default): "},
+	}
+	missing := []string{}
+	for _, s := range managed {
+		if s.value != "" {
+			settings[s.key] = s.value
+			continue
+		}
+		if _, found := settings[s.key]; found {
+			continue
+		}
+		if !utils.IsInteractive() {
+			missing = append(missing, s.key)
+			continue
+		}
+		value, err := utils.Prompt(s.prompt)
+		if err != nil {
+			return err
+		}
+		// Empty values are recorded too, so that the user isn't prompted again
+		settings[s.key] = value
+	}
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode backplane-cli configuration: %w", err)
+	}
+	data = append(data, '\n')
+	if !bytes.Equal(data, existing) {
+		err = os.MkdirAll(filepath.Dir(path), os.FileMode(0o700))
+		if err != nil {
+			return fmt.Errorf("failed to create directory '%s': %w", filepath.Dir(path), err)
+		}
+		err = utils.WriteFile(bytes.NewReader(data), path, os.FileMode(0o600))
+		if err != nil {
+			return fmt.Errorf("failed to write backplane-cli configuration: %w", err)
+		}
+		if existing == nil {
+			fmt.Printf("Created backplane-cli configuration file '%s'\n", path)
+		} else {
+			fmt.Printf("Updated backplane-cli configuration file '%s'\n", path)
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Printf("backplane-cli's configuration does not set '%s'. Run 'backplane-tools configure backplane-cli' to be prompted for them, or set them under 'backplaneCLI' in %s\n", strings.Join(missing, "', '"), config.Path)
+	}
+	return nil
+}
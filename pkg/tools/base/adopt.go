@@ -0,0 +1,72 @@
+package base
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// AdoptedVersionPrefix begins the synthetic version assigned to executables adopted into the managed layout.
+// Because these never match a released version, adopted tools are always replaced by the next upgrade
+const AdoptedVersionPrefix = "adopted-"
+
+// Adopt imports an existing, manually installed executable into the tool's directory under a synthetic version,
+// and links it as the latest version of the tool. The original executable is left in place. The synthetic version
+// assigned to the executable is returned
+func (t *Default) Adopt(path string) (string, error) {
+	sourcePath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to determine absolute path of '%s': %w", path, err)
+	}
+	sourcePath, err = filepath.EvalSymlinks(sourcePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve '%s': %w", path, err)
+	}
+	info, err := os.Stat(sourcePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat '%s': %w", sourcePath, err)
+	}
+	if !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+		return "", fmt.Errorf("'%s' is not an executable file", sourcePath)
+	}
+	if strings.HasPrefix(sourcePath, InstallDir+string(os.PathSeparator)) {
+		return "", fmt.Errorf("'%s' is already managed by backplane-tools", sourcePath)
+	}
+
+	sum, err := utils.Sha256sum(sourcePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to checksum '%s': %w", sourcePath, err)
+	}
+	version := AdoptedVersionPrefix + sum[:12]
+
+	versionedDir := t.VersionDir(version)
+	err = os.RemoveAll(versionedDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to remove existing directory '%s': %w", versionedDir, err)
+	}
+	err = os.MkdirAll(versionedDir, os.FileMode(0o755))
+	if err != nil {
+		return "", fmt.Errorf("failed to create version-specific directory '%s': %w", versionedDir, err)
+	}
+
+	executablePath := filepath.Join(versionedDir, t.executableName)
+	err = copyFile(sourcePath, executablePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to copy '%s' to '%s': %w", sourcePath, executablePath, err)
+	}
+	t.RecordArtifact(version, executablePath, "file://"+sourcePath, "adopted")
+
+	// Only the main executable can be adopted, so any additional executables remain linked to the version
+	// they were installed with
+	err = t.linkMainExecutable(executablePath)
+	if err != nil {
+		t.pendingArtifacts = nil
+		return "", err
+	}
+	t.recordPendingArtifacts()
+	t.installedVersion = version
+	return version, nil
+}
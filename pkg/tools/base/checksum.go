@@ -0,0 +1,32 @@
+package base
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/openshift/backplane-tools/pkg/config"
+)
+
+// ApplyChecksumPolicy calls check to validate the asset at the provided path against the named checksum file, handling
+// the outcome according to the checksum policy configured for the tool. The returned description of how the asset
+// was verified is suitable for recording in the inventory
+func (t *Default) ApplyChecksumPolicy(assetPath, checksumName string, check func() error) (string, error) {
+	asset := filepath.Base(assetPath)
+	switch config.Get().ChecksumPolicy(t.name) {
+	case config.ChecksumSkip:
+		fmt.Printf("WARNING: skipping checksum verification for '%s' per the configured checksum policy\n", asset)
+		return "none (checksum policy: skip)", nil
+	case config.ChecksumWarn:
+		err := check()
+		if err != nil {
+			fmt.Printf("WARNING: failed to verify checksum for '%s': %v. Continuing per the configured checksum policy\n", asset, err)
+			return fmt.Sprintf("unverified (%s mismatch ignored per checksum policy)", checksumName), nil
+		}
+	default:
+		err := check()
+		if err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%s (%s)", VerifyChecksum, checksumName), nil
+}
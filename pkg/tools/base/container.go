@@ -0,0 +1,63 @@
+package base
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/openshift/backplane-tools/pkg/sources"
+	"github.com/openshift/backplane-tools/pkg/sources/container"
+)
+
+// Container implements the installation of tools which are distributed within a container image. The executable is
+// extracted from the image's layers; no container runtime is required
+type Container struct {
+	// Default defines the default Tool implementation
+	Default
+	// Source defines the image containing the tool
+	Source *container.Source
+	// Path is the location of the tool's executable within the image
+	Path string
+}
+
+// ToolSource returns the source the tool is installed from
+func (t *Container) ToolSource() sources.Source {
+	return t.Source
+}
+
+// LatestVersion returns the version of the image containing the tool
+func (t *Container) LatestVersion() (string, error) {
+	if t.latestVersion == "" {
+		version, err := t.Source.ResolveLatest()
+		if err != nil {
+			return "", err
+		}
+		t.latestVersion = version
+	}
+	return t.latestVersion, nil
+}
+
+// Install extracts the tool's executable from the image into a new versioned directory
+func (t *Container) Install() error {
+	image, err := t.Source.FetchImage()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve image '%s': %w", t.Source, err)
+	}
+	version := image.Version()
+
+	versionedDir := filepath.Join(t.ToolDir(), version)
+	err = os.MkdirAll(versionedDir, os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create version-specific directory '%s': %w", versionedDir, err)
+	}
+
+	// Each layer's digest is verified as the executable is extracted
+	executablePath, err := t.Source.Extract(image, t.Path, versionedDir)
+	if err != nil {
+		return fmt.Errorf("failed to extract '%s': %w", t.Path, err)
+	}
+	imageRef := fmt.Sprintf("docker://%s/%s@%s", t.Source.Registry, t.Source.Repository, image.Digest)
+	t.RecordArtifact(version, executablePath, imageRef, fmt.Sprintf("digest (%s)", image.Digest))
+
+	return t.LinkExecutable(executablePath)
+}
@@ -5,22 +5,13 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/inventory"
 	"github.com/openshift/backplane-tools/pkg/utils"
 )
 
-var InstallDir = func() string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		panic(fmt.Errorf("failed to retrieve $HOME dir: %w", err))
-	}
-	return filepath.Join(homeDir, ".local", "bin", "backplane")
-}()
-
-var LatestDir = func() string {
-	return filepath.Join(InstallDir, "latest")
-}()
-
 type Default struct {
 	// Name defines the 'formal' name this tool is referred to within this program
 	name string
@@ -34,6 +25,22 @@ type Default struct {
 
 	// latestVersion is the latest version of the tool available for install
 	latestVersion string
+
+	// aliases are additional names the tool's executable is published under in the latest directory
+	aliases []string
+
+	// description briefly describes what the tool does
+	description string
+
+	// executables are the locations of any executables the tool provides in addition to its main executable,
+	// relative to the versioned directory. Each is published in the latest directory under its file name
+	executables []string
+
+	// smokeTest is the command run to verify the tool's executable works once installed
+	smokeTest SmokeTest
+
+	// pendingArtifacts are the artifacts downloaded by the current install, recorded once the tool is linked
+	pendingArtifacts []inventory.Artifact
 }
 
 // NewDefault creates a Default tool with the provided name
@@ -64,6 +71,194 @@ func (t *Default) SymlinkPath() string {
 	return filepath.Join(LatestDir, t.executableName)
 }
 
+// AddExecutables adds executables the tool provides in addition to its main executable. Each path is relative
+// to the versioned directory; if no file exists at that location, the versioned directory is searched for an
+// executable with the same name
+func (t *Default) AddExecutables(paths ...string) {
+	t.executables = append(t.executables, paths...)
+}
+
+// LinkPaths returns the location of each executable the tool publishes in the latest directory, excluding aliases
+func (t *Default) LinkPaths() []string {
+	paths := []string{t.SymlinkPath()}
+	for _, executable := range t.executables {
+		paths = append(paths, filepath.Join(LatestDir, filepath.Base(executable)))
+	}
+	return paths
+}
+
+// SetDescription sets the brief description of what the tool does, used when searching for tools
+func (t *Default) SetDescription(description string) {
+	t.description = description
+}
+
+// Description returns a brief description of what the tool does
+func (t *Default) Description() string {
+	return t.description
+}
+
+// AddAliases adds names the tool's executable is published under in the latest directory, in addition to its
+// executable name
+func (t *Default) AddAliases(aliases ...string) {
+	t.aliases = append(t.aliases, aliases...)
+}
+
+// Aliases returns the additional names the tool's executable is published under: those defined by the tool,
+// followed by any defined in the user's configuration
+func (t *Default) Aliases() []string {
+	aliases := []string{}
+	for _, alias := range append(append([]string{}, t.aliases...), config.Get().ToolAliases(t.name)...) {
+		if !utils.Contains(t.LinkPaths(), filepath.Join(LatestDir, alias)) && !utils.Contains(aliases, alias) {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
+// LinkExecutable links the provided executable into the latest directory under the tool's
+// executable name and each of its aliases, replacing any existing link. Any additional executables
+// the tool provides are linked from the same versioned directory
+func (t *Default) LinkExecutable(executablePath string) error {
+	err := t.linkMainExecutable(executablePath)
+	if err != nil {
+		t.pendingArtifacts = nil
+		return err
+	}
+	err = t.linkExecutables(executablePath)
+	if err != nil {
+		t.pendingArtifacts = nil
+		return err
+	}
+	t.recordPendingArtifacts()
+	return nil
+}
+
+// linkMainExecutable links the provided executable into the latest directory under the tool's executable
+// name and each of its aliases
+func (t *Default) linkMainExecutable(executablePath string) error {
+	err := t.recordVersionExecutable(executablePath)
+	if err != nil {
+		fmt.Printf("WARNING: failed to record executable '%s': %v\n", executablePath, err)
+	}
+	err = Link(executablePath, t.SymlinkPath())
+	if err != nil {
+		return err
+	}
+	t.linkAliases(executablePath)
+	return nil
+}
+
+// linkExecutables links each of the tool's additional executables from the versioned directory containing
+// the provided main executable
+func (t *Default) linkExecutables(executablePath string) error {
+	if len(t.executables) == 0 {
+		return nil
+	}
+	version, _, found := t.splitVersionPath(executablePath)
+	if !found {
+		return fmt.Errorf("failed to link additional executables: '%s' is not within '%s'", executablePath, t.ToolDir())
+	}
+	versionedDir := t.VersionDir(version)
+	for _, executable := range t.executables {
+		path, err := FindBinary(versionedDir, executable)
+		if err != nil {
+			return fmt.Errorf("failed to link '%s': %w", filepath.Base(executable), err)
+		}
+		err = Link(path, filepath.Join(LatestDir, filepath.Base(executable)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linkAliases links the provided executable into the latest directory under each of the tool's aliases.
+// Aliases which would replace a file not owned by this tool are skipped, and failures to link one are only warned of
+func (t *Default) linkAliases(executablePath string) {
+	aliases := t.Aliases()
+	if len(aliases) == 0 {
+		return
+	}
+	links, err := readLinks()
+	if err != nil {
+		fmt.Printf("WARNING: failed to link aliases of %s: %v\n", t.name, err)
+		return
+	}
+	for _, alias := range aliases {
+		aliasPath := filepath.Join(LatestDir, alias)
+		target, recorded := links[aliasPath]
+		if recorded && !t.owns(target) {
+			fmt.Printf("WARNING: not linking alias '%s' of %s: it is already linked to '%s'\n", alias, t.name, target)
+			continue
+		}
+		if !recorded {
+			exists, err := utils.FileExists(aliasPath)
+			if err != nil || exists {
+				fmt.Printf("WARNING: not linking alias '%s' of %s: a file not managed by backplane-tools exists at '%s'\n", alias, t.name, aliasPath)
+				continue
+			}
+		}
+		err = Link(executablePath, aliasPath)
+		if err != nil {
+			fmt.Printf("WARNING: failed to link alias '%s' of %s: %v\n", alias, t.name, err)
+		}
+	}
+}
+
+// owns returns true if the provided path is within the tool's directory
+func (t *Default) owns(path string) bool {
+	return strings.HasPrefix(path, t.ToolDir()+string(os.PathSeparator))
+}
+
+// ownedLinks returns the files in the latest directory which refer to one of the tool's executables
+func (t *Default) ownedLinks() ([]string, error) {
+	links, err := readLinks()
+	if err != nil {
+		return []string{}, err
+	}
+	owned := []string{}
+	for linkPath, target := range links {
+		if t.owns(target) {
+			owned = append(owned, linkPath)
+		}
+	}
+	return owned, nil
+}
+
+// RecordArtifact notes the downloaded file at the provided path as an artifact of the version being installed. It's
+// added to the inventory, and to the version's manifest, once the tool's executable has been linked
+func (t *Default) RecordArtifact(version, path, sourceURL, verification string) {
+	sum, err := utils.Sha256sum(path)
+	if err != nil {
+		fmt.Printf("WARNING: failed to record '%s' in inventory: %v\n", path, err)
+		return
+	}
+	t.pendingArtifacts = append(t.pendingArtifacts, inventory.Artifact{
+		Tool:         t.name,
+		Version:      version,
+		Name:         filepath.Base(path),
+		SourceURL:    sourceURL,
+		SHA256:       sum,
+		Verification: verification,
+		InstalledAt:  time.Now().UTC(),
+	})
+}
+
+// recordPendingArtifacts adds the artifacts noted by RecordArtifact to the inventory and their version's manifest
+func (t *Default) recordPendingArtifacts() {
+	for _, artifact := range t.pendingArtifacts {
+		err := inventory.Record(InventoryPath, artifact)
+		if err != nil {
+			fmt.Printf("WARNING: failed to record '%s' in inventory: %v\n", artifact.Name, err)
+		}
+		err = t.recordVersionArtifact(artifact)
+		if err != nil {
+			fmt.Printf("WARNING: failed to record '%s' in version manifest: %v\n", artifact.Name, err)
+		}
+	}
+	t.pendingArtifacts = nil
+}
+
 // Name returns the name of the tool
 func (t *Default) Name() string {
 	return t.name
@@ -75,7 +270,8 @@ func (t *Default) ExecutableName() string {
 	return t.executableName
 }
 
-// Confiure is currently unused
+// Configure performs any setup the tool requires after installation. Tools which require no
+// setup do nothing
 func (t *Default) Configure() error {
 	return nil
 }
@@ -90,13 +286,26 @@ func (t *Default) Remove() error {
 		return fmt.Errorf("failed to remove %s: %w", toolDir, err)
 	}
 
+	err = inventory.Forget(InventoryPath, t.name)
+	if err != nil {
+		fmt.Printf("WARNING: failed to remove '%s' from inventory: %v\n", t.name, err)
+	}
+
 	// Remove all symlinks owned by this tool
-	latestFilePath := t.SymlinkPath()
-	err = os.Remove(latestFilePath)
+	owned, err := t.ownedLinks()
 	if err != nil {
-		return fmt.Errorf("failed to remove symlinked file %s: %w", latestFilePath, err)
+		fmt.Printf("WARNING: failed to determine links owned by %s: %v\n", t.name, err)
 	}
-	return nil
+	for _, linkPath := range owned {
+		if linkPath == t.SymlinkPath() {
+			continue
+		}
+		err = Unlink(linkPath)
+		if err != nil {
+			fmt.Printf("WARNING: %v\n", err)
+		}
+	}
+	return Unlink(t.SymlinkPath())
 }
 
 // Installed validates whether the tool has already been installed under the
@@ -110,9 +319,9 @@ func (t *Default) Installed() (bool, error) {
 func (t *Default) InstalledVersion() (string, error) {
 	if t.installedVersion == "" {
 		latestFilePath := t.SymlinkPath()
-		latestFileTarget, err := filepath.EvalSymlinks(latestFilePath)
+		latestFileTarget, err := ResolveLink(latestFilePath)
 		if err != nil {
-			return "", fmt.Errorf("failed to retrieve symlinked file %s: %w", latestFilePath, err)
+			return "", fmt.Errorf("failed to retrieve linked file %s: %w", latestFilePath, err)
 		}
 		rootDirTarget, err := filepath.EvalSymlinks(InstallDir)
 		if err != nil {
@@ -1,7 +1,21 @@
 package base
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	gogithub "github.com/google/go-github/v51/github"
+
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/sources"
 	"github.com/openshift/backplane-tools/pkg/sources/github"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/openshift/backplane-tools/pkg/verify"
 )
 
 type Github struct {
@@ -11,6 +25,20 @@ type Github struct {
 	Source *github.Source
 	// VersionInLatestTag in
 	VersionInLatestTag bool
+	// Spec describes how the tool is installed from its release assets. Tools which
+	// define their own Install() do not need to provide one
+	Spec *AssetSpec
+
+	// targetVersion is the tag of the release installed in place of the latest release, if set
+	targetVersion string
+
+	// localFile is the asset installed in place of the latest release's asset, if set
+	localFile *LocalFile
+}
+
+// ToolSource returns the source the tool is installed from
+func (t *Github) ToolSource() sources.Source {
+	return t.Source
 }
 
 func (t *Github) _LatestVersion() (string, error) {
@@ -24,6 +52,89 @@ func (t *Github) _LatestVersion() (string, error) {
 	return release.GetTagName(), nil
 }
 
+// maxVersionPages bounds how many pages of releases or tags are retrieved when listing a tool's versions
+const maxVersionPages = 5
+
+// ListVersions returns the tags of the tool's published releases - or of the repository's tags, for tools whose
+// version is determined by the latest tag - from oldest to newest. Only the most recent releases are listed, and
+// draft releases are ignored
+func (t *Github) ListVersions() ([]string, error) {
+	versions := []string{}
+	opts := &gogithub.ListOptions{PerPage: 100}
+	for opts.Page = 1; opts.Page <= maxVersionPages; opts.Page++ {
+		var (
+			page    []string
+			fetched int
+		)
+		if t.VersionInLatestTag {
+			tags, err := t.Source.ListTags(opts)
+			if err != nil {
+				return []string{}, fmt.Errorf("failed to list tags of '%s': %w", t.Name(), err)
+			}
+			fetched = len(tags)
+			for _, tag := range tags {
+				page = append(page, tag.GetName())
+			}
+		} else {
+			releases, err := t.Source.ListReleases(opts)
+			if err != nil {
+				return []string{}, fmt.Errorf("failed to list releases of '%s': %w", t.Name(), err)
+			}
+			fetched = len(releases)
+			for _, release := range releases {
+				if !release.GetDraft() {
+					page = append(page, release.GetTagName())
+				}
+			}
+		}
+		versions = append(versions, page...)
+		if fetched < opts.PerPage {
+			break
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return utils.CompareVersions(versions[i], versions[j]) < 0
+	})
+	return versions, nil
+}
+
+// TargetVersion selects the release with the provided tag to be installed in place of the latest release. Once
+// selected, LatestVersion reports the targeted version
+func (t *Github) TargetVersion(version string) {
+	t.targetVersion = version
+	t.latestVersion = version
+}
+
+// OverrideSource replaces the GitHub repository the tool is retrieved from with the provided override. Only tools
+// installed according to a Spec support overrides, as other tools retrieve their executables from elsewhere
+func (t *Github) OverrideSource(override config.SourceOverride) error {
+	if t.Source == nil || t.Spec == nil {
+		return fmt.Errorf("%s is not installed from GitHub releases, so its source can't be overridden", t.Name())
+	}
+	owner, repo := t.Source.Owner, t.Source.Repo
+	if override.Repository != "" {
+		owner, repo, _ = strings.Cut(override.Repository, "/")
+	}
+	if override.URL == "" {
+		t.Source = github.NewSource(owner, repo)
+		return nil
+	}
+	source, err := github.NewEnterpriseSource(owner, repo, override.URL)
+	if err != nil {
+		return err
+	}
+	t.Source = source
+	return nil
+}
+
+// fetchRelease returns the release to install: the targeted release, if one was selected, or the latest release
+func (t *Github) fetchRelease() (*gogithub.RepositoryRelease, error) {
+	if t.targetVersion != "" {
+		return t.Source.FetchReleaseByTag(t.targetVersion)
+	}
+	return t.Source.FetchLatestRelease()
+}
+
 func (t *Github) LatestVersion() (string, error) {
 	if t.latestVersion == "" {
 		version, err := t._LatestVersion()
@@ -34,3 +145,271 @@ func (t *Github) LatestVersion() (string, error) {
 	}
 	return t.latestVersion, nil
 }
+
+// Install fetches the latest - or targeted - release of the tool from GitHub and installs it according to the tool's Spec
+func (t *Github) Install() error {
+	if t.Spec == nil {
+		return fmt.Errorf("no asset spec defined for '%s'", t.Name())
+	}
+	if t.localFile != nil {
+		return t.installLocalFile()
+	}
+
+	// Pull the release from GH
+	release, err := t.fetchRelease()
+	if err != nil {
+		return err
+	}
+
+	toolAsset, err := t.findToolAsset(release.Assets)
+	if err != nil {
+		return err
+	}
+
+	// Tools verified using their release notes have no verification asset
+	assets := []*gogithub.ReleaseAsset{toolAsset}
+	verificationName := "release notes"
+	if t.verifyMethod() != VerifyReleaseNotes {
+		verificationAsset, err := t.findVerificationAsset(release.Assets, toolAsset)
+		if err != nil {
+			return err
+		}
+		assets = append([]*gogithub.ReleaseAsset{verificationAsset}, assets...)
+		verificationName = verificationAsset.GetName()
+	}
+
+	// Download the arch- & os-specific assets
+	versionedDir := filepath.Join(t.ToolDir(), release.GetTagName())
+	err = os.MkdirAll(versionedDir, os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create version-specific directory '%s': %w", versionedDir, err)
+	}
+
+	err = t.Source.DownloadReleaseAssets(assets, versionedDir)
+	if err != nil {
+		return fmt.Errorf("failed to download one or more assets: %w", err)
+	}
+
+	// Verify the downloaded assets
+	toolAssetFilepath := filepath.Join(versionedDir, toolAsset.GetName())
+	verificationFilepath := filepath.Join(versionedDir, verificationName)
+	verification, err := t.verify(toolAssetFilepath, verificationFilepath, release.GetBody())
+	if err != nil {
+		return fmt.Errorf("failed to verify '%s': %w. Please retry installation. If issue persists, this tool can be downloaded manually at %s", toolAsset.GetName(), err, toolAsset.GetBrowserDownloadURL())
+	}
+
+	err = t.verifyProvenance(release, toolAssetFilepath)
+	if err != nil {
+		return err
+	}
+	t.RecordArtifact(release.GetTagName(), toolAssetFilepath, toolAsset.GetBrowserDownloadURL(), verification)
+	return t.installAsset(versionedDir, toolAssetFilepath, toolAsset.GetName())
+}
+
+// installAsset extracts the tool's executable from the asset at the provided path according to the tool's Spec, and
+// links it as latest
+func (t *Github) installAsset(versionedDir, assetPath, assetName string) error {
+	// Extract the executable
+	var err error
+	binaryPath := t.Spec.BinaryPath
+	switch t.Spec.Archive {
+	case ArchiveNone:
+		if binaryPath == "" {
+			binaryPath = assetName
+		}
+	case ArchiveTarGz, ArchiveZip, ArchiveAuto:
+		err = utils.Unarchive(assetPath, versionedDir)
+	case ArchiveOptional:
+		var format utils.ArchiveFormat
+		format, err = utils.DetectArchiveFormat(assetPath)
+		if err == nil && format != utils.ArchiveFormatUnknown {
+			err = utils.Unarchive(assetPath, versionedDir)
+		} else if err == nil && binaryPath == "" {
+			binaryPath = assetName
+		}
+	default:
+		err = fmt.Errorf("unsupported archive type '%s'", t.Spec.Archive)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to unarchive the '%s' asset file '%s': %w", t.Name(), assetPath, err)
+	}
+	if binaryPath == "" {
+		binaryPath = t.ExecutableName()
+	}
+	executablePath, err := FindBinary(versionedDir, binaryPath)
+	if err != nil {
+		return err
+	}
+
+	// Link as latest
+	return t.LinkExecutable(executablePath)
+}
+
+// findToolAsset returns the release asset matching the tool's Spec. If several match, the most suitable is chosen
+// according to the preferences of the user and the tool's Spec
+func (t *Github) findToolAsset(assets []*gogithub.ReleaseAsset) (*gogithub.ReleaseAsset, error) {
+	matches := github.FindAssetsForArchAndOS(assets)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: no assets of %s are published for %s/%s", utils.ErrUnsupportedPlatform, t.Name(), utils.TargetOS, utils.TargetArch)
+	}
+	matches = github.FindAssetsContaining(t.Spec.Include, matches)
+	matches = github.FindAssetsExcluding(t.Spec.Exclude, matches)
+	prefer := append(append([]string{}, config.Get().AssetPreferences(t.Name())...), t.Spec.Prefer...)
+	asset, err := github.SelectAsset(matches, prefer)
+	if errors.Is(err, utils.ErrAssetNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w. Configure 'preferAssets' for %s to choose between them", err, t.Name())
+	}
+	return asset, nil
+}
+
+// findVerificationAsset returns the single release asset used to verify the tool asset. Assets published for the tool
+// asset alone are preferred over those matching the Terms or Pattern of the tool's Spec
+func (t *Github) findVerificationAsset(assets []*gogithub.ReleaseAsset, toolAsset *gogithub.ReleaseAsset) (*gogithub.ReleaseAsset, error) {
+	spec := t.Spec.Verification
+	for _, suffix := range spec.AssetSuffixes {
+		for _, asset := range assets {
+			if asset.GetName() == toolAsset.GetName()+suffix {
+				return asset, nil
+			}
+		}
+	}
+	if spec.MatchSystem {
+		assets = github.FindAssetsForArchAndOS(assets)
+	}
+
+	var matches []*gogithub.ReleaseAsset
+	if spec.Pattern != "" {
+		var err error
+		matches, err = github.FindAssetsMatching(spec.Pattern, assets)
+		if err != nil {
+			return nil, fmt.Errorf("failed to filter assets by regular expression: %w", err)
+		}
+	} else {
+		matches = github.FindAssetsContaining(spec.Terms, assets)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no %s asset found: %w", t.verifyMethod(), utils.ErrAssetNotFound)
+	}
+	if len(matches) != 1 {
+		return nil, fmt.Errorf("unexpected number of %s assets found: expected 1, got %d.\nMatching assets: %v", t.verifyMethod(), len(matches), matches)
+	}
+	return matches[0], nil
+}
+
+// verifyMethod returns the method used to verify the tool's asset
+func (t *Github) verifyMethod() VerifyMethod {
+	if t.Spec.Verification.Method == "" {
+		return VerifyChecksum
+	}
+	return t.Spec.Verification.Method
+}
+
+// verify validates the asset at the provided path using the verification file or, for tools verified using their
+// release notes, the release's description. A description of how the asset was verified is returned
+func (t *Github) verify(assetPath, verificationPath, releaseNotes string) (string, error) {
+	verificationName := filepath.Base(verificationPath)
+	switch t.verifyMethod() {
+	case VerifyReleaseNotes:
+		return t.ApplyChecksumPolicy(assetPath, verificationName, func() error {
+			return t.verifyReleaseNotes(assetPath, releaseNotes)
+		})
+	case VerifyGPG:
+		return fmt.Sprintf("%s (%s)", VerifyGPG, verificationName), t.verifySignature(assetPath, verificationPath)
+	case VerifyChecksum:
+		return t.ApplyChecksumPolicy(assetPath, verificationName, func() error {
+			return t.verifyChecksum(assetPath, verificationPath)
+		})
+	default:
+		return "", fmt.Errorf("unsupported verification method '%s'", t.verifyMethod())
+	}
+}
+
+// verifySignature validates the asset at the provided path against the detached GPG signature at signaturePath,
+// which must have been made by one of the keys trusted to sign the tool's assets
+func (t *Github) verifySignature(assetPath, signaturePath string) error {
+	keyRing, err := trustedKeyRing(t.Name(), t.Spec.Verification.KeyURL, t.Spec.Verification.Fingerprints)
+	if err != nil {
+		return err
+	}
+	err = utils.VerifyGPGSignature(assetPath, signaturePath, keyRing)
+	if errors.Is(err, pgperrors.ErrUnknownIssuer) {
+		return fmt.Errorf("%w. Once you've confirmed the key belongs to the maintainers of %s, run 'backplane-tools trust add %s <fingerprint>'", err, t.Name(), t.Name())
+	}
+	return err
+}
+
+// SigningKeys returns the fingerprints of the keys pinned to sign the tool's assets. found is false if the tool's
+// assets aren't verified using GPG signatures
+func (t *Github) SigningKeys() (pinned []string, found bool) {
+	if t.Spec == nil || t.verifyMethod() != VerifyGPG {
+		return []string{}, false
+	}
+	return t.Spec.Verification.Fingerprints, true
+}
+
+// verifyProvenance validates the tool asset at the provided path against the SLSA provenance published in the
+// release, according to the provenance policy configured for the tool
+func (t *Github) verifyProvenance(release *gogithub.RepositoryRelease, assetPath string) error {
+	policy := config.Get().ProvenancePolicy(t.Name())
+	if policy == config.ProvenanceOff {
+		return nil
+	}
+
+	err := t.checkProvenance(release.Assets, assetPath, release.GetTagName())
+	if err == nil {
+		fmt.Printf("Verified SLSA provenance for '%s'\n", filepath.Base(assetPath))
+		return nil
+	}
+	if policy == config.ProvenanceWarn {
+		fmt.Printf("WARNING: failed to verify SLSA provenance for '%s': %v\n", filepath.Base(assetPath), err)
+		return nil
+	}
+	return fmt.Errorf("failed to verify SLSA provenance for '%s': %w", filepath.Base(assetPath), err)
+}
+
+// checkProvenance downloads the release's provenance asset and verifies the tool asset against it
+func (t *Github) checkProvenance(assets []*gogithub.ReleaseAsset, assetPath, tag string) error {
+	terms := t.Spec.Provenance
+	if len(terms) == 0 {
+		terms = DefaultProvenanceTerms
+	}
+	matches := github.FindAssetsContaining(terms, assets)
+	if len(matches) != 1 {
+		return fmt.Errorf("unexpected number of provenance assets found: expected 1, got %d.\nMatching assets: %v", len(matches), matches)
+	}
+	provenanceAsset := matches[0]
+
+	dir := filepath.Dir(assetPath)
+	err := t.Source.DownloadReleaseAssets([]*gogithub.ReleaseAsset{provenanceAsset}, dir)
+	if err != nil {
+		return fmt.Errorf("failed to download provenance asset: %w", err)
+	}
+
+	return verify.Provenance(assetPath, filepath.Join(dir, provenanceAsset.GetName()), t.Source.String(), tag)
+}
+
+// verifyChecksum compares the checksum of the asset at the provided path to the value recorded in the checksum file
+func (t *Github) verifyChecksum(assetPath, checksumPath string) error {
+	format := t.Spec.Verification.Format
+	if format == "" {
+		format = verify.FormatAuto
+	}
+	return verify.File(assetPath, checksumPath, format)
+}
+
+// verifyReleaseNotes compares the checksum of the asset at the provided path to the value published in the release's
+// description
+func (t *Github) verifyReleaseNotes(assetPath, releaseNotes string) error {
+	format := t.Spec.Verification.Format
+	if format == "" {
+		format = verify.FormatAuto
+	}
+	expected, err := verify.ChecksumsFromText(releaseNotes, filepath.Base(assetPath), format)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve checksum from release notes: %w", err)
+	}
+	return verify.Sum(assetPath, expected...)
+}
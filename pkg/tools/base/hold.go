@@ -0,0 +1,86 @@
+package base
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// holdsPath is the location of the file listing the tools which are held at their installed version
+var holdsPath = func() string {
+	return filepath.Join(StateDir, "holds.json")
+}()
+
+// Hold prevents the named tool from being upgraded until it is unheld
+func Hold(tool string) error {
+	holds, err := readHolds()
+	if err != nil {
+		return err
+	}
+	if utils.Contains(holds, tool) {
+		return nil
+	}
+	return writeHolds(append(holds, tool))
+}
+
+// Unhold allows the named tool to be upgraded again
+func Unhold(tool string) error {
+	holds, err := readHolds()
+	if err != nil {
+		return err
+	}
+	remaining := []string{}
+	for _, held := range holds {
+		if held != tool {
+			remaining = append(remaining, held)
+		}
+	}
+	return writeHolds(remaining)
+}
+
+// Held returns true if the named tool is being held at its installed version
+func Held(tool string) (bool, error) {
+	holds, err := readHolds()
+	if err != nil {
+		return false, err
+	}
+	return utils.Contains(holds, tool), nil
+}
+
+func readHolds() ([]string, error) {
+	holds := []string{}
+	data, err := os.ReadFile(holdsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return holds, nil
+	}
+	if err != nil {
+		return holds, fmt.Errorf("failed to read '%s': %w", holdsPath, err)
+	}
+	err = json.Unmarshal(data, &holds)
+	if err != nil {
+		return holds, fmt.Errorf("failed to parse '%s': %w", holdsPath, err)
+	}
+	return holds, nil
+}
+
+func writeHolds(holds []string) error {
+	sort.Strings(holds)
+	data, err := json.MarshalIndent(holds, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode holds: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(holdsPath), os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	err = os.WriteFile(holdsPath, data, os.FileMode(0o644))
+	if err != nil {
+		return fmt.Errorf("failed to write '%s': %w", holdsPath, err)
+	}
+	return nil
+}
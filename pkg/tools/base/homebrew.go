@@ -0,0 +1,86 @@
+package base
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/openshift/backplane-tools/pkg/sources"
+	"github.com/openshift/backplane-tools/pkg/sources/homebrew"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/openshift/backplane-tools/pkg/verify"
+)
+
+// Homebrew implements the installation of tools from the bottles published for a Homebrew formula. Only bottles
+// which can be installed to any location are supported
+type Homebrew struct {
+	// Default defines the default Tool implementation
+	Default
+	// Source defines the source of the tool in Homebrew
+	Source *homebrew.Source
+}
+
+// ToolSource returns the source the tool is installed from
+func (t *Homebrew) ToolSource() sources.Source {
+	return t.Source
+}
+
+// LatestVersion returns the current version of the tool's formula
+func (t *Homebrew) LatestVersion() (string, error) {
+	if t.latestVersion == "" {
+		version, err := t.Source.ResolveLatest()
+		if err != nil {
+			return "", err
+		}
+		t.latestVersion = version
+	}
+	return t.latestVersion, nil
+}
+
+// Install downloads the bottle of the tool's formula matching the local system and extracts it
+func (t *Homebrew) Install() error {
+	formula, err := t.Source.FetchFormula()
+	if err != nil {
+		return err
+	}
+	tag, bottle, err := homebrew.FindBottle(formula.Bottle.Stable.Files)
+	if err != nil {
+		return err
+	}
+	if bottle.Cellar != homebrew.RelocatableCellar {
+		return fmt.Errorf("the '%s' bottle of '%s' must be installed within a Homebrew prefix (cellar '%s'), and is not supported", tag, formula.Name, bottle.Cellar)
+	}
+
+	version := formula.Version()
+	versionedDir := filepath.Join(t.ToolDir(), version)
+	err = os.MkdirAll(versionedDir, os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create version-specific directory '%s': %w", versionedDir, err)
+	}
+
+	bottlePath, err := t.Source.Download(sources.Artifact{Name: formula.BottleName(tag), URL: bottle.URL}, versionedDir)
+	if err != nil {
+		return fmt.Errorf("failed to download bottle '%s': %w", formula.BottleName(tag), err)
+	}
+
+	// The formulae API publishes the sha256sum of each bottle
+	verification, err := t.ApplyChecksumPolicy(bottlePath, "homebrew sha256", func() error {
+		return verify.Sum(bottlePath, bottle.Sha256)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to verify '%s': %w", filepath.Base(bottlePath), err)
+	}
+	t.RecordArtifact(version, bottlePath, bottle.URL, verification)
+
+	err = utils.Unarchive(bottlePath, versionedDir)
+	if err != nil {
+		return fmt.Errorf("failed to unarchive '%s': %w", bottlePath, err)
+	}
+
+	// Bottles contain the formula's keg: <formula>/<version>/bin/<executable>
+	executablePath, err := FindBinary(versionedDir, filepath.Join(formula.Name, version, "bin", t.ExecutableName()))
+	if err != nil {
+		return err
+	}
+	return t.LinkExecutable(executablePath)
+}
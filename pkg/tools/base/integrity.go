@@ -0,0 +1,157 @@
+package base
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// integrityPath is the location of the file recording the digest of each executable published into the latest directory
+var integrityPath = func() string {
+	return filepath.Join(StateDir, "integrity.json")
+}()
+
+// ErrNoDigest indicates no digest was recorded for a link, typically because it was published
+// by an older version of backplane-tools
+var ErrNoDigest = errors.New("no digest has been recorded")
+
+// digest records the expected contents of a file published into the latest directory
+type digest struct {
+	// Target is the path to the executable the link refers to
+	Target string `json:"target"`
+
+	// TargetSHA256 is the sha256sum of the target at the time it was published
+	TargetSHA256 string `json:"targetSHA256"`
+
+	// LinkSHA256 is the sha256sum of the published file itself. Only recorded when the
+	// link is not a symlink (ie - shims and copies)
+	LinkSHA256 string `json:"linkSHA256,omitempty"`
+}
+
+// CheckIntegrity verifies that neither the file published at linkPath, nor the executable it refers to, have been
+// modified since they were installed. ErrNoDigest is returned if the link's digests were never recorded
+func CheckIntegrity(linkPath string) error {
+	digests, err := readDigests(integrityPath)
+	if err != nil {
+		return err
+	}
+	expected, found := digests[linkPath]
+	if !found {
+		return ErrNoDigest
+	}
+
+	actual, err := digestLink(linkPath, expected.Target)
+	if err != nil {
+		return err
+	}
+	if actual.TargetSHA256 != expected.TargetSHA256 {
+		return fmt.Errorf("'%s' has been modified since it was installed: expected sha256 '%s', got '%s'", expected.Target, expected.TargetSHA256, actual.TargetSHA256)
+	}
+	if actual.LinkSHA256 != expected.LinkSHA256 {
+		return fmt.Errorf("'%s' has been modified since it was installed: expected sha256 '%s', got '%s'", linkPath, expected.LinkSHA256, actual.LinkSHA256)
+	}
+	return nil
+}
+
+// digestLink calculates the digests of the file published at linkPath, which refers to target
+func digestLink(linkPath, target string) (digest, error) {
+	d := digest{Target: target}
+	var err error
+	d.TargetSHA256, err = utils.Sha256sum(target)
+	if err != nil {
+		return digest{}, err
+	}
+
+	info, err := os.Lstat(linkPath)
+	if err != nil {
+		return digest{}, err
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		d.LinkSHA256, err = utils.Sha256sum(linkPath)
+		if err != nil {
+			return digest{}, err
+		}
+	}
+	return d, nil
+}
+
+// recordDigest stores the digests of the file published at linkPath, along with the target it refers to
+func recordDigest(linkPath, target string) error {
+	d, err := digestLink(linkPath, target)
+	if err != nil {
+		return fmt.Errorf("failed to calculate digest for '%s': %w", linkPath, err)
+	}
+	digests, err := readDigests(integrityPath)
+	if err != nil {
+		return err
+	}
+	digests[linkPath] = d
+	return writeDigests(integrityPath, digests)
+}
+
+// forgetDigest removes the digests recorded for the file published at linkPath
+func forgetDigest(linkPath string) error {
+	digests, err := readDigests(integrityPath)
+	if err != nil {
+		return err
+	}
+	if _, found := digests[linkPath]; !found {
+		return nil
+	}
+	delete(digests, linkPath)
+	return writeDigests(integrityPath, digests)
+}
+
+// relocateDigests rewrites the digest records in the file at path using the provided relocation function,
+// recalculating the digests of any published files, which may have been rewritten during relocation
+func relocateDigests(path string, relocate func(string) string) error {
+	digests, err := readDigests(path)
+	if err != nil {
+		return err
+	}
+	relocated := map[string]digest{}
+	for linkPath, d := range digests {
+		linkPath = relocate(linkPath)
+		d.Target = relocate(d.Target)
+		if d.LinkSHA256 != "" {
+			d.LinkSHA256, err = utils.Sha256sum(linkPath)
+			if err != nil {
+				return fmt.Errorf("failed to calculate digest for '%s': %w", linkPath, err)
+			}
+		}
+		relocated[linkPath] = d
+	}
+	return writeDigests(path, relocated)
+}
+
+func readDigests(path string) (map[string]digest, error) {
+	digests := map[string]digest{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return digests, nil
+	}
+	if err != nil {
+		return digests, fmt.Errorf("failed to read '%s': %w", path, err)
+	}
+	err = json.Unmarshal(data, &digests)
+	if err != nil {
+		return digests, fmt.Errorf("failed to parse '%s': %w", path, err)
+	}
+	return digests, nil
+}
+
+func writeDigests(path string, digests map[string]digest) error {
+	data, err := json.MarshalIndent(digests, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode digests: %w", err)
+	}
+	err = os.WriteFile(path, data, os.FileMode(0o644))
+	if err != nil {
+		return fmt.Errorf("failed to write '%s': %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/backplane-tools/pkg/journal"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// activeTransaction identifies the transaction changes to the latest directory are recorded against, if any
+var activeTransaction string
+
+// BeginTransaction records the start of a change to the named tool in the journal, along with the tool's installed
+// versions and links, so that the tool can be restored if the change is interrupted. Changes to the latest
+// directory are recorded against the transaction until it is committed
+func BeginTransaction(toolName string, op journal.Operation) (string, error) {
+	versions, err := InstalledVersions(toolName)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	links, err := toolLinks(toolName)
+	if err != nil {
+		return "", err
+	}
+	tx := journal.Transaction{
+		ID:        fmt.Sprintf("%s-%d", toolName, time.Now().UnixNano()),
+		Tool:      toolName,
+		Operation: op,
+		StartedAt: time.Now().UTC(),
+		Versions:  versions,
+		Links:     links,
+		Steps:     []journal.Step{},
+	}
+	err = journal.Begin(JournalPath, tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	activeTransaction = tx.ID
+	return tx.ID, nil
+}
+
+// RecordStep records progress made by the active transaction, if any, warning when it can't be written
+func RecordStep(format string, args ...any) {
+	if activeTransaction == "" {
+		return
+	}
+	err := journal.AddStep(JournalPath, activeTransaction, fmt.Sprintf(format, args...))
+	if err != nil {
+		fmt.Printf("WARNING: failed to record progress in journal: %v\n", err)
+	}
+}
+
+// CommitTransaction marks the identified transaction as complete, removing it from the journal
+func CommitTransaction(id string) error {
+	if activeTransaction == id {
+		activeTransaction = ""
+	}
+	err := journal.Commit(JournalPath, id)
+	if err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// PendingTransactions returns the transactions which began, but were never committed
+func PendingTransactions() ([]journal.Transaction, error) {
+	j, err := journal.Read(JournalPath)
+	if err != nil {
+		return []journal.Transaction{}, err
+	}
+	return j.Transactions, nil
+}
+
+// RollBack restores the tool changed by the provided install transaction to its state before the transaction
+// began: versions installed by the transaction are removed, and links are returned to their previous targets.
+// The actions taken are returned
+func RollBack(tx journal.Transaction) ([]string, error) {
+	actions := []string{}
+	toolDir := filepath.Join(InstallDir, tx.Tool)
+	versions, err := InstalledVersions(tx.Tool)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return actions, err
+	}
+
+	// Restore links before removing versions, so that links never refer to a removed version
+	links, err := toolLinks(tx.Tool)
+	if err != nil {
+		return actions, err
+	}
+	for linkPath, target := range tx.Links {
+		if links[linkPath] == target {
+			continue
+		}
+		exists, err := utils.FileExists(target)
+		if err != nil || !exists {
+			continue
+		}
+		err = Link(target, linkPath)
+		if err != nil {
+			return actions, err
+		}
+		actions = append(actions, fmt.Sprintf("restored '%s' -> '%s'", linkPath, target))
+	}
+	for linkPath := range links {
+		if _, found := tx.Links[linkPath]; found {
+			continue
+		}
+		err = Unlink(linkPath)
+		if err != nil {
+			return actions, err
+		}
+		actions = append(actions, fmt.Sprintf("removed '%s'", linkPath))
+	}
+
+	for _, version := range versions {
+		if utils.Contains(tx.Versions, version) {
+			continue
+		}
+		versionDir := filepath.Join(toolDir, version)
+		err = os.RemoveAll(versionDir)
+		if err != nil {
+			return actions, fmt.Errorf("failed to remove incomplete version '%s': %w", versionDir, err)
+		}
+		actions = append(actions, fmt.Sprintf("removed incomplete version '%s'", versionDir))
+	}
+	if len(tx.Versions) == 0 {
+		err = os.RemoveAll(toolDir)
+		if err != nil {
+			return actions, fmt.Errorf("failed to remove '%s': %w", toolDir, err)
+		}
+	}
+	return actions, nil
+}
+
+// RepairLinks ensures every file recorded in the latest directory exists and refers to an installed executable.
+// Missing files are recreated, files whose target has been removed are relinked to the same executable within the
+// tool's newest installed version - or removed, if none exists - and temporary files left behind by interrupted
+// changes are deleted. The actions taken are returned
+func RepairLinks() ([]string, error) {
+	actions := []string{}
+	entries, err := os.ReadDir(LatestDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return actions, fmt.Errorf("failed to read '%s': %w", LatestDir, err)
+	}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), ".") || !strings.HasSuffix(entry.Name(), ".tmp") {
+			continue
+		}
+		path := filepath.Join(LatestDir, entry.Name())
+		err = os.Remove(path)
+		if err != nil {
+			return actions, fmt.Errorf("failed to remove temporary file '%s': %w", path, err)
+		}
+		actions = append(actions, fmt.Sprintf("removed temporary file '%s'", path))
+	}
+
+	links, err := readLinks()
+	if err != nil {
+		return actions, err
+	}
+	linkPaths := utils.Keys(links)
+	sort.Strings(linkPaths)
+	for _, linkPath := range linkPaths {
+		target := links[linkPath]
+		targetExists, err := utils.FileExists(target)
+		if err != nil {
+			return actions, err
+		}
+		if !targetExists {
+			target = relocateToNewest(target)
+			if target == "" {
+				err = Unlink(linkPath)
+				if err != nil {
+					return actions, err
+				}
+				actions = append(actions, fmt.Sprintf("removed '%s': its target no longer exists", linkPath))
+				continue
+			}
+		}
+
+		_, err = os.Lstat(linkPath)
+		if err == nil && targetExists {
+			continue
+		}
+		err = Link(target, linkPath)
+		if err != nil {
+			return actions, err
+		}
+		actions = append(actions, fmt.Sprintf("relinked '%s' -> '%s'", linkPath, target))
+	}
+	return actions, nil
+}
+
+// relocateToNewest returns the location of the provided executable within the newest installed version of the tool
+// it belongs to, or an empty string if no installed version contains it
+func relocateToNewest(target string) string {
+	rel, err := filepath.Rel(InstallDir, target)
+	if err != nil {
+		return ""
+	}
+	parts := strings.SplitN(rel, string(os.PathSeparator), 3)
+	if len(parts) != 3 || parts[0] == ".." {
+		return ""
+	}
+	versions, err := InstalledVersions(parts[0])
+	if err != nil {
+		return ""
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return utils.CompareVersions(versions[i], versions[j]) > 0
+	})
+	for _, version := range versions {
+		candidate := filepath.Join(InstallDir, parts[0], version, parts[2])
+		exists, err := utils.FileExists(candidate)
+		if err == nil && exists {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// toolLinks returns the files in the latest directory referring to one of the named tool's executables, mapped to
+// their targets
+func toolLinks(toolName string) (map[string]string, error) {
+	links, err := readLinks()
+	if err != nil {
+		return map[string]string{}, err
+	}
+	toolDir := filepath.Join(InstallDir, toolName) + string(os.PathSeparator)
+	owned := map[string]string{}
+	for linkPath, target := range links {
+		if strings.HasPrefix(target, toolDir) {
+			owned[linkPath] = target
+		}
+	}
+	return owned, nil
+}
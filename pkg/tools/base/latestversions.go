@@ -0,0 +1,66 @@
+package base
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// latestVersionsPath is the location of the file recording the latest version of each installed tool, as of the
+// most recent check. This allows outdated tools to be reported without reaching any source
+var latestVersionsPath = func() string {
+	return filepath.Join(StateDir, "latest-versions.json")
+}()
+
+// LatestVersions records the latest versions of the installed tools, as of the most recent check
+type LatestVersions struct {
+	// CheckedAt is when the versions were last retrieved from each tool's source
+	CheckedAt time.Time `json:"checkedAt,omitempty"`
+
+	// RefreshStartedAt is when a background refresh of the versions was last started, so that several aren't run at once
+	RefreshStartedAt time.Time `json:"refreshStartedAt,omitempty"`
+
+	// Versions maps each tool's name to the latest version available for it
+	Versions map[string]string `json:"versions"`
+}
+
+// ReadLatestVersions returns the latest versions recorded by the most recent check. An empty record is returned if
+// no check has been made yet
+func ReadLatestVersions() (LatestVersions, error) {
+	latest := LatestVersions{Versions: map[string]string{}}
+	data, err := os.ReadFile(latestVersionsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return latest, nil
+	}
+	if err != nil {
+		return latest, fmt.Errorf("failed to read '%s': %w", latestVersionsPath, err)
+	}
+	err = json.Unmarshal(data, &latest)
+	if err != nil {
+		return latest, fmt.Errorf("failed to parse '%s': %w", latestVersionsPath, err)
+	}
+	if latest.Versions == nil {
+		latest.Versions = map[string]string{}
+	}
+	return latest, nil
+}
+
+// WriteLatestVersions records the provided latest versions, replacing any previously recorded
+func WriteLatestVersions(latest LatestVersions) error {
+	data, err := json.MarshalIndent(latest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode latest versions: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(latestVersionsPath), os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	err = os.WriteFile(latestVersionsPath, data, os.FileMode(0o644))
+	if err != nil {
+		return fmt.Errorf("failed to write '%s': %w", latestVersionsPath, err)
+	}
+	return nil
+}
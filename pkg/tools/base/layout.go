@@ -0,0 +1,233 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+var homeDir = func() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		panic(fmt.Errorf("failed to retrieve $HOME dir: %w", err))
+	}
+	return homeDir
+}()
+
+// LegacyInstallDir is the directory tools are installed into when using the legacy layout. In this layout,
+// all state is stored alongside the tools themselves
+var LegacyInstallDir = func() string {
+	return filepath.Join(homeDir, ".local", "bin", "backplane")
+}()
+
+// XDGInstallDir is the directory tools are installed into when using the XDG layout
+var XDGInstallDir = func() string {
+	return filepath.Join(xdgDir("XDG_DATA_HOME", filepath.Join(".local", "share")), "backplane-tools")
+}()
+
+// XDGStateDir is the directory state is stored in when using the XDG layout
+var XDGStateDir = func() string {
+	return filepath.Join(xdgDir("XDG_STATE_HOME", filepath.Join(".local", "state")), "backplane-tools")
+}()
+
+// xdgDir returns the directory defined by the given XDG environment variable, or the provided default (relative to
+// $HOME) if unset. Per the XDG base directory specification, relative paths in the environment variable are ignored
+func xdgDir(envVar, defaultDir string) string {
+	dir := os.Getenv(envVar)
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(homeDir, defaultDir)
+}
+
+// UseXDG is true when tools are installed using the XDG layout. This is determined by the user's configuration,
+// or, if unconfigured, by whether the XDG install directory already exists
+var UseXDG = func() bool {
+	switch config.Get().Layout {
+	case config.LayoutXDG:
+		return true
+	case config.LayoutLegacy:
+		return false
+	default:
+		exists, err := utils.FileExists(XDGInstallDir)
+		return err == nil && exists
+	}
+}()
+
+// InstallDir is the root directory all tools are installed into
+var InstallDir = func() string {
+	if UseXDG {
+		return XDGInstallDir
+	}
+	return LegacyInstallDir
+}()
+
+// StateDir is the directory backplane-tools stores the records it keeps about installed tools in
+var StateDir = func() string {
+	if UseXDG {
+		return XDGStateDir
+	}
+	return LegacyInstallDir
+}()
+
+var LatestDir = func() string {
+	return filepath.Join(InstallDir, "latest")
+}()
+
+// InventoryPath is the location of the file recording every artifact installed by backplane-tools
+var InventoryPath = func() string {
+	return filepath.Join(StateDir, "inventory.json")
+}()
+
+// ReportPath is the location of the file describing the outcome of the most recent installation run
+var ReportPath = func() string {
+	return filepath.Join(StateDir, "report.json")
+}()
+
+// JournalPath is the location of the file recording changes to installed tools which have not been completed
+var JournalPath = func() string {
+	return filepath.Join(StateDir, "journal.json")
+}()
+
+// stateFiles lists the files and directories which are stored in the state directory
+var stateFiles = []string{filepath.Base(InventoryPath), filepath.Base(linksPath), filepath.Base(integrityPath), filepath.Base(ReportPath), filepath.Base(holdsPath), filepath.Base(JournalPath), filepath.Base(latestVersionsPath), filepath.Base(trustPath), filepath.Base(keyringsDir)}
+
+// StateFilePaths returns the locations of the files which may be stored in the state directory
+func StateFilePaths() []string {
+	paths := []string{}
+	for _, file := range stateFiles {
+		paths = append(paths, filepath.Join(StateDir, file))
+	}
+	return paths
+}
+
+// SetRoot installs tools into, and stores all state within, the provided directory for the remainder of the
+// process. This allows tools to be staged outside of the user's installation, such as when creating a bundle
+func SetRoot(dir string) {
+	InstallDir = dir
+	StateDir = dir
+	LatestDir = filepath.Join(dir, "latest")
+	InventoryPath = filepath.Join(dir, filepath.Base(InventoryPath))
+	ReportPath = filepath.Join(dir, filepath.Base(ReportPath))
+	JournalPath = filepath.Join(dir, filepath.Base(JournalPath))
+	linksPath = filepath.Join(dir, filepath.Base(linksPath))
+	integrityPath = filepath.Join(dir, filepath.Base(integrityPath))
+	holdsPath = filepath.Join(dir, filepath.Base(holdsPath))
+	latestVersionsPath = filepath.Join(dir, filepath.Base(latestVersionsPath))
+	trustPath = filepath.Join(dir, filepath.Base(trustPath))
+	keyringsDir = filepath.Join(dir, filepath.Base(keyringsDir))
+}
+
+// MigrateToXDG relocates tools installed using the legacy layout to the XDG layout, moving state into the XDG
+// state directory and updating all links in the latest directory to refer to the new location
+func MigrateToXDG() error {
+	exists, err := utils.FileExists(LegacyInstallDir)
+	if err != nil {
+		return fmt.Errorf("failed to check for legacy install directory '%s': %w", LegacyInstallDir, err)
+	}
+	if !exists {
+		return fmt.Errorf("no tools are installed in legacy install directory '%s'", LegacyInstallDir)
+	}
+	exists, err = utils.FileExists(XDGInstallDir)
+	if err != nil {
+		return fmt.Errorf("failed to check for XDG install directory '%s': %w", XDGInstallDir, err)
+	}
+	if exists {
+		return fmt.Errorf("XDG install directory '%s' already exists", XDGInstallDir)
+	}
+
+	// Move the tools themselves
+	err = os.MkdirAll(filepath.Dir(XDGInstallDir), os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create directory '%s': %w", filepath.Dir(XDGInstallDir), err)
+	}
+	err = os.Rename(LegacyInstallDir, XDGInstallDir)
+	if err != nil {
+		return fmt.Errorf("failed to move '%s' to '%s': %w", LegacyInstallDir, XDGInstallDir, err)
+	}
+
+	// Move state out of the install directory
+	err = os.MkdirAll(XDGStateDir, os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create state directory '%s': %w", XDGStateDir, err)
+	}
+	for _, file := range stateFiles {
+		err = os.Rename(filepath.Join(XDGInstallDir, file), filepath.Join(XDGStateDir, file))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to move '%s' to state directory '%s': %w", file, XDGStateDir, err)
+		}
+	}
+
+	return relocateLinks(LegacyInstallDir, XDGInstallDir, XDGStateDir)
+}
+
+// relocateLinks rewrites each link in the latest directory under newInstallDir, along with the link records
+// in stateDir, to refer to newInstallDir rather than oldInstallDir
+func relocateLinks(oldInstallDir, newInstallDir, stateDir string) error {
+	relocate := func(path string) string {
+		if path == oldInstallDir || strings.HasPrefix(path, oldInstallDir+string(os.PathSeparator)) {
+			return newInstallDir + strings.TrimPrefix(path, oldInstallDir)
+		}
+		return path
+	}
+
+	latestDir := filepath.Join(newInstallDir, "latest")
+	entries, err := os.ReadDir(latestDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read latest directory '%s': %w", latestDir, err)
+	}
+	for _, entry := range entries {
+		linkPath := filepath.Join(latestDir, entry.Name())
+		info, err := os.Lstat(linkPath)
+		if err != nil {
+			return fmt.Errorf("failed to inspect '%s': %w", linkPath, err)
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(linkPath)
+			if err != nil {
+				return fmt.Errorf("failed to read symlink '%s': %w", linkPath, err)
+			}
+			err = replaceFile(linkPath, func(path string) error {
+				return os.Symlink(relocate(target), path)
+			})
+			if err != nil {
+				return fmt.Errorf("failed to recreate symlink '%s': %w", linkPath, err)
+			}
+			continue
+		}
+
+		// Shims embed the path of the tool's directory, and must be rewritten
+		contents, err := os.ReadFile(linkPath)
+		if err != nil {
+			return fmt.Errorf("failed to read '%s': %w", linkPath, err)
+		}
+		if strings.HasPrefix(string(contents), shimHeader) {
+			rewritten := strings.ReplaceAll(string(contents), oldInstallDir+string(os.PathSeparator), newInstallDir+string(os.PathSeparator))
+			err = os.WriteFile(linkPath, []byte(rewritten), info.Mode().Perm())
+			if err != nil {
+				return fmt.Errorf("failed to rewrite shim '%s': %w", linkPath, err)
+			}
+		}
+	}
+
+	// Update the link records to match
+	recordsPath := filepath.Join(stateDir, filepath.Base(linksPath))
+	links, err := readLinksFile(recordsPath)
+	if err != nil {
+		return err
+	}
+	relocated := map[string]string{}
+	for linkPath, target := range links {
+		relocated[relocate(linkPath)] = relocate(target)
+	}
+	err = writeLinksFile(recordsPath, relocated)
+	if err != nil {
+		return err
+	}
+	return relocateDigests(filepath.Join(stateDir, filepath.Base(integrityPath)), relocate)
+}
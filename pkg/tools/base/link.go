@@ -0,0 +1,280 @@
+package base
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// linksPath is the location of the file recording the target of each file published into the latest directory.
+// This allows the installed version of a tool to be determined regardless of how its executable was published
+var linksPath = func() string {
+	return filepath.Join(StateDir, "links.json")
+}()
+
+// Link publishes target at linkPath, replacing any existing file at linkPath. How the target is published
+// is determined by the link mode defined in the user's configuration.
+// The target is made executable, as executables downloaded without an archive are written as data. Unless disabled
+// by the user's configuration, the target's macOS quarantine attribute is removed so that it can be executed
+func Link(target, linkPath string) error {
+	err := utils.MakeExecutable(target)
+	if err != nil {
+		return err
+	}
+	if config.Get().ShouldClearQuarantine() {
+		err = utils.ClearQuarantine(target)
+		if err != nil {
+			return err
+		}
+	}
+
+	mode := config.Get().GetLinkMode()
+	err = replaceFile(linkPath, func(path string) error {
+		switch mode {
+		case config.LinkModeSymlink:
+			return os.Symlink(target, path)
+		case config.LinkModeShim:
+			return utils.WriteFile(strings.NewReader(shimScript(target)), path, utils.ExecutableMode)
+		case config.LinkModeHardlink:
+			return os.Link(target, path)
+		case config.LinkModeCopy:
+			return copyFile(target, path)
+		default:
+			return fmt.Errorf("unsupported link mode '%s'", mode)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to link '%s' to '%s': %w", target, linkPath, err)
+	}
+	RecordStep("linked '%s' -> '%s'", linkPath, target)
+	err = recordLink(linkPath, target)
+	if err != nil {
+		return err
+	}
+	return recordDigest(linkPath, target)
+}
+
+// replaceFile atomically replaces the file at path with the one produced by create, so that a file always exists at
+// path - even while tools are being upgraded. create is provided a temporary path within the same directory, which
+// is renamed over path once the new file is complete
+func replaceFile(path string, create func(tmpPath string) error) error {
+	tmpPath := filepath.Join(filepath.Dir(path), fmt.Sprintf(".%s.%d.tmp", filepath.Base(path), os.Getpid()))
+	err := os.Remove(tmpPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale temporary file '%s': %w", tmpPath, err)
+	}
+	err = create(tmpPath)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	err = os.Rename(tmpPath, path)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace '%s': %w", path, err)
+	}
+	// Renaming a hardlink over another link to the same file succeeds without removing the temporary link
+	_ = os.Remove(tmpPath)
+	return nil
+}
+
+// Unlink removes the file at linkPath, along with any record of its target. Unlinking a file which
+// does not exist is not an error, so that tools can be removed repeatedly
+func Unlink(linkPath string) error {
+	err := os.Remove(linkPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove linked file %s: %w", linkPath, err)
+	}
+	err = forgetLink(linkPath)
+	if err != nil {
+		return err
+	}
+	return forgetDigest(linkPath)
+}
+
+// ResolveLink returns the path to the file published at linkPath
+func ResolveLink(linkPath string) (string, error) {
+	info, err := os.Lstat(linkPath)
+	if err != nil {
+		return "", err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		return filepath.EvalSymlinks(linkPath)
+	}
+
+	links, err := readLinks()
+	if err != nil {
+		return "", err
+	}
+	target, found := links[linkPath]
+	if !found {
+		return "", fmt.Errorf("no target has been recorded for '%s'", linkPath)
+	}
+	return filepath.EvalSymlinks(target)
+}
+
+// copyFile copies the file at src to dst, preserving its permissions. Symlinks are followed
+func copyFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := file.Close()
+		if closeErr != nil {
+			fmt.Fprintf(os.Stderr, "failed to close file '%s': %v\n", src, closeErr)
+		}
+	}()
+	return utils.WriteFile(file, dst, info.Mode().Perm())
+}
+
+// shimHeader begins every shim script, and identifies files generated by backplane-tools
+const shimHeader = "#!/bin/sh\n# Generated by backplane-tools: do not edit. This file is replaced whenever the tool is installed or upgraded\n"
+
+// VersionsFileName is the name of the file pinning the versions of tools run by shims within a directory tree.
+// Each line names a tool, followed by the version to run: '<tool> <version>'. Blank lines and lines beginning
+// with '#' are ignored
+const VersionsFileName = ".backplane-tools-versions"
+
+// shimVersionsFileLookup searches the current directory and its parents for a versions file pinning the tool,
+// unless a version has already been selected. The nearest file naming the tool takes precedence
+const shimVersionsFileLookup = `if [ -z "${version}" ]; then
+  dir=$(pwd)
+  while :; do
+    if [ -f "${dir}/%[1]s" ]; then
+      version=$(awk -v tool=%[2]s '$1 == tool { print $2; exit }' "${dir}/%[1]s")
+      if [ -n "${version}" ]; then
+        versions_file="${dir}/%[1]s"
+        break
+      fi
+    fi
+    [ "${dir}" = / ] && break
+    dir=$(dirname "${dir}")
+  done
+fi
+`
+
+// shimVersionCheck rejects selected versions which aren't the name of a single directory, since they're read from
+// versions files in any directory the tool is run from - such as a freshly cloned repository - and could otherwise
+// execute a file outside of the tool's directory
+const shimVersionCheck = `case "${version}" in
+  */* | . | ..)
+    echo "backplane-tools: invalid version '${version}' of %s${versions_file:+ (pinned by ${versions_file})}" >&2
+    exit 2
+    ;;
+esac
+`
+
+// shimEnvVarChars matches the characters in a tool's name which are not permitted in an environment variable name
+var shimEnvVarChars = regexp.MustCompile("[^A-Z0-9_]")
+
+// ShimVersionEnvVar returns the name of the environment variable shims consult when selecting which
+// version of the named tool to execute
+func ShimVersionEnvVar(tool string) string {
+	return fmt.Sprintf("BACKPLANE_TOOLS_%s_VERSION", shimEnvVarChars.ReplaceAllString(strings.ToUpper(tool), "_"))
+}
+
+// shimScript generates a script which executes the provided target. When the target resides within a tool's
+// versioned directory, the script allows the version executed to be selected at invocation time
+func shimScript(target string) string {
+	var builder strings.Builder
+	builder.WriteString(shimHeader)
+
+	rel, err := filepath.Rel(InstallDir, target)
+	parts := strings.SplitN(rel, string(os.PathSeparator), 3)
+	if err != nil || len(parts) != 3 || parts[0] == ".." {
+		builder.WriteString(fmt.Sprintf("exec %s \"$@\"\n", shellQuote(target)))
+		return builder.String()
+	}
+	tool, version, executable := parts[0], parts[1], parts[2]
+
+	toolDir := shellQuote(filepath.Join(InstallDir, tool))
+	builder.WriteString(fmt.Sprintf("version=${%s:-}\n", ShimVersionEnvVar(tool)))
+	builder.WriteString(fmt.Sprintf(shimVersionsFileLookup, VersionsFileName, shellQuote(tool)))
+	builder.WriteString(fmt.Sprintf("version=${version:-%s}\n", shellQuote(version)))
+	builder.WriteString(fmt.Sprintf(shimVersionCheck, tool))
+	builder.WriteString(fmt.Sprintf("if [ ! -d %s/\"${version}\" ]; then\n", toolDir))
+	builder.WriteString(fmt.Sprintf("  echo \"backplane-tools: version ${version} of %s is not installed${versions_file:+ (pinned by ${versions_file})}\" >&2\n", tool))
+	builder.WriteString("  exit 127\nfi\n")
+	builder.WriteString(fmt.Sprintf("exec %s/\"${version}\"/%s \"$@\"\n", toolDir, shellQuote(executable)))
+	return builder.String()
+}
+
+// shellQuote wraps the provided string in single quotes, escaping any it contains, so that it is
+// interpreted literally by the shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Links returns the path of every file published into the latest directory, mapped to the executable it refers to
+func Links() (map[string]string, error) {
+	return readLinks()
+}
+
+func readLinks() (map[string]string, error) {
+	return readLinksFile(linksPath)
+}
+
+func readLinksFile(path string) (map[string]string, error) {
+	links := map[string]string{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return links, nil
+	}
+	if err != nil {
+		return links, fmt.Errorf("failed to read '%s': %w", path, err)
+	}
+	err = json.Unmarshal(data, &links)
+	if err != nil {
+		return links, fmt.Errorf("failed to parse '%s': %w", path, err)
+	}
+	return links, nil
+}
+
+func writeLinks(links map[string]string) error {
+	return writeLinksFile(linksPath, links)
+}
+
+func writeLinksFile(path string, links map[string]string) error {
+	data, err := json.MarshalIndent(links, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode links: %w", err)
+	}
+	err = os.WriteFile(path, data, os.FileMode(0o644))
+	if err != nil {
+		return fmt.Errorf("failed to write '%s': %w", path, err)
+	}
+	return nil
+}
+
+func recordLink(linkPath, target string) error {
+	links, err := readLinks()
+	if err != nil {
+		return err
+	}
+	links[linkPath] = target
+	return writeLinks(links)
+}
+
+func forgetLink(linkPath string) error {
+	links, err := readLinks()
+	if err != nil {
+		return err
+	}
+	if _, found := links[linkPath]; !found {
+		return nil
+	}
+	delete(links, linkPath)
+	return writeLinks(links)
+}
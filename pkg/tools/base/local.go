@@ -0,0 +1,87 @@
+package base
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// LocalFile describes an asset supplied by the user, installed in place of the asset the tool would otherwise
+// download from its source. This allows tools to be installed on hosts without network access, or from unreleased builds
+type LocalFile struct {
+	// Path is the location of the asset on the local filesystem
+	Path string
+
+	// Version is the version the asset is installed as
+	Version string
+
+	// SHA256 is the expected sha256 checksum of the asset. If empty, the asset is not verified
+	SHA256 string
+}
+
+// Validate ensures the local file can be installed
+func (f LocalFile) Validate() error {
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s': %w", f.Path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("'%s' is not a regular file", f.Path)
+	}
+	if f.Version == "" || f.Version == "." || f.Version == ".." || strings.ContainsAny(f.Version, `/\`) {
+		return fmt.Errorf("invalid version '%s': must be usable as a directory name", f.Version)
+	}
+	return nil
+}
+
+// UseLocalFile selects an asset from the local filesystem to be installed in place of the latest release. Once
+// selected, LatestVersion reports the local file's version, and installation does not access the network. Only tools
+// installed according to a Spec support local files
+func (t *Github) UseLocalFile(file LocalFile) error {
+	if t.Spec == nil {
+		return fmt.Errorf("%s does not support installing from a local file", t.Name())
+	}
+	t.localFile = &file
+	t.latestVersion = file.Version
+	return nil
+}
+
+// installLocalFile installs the tool from the local file selected by UseLocalFile, according to the tool's Spec
+func (t *Github) installLocalFile() error {
+	versionedDir := filepath.Join(t.ToolDir(), t.localFile.Version)
+	err := os.MkdirAll(versionedDir, os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create version-specific directory '%s': %w", versionedDir, err)
+	}
+
+	assetName := filepath.Base(t.localFile.Path)
+	assetPath := filepath.Join(versionedDir, assetName)
+	err = copyFile(t.localFile.Path, assetPath)
+	if err != nil {
+		return fmt.Errorf("failed to copy '%s' into '%s': %w", t.localFile.Path, versionedDir, err)
+	}
+
+	verification := "none (installed from local file)"
+	if t.localFile.SHA256 != "" {
+		sum, err := utils.Sha256sum(assetPath)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(sum, t.localFile.SHA256) {
+			return fmt.Errorf("failed to verify '%s': %w: expected sha256 checksum '%s', got '%s'", assetName, utils.ErrChecksumMismatch, t.localFile.SHA256, sum)
+		}
+		verification = fmt.Sprintf("%s (user-supplied sha256)", VerifyChecksum)
+	} else {
+		fmt.Printf("WARNING: no checksum was provided for '%s', so it has not been verified\n", t.localFile.Path)
+	}
+
+	sourcePath, err := filepath.Abs(t.localFile.Path)
+	if err != nil {
+		sourcePath = t.localFile.Path
+	}
+	t.RecordArtifact(t.localFile.Version, assetPath, "file://"+sourcePath, verification)
+	return t.installAsset(versionedDir, assetPath, assetName)
+}
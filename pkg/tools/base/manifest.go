@@ -0,0 +1,120 @@
+package base
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/openshift/backplane-tools/pkg/inventory"
+)
+
+// VersionManifestName is the name of the file describing each installed version of a tool, stored within
+// the version's directory
+const VersionManifestName = ".backplane-tools-version.json"
+
+// VersionManifest describes a single installed version of a tool
+type VersionManifest struct {
+	// Tool is the name of the tool
+	Tool string `json:"tool"`
+
+	// Version is the tool's version, as published upstream (ie - the release tag)
+	Version string `json:"version"`
+
+	// Executable is the location of the tool's executable, relative to the version's directory
+	Executable string `json:"executable,omitempty"`
+
+	// Artifacts lists the files downloaded to install this version, along with their source and digest
+	Artifacts []inventory.Artifact `json:"artifacts"`
+
+	// UpdatedAt is the last time this manifest was written
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// VersionDir returns the directory the provided version of the tool is installed in
+func (t *Default) VersionDir(version string) string {
+	return filepath.Join(t.ToolDir(), version)
+}
+
+// VersionManifest returns the manifest describing the provided installed version of the tool
+func (t *Default) VersionManifest(version string) (VersionManifest, error) {
+	return ReadVersionManifest(t.VersionDir(version))
+}
+
+// ReadVersionManifest parses the manifest stored in the provided version directory
+func ReadVersionManifest(versionDir string) (VersionManifest, error) {
+	manifest := VersionManifest{Artifacts: []inventory.Artifact{}}
+	path := filepath.Join(versionDir, VersionManifestName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return manifest, fmt.Errorf("failed to read version manifest '%s': %w", path, err)
+	}
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return manifest, fmt.Errorf("failed to parse version manifest '%s': %w", path, err)
+	}
+	return manifest, nil
+}
+
+// updateVersionManifest applies the provided modification to the manifest for the given version of the tool,
+// creating the manifest if it doesn't exist yet
+func (t *Default) updateVersionManifest(version string, update func(*VersionManifest)) error {
+	versionDir := t.VersionDir(version)
+	manifest, err := ReadVersionManifest(versionDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	manifest.Tool = t.name
+	manifest.Version = version
+	update(&manifest)
+	manifest.UpdatedAt = time.Now().UTC()
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode version manifest: %w", err)
+	}
+	path := filepath.Join(versionDir, VersionManifestName)
+	err = os.WriteFile(path, data, os.FileMode(0o644))
+	if err != nil {
+		return fmt.Errorf("failed to write version manifest '%s': %w", path, err)
+	}
+	return nil
+}
+
+// recordVersionArtifact adds the artifact to the manifest for its version, replacing any previous record of it
+func (t *Default) recordVersionArtifact(artifact inventory.Artifact) error {
+	return t.updateVersionManifest(artifact.Version, func(manifest *VersionManifest) {
+		artifacts := []inventory.Artifact{}
+		for _, existing := range manifest.Artifacts {
+			if existing.Name != artifact.Name {
+				artifacts = append(artifacts, existing)
+			}
+		}
+		manifest.Artifacts = append(artifacts, artifact)
+	})
+}
+
+// recordVersionExecutable records the location of the executable in the manifest for the version it belongs to.
+// Executables outside of the tool's directory are ignored
+func (t *Default) recordVersionExecutable(executablePath string) error {
+	version, executable, found := t.splitVersionPath(executablePath)
+	if !found {
+		return nil
+	}
+	return t.updateVersionManifest(version, func(manifest *VersionManifest) {
+		manifest.Executable = executable
+	})
+}
+
+// splitVersionPath splits a path within the tool's directory into the version it belongs to, and its location
+// relative to that version's directory. found is false if the path is not within a versioned directory
+func (t *Default) splitVersionPath(path string) (version, relPath string, found bool) {
+	rel, err := filepath.Rel(t.ToolDir(), path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return "", "", false
+	}
+	return strings.Cut(rel, string(os.PathSeparator))
+}
@@ -2,8 +2,11 @@ package base
 
 import (
 	"fmt"
+	"path"
+	"path/filepath"
 	"strings"
 
+	"github.com/openshift/backplane-tools/pkg/sources"
 	"github.com/openshift/backplane-tools/pkg/sources/openshift.com/mirror"
 	"github.com/openshift/backplane-tools/pkg/utils"
 )
@@ -13,14 +16,102 @@ type Mirror struct {
 	Default
 	// Source defines the source of the tool in mirror.openshift.com
 	Source *mirror.Source
-	// BaseSlug refers to the base domain for the source
+	// BaseSlug refers to the base domain for the source. Any occurrence of ArchPlaceholder is
+	// replaced with the target architecture
 	BaseSlug string
+	// SignatureSuffix is appended to the name of a checksum file to locate its detached signature. If empty,
+	// checksumSignatureSuffix is used
+	SignatureSuffix string
+	// KeyURL is where the keys trusted to sign the tool's checksum files are published. If empty, Red Hat's release
+	// key is retrieved from redHatReleaseKeyURL
+	KeyURL string
+	// Fingerprints pins the keys trusted to sign the tool's checksum files. If empty, redHatReleaseKeys are pinned
+	Fingerprints []string
+}
+
+// ArchPlaceholder is replaced by the target architecture when it appears in a Mirror's BaseSlug
+const ArchPlaceholder = "{arch}"
+
+// redHatReleaseKeyURL is where the key Red Hat signs mirror.openshift.com's checksum files with is published
+const redHatReleaseKeyURL = "https://www.redhat.com/security/data/fd431d51.txt"
+
+// redHatReleaseKeys pins the keys trusted to sign mirror.openshift.com's checksum files: Red Hat's release key 2
+var redHatReleaseKeys = []string{"567E347AD0044ADE55BA8A5F199E2F91FD431D51"}
+
+// checksumSignatureSuffix is appended to the name of a checksum file to locate its detached signature, unless the
+// tool provides its own SignatureSuffix
+const checksumSignatureSuffix = ".gpg"
+
+// ToolSource returns the source the tool is installed from
+func (t *Mirror) ToolSource() sources.Source {
+	return t.Source
+}
+
+// Slug returns the tool's BaseSlug for the target architecture
+func (t *Mirror) Slug() string {
+	return strings.ReplaceAll(t.BaseSlug, ArchPlaceholder, utils.TargetArch)
+}
+
+// SigningKeys returns the fingerprints of the keys pinned to sign the checksum files the tool's assets are verified
+// against
+func (t *Mirror) SigningKeys() (pinned []string, found bool) {
+	if len(t.Fingerprints) == 0 {
+		return redHatReleaseKeys, true
+	}
+	return t.Fingerprints, true
+}
+
+// signingKeyURL returns where the keys pinned to sign the tool's checksum files are published
+func (t *Mirror) signingKeyURL() string {
+	if t.KeyURL == "" {
+		return redHatReleaseKeyURL
+	}
+	return t.KeyURL
+}
+
+// VerifyChecksumSignature validates the checksum file at checksumPath, downloaded from checksumSlug, against the
+// detached signature published alongside it. This ensures the checksums were published by Red Hat, rather than
+// by whoever controls the mirror. The signature is downloaded into the same directory as the checksum file
+func (t *Mirror) VerifyChecksumSignature(checksumSlug, checksumPath string) error {
+	suffix := t.SignatureSuffix
+	if suffix == "" {
+		suffix = checksumSignatureSuffix
+	}
+	signaturePath, err := t.Source.DownloadFile(checksumSlug+suffix, filepath.Dir(checksumPath))
+	if err != nil {
+		return fmt.Errorf("failed to download signature for checksum file %s: %w", checksumSlug, err)
+	}
+	pinned, _ := t.SigningKeys()
+	keyRing, err := trustedKeyRing(t.Name(), t.signingKeyURL(), pinned)
+	if err != nil {
+		return err
+	}
+	err = utils.VerifyGPGSignature(checksumPath, signaturePath, keyRing)
+	if err != nil {
+		return fmt.Errorf("failed to verify checksum file '%s': %w", filepath.Base(checksumPath), err)
+	}
+	return nil
+}
+
+// VersionsSlug returns the directory containing a subdirectory for each published version of the tool. This is the
+// parent of the tool's Slug, which refers to a channel directory such as "stable"
+func (t *Mirror) VersionsSlug() string {
+	return path.Dir(strings.TrimSuffix(t.Slug(), "/")) + "/"
+}
+
+// ListVersions returns every published version of the tool, from oldest to newest
+func (t *Mirror) ListVersions() ([]string, error) {
+	versions, err := t.Source.ListVersions(t.VersionsSlug())
+	if err != nil {
+		return []string{}, fmt.Errorf("failed to list versions of '%s': %w", t.Name(), err)
+	}
+	return versions, nil
 }
 
 // LatestVersion retrieves the version info contained within the provided release.txt file
 func (t *Mirror) _LatestVersion() (string, error) {
 	// Retrieve latest release info to determine which version we're operating on
-	releaseSlug := fmt.Sprintf("%s/release.txt", t.BaseSlug)
+	releaseSlug := fmt.Sprintf("%s/release.txt", t.Slug())
 	releaseData, err := t.Source.GetFileContents(releaseSlug)
 	if err != nil {
 		return "", fmt.Errorf("failed to retrieve release info from %s: %w", releaseSlug, err)
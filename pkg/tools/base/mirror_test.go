@@ -0,0 +1,88 @@
+package base_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/openshift/backplane-tools/pkg/utils/test"
+)
+
+func TestVerifyChecksumSignature(t *testing.T) {
+	tests := []struct {
+		name            string
+		signatures      test.Signatures
+		signatureSuffix string
+		expectedErr     string
+	}{
+		{name: "valid signature", signatures: test.SignaturesValid},
+		{name: "valid signature with suffix", signatures: test.SignaturesValid, signatureSuffix: ".sig"},
+		{name: "bad signature", signatures: test.SignaturesInvalid, expectedErr: "failed to verify checksum file 'sha256sum.txt'"},
+		{name: "missing signature", signatures: test.SignaturesMissing, expectedErr: "failed to download signature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := test.NewMirrorEnv()
+			if err != nil {
+				t.Fatalf("failed to create mirror environment: %v", err)
+			}
+			defer func() {
+				_ = env.Close()
+			}()
+			env.AddFile("/pub/tool/", "tool.tar.gz", []byte("tool"))
+			env.SetSignatures(tt.signatures)
+
+			tool := &base.Mirror{
+				Default:         base.NewDefault("tool"),
+				BaseSlug:        "/pub/tool/",
+				SignatureSuffix: tt.signatureSuffix,
+			}
+			env.Use(tool)
+			dir := t.TempDir()
+			checksumPath, err := tool.Source.DownloadFile("/pub/tool/sha256sum.txt", dir)
+			if err != nil {
+				t.Fatalf("failed to download checksum file: %v", err)
+			}
+
+			err = tool.VerifyChecksumSignature("/pub/tool/sha256sum.txt", checksumPath)
+			if tt.expectedErr == "" && err != nil {
+				t.Errorf("expected the signature to be verified, got '%v'", err)
+			}
+			if tt.expectedErr != "" && (err == nil || !strings.Contains(err.Error(), tt.expectedErr)) {
+				t.Errorf("expected error containing '%s', got '%v'", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestVerifyChecksumSignatureRequiresPinnedKey(t *testing.T) {
+	env, err := test.NewMirrorEnv()
+	if err != nil {
+		t.Fatalf("failed to create mirror environment: %v", err)
+	}
+	defer func() {
+		_ = env.Close()
+	}()
+	env.AddFile("/pub/tool/", "tool.tar.gz", []byte("tool"))
+
+	tool := &base.Mirror{
+		Default:  base.NewDefault("tool"),
+		BaseSlug: "/pub/tool/",
+	}
+	env.Use(tool)
+	// The environment's key is published, but another key is pinned
+	tool.Fingerprints = []string{"567E347AD0044ADE55BA8A5F199E2F91FD431D51"}
+	checksumPath, err := tool.Source.DownloadFile("/pub/tool/sha256sum.txt", t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to download checksum file: %v", err)
+	}
+	err = tool.VerifyChecksumSignature("/pub/tool/sha256sum.txt", checksumPath)
+	if err == nil || !strings.Contains(err.Error(), "none of the keys trusted to sign tool are available") {
+		t.Errorf("expected a signature made by an unpinned key to be rejected, got '%v'", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(filepath.Dir(checksumPath), "sha256sum.txt.gpg")); statErr != nil {
+		t.Errorf("expected the signature to be downloaded alongside the checksum file: %v", statErr)
+	}
+}
@@ -0,0 +1,110 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// CleanOrphans removes the versioned directories left behind by previous failed installs of the tool, along with
+// any links in the latest directory which refer to versions of the tool that no longer exist. A version is
+// considered incomplete if it was never linked: either its manifest records no executable, or it has no manifest
+// and no executable can be found within it. The currently linked version is never removed. Each file removed is
+// logged, and the paths of those removed are returned
+func (t *Default) CleanOrphans() ([]string, error) {
+	removed := []string{}
+	links, err := toolLinks(t.name)
+	if err != nil {
+		return removed, err
+	}
+	// Symlinks created before links were recorded are found by inspecting the latest directory itself
+	entries, err := os.ReadDir(LatestDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return removed, fmt.Errorf("failed to read '%s': %w", LatestDir, err)
+	}
+	for _, entry := range entries {
+		linkPath := filepath.Join(LatestDir, entry.Name())
+		if _, recorded := links[linkPath]; recorded || entry.Type()&os.ModeSymlink == 0 {
+			continue
+		}
+		target, err := os.Readlink(linkPath)
+		if err == nil && t.owns(target) {
+			links[linkPath] = target
+		}
+	}
+	for linkPath, target := range links {
+		_, err = os.Stat(target)
+		if err == nil || !errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		err = Unlink(linkPath)
+		if err != nil {
+			return removed, err
+		}
+		fmt.Printf("Removed '%s': it referred to '%s', which no longer exists\n", linkPath, target)
+		removed = append(removed, linkPath)
+	}
+
+	versions, err := InstalledVersions(t.name)
+	if errors.Is(err, os.ErrNotExist) {
+		return removed, nil
+	}
+	if err != nil {
+		return removed, err
+	}
+	linked := map[string]bool{}
+	for _, target := range links {
+		version, _, found := t.splitVersionPath(target)
+		if found {
+			linked[version] = true
+		}
+	}
+	for _, version := range versions {
+		if linked[version] || t.versionComplete(version) {
+			continue
+		}
+		versionDir := t.VersionDir(version)
+		size := dirSize(versionDir)
+		err = os.RemoveAll(versionDir)
+		if err != nil {
+			return removed, fmt.Errorf("failed to remove incomplete version '%s': %w", versionDir, err)
+		}
+		fmt.Printf("Removed incomplete version '%s' of %s left by a previous failed install, freeing %.1f MB\n", version, t.name, float64(size)/(1024*1024))
+		removed = append(removed, versionDir)
+	}
+	return removed, nil
+}
+
+// versionComplete returns true if the provided version of the tool finished installing
+func (t *Default) versionComplete(version string) bool {
+	versionDir := t.VersionDir(version)
+	manifest, err := ReadVersionManifest(versionDir)
+	if err == nil {
+		return manifest.Executable != ""
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		// Don't remove versions whose state can't be determined
+		return true
+	}
+	_, err = FindBinary(versionDir, t.executableName)
+	return err == nil
+}
+
+// dirSize returns the total size of the regular files within the provided directory. Files which cannot be
+// inspected are ignored
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,106 @@
+package base
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/sources"
+	"github.com/openshift/backplane-tools/pkg/sources/pypi"
+	"github.com/openshift/backplane-tools/pkg/verify"
+)
+
+// Python is the interpreter used to create the virtualenvs PyPI tools are installed into
+var Python = "python3"
+
+// VenvDir is the name of the virtualenv created within each of a PyPI tool's versioned directories
+const VenvDir = "venv"
+
+// PyPI implements the installation of tools distributed as Python packages. Each version is installed into its own
+// virtualenv within the versioned directory, and the entry point the package provides is linked into the latest directory
+type PyPI struct {
+	// Default defines the default Tool implementation
+	Default
+	// Source defines the source of the tool in PyPI
+	Source *pypi.Source
+}
+
+// ToolSource returns the source the tool is installed from
+func (t *PyPI) ToolSource() sources.Source {
+	return t.Source
+}
+
+// LatestVersion returns the latest version of the tool's package
+func (t *PyPI) LatestVersion() (string, error) {
+	if t.latestVersion == "" {
+		version, err := t.Source.ResolveLatest()
+		if err != nil {
+			return "", err
+		}
+		t.latestVersion = version
+	}
+	return t.latestVersion, nil
+}
+
+// Install downloads the latest release of the tool's package and installs it into a new virtualenv
+func (t *PyPI) Install() error {
+	release, err := t.Source.FetchLatestRelease()
+	if err != nil {
+		return err
+	}
+	version := release.Info.Version
+	dist, err := pypi.FindDistribution(release.Files)
+	if err != nil {
+		return fmt.Errorf("failed to select a distribution of '%s' %s: %w", t.Source.Package, version, err)
+	}
+
+	versionedDir := filepath.Join(t.ToolDir(), version)
+	err = os.MkdirAll(versionedDir, os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create version-specific directory '%s': %w", versionedDir, err)
+	}
+
+	distPath, err := t.Source.Download(sources.Artifact{Name: dist.Filename, URL: dist.URL}, versionedDir)
+	if err != nil {
+		return fmt.Errorf("failed to download '%s': %w", dist.Filename, err)
+	}
+
+	// PyPI publishes the sha256sum of each distribution alongside it
+	verification, err := t.ApplyChecksumPolicy(distPath, "pypi digest", func() error {
+		if dist.Digests.Sha256 == "" {
+			return fmt.Errorf("no sha256 digest published for '%s'", dist.Filename)
+		}
+		return verify.Sum(distPath, dist.Digests.Sha256)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to verify '%s': %w", dist.Filename, err)
+	}
+	t.RecordArtifact(version, distPath, dist.URL, verification)
+
+	venvDir := filepath.Join(versionedDir, VenvDir)
+	err = runPython(Python, "-m", "venv", "--clear", venvDir)
+	if err != nil {
+		return fmt.Errorf("failed to create virtualenv '%s': %w", venvDir, err)
+	}
+	err = runPython(filepath.Join(venvDir, "bin", "python"), "-m", "pip", "install", "--quiet", "--disable-pip-version-check", distPath)
+	if err != nil {
+		return fmt.Errorf("failed to install '%s' into virtualenv '%s': %w", dist.Filename, venvDir, err)
+	}
+
+	executablePath, err := FindBinary(versionedDir, filepath.Join(VenvDir, "bin", t.ExecutableName()))
+	if err != nil {
+		return err
+	}
+	return t.LinkExecutable(executablePath)
+}
+
+// runPython runs the interpreter with the provided arguments, including its output in any error returned
+func runPython(python string, args ...string) error {
+	out, err := exec.Command(python, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("'%s %s' failed: %w\n%s", python, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
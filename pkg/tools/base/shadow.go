@@ -0,0 +1,83 @@
+package base
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// shadowVersionTimeout limits how long a shadowing executable is given to report its version
+const shadowVersionTimeout = 3 * time.Second
+
+// Shadow describes an executable found earlier in the $PATH than the one managed in LatestDir
+type Shadow struct {
+	// Path is the location of the shadowing executable
+	Path string
+
+	// Version is the first line reported by the executable when asked for its version. Empty if it could not be determined
+	Version string
+}
+
+// Shadows returns the executables sharing the provided name that appear in userPaths before LatestDir, and
+// would therefore be run instead of the managed tool. No shadows are returned if LatestDir isn't in userPaths
+func Shadows(executableName string, userPaths []string) []Shadow {
+	latestDir := filepath.Clean(LatestDir)
+	shadows := []Shadow{}
+	for _, dir := range userPaths {
+		if dir == "" {
+			continue
+		}
+		if sameDir(filepath.Clean(dir), latestDir) {
+			return shadows
+		}
+		path := filepath.Join(dir, executableName)
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+		if target, err := filepath.EvalSymlinks(path); err == nil && strings.HasPrefix(target, InstallDir+string(os.PathSeparator)) {
+			// Links into the install directory run a managed version of the tool, and aren't conflicts
+			continue
+		}
+		shadows = append(shadows, Shadow{Path: path, Version: shadowVersion(path)})
+	}
+	// LatestDir isn't on the $PATH at all, which is reported separately
+	return []Shadow{}
+}
+
+// sameDir returns true if both paths refer to the same directory
+func sameDir(a, b string) bool {
+	if a == b {
+		return true
+	}
+	aInfo, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bInfo, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(aInfo, bInfo)
+}
+
+// shadowVersion makes a best-effort attempt at retrieving the version of the provided executable
+func shadowVersion(path string) string {
+	for _, args := range [][]string{{"--version"}, {"version"}} {
+		ctx, cancel := context.WithTimeout(context.Background(), shadowVersionTimeout)
+		out, err := exec.CommandContext(ctx, path, args...).Output()
+		cancel()
+		if err != nil {
+			continue
+		}
+		line, _, _ := strings.Cut(string(bytes.TrimSpace(out)), "\n")
+		if line != "" {
+			return strings.TrimSpace(line)
+		}
+	}
+	return ""
+}
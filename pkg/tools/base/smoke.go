@@ -0,0 +1,107 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// smokeTestTimeout bounds how long a tool's executable may run when smoke tested
+const smokeTestTimeout = 30 * time.Second
+
+// maxSmokeTestOutput bounds how much of the executable's output is included in smoke test errors
+const maxSmokeTestOutput = 1024
+
+// versionInOutput matches versions printed by a tool (ie - "4.15.3" in "Client Version: 4.15.3")
+var versionInOutput = regexp.MustCompile(`v?\d+\.\d+(\.\d+)*([-+][0-9A-Za-z.-]+)?`)
+
+// DefaultSmokeTestArgs are passed to a tool's executable when smoke testing it, unless the tool defines its own
+var DefaultSmokeTestArgs = []string{"--version"}
+
+// SmokeTest describes the harmless command run to verify a tool's executable works once installed
+type SmokeTest struct {
+	// Args are passed to the executable. If empty, DefaultSmokeTestArgs are used
+	Args []string
+
+	// SkipVersionCheck disables checking that the command's output contains the installed version, for tools
+	// which have no way of reporting their version
+	SkipVersionCheck bool
+}
+
+// SetSmokeTest sets the command run to verify the tool's executable works once installed
+func (t *Default) SetSmokeTest(test SmokeTest) {
+	t.smokeTest = test
+}
+
+// SmokeTest returns the command run to verify the tool's executable works once installed
+func (t *Default) SmokeTest() SmokeTest {
+	test := t.smokeTest
+	if len(test.Args) == 0 {
+		test.Args = DefaultSmokeTestArgs
+	}
+	return test
+}
+
+// RunSmokeTest runs the executable at the provided path as described by the given test, returning an error if it
+// fails to run, exits unsuccessfully, or - unless the test skips the check - doesn't report the expected version.
+// Versions are compared without any leading 'v', as tools commonly omit it
+func RunSmokeTest(executablePath, version string, test SmokeTest) error {
+	out, err := runSmokeTestCommand(executablePath, test)
+	if err != nil {
+		return err
+	}
+	if !test.SkipVersionCheck && !strings.Contains(out, strings.TrimPrefix(version, "v")) {
+		return fmt.Errorf("'%s' did not report the installed version %s%s", describeCommand(executablePath, test), version, describeOutput(out))
+	}
+	return nil
+}
+
+// ReportedVersion runs the executable at the provided path as described by the given test, and returns the first
+// version in its output. found is false if the test doesn't report a version, or no version was found in the output
+func ReportedVersion(executablePath string, test SmokeTest) (version string, found bool, err error) {
+	if test.SkipVersionCheck {
+		return "", false, nil
+	}
+	out, err := runSmokeTestCommand(executablePath, test)
+	if err != nil {
+		return "", false, err
+	}
+	version = versionInOutput.FindString(out)
+	return version, version != "", nil
+}
+
+// runSmokeTestCommand runs the executable at the provided path with the given test's arguments, returning its output
+func runSmokeTestCommand(executablePath string, test SmokeTest) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), smokeTestTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, executablePath, test.Args...).CombinedOutput()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("'%s' did not complete within %s", describeCommand(executablePath, test), smokeTestTimeout)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to run '%s': %w%s", describeCommand(executablePath, test), err, describeOutput(string(out)))
+	}
+	return string(out), nil
+}
+
+// describeCommand returns the command line run for the provided test
+func describeCommand(executablePath string, test SmokeTest) string {
+	return strings.Join(append([]string{executablePath}, test.Args...), " ")
+}
+
+// describeOutput formats the provided command output for inclusion in an error, truncating it if it's too long
+func describeOutput(out string) string {
+	out = strings.TrimSpace(out)
+	if out == "" {
+		return ""
+	}
+	if len(out) > maxSmokeTestOutput {
+		out = out[:maxSmokeTestOutput] + "..."
+	}
+	return ". Output:\n" + out
+}
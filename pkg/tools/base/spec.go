@@ -0,0 +1,129 @@
+package base
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/openshift/backplane-tools/pkg/verify"
+)
+
+// ArchiveType defines how a tool's release asset is packaged
+type ArchiveType string
+
+const (
+	// ArchiveNone indicates the asset is the executable itself
+	ArchiveNone ArchiveType = ""
+	// ArchiveTarGz indicates the asset is a gzip-compressed tarball
+	ArchiveTarGz ArchiveType = "tar.gz"
+	// ArchiveZip indicates the asset is a zip archive
+	ArchiveZip ArchiveType = "zip"
+	// ArchiveAuto indicates the asset is an archive whose format is detected at install time. Any format
+	// supported by utils.Unarchive is accepted
+	ArchiveAuto ArchiveType = "auto"
+	// ArchiveOptional indicates the asset is either an archive, whose format is detected at install time, or the
+	// executable itself, for projects which publish either or have switched between them
+	ArchiveOptional ArchiveType = "optional"
+)
+
+// VerifyMethod defines how a tool's release asset is verified after being downloaded
+type VerifyMethod string
+
+const (
+	// VerifyChecksum compares the asset's checksum to the value published in a checksum asset. sha256, sha384,
+	// sha512, and blake2b checksums are supported
+	VerifyChecksum VerifyMethod = "checksum"
+	// VerifyGPG validates the asset against a detached, armored GPG signature asset
+	VerifyGPG VerifyMethod = "gpg"
+	// VerifyReleaseNotes compares the asset's checksum to the value published in the release's description, for
+	// projects which don't publish a checksum asset. No verification asset is used
+	VerifyReleaseNotes VerifyMethod = "release-notes"
+)
+
+// AssetSpec declaratively describes how a tool is installed from its release assets
+type AssetSpec struct {
+	// Include lists terms the tool asset's name must contain, in addition to
+	// matching the local OS and architecture
+	Include []string
+
+	// Exclude lists terms the tool asset's name must not contain
+	Exclude []string
+
+	// Prefer lists terms used to choose between several assets matching the local system, once other preferences -
+	// such as for archives over packages - have been applied. An asset containing an earlier term is preferred over
+	// one which doesn't. Terms configured by the user take precedence over these
+	Prefer []string
+
+	// Archive defines how the tool asset is packaged. Archived assets are extracted according to their detected
+	// format, so that tools continue to install if upstream changes how they're compressed
+	Archive ArchiveType
+
+	// BinaryPath is the location of the executable, relative to the versioned directory, once the tool
+	// asset has been extracted. If unset, this defaults to the tool's executable name when the asset is
+	// an archive, and to the asset's name otherwise. If no file exists at this location, the extracted tree
+	// is searched for an executable with the same name
+	BinaryPath string
+
+	// Verification describes how the tool asset's integrity is verified
+	Verification VerificationSpec
+
+	// Provenance lists terms the name of the release asset containing the tool's SLSA provenance must contain.
+	// Provenance is only verified when enabled by the user's configuration. Defaults to DefaultProvenanceTerms
+	Provenance []string
+}
+
+// DefaultProvenanceTerms identify the SLSA provenance asset published by most projects, including those using goreleaser
+var DefaultProvenanceTerms = []string{".intoto.jsonl"}
+
+// VerificationSpec describes the release asset used to verify a tool asset
+type VerificationSpec struct {
+	// Method defines how the verification asset is used. Defaults to VerifyChecksum
+	Method VerifyMethod
+
+	// Terms lists terms the verification asset's name must contain
+	Terms []string
+
+	// Pattern is a regular expression the verification asset's name must match. If set, Terms is ignored
+	Pattern string
+
+	// AssetSuffixes lists suffixes which, appended to the tool asset's name, name a checksum asset published for the
+	// tool asset alone, such as '.sha256'. These are tried in order before Terms or Pattern, so that tools remain
+	// verifiable when upstream switches between publishing per-asset and aggregate checksum files
+	AssetSuffixes []string
+
+	// MatchSystem restricts the search for the verification asset to assets matching the local OS and architecture
+	MatchSystem bool
+
+	// KeyURL is where the public keys used to sign the tool's assets are published. Only used when Method is
+	// VerifyGPG. Keys retrieved from this URL are only used if they're trusted
+	KeyURL string
+
+	// Fingerprints pins the keys trusted to sign the tool's assets, so that signatures are never verified against
+	// whichever keys happen to be published at KeyURL. Users may trust or distrust keys with 'backplane-tools trust'.
+	// Only used when Method is VerifyGPG
+	Fingerprints []string
+
+	// Format defines the layout of checksum assets, or of the checksum lines within the release's description.
+	// Only used when Method is VerifyChecksum or VerifyReleaseNotes. Defaults to verify.FormatAuto, which detects
+	// the layout automatically
+	Format verify.Format
+}
+
+// FindBinary returns the location of the executable at binaryPath, relative to versionedDir. If upstream has moved
+// the executable, such as by nesting it within a release-named directory, the tree is searched for an executable
+// with the same name instead
+func FindBinary(versionedDir, binaryPath string) (string, error) {
+	executablePath := filepath.Join(versionedDir, binaryPath)
+	exists, err := utils.FileExists(executablePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to check for executable '%s': %w", executablePath, err)
+	}
+	if exists {
+		return executablePath, nil
+	}
+	executablePath, err = utils.FindExecutable(versionedDir, filepath.Base(binaryPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to locate executable: %w", err)
+	}
+	return executablePath, nil
+}
@@ -0,0 +1,351 @@
+package base
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// trustPath is the location of the file recording the keys the user has trusted or distrusted to sign each tool's
+// assets, in addition to those pinned by backplane-tools
+var trustPath = func() string {
+	return filepath.Join(StateDir, "trust.json")
+}()
+
+// keyringsDir is the directory containing a keyring for each tool verified using GPG signatures. Each keyring only
+// contains the tool's trusted keys, so that signatures are never verified against keys which weren't pinned
+var keyringsDir = func() string {
+	return filepath.Join(StateDir, "keyrings")
+}()
+
+// trustRecord lists the changes the user has made to the keys trusted to sign a tool's assets
+type trustRecord struct {
+	// Added lists the fingerprints of keys trusted in addition to those pinned by backplane-tools
+	Added []string `json:"added,omitempty"`
+
+	// Removed lists the fingerprints of keys pinned by backplane-tools which are no longer trusted
+	Removed []string `json:"removed,omitempty"`
+}
+
+// TrustedKey describes a key trusted to sign a tool's assets
+type TrustedKey struct {
+	// Fingerprint is the fingerprint of the key's primary key, in uppercase hexadecimal
+	Fingerprint string `json:"fingerprint"`
+
+	// Pinned is true for keys pinned by backplane-tools, and false for keys trusted by the user
+	Pinned bool `json:"pinned"`
+
+	// Cached is true once the key has been stored in the tool's local keyring
+	Cached bool `json:"cached"`
+
+	// UserIDs identify the key's owner. These are only known once the key has been cached
+	UserIDs []string `json:"userIds,omitempty"`
+}
+
+// TrustedKeys returns the keys trusted to sign the named tool's assets: those pinned by backplane-tools, less any the
+// user has distrusted, followed by any the user has trusted
+func TrustedKeys(tool string, pinned []string) ([]TrustedKey, error) {
+	records, err := readTrust()
+	if err != nil {
+		return []TrustedKey{}, err
+	}
+	keyRing, err := readKeyRing(tool)
+	if err != nil {
+		return []TrustedKey{}, err
+	}
+	cached := map[string]*openpgp.Entity{}
+	for _, key := range keyRing {
+		cached[utils.Fingerprint(key)] = key
+	}
+
+	keys := []TrustedKey{}
+	add := func(fingerprint string, isPinned bool) {
+		key := TrustedKey{Fingerprint: fingerprint, Pinned: isPinned}
+		entity, found := cached[fingerprint]
+		if found {
+			key.Cached = true
+			for name := range entity.Identities {
+				key.UserIDs = append(key.UserIDs, name)
+			}
+			sort.Strings(key.UserIDs)
+		}
+		keys = append(keys, key)
+	}
+	record := records[tool]
+	for _, fingerprint := range pinned {
+		fingerprint = utils.NormalizeFingerprint(fingerprint)
+		if !utils.Contains(record.Removed, fingerprint) {
+			add(fingerprint, true)
+		}
+	}
+	for _, fingerprint := range record.Added {
+		add(fingerprint, false)
+	}
+	return keys, nil
+}
+
+// Trust adds the key with the provided fingerprint to those trusted to sign the named tool's assets. If keyFile is
+// provided, the key is read from it and cached, rather than being retrieved from the tool's key URL when next needed
+func Trust(tool, fingerprint string, pinned []string, keyFile string) error {
+	fingerprint = utils.NormalizeFingerprint(fingerprint)
+	if !utils.ValidFingerprint(fingerprint) {
+		return fmt.Errorf("invalid fingerprint '%s': must be 40 or 64 hexadecimal characters", fingerprint)
+	}
+
+	if keyFile != "" {
+		file, err := os.Open(keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to open key file '%s': %w", keyFile, err)
+		}
+		keys, err := utils.ReadKeyRing(file)
+		_ = file.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read key file '%s': %w", keyFile, err)
+		}
+		keys = filterKeys(keys, []string{fingerprint})
+		if len(keys) == 0 {
+			return fmt.Errorf("key file '%s' does not contain a key with fingerprint %s", keyFile, fingerprint)
+		}
+		err = cacheKeys(tool, keys)
+		if err != nil {
+			return err
+		}
+	}
+
+	records, err := readTrust()
+	if err != nil {
+		return err
+	}
+	record := records[tool]
+	record.Removed = removeFingerprint(record.Removed, fingerprint)
+	isPinned := false
+	for _, p := range pinned {
+		if utils.NormalizeFingerprint(p) == fingerprint {
+			isPinned = true
+		}
+	}
+	if !isPinned && !utils.Contains(record.Added, fingerprint) {
+		record.Added = append(record.Added, fingerprint)
+	}
+	records[tool] = record
+	return writeTrust(records)
+}
+
+// Distrust removes the key with the provided fingerprint from those trusted to sign the named tool's assets, whether
+// it was pinned by backplane-tools or trusted by the user
+func Distrust(tool, fingerprint string, pinned []string) error {
+	fingerprint = utils.NormalizeFingerprint(fingerprint)
+	keys, err := TrustedKeys(tool, pinned)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, key := range keys {
+		if key.Fingerprint == fingerprint {
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("key %s is not trusted to sign %s", fingerprint, tool)
+	}
+
+	records, err := readTrust()
+	if err != nil {
+		return err
+	}
+	record := records[tool]
+	record.Added = removeFingerprint(record.Added, fingerprint)
+	for _, p := range pinned {
+		if utils.NormalizeFingerprint(p) == fingerprint && !utils.Contains(record.Removed, fingerprint) {
+			record.Removed = append(record.Removed, fingerprint)
+		}
+	}
+	records[tool] = record
+	err = writeTrust(records)
+	if err != nil {
+		return err
+	}
+
+	keyRing, err := readKeyRing(tool)
+	if err != nil {
+		return err
+	}
+	remaining := openpgp.EntityList{}
+	for _, key := range keyRing {
+		if utils.Fingerprint(key) != fingerprint {
+			remaining = append(remaining, key)
+		}
+	}
+	return writeKeyRing(tool, remaining)
+}
+
+// trustedKeyRing returns the keys trusted to sign the named tool's assets. Trusted keys which haven't been cached yet
+// are retrieved from keyURL, and cached for subsequent verifications. Keys retrieved from keyURL which aren't
+// trusted are discarded
+func trustedKeyRing(tool, keyURL string, pinned []string) (openpgp.EntityList, error) {
+	trusted, err := TrustedKeys(tool, pinned)
+	if err != nil {
+		return openpgp.EntityList{}, err
+	}
+	fingerprints := []string{}
+	missing := false
+	for _, key := range trusted {
+		fingerprints = append(fingerprints, key.Fingerprint)
+		missing = missing || !key.Cached
+	}
+	hint := fmt.Sprintf("Run 'backplane-tools trust add %s <fingerprint>' once you've confirmed the key belongs to the tool's maintainers", tool)
+	if len(fingerprints) == 0 {
+		return openpgp.EntityList{}, fmt.Errorf("no keys are trusted to sign %s. %s", tool, hint)
+	}
+
+	if missing && keyURL != "" {
+		fetched, err := utils.FetchKeyRing(keyURL)
+		if err != nil {
+			fmt.Printf("WARNING: failed to retrieve the keys trusted to sign %s: %v\n", tool, err)
+		} else {
+			err = cacheKeys(tool, filterKeys(fetched, fingerprints))
+			if err != nil {
+				return openpgp.EntityList{}, err
+			}
+		}
+	}
+
+	keyRing, err := readKeyRing(tool)
+	if err != nil {
+		return openpgp.EntityList{}, err
+	}
+	keyRing = filterKeys(keyRing, fingerprints)
+	if len(keyRing) == 0 {
+		return openpgp.EntityList{}, fmt.Errorf("none of the keys trusted to sign %s are available. %s, passing the key with --key-file", tool, hint)
+	}
+	return keyRing, nil
+}
+
+// filterKeys returns the keys whose fingerprint is one of those provided
+func filterKeys(keys openpgp.EntityList, fingerprints []string) openpgp.EntityList {
+	filtered := openpgp.EntityList{}
+	for _, key := range keys {
+		if utils.Contains(fingerprints, utils.Fingerprint(key)) {
+			filtered = append(filtered, key)
+		}
+	}
+	return filtered
+}
+
+// cacheKeys adds the provided keys to the named tool's keyring, replacing any previously cached copies
+func cacheKeys(tool string, keys openpgp.EntityList) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	keyRing, err := readKeyRing(tool)
+	if err != nil {
+		return err
+	}
+	replaced := openpgp.EntityList{}
+	fingerprints := []string{}
+	for _, key := range keys {
+		fingerprints = append(fingerprints, utils.Fingerprint(key))
+	}
+	for _, key := range keyRing {
+		if !utils.Contains(fingerprints, utils.Fingerprint(key)) {
+			replaced = append(replaced, key)
+		}
+	}
+	return writeKeyRing(tool, append(replaced, keys...))
+}
+
+// keyRingPath returns the location of the named tool's keyring
+func keyRingPath(tool string) string {
+	return filepath.Join(keyringsDir, tool+".gpg")
+}
+
+func readKeyRing(tool string) (openpgp.EntityList, error) {
+	path := keyRingPath(tool)
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return openpgp.EntityList{}, nil
+	}
+	if err != nil {
+		return openpgp.EntityList{}, fmt.Errorf("failed to open keyring '%s': %w", path, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	keyRing, err := utils.ReadKeyRing(file)
+	if err != nil {
+		return openpgp.EntityList{}, fmt.Errorf("failed to read keyring '%s': %w", path, err)
+	}
+	return keyRing, nil
+}
+
+func writeKeyRing(tool string, keyRing openpgp.EntityList) error {
+	path := keyRingPath(tool)
+	var buf bytes.Buffer
+	for _, key := range keyRing {
+		err := key.Serialize(&buf)
+		if err != nil {
+			return fmt.Errorf("failed to encode key %s: %w", utils.Fingerprint(key), err)
+		}
+	}
+	err := os.MkdirAll(keyringsDir, os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create keyring directory '%s': %w", keyringsDir, err)
+	}
+	return utils.WriteFile(&buf, path, utils.DataMode)
+}
+
+func readTrust() (map[string]trustRecord, error) {
+	records := map[string]trustRecord{}
+	data, err := os.ReadFile(trustPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return records, nil
+	}
+	if err != nil {
+		return records, fmt.Errorf("failed to read '%s': %w", trustPath, err)
+	}
+	err = json.Unmarshal(data, &records)
+	if err != nil {
+		return records, fmt.Errorf("failed to parse '%s': %w", trustPath, err)
+	}
+	return records, nil
+}
+
+func writeTrust(records map[string]trustRecord) error {
+	for tool, record := range records {
+		if len(record.Added) == 0 && len(record.Removed) == 0 {
+			delete(records, tool)
+		}
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode trusted keys: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(trustPath), os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	err = os.WriteFile(trustPath, data, os.FileMode(0o644))
+	if err != nil {
+		return fmt.Errorf("failed to write '%s': %w", trustPath, err)
+	}
+	return nil
+}
+
+// removeFingerprint returns the provided fingerprints, excluding the one to remove
+func removeFingerprint(fingerprints []string, remove string) []string {
+	remaining := []string{}
+	for _, fingerprint := range fingerprints {
+		if fingerprint != remove {
+			remaining = append(remaining, fingerprint)
+		}
+	}
+	return remaining
+}
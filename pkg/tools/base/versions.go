@@ -0,0 +1,53 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// InstalledVersions returns the versions of the named tool present in its tool directory, sorted by name
+func InstalledVersions(toolName string) ([]string, error) {
+	toolDir := filepath.Join(InstallDir, toolName)
+	entries, err := os.ReadDir(toolDir)
+	if err != nil {
+		return []string{}, fmt.Errorf("failed to read tool directory '%s': %w", toolDir, err)
+	}
+	versions := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			versions = append(versions, entry.Name())
+		}
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+// VersionExecutable returns the location of the named tool's executable within the provided installed version.
+// The location recorded in the version's manifest is preferred; versions installed without a manifest are searched
+// for an executable with the provided name
+func VersionExecutable(toolName, executableName, version string) (string, error) {
+	if version == "" || version == "." || version == ".." || strings.ContainsRune(version, os.PathSeparator) {
+		return "", fmt.Errorf("invalid version '%s'", version)
+	}
+	versionDir := filepath.Join(InstallDir, toolName, version)
+	info, err := os.Stat(versionDir)
+	if err != nil || !info.IsDir() {
+		installed, _ := InstalledVersions(toolName)
+		return "", fmt.Errorf("version '%s' of %s is not installed. Installed versions: [%s]", version, toolName, strings.Join(installed, ", "))
+	}
+
+	manifest, err := ReadVersionManifest(versionDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Printf("WARNING: %v\n", err)
+	}
+	if manifest.Executable != "" {
+		return utils.SafeJoin(versionDir, manifest.Executable)
+	}
+	return FindBinary(versionDir, executableName)
+}
@@ -1,12 +1,6 @@
 package butane
 
 import (
-	"fmt"
-	"os"
-	"path/filepath"
-
-	gogithub "github.com/google/go-github/v51/github"
-
 	"github.com/openshift/backplane-tools/pkg/sources/github"
 	"github.com/openshift/backplane-tools/pkg/tools/base"
 	"github.com/openshift/backplane-tools/pkg/utils"
@@ -22,61 +16,28 @@ func New() *Tool {
 		Github: base.Github{
 			Default: base.NewDefault("butane"),
 			Source:  github.NewSource("coreos", "butane"),
+			Spec: &base.AssetSpec{
+				Exclude: []string{".asc"},
+				Verification: base.VerificationSpec{
+					Method:      base.VerifyGPG,
+					Terms:       []string{".asc"},
+					MatchSystem: true,
+					// butane releases are signed with the key of the Fedora release current at the time
+					KeyURL: utils.FedoraSigningKeyURL,
+					Fingerprints: []string{
+						// Fedora 39
+						"E8F23996F23218640CB44CBE75CF5AC418B8E74C",
+						// Fedora 40
+						"115DF9AEF857853EE8445D0A0727707EA15B79CC",
+						// Fedora 41
+						"466CF2D8B60BC3057AA9453ED0622462E99D6AD1",
+						// Fedora 42
+						"B0F4950458F69E1150C6C5EDC8AC4916105EF944",
+					},
+				},
+			},
 		},
 	}
+	t.SetDescription("Translates Butane configs into Ignition configs for Fedora CoreOS and RHCOS")
 	return t
 }
-
-func (t *Tool) Install() error {
-	// Pull latest release from GH
-	release, err := t.Source.FetchLatestRelease()
-	if err != nil {
-		return err
-	}
-
-	matches := github.FindAssetsExcluding([]string{".asc"}, github.FindAssetsForArchAndOS(release.Assets))
-	if len(matches) != 1 {
-		return fmt.Errorf("unexpected number of executable assets found matching system spec: expected 1, got %d.\nMatching assets: %v", len(matches), matches)
-	}
-	executableAsset := matches[0]
-
-	matches = github.FindAssetsContaining([]string{".asc"}, github.FindAssetsForArchAndOS(release.Assets))
-	if len(matches) != 1 {
-		return fmt.Errorf("unexpected number of checksum assets found: expected 1, got %d.\nMatching assets: %v", len(matches), matches)
-	}
-	signatureAsset := matches[0]
-
-	// Download the arch- & os-specific assets
-	toolDir := t.ToolDir()
-	versionedDir := filepath.Join(toolDir, release.GetTagName())
-	err = os.MkdirAll(versionedDir, os.FileMode(0o755))
-	if err != nil {
-		return fmt.Errorf("failed to create version-specific directory '%s': %w", versionedDir, err)
-	}
-
-	err = t.Source.DownloadReleaseAssets([]*gogithub.ReleaseAsset{signatureAsset, executableAsset}, versionedDir)
-	if err != nil {
-		return fmt.Errorf("failed to download one or more assets: %w", err)
-	}
-
-	// Verify signature of downloaded assets
-	executableFilepath := filepath.Join(versionedDir, executableAsset.GetName())
-	signatureFilepath := filepath.Join(versionedDir, signatureAsset.GetName())
-
-	err = utils.VerifyGPGSignature(executableFilepath, signatureFilepath)
-	if err != nil {
-		return fmt.Errorf("failed to verify executable signature: %w", err)
-	}
-
-	// Link as latest
-	latestFilePath := t.SymlinkPath()
-	err = os.Remove(latestFilePath)
-	if err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to remove existing executable at '%s': %w", base.LatestDir, err)
-	}
-	err = os.Symlink(executableFilepath, latestFilePath)
-	if err != nil {
-		return fmt.Errorf("failed to link new executable to '%s': %w", base.LatestDir, err)
-	}
-	return nil
-}
@@ -0,0 +1,49 @@
+package butane_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/openshift/backplane-tools/pkg/tools/butane"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/openshift/backplane-tools/pkg/utils/test"
+)
+
+func TestConformance(t *testing.T) {
+	key, err := test.NewKey()
+	if err != nil {
+		t.Fatalf("failed to create signing key: %v", err)
+	}
+	armored, err := key.Armored()
+	if err != nil {
+		t.Fatalf("failed to encode signing key: %v", err)
+	}
+	asset := fmt.Sprintf("butane-%s-%s", utils.TargetArch, utils.TargetOS)
+	executable := []byte("#!/bin/sh\necho 'Butane 1.0.0'\n")
+	signature, err := key.Sign(executable)
+	if err != nil {
+		t.Fatalf("failed to sign asset: %v", err)
+	}
+	assets := map[string][]byte{
+		asset:          executable,
+		asset + ".asc": signature,
+	}
+
+	// The release is signed by the test's key, published in place of Fedora's
+	tool := butane.New()
+	tool.Spec.Verification.Fingerprints = []string{key.Fingerprint()}
+	routes := map[string]http.Handler{
+		"fedoraproject.org": http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write(armored)
+		}),
+	}
+
+	violations, err := test.GithubConformance(tool, "coreos", "butane", "v1.0.0", assets, routes)
+	if err != nil {
+		t.Fatalf("failed to run conformance test: %v", err)
+	}
+	for _, violation := range violations {
+		t.Error(violation)
+	}
+}
@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/openshift/backplane-tools/pkg/verify"
+)
+
+// VerifyCodeSignatures checks the macOS code signature and notarization of each executable the provided tool
+// publishes into the latest directory, according to the tool's code signature policy. Under the 'warn' policy,
+// failures are reported as warnings; under 'enforce', an error is returned. Executables which can't be signed, such as
+// shell scripts, are skipped. Signatures can only be checked on macOS, so tools installed elsewhere are not verified
+func VerifyCodeSignatures(tool Tool) error {
+	policy := config.Get().CodeSignaturePolicy(tool.Name())
+	if policy == config.CodeSignatureOff || runtime.GOOS != "darwin" {
+		return nil
+	}
+
+	linkPaths := []string{filepath.Join(base.LatestDir, tool.ExecutableName())}
+	if l, ok := tool.(linker); ok {
+		linkPaths = l.LinkPaths()
+	}
+	for _, linkPath := range linkPaths {
+		target, err := base.ResolveLink(linkPath)
+		if err != nil {
+			return fmt.Errorf("failed to resolve '%s': %w", linkPath, err)
+		}
+		err = verify.CodeSignature(target)
+		if err == nil || errors.Is(err, verify.ErrNotMachO) {
+			continue
+		}
+		if policy == config.CodeSignatureWarn {
+			fmt.Printf("WARNING: failed to verify the code signature of '%s': %v. Continuing per the configured code signature policy\n", target, err)
+			continue
+		}
+		return fmt.Errorf("failed to verify the code signature of '%s': %w", target, err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package crc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/openshift/backplane-tools/pkg/sources/openshift.com/mirror"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/openshift/backplane-tools/pkg/verify"
+)
+
+// executableName is the name of the crc executable within the release archive
+const executableName = "crc"
+
+// Tool implements the interface to manage the 'crc' binary
+type Tool struct {
+	base.Mirror
+
+	// latestVersion caches the version published in the latest directory
+	latestVersion string
+}
+
+// releaseInfo is the subset of the release-info.json file, published alongside each release, used to determine the
+// release's version
+type releaseInfo struct {
+	Version struct {
+		CrcVersion string `json:"crcVersion"`
+	} `json:"version"`
+}
+
+func New() *Tool {
+	t := &Tool{
+		Mirror: base.Mirror{
+			Default:  base.NewDefault("crc"),
+			Source:   mirror.NewSource(),
+			BaseSlug: "/pub/openshift-v4/clients/crc/latest/",
+			// crc's checksum file is signed separately from the OpenShift clients', and named differently
+			SignatureSuffix: ".sig",
+		},
+	}
+	t.SetDescription("OpenShift Local, which runs a single-node OpenShift cluster on the local machine")
+	t.SetSmokeTest(base.SmokeTest{Args: []string{"version"}})
+	return t
+}
+
+// LatestVersion retrieves the version of the latest release from its release-info.json file, as crc releases
+// aren't published with a release.txt file
+func (t *Tool) LatestVersion() (string, error) {
+	if t.latestVersion != "" {
+		return t.latestVersion, nil
+	}
+	releaseSlug, err := url.JoinPath(t.Slug(), "release-info.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to build release info URL: %w", err)
+	}
+	releaseData, err := t.Source.GetFileContents(releaseSlug)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve release info from %s: %w", releaseSlug, err)
+	}
+	defer func() {
+		closeErr := releaseData.Close()
+		if closeErr != nil {
+			fmt.Printf("WARNING: failed to close response body: %v\n", closeErr)
+		}
+	}()
+
+	info := releaseInfo{}
+	err = json.NewDecoder(releaseData).Decode(&info)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse release info from %s: %w", releaseSlug, err)
+	}
+	if !utils.IsVersion(info.Version.CrcVersion) {
+		return "", fmt.Errorf("failed to parse release info from %s: invalid version '%s'", releaseSlug, info.Version.CrcVersion)
+	}
+	t.latestVersion = info.Version.CrcVersion
+	return t.latestVersion, nil
+}
+
+// archiveName returns the name of the release archive for the target platform
+func archiveName() (string, error) {
+	switch utils.TargetOS {
+	case "linux":
+		return fmt.Sprintf("crc-linux-%s.tar.xz", utils.TargetArch), nil
+	case "darwin":
+		// A single universal installer is published for macOS
+		return "crc-macos-installer.pkg", nil
+	}
+	return "", fmt.Errorf("%w: crc is not published for %s/%s", utils.ErrUnsupportedPlatform, utils.TargetOS, utils.TargetArch)
+}
+
+func (t *Tool) Install() error {
+	archive, err := archiveName()
+	if err != nil {
+		return err
+	}
+
+	version, err := t.LatestVersion()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve version info: %w", err)
+	}
+
+	versionedDir := filepath.Join(t.ToolDir(), version)
+	err = os.MkdirAll(versionedDir, os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create version-specific directory '%s': %w", versionedDir, err)
+	}
+
+	// Download release archive
+	archiveSlug, err := url.JoinPath(t.Slug(), archive)
+	if err != nil {
+		return fmt.Errorf("failed to build archive URL: %w", err)
+	}
+	archiveFilePath, err := t.Source.DownloadFile(archiveSlug, versionedDir)
+	if err != nil {
+		return fmt.Errorf("failed to download archive file %s: %w", archiveSlug, err)
+	}
+
+	// Download latest checksum file
+	checksumSlug, err := url.JoinPath(t.Slug(), "sha256sum.txt")
+	if err != nil {
+		return fmt.Errorf("failed to build checksum URL: %w", err)
+	}
+	checksumFilePath, err := t.Source.DownloadFile(checksumSlug, versionedDir)
+	if err != nil {
+		return fmt.Errorf("failed to download checksum file %s: %w", checksumSlug, err)
+	}
+
+	// Verify the checksum file was signed by Red Hat, then checksum the archive & compare
+	checksumName := fmt.Sprintf("%s, signed", filepath.Base(checksumFilePath))
+	verification, err := t.ApplyChecksumPolicy(archiveFilePath, checksumName, func() error {
+		err := t.VerifyChecksumSignature(checksumSlug, checksumFilePath)
+		if err != nil {
+			return err
+		}
+		return verify.File(archiveFilePath, checksumFilePath, verify.FormatAuto)
+	})
+	if err != nil {
+		sourceURL, urlErr := t.Source.BuildURL(archiveSlug)
+		if urlErr != nil {
+			fmt.Fprintf(os.Stderr, "failed to construct source URL for manual retrieval: %v\n", urlErr)
+			return fmt.Errorf("failed to verify '%s': %w. Please retry installation", archiveFilePath, err)
+		}
+		return fmt.Errorf("failed to verify '%s': %w. Please retry installation. If issue persists, this tool can be downloaded manually at %s", archiveFilePath, err, sourceURL)
+	}
+
+	sourceURL, err := t.Source.BuildURL(archiveSlug)
+	if err != nil {
+		return fmt.Errorf("failed to construct source URL: %w", err)
+	}
+	t.RecordArtifact(version, archiveFilePath, sourceURL, verification)
+
+	// Extract the executable. The macOS installer bundles crc within an application, which FindBinary locates
+	if utils.TargetOS == "darwin" {
+		err = utils.ExpandPkg(archiveFilePath, filepath.Join(versionedDir, "crc-macos-installer"))
+	} else {
+		err = utils.Unarchive(archiveFilePath, versionedDir)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", archiveFilePath, err)
+	}
+
+	// Link as latest
+	binaryFilepath, err := base.FindBinary(versionedDir, executableName)
+	if err != nil {
+		return err
+	}
+	return t.LinkExecutable(binaryFilepath)
+}
@@ -0,0 +1,153 @@
+package crc_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/openshift/backplane-tools/pkg/tools/crc"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/openshift/backplane-tools/pkg/utils/test"
+)
+
+// version is the version of crc published by newMirror
+const version = "2.32.0"
+
+// slug is the directory crc's latest release is published to
+const slug = "/pub/openshift-v4/clients/crc/latest/"
+
+// tarXz returns an xz-compressed tarball containing the provided executable at name
+func tarXz(t *testing.T, name string, executable []byte) []byte {
+	t.Helper()
+	xz, err := exec.LookPath("xz")
+	if err != nil {
+		t.Skip("'xz' is required to publish crc's release archive")
+	}
+	var archive bytes.Buffer
+	tarWriter := tar.NewWriter(&archive)
+	err = tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(executable)), Typeflag: tar.TypeReg})
+	if err == nil {
+		_, err = tarWriter.Write(executable)
+	}
+	if err == nil {
+		err = tarWriter.Close()
+	}
+	if err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+
+	cmd := exec.Command(xz, "-c")
+	cmd.Stdin = &archive
+	compressed, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("failed to compress archive: %v", err)
+	}
+	return compressed
+}
+
+// newMirror starts a MirrorEnv publishing version of crc, and returns it along with a 'crc' tool installing from it
+func newMirror(t *testing.T) (*test.MirrorEnv, *crc.Tool) {
+	t.Helper()
+	if utils.TargetOS != "linux" {
+		t.Skip("crc is published as an installer package for macOS")
+	}
+	env, err := test.NewMirrorEnv()
+	if err != nil {
+		t.Fatalf("failed to create mirror environment: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = env.Close()
+	})
+
+	env.AddFile(slug, "release-info.json", []byte(`{"version": {"crcVersion": "`+version+`"}}`))
+	archiveDir := "crc-linux-" + version + "-" + utils.TargetArch
+	executable := []byte("#!/bin/sh\necho 'CRC version: " + version + "'\n")
+	env.AddFile(slug, "crc-linux-"+utils.TargetArch+".tar.xz", tarXz(t, archiveDir+"/crc", executable))
+
+	tool := crc.New()
+	env.Use(&tool.Mirror)
+	return env, tool
+}
+
+func TestLatestVersion(t *testing.T) {
+	_, tool := newMirror(t)
+	latest, err := tool.LatestVersion()
+	if err != nil {
+		t.Fatalf("failed to retrieve latest version: %v", err)
+	}
+	if latest != version {
+		t.Errorf("expected latest version '%s', got '%s'", version, latest)
+	}
+}
+
+func TestInstall(t *testing.T) {
+	_, tool := newMirror(t)
+	err := tool.Install()
+	if err != nil {
+		t.Fatalf("failed to install: %v", err)
+	}
+
+	installed, err := tool.InstalledVersion()
+	if err != nil {
+		t.Fatalf("failed to determine installed version: %v", err)
+	}
+	if installed != version {
+		t.Errorf("expected version '%s' to be installed, got '%s'", version, installed)
+	}
+	target, err := os.Readlink(filepath.Join(base.LatestDir, "crc"))
+	if err != nil {
+		t.Fatalf("expected 'crc' to be linked: %v", err)
+	}
+	if !strings.HasPrefix(target, filepath.Join(tool.ToolDir(), version)) {
+		t.Errorf("expected 'crc' to link into version '%s', got '%s'", version, target)
+	}
+}
+
+func TestInstallRejectsUnverifiedChecksums(t *testing.T) {
+	tests := []struct {
+		name        string
+		signatures  test.Signatures
+		expectedErr string
+	}{
+		{name: "bad signature", signatures: test.SignaturesInvalid, expectedErr: "failed to verify checksum file"},
+		{name: "missing signature", signatures: test.SignaturesMissing, expectedErr: "failed to download signature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, tool := newMirror(t)
+			env.SetSignatures(tt.signatures)
+			err := tool.Install()
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("expected error containing '%s', got '%v'", tt.expectedErr, err)
+			}
+			_, err = os.Lstat(filepath.Join(base.LatestDir, "crc"))
+			if !os.IsNotExist(err) {
+				t.Errorf("expected 'crc' not to be linked after a failed installation")
+			}
+		})
+	}
+}
+
+func TestConformance(t *testing.T) {
+	env, tool := newMirror(t)
+	h, err := test.NewHarness()
+	if err != nil {
+		t.Fatalf("failed to create harness: %v", err)
+	}
+	defer func() {
+		_ = h.Close()
+	}()
+	h.Route("127.0.0.1", env.Server.Config.Handler)
+
+	violations := test.Conformance(h, tool, func() {
+		env.SetSignatures(test.SignaturesMissing)
+	})
+	for _, violation := range violations {
+		t.Error(violation)
+	}
+}
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// dependent is implemented by tools which rely on other tools managed by backplane-tools, such as plugins
+// invoked through another tool's executable
+type dependent interface {
+	Dependencies() []string
+}
+
+// DependenciesOf returns the names of the tools the provided tool relies on
+func DependenciesOf(tool Tool) []string {
+	d, ok := tool.(dependent)
+	if !ok {
+		return []string{}
+	}
+	return d.Dependencies()
+}
+
+// requirer is implemented by tools which rely on executables not managed by backplane-tools
+type requirer interface {
+	Requirements() []string
+}
+
+// MissingRequirements returns the executables the provided tool relies on which cannot be found in the $PATH
+func MissingRequirements(tool Tool) []string {
+	r, ok := tool.(requirer)
+	if !ok {
+		return []string{}
+	}
+	missing := []string{}
+	for _, executable := range r.Requirements() {
+		_, err := exec.LookPath(executable)
+		if err != nil {
+			missing = append(missing, executable)
+		}
+	}
+	return missing
+}
+
+// WithDependencies returns the provided tools, along with any tools they depend upon - directly or transitively -
+// which have not been installed yet
+func WithDependencies(list []Tool) ([]Tool, error) {
+	toolMap := GetMap()
+	included := map[string]bool{}
+	for _, tool := range list {
+		included[tool.Name()] = true
+	}
+
+	result := append([]Tool{}, list...)
+	for i := 0; i < len(result); i++ {
+		for _, name := range DependenciesOf(result[i]) {
+			if included[name] {
+				continue
+			}
+			dependency, found := toolMap[name]
+			if !found {
+				return []Tool{}, fmt.Errorf("%s depends on '%s', which is not a supported tool", result[i].Name(), name)
+			}
+			installed, err := dependency.Installed()
+			if err != nil {
+				return []Tool{}, fmt.Errorf("failed to determine if %s is installed: %w", name, err)
+			}
+			included[name] = true
+			if !installed {
+				result = append(result, dependency)
+			}
+		}
+	}
+	return result, nil
+}
+
+// Order sorts the provided tools so that each is preceded by the tools it depends upon. Tools are otherwise
+// sorted by name, so that the order is consistent between runs. Dependencies absent from the provided tools are
+// ignored. An error is returned if the tools depend upon each other cyclically
+func Order(list []Tool) ([]Tool, error) {
+	byName := map[string]Tool{}
+	for _, tool := range list {
+		byName[tool.Name()] = tool
+	}
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	ordered := make([]Tool, 0, len(names))
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("tools depend on each other cyclically: %s", strings.Join(append(path, name), " -> "))
+		}
+		state[name] = visiting
+		dependencies := DependenciesOf(byName[name])
+		sort.Strings(dependencies)
+		for _, dependency := range dependencies {
+			if _, found := byName[dependency]; !found {
+				continue
+			}
+			err := visit(dependency, append(path, name))
+			if err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		ordered = append(ordered, byName[name])
+		return nil
+	}
+	for _, name := range names {
+		err := visit(name, []string{})
+		if err != nil {
+			return []Tool{}, err
+		}
+	}
+	return ordered, nil
+}
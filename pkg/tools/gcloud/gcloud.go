@@ -4,13 +4,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	gstorage "cloud.google.com/go/storage"
 
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/sources"
 	"github.com/openshift/backplane-tools/pkg/sources/cloud.google.com/storage"
 	"github.com/openshift/backplane-tools/pkg/tools/base"
 	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/openshift/backplane-tools/pkg/verify"
 )
 
 const (
@@ -18,7 +22,18 @@ const (
 	// https://console.cloud.google.com/storage/browser/cloud-sdk-release;tab=objects?prefix=&forceOnObjectsSortingFiltering=false for reference
 	toolBucket = "cloud-sdk-release"
 	// listPrefix is a filter used to identify the tool's objects within the bucket
-	listPrefix = "google-cloud-cli"
+	listPrefix = "google-cloud-cli-"
+	// versionDelimiter separates the major version from the rest of the object's name. Listing with this
+	// delimiter returns a single prefix for each major version, rather than every object in the bucket
+	versionDelimiter = "."
+	// maxListedMajorVersions bounds how many of the newest major versions are included when listing the tool's
+	// versions, as each requires listing the bucket separately
+	maxListedMajorVersions = 10
+	// maxMajorVersions bounds how many of the newest major versions are searched for an archive matching the
+	// local system, in case the newest has not yet been published for every platform
+	maxMajorVersions = 3
+	// objectHashesName describes where the hashes the archive is verified against are published
+	objectHashesName = "object metadata crc32c/md5"
 )
 
 // Tool manages the installation, upgrade and removal of the 'gcloud' tool
@@ -31,7 +46,11 @@ type Tool struct {
 
 // New initializes a new 'gcloud' tool
 func New() (*Tool, error) {
-	src, err := storage.NewSource(toolBucket)
+	settings := config.Get().GoogleCloudStorage
+	src, err := storage.NewSourceWithOptions(toolBucket, storage.Options{
+		Endpoint:      settings.Endpoint,
+		Authenticated: settings.Authenticated,
+	})
 	if err != nil {
 		return &Tool{}, fmt.Errorf("error while initializing gcloud bucket: %w", err)
 	}
@@ -39,6 +58,9 @@ func New() (*Tool, error) {
 		Default: base.NewDefault("gcloud"),
 		Source:  src,
 	}
+	// The SDK also provides the gsutil and bq clients
+	t.AddExecutables(filepath.Join(sdkDirName, "bin", "gsutil"), filepath.Join(sdkDirName, "bin", "bq"))
+	t.SetDescription("The Google Cloud CLI, including gsutil and bq, for managing Google Cloud resources")
 	return t, nil
 }
 
@@ -65,25 +87,39 @@ func (t *Tool) Install() error {
 		return fmt.Errorf("failed to download object '%s': %w", latestArchive.Name, err)
 	}
 
+	// Verify the archive against the hashes stored in the object's metadata
 	archiveFilePath := filepath.Join(versionedDir, latestArchive.Name)
+	attrs, err := t.Source.ObjectAttrs(latestArchive.Name)
+	if err != nil {
+		return err
+	}
+	verification, err := t.ApplyChecksumPolicy(archiveFilePath, objectHashesName, func() error {
+		return verify.ObjectHashes(archiveFilePath, attrs.CRC32C, attrs.MD5)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to verify '%s': %w. Please retry installation", archiveFilePath, err)
+	}
+	t.RecordArtifact(versionName, archiveFilePath, fmt.Sprintf("gs://%s/%s", toolBucket, latestArchive.Name), verification)
+
 	err = utils.Unarchive(archiveFilePath, versionedDir)
 	if err != nil {
 		return fmt.Errorf("failed to unarchive '%s': %w", archiveFilePath, err)
 	}
 
-	// Link as latest
-	latestFilePath := t.SymlinkPath()
-	err = os.Remove(latestFilePath)
-	if err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to remove existing symlink '%s': %w", latestFilePath, err)
+	// Carry the previously installed version's properties and components over, so that upgrades are transparent
+	previousVersion, err := t.InstalledVersion()
+	if err == nil && previousVersion != "" && previousVersion != versionName {
+		t.migrateInstallation(filepath.Join(toolDir, previousVersion), versionedDir)
 	}
 
-	executableFilePath := filepath.Join(versionedDir, "google-cloud-sdk", "bin", "gcloud")
-	err = os.Symlink(executableFilePath, latestFilePath)
-	if err != nil {
-		return fmt.Errorf("failed to link new executable '%s' to '%s': %w", executableFilePath, latestFilePath, err)
-	}
-	return nil
+	// Link as latest
+	executableFilePath := filepath.Join(versionedDir, sdkDirName, "bin", "gcloud")
+	return t.LinkExecutable(executableFilePath)
+}
+
+// ToolSource returns the source the tool is installed from
+func (t *Tool) ToolSource() sources.Source {
+	return t.Source
 }
 
 // LatestVersion determines the latest version of the tool available for install
@@ -96,19 +132,71 @@ func (t *Tool) LatestVersion() (string, error) {
 	return version, nil
 }
 
+// SemanticVersioning returns false: gcloud increments its major version with every weekly release, so a new major
+// version doesn't indicate breaking changes
+func (t *Tool) SemanticVersioning() bool {
+	return false
+}
+
+// ListVersions returns the versions of the tool published for the local system within the most recent major
+// versions, from oldest to newest
+func (t *Tool) ListVersions() ([]string, error) {
+	majorPrefixes, err := t.newestMajorPrefixes(maxListedMajorVersions)
+	if err != nil {
+		return []string{}, err
+	}
+	versions := []string{}
+	for _, prefix := range majorPrefixes {
+		objs, err := t.Source.ListObjects(prefix)
+		if err != nil {
+			return []string{}, fmt.Errorf("failed to list objects in bucket: %w", err)
+		}
+		for _, obj := range t.Source.FindObjectsForArchAndOS(objs) {
+			version, found := t.getVersionNameFromArchive(obj)
+			if found {
+				versions = append(versions, version)
+			}
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return storage.CompareNames(versions[i], versions[j]) < 0
+	})
+	return versions, nil
+}
+
+// newestMajorPrefixes returns the prefixes of the provided number of newest major versions in the bucket, newest first
+func (t *Tool) newestMajorPrefixes(count int) ([]string, error) {
+	majorPrefixes, err := t.Source.ListPrefixes(listPrefix, versionDelimiter)
+	if err != nil {
+		return []string{}, fmt.Errorf("failed to list versions in bucket: %w", err)
+	}
+	sort.Slice(majorPrefixes, func(i, j int) bool {
+		return storage.CompareNames(majorPrefixes[i], majorPrefixes[j]) > 0
+	})
+	if len(majorPrefixes) > count {
+		majorPrefixes = majorPrefixes[:count]
+	}
+	return majorPrefixes, nil
+}
+
 // findLatestObjectForSystem locates the most recent version of the gcloud tool based on the system's spec (OS+architecture)
 func (t *Tool) findLatestObjectForSystem() (*gstorage.ObjectAttrs, error) {
-	objs, err := t.Source.ListObjects(listPrefix)
+	majorPrefixes, err := t.newestMajorPrefixes(maxMajorVersions)
 	if err != nil {
-		return &gstorage.ObjectAttrs{}, fmt.Errorf("failed to list objects in bucket: %w", err)
+		return &gstorage.ObjectAttrs{}, err
 	}
 
-	matches := t.Source.FindObjectsForArchAndOS(objs)
-	if len(matches) == 0 {
-		return &gstorage.ObjectAttrs{}, fmt.Errorf("unexpected number of assets found matching system spec: expected at least 1, got %d", len(matches))
+	for _, prefix := range majorPrefixes {
+		objs, err := t.Source.ListObjects(prefix)
+		if err != nil {
+			return &gstorage.ObjectAttrs{}, fmt.Errorf("failed to list objects in bucket: %w", err)
+		}
+		matches := t.Source.FindObjectsForArchAndOS(objs)
+		if len(matches) > 0 {
+			return t.Source.FindLatest(matches), nil
+		}
 	}
-
-	return t.Source.FindLatest(matches), nil
+	return &gstorage.ObjectAttrs{}, fmt.Errorf("unexpected number of assets found matching system spec: expected at least 1, got 0: %w", utils.ErrUnsupportedPlatform)
 }
 
 // getVersionNameFromArchive is a helper function to convert a bucket object's name from <versioned-name>.tar.gz format to <versioned-name>
@@ -0,0 +1,149 @@
+package gcloud_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/openshift/backplane-tools/pkg/tools/gcloud"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/openshift/backplane-tools/pkg/utils/test"
+)
+
+// bucket is the name of the bucket gcloud is published to
+const bucket = "cloud-sdk-release"
+
+// newEnv starts a GCSEnv, and returns it along with a 'gcloud' tool installing from it
+func newEnv(t *testing.T) (*test.GCSEnv, *gcloud.Tool) {
+	t.Helper()
+	env, err := test.NewGCSEnv()
+	if err != nil {
+		t.Fatalf("failed to create storage environment: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = env.Close()
+	})
+
+	tool, err := gcloud.New()
+	if err != nil {
+		t.Fatalf("failed to initialize gcloud: %v", err)
+	}
+	tool.Source, err = env.Source(bucket)
+	if err != nil {
+		t.Fatalf("failed to initialize storage source: %v", err)
+	}
+	return env, tool
+}
+
+// newBucket starts a GCSEnv publishing the provided archives, and returns a 'gcloud' tool installing from it
+func newBucket(t *testing.T, archives ...string) *gcloud.Tool {
+	t.Helper()
+	env, tool := newEnv(t)
+	for _, archive := range archives {
+		env.AddObject(bucket, archive, []byte(archive))
+	}
+	return tool
+}
+
+// setTarget installs tools for the provided platform for the duration of the test
+func setTarget(t *testing.T, os, arch string) {
+	t.Helper()
+	originalOS, originalArch := utils.TargetOS, utils.TargetArch
+	utils.TargetOS, utils.TargetArch = os, arch
+	t.Cleanup(func() {
+		utils.TargetOS, utils.TargetArch = originalOS, originalArch
+	})
+}
+
+// published lists the archives used to test version and asset selection. The newest major version has only been
+// published for macOS on amd64, and versions must be compared numerically to find the newest of the rest
+var published = []string{
+	"google-cloud-cli-99.0.0-linux-x86_64.tar.gz",
+	"google-cloud-cli-469.0.0-linux-x86_64.tar.gz",
+	"google-cloud-cli-470.0.0-linux-x86_64.tar.gz",
+	"google-cloud-cli-470.0.0-linux-arm.tar.gz",
+	"google-cloud-cli-470.0.0-darwin-arm.tar.gz",
+	"google-cloud-cli-471.0.0-darwin-x86_64.tar.gz",
+}
+
+func TestLatestVersion(t *testing.T) {
+	tests := []struct {
+		os          string
+		arch        string
+		expected    string
+		expectedErr error
+	}{
+		{os: "linux", arch: "amd64", expected: "google-cloud-cli-470.0.0-linux-x86_64"},
+		{os: "linux", arch: "arm64", expected: "google-cloud-cli-470.0.0-linux-arm"},
+		{os: "darwin", arch: "amd64", expected: "google-cloud-cli-471.0.0-darwin-x86_64"},
+		{os: "darwin", arch: "arm64", expected: "google-cloud-cli-470.0.0-darwin-arm"},
+		{os: "windows", arch: "amd64", expectedErr: utils.ErrUnsupportedPlatform},
+	}
+	for _, tt := range tests {
+		t.Run(tt.os+"/"+tt.arch, func(t *testing.T) {
+			setTarget(t, tt.os, tt.arch)
+			tool := newBucket(t, published...)
+			version, err := tool.LatestVersion()
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("expected error '%v', got '%v'", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to determine latest version: %v", err)
+			}
+			if version != tt.expected {
+				t.Errorf("expected latest version '%s', got '%s'", tt.expected, version)
+			}
+		})
+	}
+}
+
+func TestListVersions(t *testing.T) {
+	setTarget(t, "linux", "amd64")
+	tool := newBucket(t, published...)
+	versions, err := tool.ListVersions()
+	if err != nil {
+		t.Fatalf("failed to list versions: %v", err)
+	}
+	expected := []string{
+		"google-cloud-cli-99.0.0-linux-x86_64",
+		"google-cloud-cli-469.0.0-linux-x86_64",
+		"google-cloud-cli-470.0.0-linux-x86_64",
+	}
+	if !reflect.DeepEqual(versions, expected) {
+		t.Errorf("expected versions %v, got %v", expected, versions)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	env, tool := newEnv(t)
+	executable := []byte("#!/bin/sh\necho 'Google Cloud SDK 470.0.0'\n")
+	err := env.AddArchive(bucket, fmt.Sprintf("google-cloud-cli-470.0.0-%s-%s.tar.gz", utils.TargetOS, utils.TargetArch), map[string][]byte{
+		"google-cloud-sdk/bin/gcloud": executable,
+		"google-cloud-sdk/bin/gsutil": executable,
+		"google-cloud-sdk/bin/bq":     executable,
+	})
+	if err != nil {
+		t.Fatalf("failed to publish archive: %v", err)
+	}
+	h, err := test.NewHarness()
+	if err != nil {
+		t.Fatalf("failed to create harness: %v", err)
+	}
+	defer func() {
+		_ = h.Close()
+	}()
+	h.Route("127.0.0.1", env.Server.Config.Handler)
+
+	// Server errors are retried by the storage client, so the bucket is removed instead
+	violations := test.Conformance(h, tool, func() {
+		h.Route("127.0.0.1", http.NotFoundHandler())
+	})
+	for _, violation := range violations {
+		t.Error(violation)
+	}
+}
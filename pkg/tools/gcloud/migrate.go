@@ -0,0 +1,131 @@
+package gcloud
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+const (
+	// sdkDirName is the directory each version of the SDK is unpacked into within its versioned directory
+	sdkDirName = "google-cloud-sdk"
+
+	// installStateDirName is the directory within the SDK recording the components installed into it
+	installStateDirName = ".install"
+
+	// componentManifestSuffix ends the name of the file recording each installed component within installStateDirName
+	componentManifestSuffix = ".manifest"
+
+	// propertiesFileName is the file within the SDK holding installation-wide properties, as set by
+	// 'gcloud config set --installation'. Per-user properties are stored outside of the SDK, and are unaffected by upgrades
+	propertiesFileName = "properties"
+)
+
+// migrateInstallation carries the state of the SDK installed at previousDir over to the one installed at newDir: its
+// installation-wide properties are copied, and any components the user added to it are installed into the new SDK.
+// Anything that can't be carried over is warned of, leaving the new SDK in place
+func (t *Tool) migrateInstallation(previousDir, newDir string) {
+	previousSDK := filepath.Join(previousDir, sdkDirName)
+	newSDK := filepath.Join(newDir, sdkDirName)
+
+	err := migrateProperties(previousSDK, newSDK)
+	if err != nil {
+		fmt.Printf("WARNING: failed to carry over gcloud's installation properties: %v\n", err)
+	}
+
+	added, err := addedComponents(previousSDK, newSDK)
+	if err != nil {
+		fmt.Printf("WARNING: failed to determine the gcloud components installed into the previous version: %v\n", err)
+		return
+	}
+	if len(added) == 0 {
+		return
+	}
+	if utils.TargetOS != runtime.GOOS || utils.TargetArch != runtime.GOARCH {
+		// The new SDK can't be run to install components for another platform
+		return
+	}
+	fmt.Printf("Installing the gcloud components installed into the previous version: %s\n", strings.Join(added, ", "))
+	args := append([]string{"components", "install", "--quiet"}, added...)
+	cmd := exec.Command(filepath.Join(newSDK, "bin", "gcloud"), args...)
+	cmd.Env = append(os.Environ(), "CLOUDSDK_CORE_DISABLE_PROMPTS=1")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		fmt.Printf("WARNING: failed to install gcloud components '%s': %v: %s. Run 'gcloud components install %s' to retry\n", strings.Join(added, "', '"), err, strings.TrimSpace(string(out)), strings.Join(added, " "))
+	}
+}
+
+// migrateProperties copies the installation-wide properties of the previous SDK to the new SDK, unless the new SDK
+// already defines its own
+func migrateProperties(previousSDK, newSDK string) error {
+	previousPath := filepath.Join(previousSDK, propertiesFileName)
+	previous, err := os.Open(previousPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %w", previousPath, err)
+	}
+	defer func() {
+		closeErr := previous.Close()
+		if closeErr != nil {
+			fmt.Fprintf(os.Stderr, "failed to close file '%s': %v\n", previousPath, closeErr)
+		}
+	}()
+
+	newPath := filepath.Join(newSDK, propertiesFileName)
+	_, err = os.Stat(newPath)
+	if err == nil {
+		return nil
+	}
+	if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to stat '%s': %w", newPath, err)
+	}
+	return utils.WriteFile(previous, newPath, os.FileMode(0o644))
+}
+
+// addedComponents returns the sorted IDs of the components installed into the previous SDK, but not the new SDK
+func addedComponents(previousSDK, newSDK string) ([]string, error) {
+	previous, err := installedComponents(previousSDK)
+	if err != nil {
+		return []string{}, err
+	}
+	current, err := installedComponents(newSDK)
+	if err != nil {
+		return []string{}, err
+	}
+	added := []string{}
+	for _, component := range previous {
+		if !utils.Contains(current, component) {
+			added = append(added, component)
+		}
+	}
+	sort.Strings(added)
+	return added, nil
+}
+
+// installedComponents returns the IDs of the components installed into the provided SDK, as recorded by the manifest
+// gcloud writes for each
+func installedComponents(sdkDir string) ([]string, error) {
+	stateDir := filepath.Join(sdkDir, installStateDirName)
+	entries, err := os.ReadDir(stateDir)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return []string{}, fmt.Errorf("failed to read '%s': %w", stateDir, err)
+	}
+	components := []string{}
+	for _, entry := range entries {
+		if component, found := strings.CutSuffix(entry.Name(), componentManifestSuffix); found && !entry.IsDir() {
+			components = append(components, component)
+		}
+	}
+	return components, nil
+}
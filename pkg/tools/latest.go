@@ -0,0 +1,35 @@
+package tools
+
+import (
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// maxConcurrentLookups bounds how many tools' latest versions are retrieved at once, so that sources aren't flooded
+// with requests
+const maxConcurrentLookups = 8
+
+// LatestVersions retrieves the latest version of each provided tool concurrently. The results are indexed like the
+// provided tools: versions[i] is the latest version of tools[i], unless errs[i] describes why it couldn't be
+// determined. A tool failing doesn't prevent the others from being looked up
+func LatestVersions(tools []Tool) (versions []string, errs []error) {
+	versions = make([]string, len(tools))
+	errs = make([]error, len(tools))
+	var group errgroup.Group
+	group.SetLimit(maxConcurrentLookups)
+	for i, t := range tools {
+		i, t := i, t
+		group.Go(func() error {
+			version, err := t.LatestVersion()
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to determine latest version for '%s': %w", t.Name(), err)
+				return nil
+			}
+			versions[i] = version
+			return nil
+		})
+	}
+	_ = group.Wait()
+	return versions, errs
+}
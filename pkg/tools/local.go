@@ -0,0 +1,26 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+)
+
+// localInstaller is implemented by tools able to install an asset from the local filesystem in place of downloading
+// their latest version
+type localInstaller interface {
+	UseLocalFile(file base.LocalFile) error
+}
+
+// UseLocalFile selects the local file installed by the provided tool in place of its latest version
+func UseLocalFile(tool Tool, file base.LocalFile) error {
+	t, ok := tool.(localInstaller)
+	if !ok {
+		return fmt.Errorf("%s does not support installing from a local file", tool.Name())
+	}
+	err := file.Validate()
+	if err != nil {
+		return err
+	}
+	return t.UseLocalFile(file)
+}
@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// semanticVersioner is implemented by tools reporting whether their major versions indicate breaking changes.
+// Tools which don't implement it are assumed to do so
+type semanticVersioner interface {
+	SemanticVersioning() bool
+}
+
+// versionTargeter is implemented by tools able to install a specific version in place of their latest version
+type versionTargeter interface {
+	TargetVersion(version string)
+}
+
+// CrossesMajor returns true if upgrading the provided tool from the installed version to the latest version crosses
+// a major version boundary, and so may introduce breaking changes
+func CrossesMajor(tool Tool, installedVersion, latestVersion string) bool {
+	if v, ok := tool.(semanticVersioner); ok && !v.SemanticVersioning() {
+		return false
+	}
+	if !utils.IsVersion(installedVersion) || !utils.IsVersion(latestVersion) {
+		return false
+	}
+	return utils.MajorVersion(installedVersion) != utils.MajorVersion(latestVersion)
+}
+
+// NewestWithinMajor returns the newest published release of the provided tool sharing the installed version's
+// major version, if it's newer than the installed version. found is false if there is no such release, or if the
+// tool is unable to install a specific version
+func NewestWithinMajor(tool Tool, installedVersion string) (version string, found bool, err error) {
+	if _, ok := tool.(versionTargeter); !ok {
+		return "", false, nil
+	}
+	versions, listed, err := ListVersions(tool)
+	if err != nil || !listed {
+		return "", false, err
+	}
+	major := utils.MajorVersion(installedVersion)
+	for _, v := range versions {
+		if utils.IsPrerelease(v) || utils.MajorVersion(v) != major {
+			continue
+		}
+		if utils.CompareVersions(v, installedVersion) > 0 && (version == "" || utils.CompareVersions(v, version) > 0) {
+			version = v
+		}
+	}
+	return version, version != "", nil
+}
+
+// TargetVersion selects the version installed by the provided tool in place of its latest version
+func TargetVersion(tool Tool, version string) error {
+	t, ok := tool.(versionTargeter)
+	if !ok {
+		return fmt.Errorf("%s does not support installing a specific version", tool.Name())
+	}
+	t.TargetVersion(version)
+	return nil
+}
@@ -5,16 +5,24 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
-	"runtime"
 	"strings"
 
 	"github.com/openshift/backplane-tools/pkg/sources/openshift.com/mirror"
 	"github.com/openshift/backplane-tools/pkg/tools/base"
 	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/openshift/backplane-tools/pkg/verify"
 )
 
-// Tool implements the interface to manage the 'backplane-cli' binary
+// executableName is the name of the client's executable within the client archive
+const executableName = "oc"
 
+// DefaultChannel is the channel the 'oc' tool is installed from
+const DefaultChannel = "stable"
+
+// clientSmokeTest reports the client's version without contacting a cluster
+var clientSmokeTest = base.SmokeTest{Args: []string{"version", "--client"}}
+
+// Tool implements the interface to manage the 'oc' binary
 type Tool struct {
 	base.Mirror
 }
@@ -24,12 +32,44 @@ func New() *Tool {
 		Mirror: base.Mirror{
 			Default:  base.NewDefault("oc"),
 			Source:   mirror.NewSource(),
-			BaseSlug: fmt.Sprintf("/pub/openshift-v4/%s/clients/ocp/stable/", runtime.GOARCH),
+			BaseSlug: channelSlug(DefaultChannel),
+		},
+	}
+	// The client archive also provides kubectl
+	t.AddExecutables("kubectl")
+	t.SetDescription("The OpenShift CLI, including kubectl, from the stable channel")
+	t.SetSmokeTest(clientSmokeTest)
+	return t
+}
+
+// NewChannel creates a tool installing the client from the provided channel, such as 'candidate' or 'stable-4.15',
+// alongside the 'oc' tool. The tool is named by ChannelToolName, and its executable is linked under the same name.
+// kubectl is only provided by the 'oc' tool, so that channels don't replace each other's
+func NewChannel(channel string) *Tool {
+	name := ChannelToolName(channel)
+	t := &Tool{
+		Mirror: base.Mirror{
+			Default:  base.NewDefaultWithExecutable(name, name),
+			Source:   mirror.NewSource(),
+			BaseSlug: channelSlug(channel),
 		},
 	}
+	t.SetDescription(fmt.Sprintf("The OpenShift CLI from the %s channel", channel))
+	t.SetSmokeTest(clientSmokeTest)
 	return t
 }
 
+// ChannelToolName returns the name of the tool installing the client from the provided channel: 'oc-<channel>',
+// with the 'stable-' prefix of versioned stable channels omitted (ie - 'oc-candidate' and 'oc-4.15')
+func ChannelToolName(channel string) string {
+	return "oc-" + strings.TrimPrefix(channel, DefaultChannel+"-")
+}
+
+// channelSlug returns the mirror.openshift.com directory containing the latest client published to the channel
+func channelSlug(channel string) string {
+	return fmt.Sprintf("/pub/openshift-v4/%s/clients/ocp/%s/", base.ArchPlaceholder, channel)
+}
+
 func (t *Tool) Install() error {
 	version, err := t.LatestVersion()
 	if err != nil {
@@ -43,13 +83,13 @@ func (t *Tool) Install() error {
 	}
 
 	// Download client archive
-	clientArchiveName := fmt.Sprintf("openshift-client-%s-%s.tar.gz", runtime.GOOS, version)
-	if runtime.GOOS == "darwin" {
+	clientArchiveName := fmt.Sprintf("openshift-client-%s-%s.tar.gz", utils.TargetOS, version)
+	if utils.TargetOS == "darwin" {
 		// 'darwin' OSes are referred to as 'mac' in mirror.openshift.com
 		clientArchiveName = fmt.Sprintf("openshift-client-mac-%s.tar.gz", version)
 	}
 
-	clientArchiveSlug, err := url.JoinPath(t.BaseSlug, clientArchiveName)
+	clientArchiveSlug, err := url.JoinPath(t.Slug(), clientArchiveName)
 	if err != nil {
 		return fmt.Errorf("failed to build client URL: %w", err)
 	}
@@ -57,13 +97,9 @@ func (t *Tool) Install() error {
 	if err != nil {
 		return fmt.Errorf("failed to download client archive file %s: %w", clientArchiveSlug, err)
 	}
-	err = os.Chmod(clientArchiveFilePath, os.FileMode(0o755))
-	if err != nil {
-		return fmt.Errorf("failed to update file mode for %s: %w", clientArchiveFilePath, err)
-	}
 
 	// Download latest checksum file
-	checksumSlug, err := url.JoinPath(t.BaseSlug, "sha256sum.txt")
+	checksumSlug, err := url.JoinPath(t.Slug(), "sha256sum.txt")
 	if err != nil {
 		return fmt.Errorf("failed to build checksum URL: %w", err)
 	}
@@ -73,25 +109,29 @@ func (t *Tool) Install() error {
 		return fmt.Errorf("failed to download checksum file %s: %w", checksumSlug, err)
 	}
 
-	checksum, err := t.extractChecksumFromFile(checksumFilePath, clientArchiveName)
+	// Verify the checksum file was signed by Red Hat, then checksum client archive & compare
+	checksumName := fmt.Sprintf("%s, signed", filepath.Base(checksumFilePath))
+	verification, err := t.ApplyChecksumPolicy(clientArchiveFilePath, checksumName, func() error {
+		err := t.VerifyChecksumSignature(checksumSlug, checksumFilePath)
+		if err != nil {
+			return err
+		}
+		return verify.File(clientArchiveFilePath, checksumFilePath, verify.FormatAuto)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to retrieve checksum from file %s: %w", checksumFilePath, err)
+		sourceURL, urlErr := t.Source.BuildURL(clientArchiveSlug)
+		if urlErr != nil {
+			fmt.Fprintf(os.Stderr, "failed to construct source URL for manual retrieval: %v\n", urlErr)
+			return fmt.Errorf("failed to verify '%s': %w. Please retry installation", clientArchiveFilePath, err)
+		}
+		return fmt.Errorf("failed to verify '%s': %w. Please retry installation. If issue persists, this tool can be downloaded manually at %s", clientArchiveFilePath, err, sourceURL)
 	}
 
-	// Checksum client archive & compare
-	archiveSum, err := utils.Sha256sum(clientArchiveFilePath)
+	sourceURL, err := t.Source.BuildURL(clientArchiveSlug)
 	if err != nil {
-		return fmt.Errorf("failed to calculate checksum for '%s': %w", clientArchiveFilePath, err)
-	}
-
-	if strings.TrimSpace(archiveSum) != strings.TrimSpace(checksum) {
-		sourceURL, err := t.Source.BuildURL(clientArchiveSlug)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "failed to construct source URL for manual retrieval: %v\n", err)
-			return fmt.Errorf("checksum for %s does not match the calculated value: expected '%s', got '%s'. Please retry installation", clientArchiveFilePath, strings.TrimSpace(checksum), strings.TrimSpace(archiveSum))
-		}
-		return fmt.Errorf("checksum for %s does not match the calculated value: expected '%s', got '%s'. Please retry installation. If issue persists, this tool can be downloaded manually at %s", clientArchiveFilePath, strings.TrimSpace(checksum), strings.TrimSpace(archiveSum), sourceURL)
+		return fmt.Errorf("failed to construct source URL: %w", err)
 	}
+	t.RecordArtifact(version, clientArchiveFilePath, sourceURL, verification)
 
 	// Unarchive client
 	err = utils.Unarchive(clientArchiveFilePath, versionedDir)
@@ -100,30 +140,9 @@ func (t *Tool) Install() error {
 	}
 
 	// Link as latest
-	latestFilePath := t.SymlinkPath()
-	err = os.Remove(latestFilePath)
-	if err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to remove existing symlink at '%s': %w", latestFilePath, err)
-	}
-
-	clientBinaryFilepath := filepath.Join(versionedDir, t.Name())
-	err = os.Symlink(clientBinaryFilepath, latestFilePath)
-	if err != nil {
-		return fmt.Errorf("failed to link %s to %s: %w", clientBinaryFilepath, latestFilePath, err)
-	}
-
-	return nil
-}
-
-func (t *Tool) extractChecksumFromFile(checksumFile, searchPattern string) (string, error) {
-	line, err := utils.GetLineInFileMatchingKey(checksumFile, searchPattern)
+	clientBinaryFilepath, err := base.FindBinary(versionedDir, executableName)
 	if err != nil {
-		return "", err
-	}
-
-	tokens := strings.Fields(line)
-	if len(tokens) != 2 {
-		return "", fmt.Errorf("failed to parse checksum info: expected 2 tokens, got %d.\nChecksum info retrieved:\n%s", len(tokens), line)
+		return err
 	}
-	return tokens[0], nil
+	return t.LinkExecutable(clientBinaryFilepath)
 }
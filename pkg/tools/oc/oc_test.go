@@ -0,0 +1,127 @@
+package oc_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/openshift/backplane-tools/pkg/tools/oc"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/openshift/backplane-tools/pkg/utils/test"
+)
+
+// version is the version of the client published by newMirror
+const version = "4.15.3"
+
+// newMirror starts a MirrorEnv publishing version of the client to the 'stable' channel, and returns it along with an
+// 'oc' tool installing from it
+func newMirror(t *testing.T) (*test.MirrorEnv, *oc.Tool) {
+	t.Helper()
+	env, err := test.NewMirrorEnv()
+	if err != nil {
+		t.Fatalf("failed to create mirror environment: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = env.Close()
+	})
+
+	slug := "/pub/openshift-v4/" + utils.TargetArch + "/clients/ocp/stable/"
+	env.SetVersion(slug, version)
+	archiveName := "openshift-client-" + utils.TargetOS + "-" + version + ".tar.gz"
+	if utils.TargetOS == "darwin" {
+		archiveName = "openshift-client-mac-" + version + ".tar.gz"
+	}
+	executable := []byte("#!/bin/sh\necho 'Client Version: " + version + "'\n")
+	err = env.AddArchive(slug, archiveName, map[string][]byte{"oc": executable, "kubectl": executable})
+	if err != nil {
+		t.Fatalf("failed to publish client archive: %v", err)
+	}
+
+	tool := oc.New()
+	env.Use(&tool.Mirror)
+	return env, tool
+}
+
+func TestLatestVersion(t *testing.T) {
+	_, tool := newMirror(t)
+	latest, err := tool.LatestVersion()
+	if err != nil {
+		t.Fatalf("failed to retrieve latest version: %v", err)
+	}
+	if latest != version {
+		t.Errorf("expected latest version '%s', got '%s'", version, latest)
+	}
+}
+
+func TestInstall(t *testing.T) {
+	_, tool := newMirror(t)
+	err := tool.Install()
+	if err != nil {
+		t.Fatalf("failed to install: %v", err)
+	}
+
+	installed, err := tool.InstalledVersion()
+	if err != nil {
+		t.Fatalf("failed to determine installed version: %v", err)
+	}
+	if installed != version {
+		t.Errorf("expected version '%s' to be installed, got '%s'", version, installed)
+	}
+	// The client archive also provides kubectl
+	for _, executable := range []string{"oc", "kubectl"} {
+		target, err := os.Readlink(filepath.Join(base.LatestDir, executable))
+		if err != nil {
+			t.Errorf("expected '%s' to be linked: %v", executable, err)
+			continue
+		}
+		if !strings.HasPrefix(target, filepath.Join(tool.ToolDir(), version)) {
+			t.Errorf("expected '%s' to link into version '%s', got '%s'", executable, version, target)
+		}
+	}
+}
+
+func TestInstallRejectsUnverifiedChecksums(t *testing.T) {
+	tests := []struct {
+		name        string
+		signatures  test.Signatures
+		expectedErr string
+	}{
+		{name: "bad signature", signatures: test.SignaturesInvalid, expectedErr: "failed to verify checksum file"},
+		{name: "missing signature", signatures: test.SignaturesMissing, expectedErr: "failed to download signature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, tool := newMirror(t)
+			env.SetSignatures(tt.signatures)
+			err := tool.Install()
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("expected error containing '%s', got '%v'", tt.expectedErr, err)
+			}
+			_, err = os.Lstat(filepath.Join(base.LatestDir, "oc"))
+			if !os.IsNotExist(err) {
+				t.Errorf("expected 'oc' not to be linked after a failed installation")
+			}
+		})
+	}
+}
+
+func TestConformance(t *testing.T) {
+	env, tool := newMirror(t)
+	h, err := test.NewHarness()
+	if err != nil {
+		t.Fatalf("failed to create harness: %v", err)
+	}
+	defer func() {
+		_ = h.Close()
+	}()
+	h.Route("127.0.0.1", env.Server.Config.Handler)
+
+	violations := test.Conformance(h, tool, func() {
+		env.SetSignatures(test.SignaturesMissing)
+	})
+	for _, violation := range violations {
+		t.Error(violation)
+	}
+}
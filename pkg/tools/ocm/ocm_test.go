@@ -0,0 +1,28 @@
+package ocm_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/openshift/backplane-tools/pkg/tools/ocm"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/openshift/backplane-tools/pkg/utils/test"
+)
+
+func TestConformance(t *testing.T) {
+	asset := fmt.Sprintf("ocm-%s-%s", utils.TargetOS, utils.TargetArch)
+	executable := map[string][]byte{asset: []byte("#!/bin/sh\necho '1.0.0'\n")}
+	// ocm publishes a checksum file for each asset
+	assets := map[string][]byte{
+		asset:             executable[asset],
+		asset + ".sha256": test.SHA256Sums(executable),
+	}
+
+	violations, err := test.GithubConformance(ocm.New(), "openshift-online", "ocm-cli", "v1.0.0", assets, nil)
+	if err != nil {
+		t.Fatalf("failed to run conformance test: %v", err)
+	}
+	for _, violation := range violations {
+		t.Error(violation)
+	}
+}
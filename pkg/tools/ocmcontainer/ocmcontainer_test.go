@@ -0,0 +1,27 @@
+package ocmcontainer_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/openshift/backplane-tools/pkg/tools/ocmcontainer"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/openshift/backplane-tools/pkg/utils/test"
+)
+
+func TestConformance(t *testing.T) {
+	archive, err := test.TarGz(map[string][]byte{"ocm-container": []byte("#!/bin/sh\necho 'version 1.0.0'\n")})
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	assets := map[string][]byte{fmt.Sprintf("ocm-container_1.0.0_%s_%s.tar.gz", utils.TargetOS, utils.TargetArch): archive}
+	assets["sha256sum.txt"] = test.SHA256Sums(assets)
+
+	violations, err := test.GithubConformance(ocmcontainer.New(), "openshift", "ocm-container", "v1.0.0", assets, nil)
+	if err != nil {
+		t.Fatalf("failed to run conformance test: %v", err)
+	}
+	for _, violation := range violations {
+		t.Error(violation)
+	}
+}
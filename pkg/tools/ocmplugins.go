@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+)
+
+// ocmPluginPrefix begins the name of every ocm plugin's executable. ocm discovers plugins by searching the $PATH for
+// executables named 'ocm-<plugin>', and runs them when invoked as 'ocm <plugin>'
+const ocmPluginPrefix = "ocm-"
+
+// ocmPlugin is implemented by tools whose executable is an ocm plugin
+type ocmPlugin interface {
+	// OCMPlugin returns the name ocm invokes the plugin by
+	OCMPlugin() string
+}
+
+// OCMPluginStatus describes whether a tool installed as an ocm plugin can be invoked through ocm
+type OCMPluginStatus struct {
+	// Tool is the name of the tool providing the plugin
+	Tool string `json:"tool"`
+
+	// Plugin is the name ocm invokes the plugin by
+	Plugin string `json:"plugin"`
+
+	// Path is the location the plugin is published at within the latest directory
+	Path string `json:"path"`
+
+	// Installed is true if the tool providing the plugin is installed
+	Installed bool `json:"installed"`
+
+	// Problems describes each issue preventing ocm from invoking the plugin. Empty if the plugin is healthy
+	Problems []string `json:"problems,omitempty"`
+}
+
+// Healthy returns true if ocm is able to invoke the plugin
+func (s OCMPluginStatus) Healthy() bool {
+	return s.Installed && len(s.Problems) == 0
+}
+
+// OCMPlugins returns the tools whose executable is an ocm plugin, sorted by name
+func OCMPlugins() []Tool {
+	plugins := []Tool{}
+	for _, tool := range GetMap() {
+		if _, ok := tool.(ocmPlugin); ok {
+			plugins = append(plugins, tool)
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name() < plugins[j].Name()
+	})
+	return plugins
+}
+
+// CheckOCMPlugins returns the status of each tool whose executable is an ocm plugin
+func CheckOCMPlugins() ([]OCMPluginStatus, error) {
+	ocmInstalled := false
+	if ocm, found := GetMap()["ocm"]; found {
+		var err error
+		ocmInstalled, err = ocm.Installed()
+		if err != nil {
+			return []OCMPluginStatus{}, fmt.Errorf("failed to determine if 'ocm' has been installed: %w", err)
+		}
+	}
+
+	statuses := []OCMPluginStatus{}
+	for _, tool := range OCMPlugins() {
+		status, err := checkOCMPlugin(tool, ocmInstalled)
+		if err != nil {
+			return statuses, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// checkOCMPlugin returns the status of the provided plugin. A plugin is healthy if ocm is installed, and the plugin is
+// published under its expected name in the latest directory, refers to the plugin's installed executable, and is the
+// first executable of that name in the $PATH
+func checkOCMPlugin(tool Tool, ocmInstalled bool) (OCMPluginStatus, error) {
+	plugin := tool.(ocmPlugin).OCMPlugin()
+	status := OCMPluginStatus{
+		Tool:     tool.Name(),
+		Plugin:   plugin,
+		Path:     filepath.Join(base.LatestDir, ocmPluginPrefix+plugin),
+		Problems: []string{},
+	}
+	installed, err := tool.Installed()
+	if err != nil {
+		return status, fmt.Errorf("failed to determine if '%s' has been installed: %w", tool.Name(), err)
+	}
+	status.Installed = installed
+	if !installed {
+		return status, nil
+	}
+
+	if !ocmInstalled {
+		status.Problems = append(status.Problems, "ocm is not installed: run 'backplane-tools install ocm'")
+	}
+	if tool.ExecutableName() != ocmPluginPrefix+plugin && !hasLinkPath(tool, status.Path) {
+		status.Problems = append(status.Problems, fmt.Sprintf("the executable is published as '%s', which ocm does not recognize as the '%s' plugin", tool.ExecutableName(), plugin))
+	}
+
+	target, err := base.ResolveLink(status.Path)
+	if err != nil {
+		status.Problems = append(status.Problems, fmt.Sprintf("'%s' is missing or broken: run 'backplane-tools repair'", status.Path))
+		return status, nil
+	}
+	toolDir := filepath.Join(base.InstallDir, tool.Name())
+	if !strings.HasPrefix(target, toolDir+string(os.PathSeparator)) {
+		status.Problems = append(status.Problems, fmt.Sprintf("'%s' refers to '%s', which was not installed by backplane-tools for %s", status.Path, target, tool.Name()))
+	}
+	info, err := os.Stat(target)
+	if err != nil || info.Mode().Perm()&0o111 == 0 {
+		status.Problems = append(status.Problems, fmt.Sprintf("'%s' is not executable: run 'backplane-tools repair'", target))
+	}
+
+	found, err := exec.LookPath(ocmPluginPrefix + plugin)
+	switch {
+	case err != nil:
+		status.Problems = append(status.Problems, fmt.Sprintf("'%s' is not in $PATH, so ocm can't discover the plugin", base.LatestDir))
+	case !sameFile(found, status.Path):
+		status.Problems = append(status.Problems, fmt.Sprintf("'%s' appears earlier in $PATH, and is run by ocm instead", found))
+	}
+	return status, nil
+}
+
+// hasLinkPath returns true if the provided tool publishes a file at the given path within the latest directory
+func hasLinkPath(tool Tool, path string) bool {
+	l, ok := tool.(linker)
+	if !ok {
+		return false
+	}
+	for _, linkPath := range l.LinkPaths() {
+		if linkPath == path {
+			return true
+		}
+	}
+	return false
+}
+
+// sameFile returns true if both paths refer to the same file
+func sameFile(a, b string) bool {
+	aInfo, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bInfo, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(aInfo, bInfo)
+}
@@ -0,0 +1,27 @@
+package osdctl_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/openshift/backplane-tools/pkg/tools/osdctl"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/openshift/backplane-tools/pkg/utils/test"
+)
+
+func TestConformance(t *testing.T) {
+	archive, err := test.TarGz(map[string][]byte{"osdctl": []byte("#!/bin/sh\necho 'version 1.0.0'\n")})
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	assets := map[string][]byte{fmt.Sprintf("osdctl_1.0.0_%s_%s.tar.gz", utils.TargetOS, utils.TargetArch): archive}
+	assets["checksums.txt"] = test.SHA256Sums(assets)
+
+	violations, err := test.GithubConformance(osdctl.New(), "openshift", "osdctl", "v1.0.0", assets, nil)
+	if err != nil {
+		t.Fatalf("failed to run conformance test: %v", err)
+	}
+	for _, violation := range violations {
+		t.Error(violation)
+	}
+}
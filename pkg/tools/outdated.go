@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+)
+
+// RefreshLatestVersions retrieves the latest version of each installed tool from its source, and records them so
+// that outdated tools can later be reported without reaching any source. Tools whose latest version can't be
+// determined keep the version recorded for them previously, if any
+func RefreshLatestVersions() error {
+	installed, err := ListInstalled()
+	if err != nil {
+		return err
+	}
+	latest, err := base.ReadLatestVersions()
+	if err != nil {
+		return err
+	}
+
+	versions := map[string]string{}
+	latestVersions, errs := LatestVersions(installed)
+	for i, t := range installed {
+		version, err := latestVersions[i], errs[i]
+		if err != nil {
+			fmt.Printf("WARNING: %v\n", err)
+			previous, found := latest.Versions[t.Name()]
+			if found {
+				versions[t.Name()] = previous
+			}
+			continue
+		}
+		versions[t.Name()] = version
+	}
+	latest.Versions = versions
+	latest.CheckedAt = time.Now().UTC()
+	return base.WriteLatestVersions(latest)
+}
+
+// CachedOutdated returns the names of the installed tools whose installed version differs from the latest version
+// recorded for them, sorted by name, along with when the latest versions were checked. No source is reached: tools
+// without a recorded latest version aren't reported. Held tools aren't reported either, as they won't be upgraded
+func CachedOutdated() ([]string, time.Time, error) {
+	latest, err := base.ReadLatestVersions()
+	if err != nil {
+		return []string{}, time.Time{}, err
+	}
+
+	outdated := []string{}
+	for name, latestVersion := range latest.Versions {
+		t, found := GetMap()[name]
+		if !found {
+			continue
+		}
+		installed, err := t.Installed()
+		if err != nil || !installed {
+			continue
+		}
+		installedVersion, err := t.InstalledVersion()
+		if err != nil || installedVersion == latestVersion {
+			continue
+		}
+		held, err := base.Held(name)
+		if err != nil {
+			return []string{}, time.Time{}, fmt.Errorf("failed to determine if '%s' is held: %w", name, err)
+		}
+		if !held {
+			outdated = append(outdated, name)
+		}
+	}
+	sort.Strings(outdated)
+	return outdated, latest.CheckedAt, nil
+}
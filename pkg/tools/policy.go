@@ -0,0 +1,33 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/openshift/backplane-tools/pkg/policy"
+)
+
+// checkPolicy ensures the version of each provided tool which would be installed is approved by the user's
+// organization, and returns the names of those installed regardless because the policy was overridden. Nothing
+// should be installed if any tool isn't approved
+func checkPolicy(tools []Tool) (overridden map[string]bool, err error) {
+	overridden = map[string]bool{}
+	if !policy.Enabled() {
+		return overridden, nil
+	}
+	versions, errs := LatestVersions(tools)
+	policyErrs := []error{}
+	for i, tool := range tools {
+		if errs[i] != nil {
+			policyErrs = append(policyErrs, fmt.Errorf("failed to check %s against the policy: %w", tool.Name(), errs[i]))
+			continue
+		}
+		override, err := policy.Check(tool.Name(), versions[i])
+		if err != nil {
+			policyErrs = append(policyErrs, err)
+			continue
+		}
+		overridden[tool.Name()] = override
+	}
+	return overridden, errors.Join(policyErrs...)
+}
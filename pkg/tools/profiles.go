@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// ProfileCore contains the tools needed to work with OpenShift clusters
+	ProfileCore = "core"
+	// ProfileCloud adds the clients of the cloud providers clusters run on to ProfileCore
+	ProfileCloud = "cloud"
+	// ProfileAll contains every tool
+	ProfileAll = "all"
+)
+
+// ProfileNames lists the profiles tools may be installed by, from smallest to largest
+var ProfileNames = []string{ProfileCore, ProfileCloud, ProfileAll}
+
+// profileTools lists the names of the tools in each profile, other than ProfileAll
+var profileTools = map[string][]string{
+	ProfileCore:  {"backplane-tools", "oc", "ocm", "backplane-cli", "osdctl"},
+	ProfileCloud: {"backplane-tools", "oc", "ocm", "backplane-cli", "osdctl", "aws", "gcloud", "rosa"},
+}
+
+// Profile returns the tools in the named profile, sorted by name. Tools which aren't available - such as gcloud,
+// when it fails to initialize - are omitted
+func Profile(name string) ([]Tool, error) {
+	toolMap := GetMap()
+	if name == ProfileAll {
+		profile := []Tool{}
+		for _, tool := range toolMap {
+			profile = append(profile, tool)
+		}
+		sort.Slice(profile, func(i, j int) bool {
+			return profile[i].Name() < profile[j].Name()
+		})
+		return profile, nil
+	}
+	names, found := profileTools[name]
+	if !found {
+		return []Tool{}, fmt.Errorf("unknown profile '%s': must be one of '%s'", name, strings.Join(ProfileNames, "', '"))
+	}
+	profile := []Tool{}
+	for _, toolName := range names {
+		if tool, found := toolMap[toolName]; found {
+			profile = append(profile, tool)
+		}
+	}
+	sort.Slice(profile, func(i, j int) bool {
+		return profile[i].Name() < profile[j].Name()
+	})
+	return profile, nil
+}
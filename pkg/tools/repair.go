@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/openshift/backplane-tools/pkg/journal"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+)
+
+// Repair completes or rolls back every change to installed tools which was interrupted, such as by a crash or power
+// loss, and ensures every file in the latest directory refers to an installed executable. Interrupted installs and
+// upgrades are rolled back, while interrupted removals are completed. The actions taken are returned
+func Repair() ([]string, error) {
+	actions := []string{}
+	pending, err := base.PendingTransactions()
+	if err != nil {
+		return actions, err
+	}
+	for _, tx := range pending {
+		var txActions []string
+		switch tx.Operation {
+		case journal.OperationInstall:
+			txActions, err = base.RollBack(tx)
+			if err != nil {
+				return actions, fmt.Errorf("failed to roll back interrupted install of %s: %w", tx.Tool, err)
+			}
+			actions = append(actions, fmt.Sprintf("rolled back interrupted install of %s", tx.Tool))
+		case journal.OperationRemove:
+			err = completeRemoval(tx.Tool)
+			if err != nil {
+				return actions, fmt.Errorf("failed to complete interrupted removal of %s: %w", tx.Tool, err)
+			}
+			actions = append(actions, fmt.Sprintf("completed interrupted removal of %s", tx.Tool))
+		default:
+			return actions, fmt.Errorf("unsupported operation '%s' recorded for %s", tx.Operation, tx.Tool)
+		}
+		for _, action := range txActions {
+			actions = append(actions, "  "+action)
+		}
+		err = base.CommitTransaction(tx.ID)
+		if err != nil {
+			return actions, err
+		}
+	}
+
+	linkActions, err := base.RepairLinks()
+	actions = append(actions, linkActions...)
+	if err != nil {
+		return actions, fmt.Errorf("failed to repair links: %w", err)
+	}
+	return actions, nil
+}
+
+// completeRemoval removes the named tool. Tools which are no longer supported, such as uninstalled plugins, have
+// their directory removed directly
+func completeRemoval(name string) error {
+	tool, found := GetMap()[name]
+	if found {
+		return tool.Remove()
+	}
+	toolDir := filepath.Join(base.InstallDir, name)
+	err := os.RemoveAll(toolDir)
+	if err != nil {
+		return fmt.Errorf("failed to remove '%s': %w", toolDir, err)
+	}
+	return nil
+}
@@ -1,14 +1,29 @@
 package tools
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
-
+	"time"
+
+	"github.com/openshift/backplane-tools/pkg/catalog"
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/hooks"
+	"github.com/openshift/backplane-tools/pkg/journal"
+	"github.com/openshift/backplane-tools/pkg/plugin"
+	"github.com/openshift/backplane-tools/pkg/report"
+	"github.com/openshift/backplane-tools/pkg/sources"
+	"github.com/openshift/backplane-tools/pkg/telemetry"
 	"github.com/openshift/backplane-tools/pkg/tools/awscli"
 	"github.com/openshift/backplane-tools/pkg/tools/backplanecli"
 	"github.com/openshift/backplane-tools/pkg/tools/base"
 	"github.com/openshift/backplane-tools/pkg/tools/butane"
+	"github.com/openshift/backplane-tools/pkg/tools/crc"
 	"github.com/openshift/backplane-tools/pkg/tools/gcloud"
 	"github.com/openshift/backplane-tools/pkg/tools/oc"
 	"github.com/openshift/backplane-tools/pkg/tools/ocm"
@@ -34,7 +49,9 @@ type Tool interface {
 	// it to provided the latestDir
 	Install() error
 
-	// Configure currently unused
+	// Configure performs any setup the tool requires after installation, such as
+	// seeding configuration files. It's invoked after each successful install, and
+	// must be safe to run repeatedly without overwriting the user's existing settings
 	Configure() error
 
 	// Remove uninstalls the tool by deleting its tool-unique directory under
@@ -52,58 +69,152 @@ type Tool interface {
 	LatestVersion() (string, error)
 }
 
+// builtinMap holds the tools built into backplane-tools
+var builtinMap map[string]Tool
+
+// toolMap holds every tool: those built into backplane-tools, as well as those defined by the user's catalog and
+// provided by plugins. It's only populated when first needed, since loading the catalog and plugins makes network
+// requests and runs plugin executables, which must honor the user's configuration
 var toolMap map[string]Tool
 
-func initMap() {
-	toolMap = map[string]Tool{}
+func initBuiltinMap() {
+	builtinMap = map[string]Tool{}
 
 	// Self-management
 	selfTool := self.New()
-	toolMap[selfTool.Name()] = selfTool
+	builtinMap[selfTool.Name()] = selfTool
 
 	// 3rd party tools
 	awsTool := awscli.New()
-	toolMap[awsTool.Name()] = awsTool
+	builtinMap[awsTool.Name()] = awsTool
 
 	ocTool := oc.New()
-	toolMap[ocTool.Name()] = ocTool
+	builtinMap[ocTool.Name()] = ocTool
+	for _, channel := range config.Get().OC.Channels {
+		channelTool := oc.NewChannel(channel)
+		builtinMap[channelTool.Name()] = channelTool
+	}
 
 	ocmTool := ocm.New()
-	toolMap[ocmTool.Name()] = ocmTool
+	builtinMap[ocmTool.Name()] = ocmTool
 
 	ocmaddonsTool := ocmaddons.New()
-	toolMap[ocmaddonsTool.Name()] = ocmaddonsTool
+	builtinMap[ocmaddonsTool.Name()] = ocmaddonsTool
 
 	osdctlTool := osdctl.New()
-	toolMap[osdctlTool.Name()] = osdctlTool
+	builtinMap[osdctlTool.Name()] = osdctlTool
 
 	backplanecliTool := backplanecli.New()
-	toolMap[backplanecliTool.Name()] = backplanecliTool
+	builtinMap[backplanecliTool.Name()] = backplanecliTool
 
 	rosaTool := rosa.New()
-	toolMap[rosaTool.Name()] = rosaTool
+	builtinMap[rosaTool.Name()] = rosaTool
 
 	yqTool := yq.New()
-	toolMap[yqTool.Name()] = yqTool
+	builtinMap[yqTool.Name()] = yqTool
 
 	butaneTool := butane.New()
-	toolMap[butaneTool.Name()] = butaneTool
+	builtinMap[butaneTool.Name()] = butaneTool
+
+	crcTool := crc.New()
+	builtinMap[crcTool.Name()] = crcTool
 
 	gcloudTool, err := gcloud.New()
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "Encountered error while initializing the 'gcloud' tool: %v\n", err)
 		_, _ = fmt.Fprintln(os.Stderr, "Unable to install, upgrade, or remove 'gcloud' until the error is resolved")
 	} else {
-		toolMap[gcloudTool.Name()] = gcloudTool
+		builtinMap[gcloudTool.Name()] = gcloudTool
 	}
 
 	serviceloggerTool := servicelogger.New()
-	toolMap[serviceloggerTool.Name()] = serviceloggerTool
+	builtinMap[serviceloggerTool.Name()] = serviceloggerTool
 
 	ocmContainerTool := ocmcontainer.New()
-	toolMap[ocmContainerTool.Name()] = ocmContainerTool
+	builtinMap[ocmContainerTool.Name()] = ocmContainerTool
 }
 
+func initMap() {
+	// The built-in tools are created again, as those created before the user's configuration was applied - such as
+	// to list their names - may not honor it
+	initBuiltinMap()
+	toolMap = map[string]Tool{}
+	for name, tool := range builtinMap {
+		toolMap[name] = tool
+	}
+
+	// Tools defined by the user's catalog
+	catalogTools, err := catalog.Load(config.Get().Catalog)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Encountered error while loading the tool catalog: %v\n", err)
+	}
+	for _, catalogTool := range catalogTools {
+		_, found := toolMap[catalogTool.Name()]
+		if found {
+			_, _ = fmt.Fprintf(os.Stderr, "Ignoring catalog tool '%s': a tool of the same name is already provided by backplane-tools\n", catalogTool.Name())
+			continue
+		}
+		toolMap[catalogTool.Name()] = catalogTool
+	}
+
+	overrideSources()
+
+	// External plugins
+	for _, path := range plugin.Discover() {
+		pluginTool, err := plugin.New(path)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Encountered error while initializing plugin '%s': %v\n", path, err)
+			continue
+		}
+		_, found := toolMap[pluginTool.Name()]
+		if found {
+			_, _ = fmt.Fprintf(os.Stderr, "Ignoring plugin '%s': a tool named '%s' is already provided by backplane-tools\n", path, pluginTool.Name())
+			continue
+		}
+		toolMap[pluginTool.Name()] = pluginTool
+	}
+}
+
+// sourceOverrider is implemented by tools able to be retrieved from a different source than their usual one
+type sourceOverrider interface {
+	OverrideSource(override config.SourceOverride) error
+}
+
+// overrideSources replaces the sources of built-in tools with those configured by the user. Tools whose source
+// can't be overridden are reported, but remain available using their usual source
+func overrideSources() {
+	for name := range config.Get().Tools {
+		override, found := config.Get().SourceOverride(name)
+		if !found {
+			continue
+		}
+		tool, found := toolMap[name]
+		if !found {
+			_, _ = fmt.Fprintf(os.Stderr, "Ignoring the source configured for '%s': no such tool is provided by backplane-tools\n", name)
+			continue
+		}
+		o, ok := tool.(sourceOverrider)
+		if !ok {
+			_, _ = fmt.Fprintf(os.Stderr, "Ignoring the source configured for '%s': its source can't be overridden\n", name)
+			continue
+		}
+		err := o.OverrideSource(override)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Ignoring the source configured for '%s': %v\n", name, err)
+		}
+	}
+}
+
+// getBuiltinMap returns the tools built into backplane-tools, creating them if needed
+func getBuiltinMap() map[string]Tool {
+	if builtinMap == nil {
+		initBuiltinMap()
+	}
+	return builtinMap
+}
+
+// GetMap returns every tool, loading the user's catalog and plugins if needed. The user's configuration must be
+// applied beforehand
 func GetMap() map[string]Tool {
 	if toolMap == nil {
 		initMap()
@@ -111,30 +222,112 @@ func GetMap() map[string]Tool {
 	return toolMap
 }
 
+// Reload discards every tool's state - such as the latest version retrieved from its source, or a version it was
+// targeted at - so that long-running processes notice new releases. The tools are created again when next needed
+func Reload() {
+	builtinMap = nil
+	toolMap = nil
+}
+
+// Names returns the name of every tool, including those defined by the user's catalog and provided by plugins
 func Names() []string {
 	return utils.Keys(GetMap())
 }
 
-// Remove removes the provided tools from the installation directory
+// BuiltinNames returns the names of the tools built into backplane-tools. Unlike Names, it makes no network requests
+// and runs no plugins, so it's safe to use before the user's configuration has been applied, such as when building
+// commands
+func BuiltinNames() []string {
+	return utils.Keys(getBuiltinMap())
+}
+
+// ValidateArgs returns an error if any of the provided args doesn't name a supported tool or one of the extra args
+// the command accepts, such as 'all'. The error suggests the tools the arg was most likely a typo of
+func ValidateArgs(args []string, extra ...string) error {
+	valid := append(Names(), extra...)
+	for _, arg := range args {
+		if utils.Contains(valid, arg) {
+			continue
+		}
+		suggestions := utils.Suggest(arg, Names())
+		if len(suggestions) == 0 {
+			return fmt.Errorf("failed to locate '%s' in list of supported tools. Run 'backplane-tools list available' to see every supported tool", arg)
+		}
+		return fmt.Errorf("failed to locate '%s' in list of supported tools\n\nDid you mean this?\n\t%s", arg, strings.Join(suggestions, "\n\t"))
+	}
+	return nil
+}
+
+// Remove removes the provided tools from the installation directory. Each failure is reported as it occurs, and
+// the remaining tools are still removed: the errors are returned together once every tool has been attempted
 func Remove(tools []Tool) error {
+	errs := []error{}
+	for _, tool := range tools {
+		fmt.Println()
+		fmt.Println(utils.Info(fmt.Sprintf("Removing %s", tool.Name())))
+		err := removeTool(tool)
+		if err != nil {
+			fmt.Println(utils.Failure(fmt.Sprintf("Encountered error while removing %s: %v", tool.Name(), err)))
+			fmt.Println(utils.Warning("Skipping..."))
+			errs = append(errs, fmt.Errorf("failed to remove %s: %w", tool.Name(), err))
+		} else {
+			fmt.Println(utils.Success(fmt.Sprintf("Successfully removed %s", tool.Name())))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// removeTool removes the provided tool within a transaction, so that the removal is completed by 'repair' if
+// it's interrupted
+func removeTool(tool Tool) error {
+	// Tools which aren't installed have nothing to interrupt: removing them only cleans up stray links
+	installed, err := tool.Installed()
+	if err != nil || !installed {
+		return tool.Remove()
+	}
+	id, err := base.BeginTransaction(tool.Name(), journal.OperationRemove)
+	if err != nil {
+		return err
+	}
+	err = tool.Remove()
+	if err != nil {
+		return err
+	}
+	return base.CommitTransaction(id)
+}
+
+// Configure runs the configuration phase for the provided tools
+func Configure(tools []Tool) error {
 	for _, tool := range tools {
 		fmt.Println()
-		fmt.Printf("Removing %s\n", tool.Name())
-		err := tool.Remove()
+		fmt.Println(utils.Info(fmt.Sprintf("Configuring %s", tool.Name())))
+		err := tool.Configure()
 		if err != nil {
-			fmt.Printf("Encountered error while removing %s: %v\n", tool.Name(), err)
-			fmt.Println("Skipping...")
+			fmt.Println(utils.Failure(fmt.Sprintf("Encountered error while configuring %s: %v", tool.Name(), err)))
+			fmt.Println(utils.Warning("Skipping..."))
 		} else {
-			fmt.Printf("Successfully removed %s\n", tool.Name())
+			fmt.Println(utils.Success(fmt.Sprintf("Successfully configured %s", tool.Name())))
 		}
 	}
 	return nil
 }
 
-// Install creates the directories necessary to install the provided tools and
+// Install creates the directories necessary to install the provided tools and installs them, such that each tool
+// is installed after the tools it depends upon. Nothing is installed if any tool's version isn't approved by the
+// user's organization. A tool failing to install doesn't prevent the others from being installed: the errors are
+// returned together once every tool has been attempted
 func Install(tools []Tool) error {
+	tools, err := Order(tools)
+	if err != nil {
+		return err
+	}
+	overridden, err := checkPolicy(tools)
+	if err != nil {
+		return err
+	}
+
 	// Create the root directory for all tools to install into
-	err := createInstallDir()
+	err = createInstallDir()
 	if err != nil {
 		return fmt.Errorf("failed to create installation directory: %w", err)
 	}
@@ -145,33 +338,263 @@ func Install(tools []Tool) error {
 		return fmt.Errorf("failed to create latest directory: %w", err)
 	}
 
+	// Create the directory which holds the records kept about installed tools
+	err = createStateDir()
+	if err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	run := report.Report{StartedAt: time.Now().UTC()}
+	startBytes := utils.DownloadedBytes()
+	events := []telemetry.Event{}
+	errs := []error{}
 	for _, tool := range tools {
-		fmt.Println()
-		fmt.Printf("Installing %s\n", tool.Name())
-		err = tool.Install()
+		result, event, err := installTool(tool)
 		if err != nil {
-			fmt.Printf("Encountered error while installing %s: %v\n", tool.Name(), err)
-			fmt.Println("Skipping...")
-		} else {
-			fmt.Printf("Successfully installed %s\n", tool.Name())
+			errs = append(errs, fmt.Errorf("failed to install %s: %w", tool.Name(), err))
 		}
+		result.Unapproved = overridden[tool.Name()]
+		run.Results = append(run.Results, result)
+		events = append(events, event)
 	}
+	run.FinishedAt = time.Now().UTC()
+	run.DurationMS = run.FinishedAt.Sub(run.StartedAt).Milliseconds()
+	run.BytesDownloaded = utils.DownloadedBytes() - startBytes
+
+	err = report.Write(base.ReportPath, run)
+	if err != nil {
+		fmt.Printf("WARNING: %v\n", err)
+	}
+	reportTelemetry(events)
 
 	// Check $PATH for the latest binaries
 	userPath, found := os.LookupEnv("PATH")
 	if !found {
 		fmt.Println()
 		fmt.Printf("WARNING: Couldn't determine current $PATH: it's recommended '%s' is added to your $PATH to utilize the tools provided by this application", base.LatestDir)
-		return nil
+		return errors.Join(errs...)
 	}
 	userPaths := strings.Split(userPath, string(os.PathListSeparator))
 	if !utils.Contains(userPaths, base.LatestDir) {
 		fmt.Println()
 		fmt.Printf("WARNING: Detected that '%s' is not present in $PATH: it's recommended '%s' is added to your $PATH to utilize the tools provided by this application\n", base.LatestDir, base.LatestDir)
 	}
+	warnShadows(tools, userPaths)
+	return errors.Join(errs...)
+}
+
+// warnShadows warns about executables earlier in the $PATH than the 'latest' directory, which would be run
+// instead of the provided tools
+func warnShadows(tools []Tool, userPaths []string) {
+	for _, tool := range tools {
+		shadows := base.Shadows(tool.ExecutableName(), userPaths)
+		if len(shadows) == 0 {
+			continue
+		}
+		fmt.Println()
+		fmt.Printf("WARNING: '%s' is shadowed by other executables earlier in your $PATH, which will be run instead of the version installed by this application:\n", tool.ExecutableName())
+		for _, shadow := range shadows {
+			version := shadow.Version
+			if version == "" {
+				version = "unknown version"
+			}
+			fmt.Printf("  %s (%s)\n", shadow.Path, version)
+		}
+		fmt.Printf("Remove these executables, or move '%s' earlier in your $PATH\n", base.LatestDir)
+	}
+}
+
+// installTool installs and configures the provided tool, running any hooks defined for it, and returns the
+// outcome of the installation, along with the error it failed with, if any
+func installTool(tool Tool) (report.Result, telemetry.Event, error) {
+	fmt.Println()
+	fmt.Println(utils.Info(fmt.Sprintf("Installing %s", tool.Name())))
+	start := time.Now()
+	startBytes := utils.DownloadedBytes()
+	result := report.Result{Tool: tool.Name()}
+	installed, err := tool.Installed()
+	if err == nil && installed {
+		result.VersionBefore, _ = tool.InstalledVersion()
+	}
+
+	if c, ok := tool.(cleaner); ok {
+		_, err = c.CleanOrphans()
+		if err != nil {
+			fmt.Printf("WARNING: failed to clean up after previous installs of %s: %v\n", tool.Name(), err)
+		}
+	}
+
+	toolHooks := config.Get().ToolHooks(tool.Name())
+	hookCtx := hooks.Context{Tool: tool.Name(), OldVersion: result.VersionBefore}
+	if len(toolHooks.PreInstall) > 0 || len(toolHooks.PostInstall) > 0 {
+		hookCtx.NewVersion, _ = tool.LatestVersion()
+	}
+	txID, err := base.BeginTransaction(tool.Name(), journal.OperationInstall)
+	if err == nil {
+		err = hooks.Run(hooks.PreInstall, toolHooks.PreInstall, hookCtx)
+	}
+	if err == nil {
+		err = tool.Install()
+	}
+	if err == nil {
+		err = VerifyCodeSignatures(tool)
+	}
+	if err == nil && config.Get().SmokeTestEnabled(tool.Name()) {
+		err = SmokeTest(tool)
+	}
+	if txID != "" {
+		finishTransaction(txID, err)
+	}
+
+	duration := time.Since(start)
+	result.VersionAfter, _ = tool.LatestVersion()
+	result.Action = report.NewAction(result.VersionBefore, result.VersionAfter)
+	result.DurationMS = duration.Milliseconds()
+	result.BytesDownloaded = utils.DownloadedBytes() - startBytes
+	result.Success = err == nil
+	event := telemetry.NewEvent(tool.Name(), result.VersionAfter, duration, err)
+	if err != nil {
+		result.Error = err.Error()
+		fmt.Println(utils.Failure(fmt.Sprintf("Encountered error while installing %s: %v", tool.Name(), err)))
+		fmt.Println(utils.Warning("Skipping..."))
+		return result, event, err
+	}
+	fmt.Println(utils.Success(describeSuccess(result)))
+	if missing := MissingRequirements(tool); len(missing) > 0 {
+		fmt.Printf("WARNING: %s relies on '%s', which could not be found in your $PATH. Install it using your system's package manager\n", tool.Name(), strings.Join(missing, "', '"))
+	}
+
+	err = hooks.Run(hooks.PostInstall, toolHooks.PostInstall, hookCtx)
+	if err != nil {
+		fmt.Printf("WARNING: %v\n", err)
+	}
+
+	err = tool.Configure()
+	if err != nil {
+		fmt.Printf("WARNING: failed to configure %s: %v. Run 'backplane-tools configure %s' to retry\n", tool.Name(), err, tool.Name())
+	}
+	return result, event, nil
+}
+
+// smokeTester is implemented by tools describing how their executable is smoke tested
+type smokeTester interface {
+	SmokeTest() base.SmokeTest
+}
+
+// SmokeTest runs the provided tool's installed executable with a harmless command, such as '--version', verifying
+// that it executes and reports the version just installed. Tools installed for another platform can't be run, and
+// are not tested
+func SmokeTest(tool Tool) error {
+	if utils.TargetOS != runtime.GOOS || utils.TargetArch != runtime.GOARCH {
+		return nil
+	}
+	version, err := tool.LatestVersion()
+	if err != nil {
+		return fmt.Errorf("failed to determine the installed version of %s: %w", tool.Name(), err)
+	}
+	err = base.RunSmokeTest(filepath.Join(base.LatestDir, tool.ExecutableName()), version, smokeTestOf(tool))
+	if err != nil {
+		return fmt.Errorf("smoke test failed: %w", err)
+	}
 	return nil
 }
 
+// ReportedVersion runs the provided tool's installed executable, and returns the version it reports itself as.
+// found is false for tools with no way of reporting their version
+func ReportedVersion(tool Tool) (version string, found bool, err error) {
+	return base.ReportedVersion(filepath.Join(base.LatestDir, tool.ExecutableName()), smokeTestOf(tool))
+}
+
+// smokeTestOf returns the command run to smoke test the provided tool's executable
+func smokeTestOf(tool Tool) base.SmokeTest {
+	test := base.SmokeTest{}
+	if s, ok := tool.(smokeTester); ok {
+		test = s.SmokeTest()
+	}
+	if len(test.Args) == 0 {
+		test.Args = base.DefaultSmokeTestArgs
+	}
+	return test
+}
+
+// describeSuccess describes the outcome of a successful installation
+func describeSuccess(result report.Result) string {
+	switch result.Action {
+	case report.ActionUpgrade:
+		return fmt.Sprintf("Successfully upgraded %s from %s to %s", result.Tool, result.VersionBefore, result.VersionAfter)
+	case report.ActionReinstall:
+		return fmt.Sprintf("Successfully reinstalled %s", result.Tool)
+	default:
+		return fmt.Sprintf("Successfully installed %s", result.Tool)
+	}
+}
+
+// finishTransaction commits the identified install transaction. If the install failed, any partially installed
+// version is rolled back first, so that the tool is left as it was before the install began
+func finishTransaction(id string, installErr error) {
+	if installErr != nil {
+		pending, err := base.PendingTransactions()
+		if err != nil {
+			fmt.Printf("WARNING: failed to roll back incomplete install: %v\n", err)
+		}
+		for _, tx := range pending {
+			if tx.ID != id {
+				continue
+			}
+			_, err = base.RollBack(tx)
+			if err != nil {
+				fmt.Printf("WARNING: failed to roll back incomplete install: %v. Run 'backplane-tools repair' to retry\n", err)
+				return
+			}
+		}
+	}
+	err := base.CommitTransaction(id)
+	if err != nil {
+		fmt.Printf("WARNING: %v\n", err)
+	}
+}
+
+// reportTelemetry sends the provided events to the configured telemetry endpoint, if the user has opted in.
+// Events which can't be delivered are dropped with a warning
+func reportTelemetry(events []telemetry.Event) {
+	settings := config.Get().Telemetry
+	if !settings.Enabled {
+		return
+	}
+	err := telemetry.Send(settings.Endpoint, events)
+	if err != nil {
+		fmt.Printf("WARNING: failed to report telemetry: %v\n", err)
+	}
+}
+
+// adopter is implemented by tools capable of importing manually installed executables
+type adopter interface {
+	Adopt(path string) (string, error)
+}
+
+// Adopt imports the executable at the provided path into the managed layout as the installed version of the tool,
+// returning the synthetic version it was assigned
+func Adopt(tool Tool, path string) (string, error) {
+	a, ok := tool.(adopter)
+	if !ok {
+		return "", fmt.Errorf("%s does not support adopting existing executables", tool.Name())
+	}
+
+	err := createInstallDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to create installation directory: %w", err)
+	}
+	err = createLatestDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to create latest directory: %w", err)
+	}
+	err = createStateDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to create state directory: %w", err)
+	}
+	return a.Adopt(path)
+}
+
 func createInstallDir() error {
 	return os.MkdirAll(base.InstallDir, os.FileMode(0o755))
 }
@@ -180,8 +603,105 @@ func createLatestDir() error {
 	return os.MkdirAll(base.LatestDir, os.FileMode(0o755))
 }
 
+func createStateDir() error {
+	return os.MkdirAll(base.StateDir, os.FileMode(0o755))
+}
+
 func RemoveInstallDir() error {
-	return os.RemoveAll(base.InstallDir)
+	err := os.RemoveAll(base.InstallDir)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(base.StateDir)
+}
+
+// versionLister is implemented by tools able to enumerate every version published for them
+type versionLister interface {
+	ListVersions() ([]string, error)
+}
+
+// ListVersions returns every published version of the provided tool, from oldest to newest. found is false for
+// tools which cannot enumerate their versions
+func ListVersions(tool Tool) (versions []string, found bool, err error) {
+	lister, ok := tool.(versionLister)
+	if !ok {
+		return []string{}, false, nil
+	}
+	versions, err = lister.ListVersions()
+	return versions, true, err
+}
+
+// sourced is implemented by tools which are installed from a single source
+type sourced interface {
+	ToolSource() sources.Source
+}
+
+// SourceOf returns the source the provided tool is installed from. found is false for tools which
+// don't expose their source, such as plugins
+func SourceOf(tool Tool) (src sources.Source, found bool) {
+	s, ok := tool.(sourced)
+	if !ok {
+		return nil, false
+	}
+	return s.ToolSource(), true
+}
+
+// describer is implemented by tools which provide a brief description of what they do
+type describer interface {
+	Description() string
+}
+
+// DescriptionOf returns a brief description of what the provided tool does, or an empty string if it has none
+func DescriptionOf(tool Tool) string {
+	d, ok := tool.(describer)
+	if !ok {
+		return ""
+	}
+	return d.Description()
+}
+
+// Search returns the tools whose name, executable name, or description contains the provided term, ignoring case.
+// Results are sorted by name
+func Search(term string) []Tool {
+	term = strings.ToLower(term)
+	matches := []Tool{}
+	for _, tool := range GetMap() {
+		for _, field := range []string{tool.Name(), tool.ExecutableName(), DescriptionOf(tool)} {
+			if strings.Contains(strings.ToLower(field), term) {
+				matches = append(matches, tool)
+				break
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Name() < matches[j].Name()
+	})
+	return matches
+}
+
+// cleaner is implemented by tools able to remove the files left behind by previous failed installs
+type cleaner interface {
+	CleanOrphans() ([]string, error)
+}
+
+// linker is implemented by tools which publish more than one executable into the latest directory
+type linker interface {
+	LinkPaths() []string
+}
+
+// CheckIntegrity verifies the provided tool's executables have not been modified since they were installed
+func CheckIntegrity(tool Tool) error {
+	linkPaths := []string{filepath.Join(base.LatestDir, tool.ExecutableName())}
+	if l, ok := tool.(linker); ok {
+		linkPaths = l.LinkPaths()
+	}
+	for _, linkPath := range linkPaths {
+		err := base.CheckIntegrity(linkPath)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // ListInstalled returns a slice containing all tools the current machine has installed
@@ -200,3 +720,75 @@ func ListInstalled() ([]Tool, error) {
 	}
 	return installedTools, nil
 }
+
+// Exec runs the provided installed version of the tool with the given arguments, connected to the current
+// process' standard streams, and returns its exit code. If version is empty, the currently installed version is run.
+// The tool's link in the 'latest' directory is not modified
+func Exec(tool Tool, version string, args []string) (int, error) {
+	if version == "" {
+		var err error
+		version, err = tool.InstalledVersion()
+		if err != nil {
+			return 1, fmt.Errorf("failed to determine installed version of %s: %w", tool.Name(), err)
+		}
+	}
+	executablePath, err := base.VersionExecutable(tool.Name(), tool.ExecutableName(), version)
+	if err != nil {
+		return 1, err
+	}
+
+	cmd := exec.Command(executablePath, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode(), nil
+		}
+		return 1, fmt.Errorf("failed to run '%s': %w", executablePath, err)
+	}
+	return 0, nil
+}
+
+// signer is implemented by tools whose assets are verified using GPG signatures
+type signer interface {
+	SigningKeys() (pinned []string, found bool)
+}
+
+// SigningKeys returns the fingerprints of the keys backplane-tools pins to sign the provided tool's assets. found is
+// false if the tool's assets aren't verified using GPG signatures
+func SigningKeys(tool Tool) (pinned []string, found bool) {
+	s, ok := tool.(signer)
+	if !ok {
+		return []string{}, false
+	}
+	return s.SigningKeys()
+}
+
+// SignedTools returns the tools whose assets are verified using GPG signatures, sorted by name
+func SignedTools() []Tool {
+	return signedTools(GetMap())
+}
+
+// BuiltinSignedTools returns the built-in tools whose assets are verified using GPG signatures, sorted by name. Unlike
+// SignedTools, it's safe to call before the user's configuration has been applied
+func BuiltinSignedTools() []Tool {
+	return signedTools(getBuiltinMap())
+}
+
+// signedTools returns the tools in toolMap whose assets are verified using GPG signatures, sorted by name
+func signedTools(toolMap map[string]Tool) []Tool {
+	signed := []Tool{}
+	for _, t := range toolMap {
+		_, found := SigningKeys(t)
+		if found {
+			signed = append(signed, t)
+		}
+	}
+	sort.Slice(signed, func(i, j int) bool {
+		return signed[i].Name() < signed[j].Name()
+	})
+	return signed
+}
@@ -0,0 +1,24 @@
+package yq_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/openshift/backplane-tools/pkg/tools/yq"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/openshift/backplane-tools/pkg/utils/test"
+)
+
+func TestConformance(t *testing.T) {
+	asset := fmt.Sprintf("yq_%s_%s", utils.TargetOS, utils.TargetArch)
+	assets := map[string][]byte{asset: []byte("#!/bin/sh\necho 'yq version v4.40.5'\n")}
+	assets["checksums"] = test.SHA256Sums(assets)
+
+	violations, err := test.GithubConformance(yq.New(), "mikefarah", "yq", "v4.40.5", assets, nil)
+	if err != nil {
+		t.Fatalf("failed to run conformance test: %v", err)
+	}
+	for _, violation := range violations {
+		t.Error(violation)
+	}
+}
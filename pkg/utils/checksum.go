@@ -4,15 +4,24 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 )
 
-// Checksum reads the file at the provided path and calculates the sha256sum
+// Sha256sum calculates the sha256sum of the file at the provided path. The file is streamed through the hash rather
+// than read into memory, so that large archives can be checksummed
 func Sha256sum(filepath string) (string, error) {
-	fileBytes, err := os.ReadFile(filepath)
+	file, err := os.Open(filepath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file '%s' while generating sha256sum: %w", filepath, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
 	if err != nil {
 		return "", fmt.Errorf("failed to read file '%s' while generating sha256sum: %w", filepath, err)
 	}
-	sumBytes := sha256.Sum256(fileBytes)
-	return hex.EncodeToString(sumBytes[:]), nil
+	return hex.EncodeToString(hash.Sum(nil)), nil
 }
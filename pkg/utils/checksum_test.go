@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// syntheticData returns size bytes of pseudo-random data, generated from seed so that benchmarks are repeatable.
+// Half of each block repeats, so that the data compresses about as well as an executable
+func syntheticData(size int, seed int64) []byte {
+	data := make([]byte, size)
+	random := rand.New(rand.NewSource(seed))
+	for i := 0; i < size; i += 4096 {
+		end := i + 2048
+		if end > size {
+			end = size
+		}
+		_, _ = random.Read(data[i:end])
+	}
+	return data
+}
+
+func BenchmarkSha256sum(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "asset")
+	data := syntheticData(64<<20, 1)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		b.Fatalf("failed to write '%s': %v", path, err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Sha256sum(path); err != nil {
+			b.Fatalf("failed to checksum '%s': %v", path, err)
+		}
+	}
+}
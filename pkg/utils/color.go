@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"os"
+)
+
+// ANSI escape codes used to color output
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorBlue   = "\033[34m"
+)
+
+// colorEnabled determines whether output is colored. Color is enabled when stdout is a terminal, unless disabled
+// by the NO_COLOR environment variable (https://no-color.org) or a dumb terminal
+var colorEnabled = func() bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}()
+
+// DisableColor prevents output from being colored for the remainder of the process
+func DisableColor() {
+	colorEnabled = false
+}
+
+// colorize wraps the provided text in the given color, if color is enabled
+func colorize(color, text string) string {
+	if !colorEnabled {
+		return text
+	}
+	return color + text + colorReset
+}
+
+// Success colors text describing a successful outcome, such as a tool being installed
+func Success(text string) string {
+	return colorize(colorGreen, text)
+}
+
+// Failure colors text describing a failed outcome
+func Failure(text string) string {
+	return colorize(colorRed, text)
+}
+
+// Warning colors text describing a problem which did not prevent the operation from completing, or an operation
+// which was skipped
+func Warning(text string) string {
+	return colorize(colorYellow, text)
+}
+
+// Info colors text describing an operation about to be performed
+func Info(text string) string {
+	return colorize(colorBlue, text)
+}
@@ -0,0 +1,258 @@
+package utils
+
+import (
+	"archive/tar"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// cpioOdcMagic begins each header of a portable ASCII ('odc') cpio archive, as used by macOS installer payloads
+	cpioOdcMagic = "070707"
+	// cpioNewcMagic begins each header of a 'newc' cpio archive
+	cpioNewcMagic = "070701"
+	// cpioCrcMagic begins each header of a 'newc' cpio archive with checksums
+	cpioCrcMagic = "070702"
+	// cpioTrailer names the entry ending a cpio archive
+	cpioTrailer = "TRAILER!!!"
+
+	// cpioOdcHeaderSize is the size of an 'odc' header, including its magic
+	cpioOdcHeaderSize = 76
+	// cpioNewcHeaderSize is the size of a 'newc' header, including its magic
+	cpioNewcHeaderSize = 110
+
+	// cpio file type bits, as found in each entry's mode
+	cpioTypeMask    = 0o170000
+	cpioTypeDir     = 0o040000
+	cpioTypeReg     = 0o100000
+	cpioTypeSymlink = 0o120000
+)
+
+// cpioReader reads the entries of a cpio archive in either the 'odc' or 'newc' formats. Like tar.Reader, Next
+// advances to the next entry, whose contents are then returned by Read
+type cpioReader struct {
+	r *bufio.Reader
+
+	// remaining is the number of bytes of the current entry's contents which have not been read
+	remaining int64
+
+	// padding is the number of bytes following the current entry's contents which align the next header
+	padding int64
+}
+
+// newCpioReader returns a reader for the cpio archive read from r
+func newCpioReader(r io.Reader) *cpioReader {
+	return &cpioReader{r: bufio.NewReader(r)}
+}
+
+// Read reads the contents of the current entry
+func (c *cpioReader) Read(p []byte) (int, error) {
+	if c.remaining <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > c.remaining {
+		p = p[:c.remaining]
+	}
+	n, err := c.r.Read(p)
+	c.remaining -= int64(n)
+	if errors.Is(err, io.EOF) && c.remaining > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// Next advances to the next entry in the archive, returning its header. Symlink targets are returned as the header's
+// Linkname, rather than as the entry's contents. io.EOF is returned once the archive's trailer is reached
+func (c *cpioReader) Next() (*tar.Header, error) {
+	// Skip whatever remains of the previous entry
+	_, err := io.CopyN(io.Discard, c.r, c.remaining+c.padding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to skip cpio entry: %w", err)
+	}
+	c.remaining, c.padding = 0, 0
+
+	magic, err := c.r.Peek(len(cpioOdcMagic))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cpio header: %w", err)
+	}
+	var (
+		header      *tar.Header
+		nameSize    int64
+		namePadding int64
+	)
+	switch string(magic) {
+	case cpioOdcMagic:
+		header, nameSize, err = c.readOdcHeader()
+	case cpioNewcMagic, cpioCrcMagic:
+		header, nameSize, err = c.readNewcHeader()
+		namePadding = cpioAlignment(cpioNewcHeaderSize + nameSize)
+		c.padding = cpioAlignment(header.Size)
+	default:
+		return nil, fmt.Errorf("unsupported cpio header magic '%s'", magic)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	name := make([]byte, nameSize+namePadding)
+	_, err = io.ReadFull(c.r, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cpio entry name: %w", err)
+	}
+	header.Name = strings.TrimRight(string(name[:nameSize]), "\x00")
+	if header.Name == cpioTrailer {
+		return nil, io.EOF
+	}
+	c.remaining = header.Size
+
+	if header.Typeflag == tar.TypeSymlink {
+		target, err := io.ReadAll(c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read target of symlink '%s': %w", header.Name, err)
+		}
+		header.Linkname = string(target)
+	}
+	return header, nil
+}
+
+// readOdcHeader reads an 'odc' header, whose fields are octal numbers, returning it along with the size of the
+// entry's name
+func (c *cpioReader) readOdcHeader() (*tar.Header, int64, error) {
+	raw := make([]byte, cpioOdcHeaderSize)
+	_, err := io.ReadFull(c.r, raw)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read cpio header: %w", err)
+	}
+	// Each field's offset and width within the header, following the 6 byte magic
+	fields := map[string][2]int{
+		"ino":      {12, 6},
+		"mode":     {18, 6},
+		"nlink":    {36, 6},
+		"mtime":    {48, 11},
+		"namesize": {59, 6},
+		"filesize": {65, 11},
+	}
+	values := map[string]int64{}
+	for name, f := range fields {
+		values[name], err = strconv.ParseInt(string(raw[f[0]:f[0]+f[1]]), 8, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid cpio header field '%s': %w", name, err)
+		}
+	}
+	return cpioHeader(values), values["namesize"], nil
+}
+
+// readNewcHeader reads a 'newc' header, whose fields are hexadecimal numbers, returning it along with the size of
+// the entry's name
+func (c *cpioReader) readNewcHeader() (*tar.Header, int64, error) {
+	raw := make([]byte, cpioNewcHeaderSize)
+	_, err := io.ReadFull(c.r, raw)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read cpio header: %w", err)
+	}
+	// Each field is 8 characters wide, following the 6 byte magic
+	names := []string{"ino", "mode", "uid", "gid", "nlink", "mtime", "filesize", "devmajor", "devminor", "rdevmajor", "rdevminor", "namesize", "check"}
+	values := map[string]int64{}
+	for i, name := range names {
+		offset := len(cpioNewcMagic) + i*8
+		values[name], err = strconv.ParseInt(string(raw[offset:offset+8]), 16, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid cpio header field '%s': %w", name, err)
+		}
+	}
+	return cpioHeader(values), values["namesize"], nil
+}
+
+// cpioHeader converts the provided cpio header fields to a tar header, so that entries can be extracted like those
+// of a tarball
+func cpioHeader(values map[string]int64) *tar.Header {
+	mode := values["mode"]
+	header := &tar.Header{
+		Mode:    mode & 0o7777,
+		Size:    values["filesize"],
+		ModTime: time.Unix(values["mtime"], 0),
+	}
+	switch mode & cpioTypeMask {
+	case cpioTypeDir:
+		header.Typeflag = tar.TypeDir
+	case cpioTypeReg:
+		header.Typeflag = tar.TypeReg
+	case cpioTypeSymlink:
+		header.Typeflag = tar.TypeSymlink
+	default:
+		header.Typeflag = tar.TypeChar
+	}
+	return header
+}
+
+// cpioAlignment returns the number of bytes padding the provided size to the 4 byte alignment 'newc' archives use
+func cpioAlignment(size int64) int64 {
+	return (4 - size%4) % 4
+}
+
+// Uncpio extracts the contents of the cpio archive read from src to the specified destination. Archives in the
+// portable ASCII ('odc') and 'newc' formats are supported. Directories, regular files, and symlinks are extracted,
+// and each entry's permissions and modification time are preserved. The source is used to describe the archive in errors
+func Uncpio(source string, src io.Reader, destination string) error {
+	arc := newCpioReader(src)
+	dirs := []*tar.Header{}
+	for {
+		f, err := arc.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read from archive '%s': %w", source, err)
+		}
+		path, err := SafeJoin(destination, f.Name)
+		if err != nil {
+			return fmt.Errorf("refusing to extract archive '%s': %w", source, err)
+		}
+
+		if f.Typeflag == tar.TypeDir {
+			// Existing directories may be read-only from a previous extraction
+			err = os.MkdirAll(path, os.FileMode(0o755))
+			if err == nil {
+				err = os.Chmod(path, os.FileMode(0o755))
+			}
+			if err != nil {
+				return fmt.Errorf("failed to create a directory : %w", err)
+			}
+			dirs = append(dirs, f)
+			continue
+		}
+
+		err = os.MkdirAll(filepath.Dir(path), os.FileMode(0o755))
+		if err != nil {
+			return fmt.Errorf("failed to create a directory : %w", err)
+		}
+		switch f.Typeflag {
+		case tar.TypeReg:
+			err = extractFile(path, f, arc)
+		case tar.TypeSymlink:
+			err = extractSymlink(destination, path, f.Linkname)
+		default:
+			fmt.Printf("WARNING: skipping unsupported entry '%s' in archive '%s'\n", f.Name, source)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to extract files: %w", err)
+		}
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		path, _ := SafeJoin(destination, dirs[i].Name)
+		err := applyMetadata(path, dirs[i].FileInfo().Mode(), dirs[i].ModTime)
+		if err != nil {
+			return fmt.Errorf("failed to extract files: %w", err)
+		}
+	}
+	return nil
+}
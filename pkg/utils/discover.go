@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FindExecutable locates the file named name within the directory tree rooted at root. This allows executables to
+// be found in extracted archives regardless of how upstream nests them (ie - within a release-named directory).
+// If several files share the name, executable files are preferred over non-executable ones, then the file closest
+// to root is chosen. An error is returned if no file matches, or if the choice is ambiguous
+func FindExecutable(root, name string) (string, error) {
+	type candidate struct {
+		path       string
+		depth      int
+		executable bool
+	}
+	var best *candidate
+	ambiguous := false
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || entry.Name() != name {
+			return nil
+		}
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			// Dangling symlinks, or those referring to directories, can't be executed
+			return nil
+		}
+
+		c := candidate{
+			path:       path,
+			depth:      strings.Count(path, string(os.PathSeparator)),
+			executable: info.Mode().Perm()&0o111 != 0,
+		}
+		switch {
+		case best == nil:
+			best = &c
+		case c.executable != best.executable:
+			if c.executable {
+				best = &c
+				ambiguous = false
+			}
+		case c.depth < best.depth:
+			best = &c
+			ambiguous = false
+		case c.depth == best.depth:
+			ambiguous = true
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("failed to search '%s' for '%s': %w", root, name, err)
+	}
+	if best == nil {
+		return "", fmt.Errorf("failed to locate '%s' in '%s'", name, root)
+	}
+	if ambiguous {
+		return "", fmt.Errorf("found multiple files named '%s' in '%s'", name, root)
+	}
+	return best.path, nil
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// downloadedBytes tracks the total number of bytes downloaded by this process
+var downloadedBytes atomic.Int64
+
+// DownloadedBytes returns the total number of bytes downloaded by this process
+func DownloadedBytes() int64 {
+	return downloadedBytes.Load()
+}
+
+// CountDownload wraps the provided reader, so that all bytes read from it are included in DownloadedBytes. Reads are
+// throttled to the rate set by SetDownloadRateLimit, if any
+func CountDownload(r io.Reader) io.Reader {
+	return &countingReader{r: r}
+}
+
+type countingReader struct {
+	r io.Reader
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	if chunk := downloadLimiter.chunkSize(); chunk > 0 && len(p) > chunk {
+		p = p[:chunk]
+	}
+	n, err := c.r.Read(p)
+	downloadedBytes.Add(int64(n))
+	downloadLimiter.wait(n)
+	return n, err
+}
+
+// ErrTruncated indicates a download ended before all of the content the server reported was received. It wraps
+// ErrNetwork, as the connection is typically what failed
+var ErrTruncated = fmt.Errorf("%w: download truncated", ErrNetwork)
+
+// ExpectSize wraps the provided reader, so that reading fails if the number of bytes it provides differs from the
+// given size: a wrapped ErrTruncated is returned if it ends early, typically because the connection was dropped.
+// Sizes of zero or less are treated as unknown, and aren't validated
+func ExpectSize(r io.Reader, size int64) io.Reader {
+	if size <= 0 {
+		return r
+	}
+	return &sizedReader{r: r, size: size}
+}
+
+type sizedReader struct {
+	r    io.Reader
+	size int64
+	read int64
+}
+
+func (s *sizedReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	s.read += int64(n)
+	if s.read > s.size {
+		return n, fmt.Errorf("received more data than expected: expected %d bytes, got at least %d", s.size, s.read)
+	}
+	if (errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)) && s.read < s.size {
+		return n, fmt.Errorf("%w: received %d of %d bytes", ErrTruncated, s.read, s.size)
+	}
+	return n, err
+}
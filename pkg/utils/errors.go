@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// The following errors categorize why an operation failed. Errors returned by sources and tools wrap the matching
+// category, if any, so that callers can check for it using errors.Is rather than inspecting the error's message
+var (
+	// ErrAssetNotFound indicates a release, asset, or file which was expected to be published could not be found
+	ErrAssetNotFound = errors.New("asset not found")
+
+	// ErrChecksumMismatch indicates a file's contents differ from its published checksum or digest
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+
+	// ErrUnsupportedPlatform indicates a tool isn't published for the target operating system or architecture
+	ErrUnsupportedPlatform = errors.New("unsupported platform")
+
+	// ErrRateLimited indicates a server refused a request because too many have been made
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrNetwork indicates a request failed before a response was received, such as when a connection couldn't be
+	// established or was dropped
+	ErrNetwork = errors.New("network error")
+
+	// ErrNotApproved indicates a tool or version isn't approved by the organization's policy
+	ErrNotApproved = errors.New("not approved")
+)
+
+// UnexpectedStatus returns an error describing a response whose status code was something other than 200 OK. The
+// error wraps ErrAssetNotFound or ErrRateLimited when the status code indicates either
+func UnexpectedStatus(statusCode int) error {
+	switch statusCode {
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: received non-%d status code: %d", ErrAssetNotFound, http.StatusOK, statusCode)
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: received non-%d status code: %d", ErrRateLimited, http.StatusOK, statusCode)
+	}
+	return fmt.Errorf("received non-%d status code: %d", http.StatusOK, statusCode)
+}
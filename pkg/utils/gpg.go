@@ -1,18 +1,26 @@
 package utils
 
 import (
+	"bytes"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
 	"github.com/ProtonMail/go-crypto/openpgp/packet"
 )
 
 const FedoraSigningKeyURL string = "https://fedoraproject.org/fedora.gpg"
 
-func VerifyGPGSignature(targetFilePath, signatureFilePath string) error {
+// VerifyGPGSignature validates the file at targetFilePath against the detached signature at signatureFilePath, which
+// may be armored or binary. The signature must have been made by one of the keys in the provided keyring
+func VerifyGPGSignature(targetFilePath, signatureFilePath string, keyRing openpgp.EntityList) error {
 	targetFile, err := os.Open(targetFilePath)
 	if err != nil {
 		return fmt.Errorf("failed to open file '%s': %w", targetFilePath, err)
@@ -35,35 +43,127 @@ func VerifyGPGSignature(targetFilePath, signatureFilePath string) error {
 		}
 	}()
 
-	fedoraKey, err := GetFedoraGPGKeys()
+	armored, err := isArmored(signatureFile)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve Fedora GPG signing keys: %w", err)
+		return fmt.Errorf("failed to read signature '%s': %w", signatureFilePath, err)
+	}
+	if armored {
+		_, err = openpgp.CheckArmoredDetachedSignature(keyRing, targetFile, signatureFile, &packet.Config{})
+	} else {
+		_, err = openpgp.CheckDetachedSignature(keyRing, targetFile, signatureFile, &packet.Config{})
+	}
+	if errors.Is(err, pgperrors.ErrUnknownIssuer) {
+		return fmt.Errorf("failed to verify file signature: the file was signed by %s, which isn't trusted: %w", signatureIssuer(signatureFilePath), err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to verify file signature: %w", err)
 	}
-	defer func() {
-		closeErr := fedoraKey.Close()
-		if closeErr != nil {
-			fmt.Fprintf(os.Stderr, "failed to close fedora signing key: %v\n", err)
-		}
-	}()
 
-	keyRing, err := openpgp.ReadKeyRing(fedoraKey)
+	return nil
+}
+
+// isArmored returns true if the provided file is ASCII armored, leaving it positioned at its start
+func isArmored(file *os.File) (bool, error) {
+	header := make([]byte, 64)
+	n, err := io.ReadFull(file, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	_, err = file.Seek(0, io.SeekStart)
 	if err != nil {
-		return fmt.Errorf("failed to read Fedora GPG signing keys: %w", err)
+		return false, err
 	}
+	return bytes.HasPrefix(bytes.TrimSpace(header[:n]), []byte("-----BEGIN")), nil
+}
 
-	_, err = openpgp.CheckArmoredDetachedSignature(keyRing, targetFile, signatureFile, &packet.Config{})
+// signatureIssuer describes the key which made the signature at the provided path, for use in error messages
+func signatureIssuer(signatureFilePath string) string {
+	unknown := "an unknown key"
+	file, err := os.Open(signatureFilePath)
 	if err != nil {
-		return fmt.Errorf("failed to verify file signature: %w", err)
+		return unknown
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	armored, err := isArmored(file)
+	if err != nil {
+		return unknown
+	}
+	var body io.Reader = file
+	if armored {
+		block, err := armor.Decode(file)
+		if err != nil {
+			return unknown
+		}
+		body = block.Body
 	}
+	p, err := packet.Read(body)
+	if err != nil {
+		return unknown
+	}
+	sig, ok := p.(*packet.Signature)
+	if !ok {
+		return unknown
+	}
+	if len(sig.IssuerFingerprint) > 0 {
+		return fmt.Sprintf("key %s", strings.ToUpper(hex.EncodeToString(sig.IssuerFingerprint)))
+	}
+	if sig.IssuerKeyId != nil {
+		return fmt.Sprintf("key ID %016X", *sig.IssuerKeyId)
+	}
+	return unknown
+}
 
-	return nil
+// ReadKeyRing reads the public keys from the provided keyring, which may be armored or binary
+func ReadKeyRing(r io.Reader) (openpgp.EntityList, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return openpgp.EntityList{}, fmt.Errorf("failed to read keyring: %w", err)
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN")) {
+		return openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	}
+	return openpgp.ReadKeyRing(bytes.NewReader(data))
 }
 
-func GetFedoraGPGKeys() (io.ReadCloser, error) {
-	resp, err := http.Get(FedoraSigningKeyURL)
+// FetchKeyRing retrieves the public keys published at the provided URL
+func FetchKeyRing(url string) (openpgp.EntityList, error) {
+	resp, err := HTTPClient().Get(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to GET '%s': %w", FedoraSigningKeyURL, err)
+		return openpgp.EntityList{}, fmt.Errorf("failed to GET '%s': %w", url, err)
 	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return openpgp.EntityList{}, fmt.Errorf("failed to GET '%s': unexpected status '%s'", url, resp.Status)
+	}
+	keyRing, err := ReadKeyRing(resp.Body)
+	if err != nil {
+		return openpgp.EntityList{}, fmt.Errorf("failed to read keys from '%s': %w", url, err)
+	}
+	return keyRing, nil
+}
 
-	return resp.Body, nil
+// Fingerprint returns the fingerprint of the provided key's primary key, in uppercase hexadecimal
+func Fingerprint(key *openpgp.Entity) string {
+	return strings.ToUpper(hex.EncodeToString(key.PrimaryKey.Fingerprint))
+}
+
+// NormalizeFingerprint converts a fingerprint as commonly written - such as in groups separated by spaces, or
+// prefixed with '0x' - to uppercase hexadecimal
+func NormalizeFingerprint(fingerprint string) string {
+	fingerprint = strings.ReplaceAll(strings.TrimSpace(fingerprint), " ", "")
+	fingerprint = strings.TrimPrefix(strings.TrimPrefix(fingerprint, "0x"), "0X")
+	return strings.ToUpper(fingerprint)
+}
+
+// ValidFingerprint returns true if the provided normalized fingerprint is a hexadecimal v4 or v5 key fingerprint
+func ValidFingerprint(fingerprint string) bool {
+	if len(fingerprint) != 40 && len(fingerprint) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(fingerprint)
+	return err == nil
 }
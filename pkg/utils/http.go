@@ -0,0 +1,296 @@
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"time"
+)
+
+// HTTPTransport performs every HTTP request made by backplane-tools. It may be replaced at any time - such as to
+// record or replay traffic - including after clients have been created by HTTPClient
+var HTTPTransport http.RoundTripper = http.DefaultTransport
+
+// Offline prevents any request from being made, so that metadata and assets cached by earlier runs are used wherever
+// they're available. Requests fail with ErrOffline instead
+var Offline bool
+
+// ErrOffline indicates a request wasn't made because offline mode is enabled
+var ErrOffline = fmt.Errorf("%w: offline mode is enabled", ErrNetwork)
+
+// CacheServerPath is the path 'backplane-tools serve' accepts requests at. The URL to retrieve is passed in the
+// 'url' query parameter
+const CacheServerPath = "/fetch"
+
+// cacheServer is the URL of the 'backplane-tools serve' instance requests are made through, if any
+var cacheServer *url.URL
+
+// SetCacheServer routes every subsequent request through the 'backplane-tools serve' instance at the provided URL,
+// so that assets already retrieved by another machine are downloaded from it instead. An empty URL makes requests
+// directly again
+func SetCacheServer(serverURL string) error {
+	if serverURL == "" {
+		cacheServer = nil
+		return nil
+	}
+	parsed, err := ParseCacheServer(serverURL)
+	if err != nil {
+		return err
+	}
+	cacheServer = parsed
+	return nil
+}
+
+// ParseCacheServer parses the URL of a 'backplane-tools serve' instance. Requests relayed through the server reveal
+// which URLs are being retrieved, so it must be served over HTTPS, unless it's on the loopback interface
+func ParseCacheServer(serverURL string) (*url.URL, error) {
+	parsed, err := url.Parse(serverURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid cache server '%s': must be an http(s) URL", serverURL)
+	}
+	if parsed.Scheme != "https" && !isLoopback(parsed.Hostname()) {
+		return nil, fmt.Errorf("invalid cache server '%s': must be an https URL, unless it's a loopback address", serverURL)
+	}
+	return parsed, nil
+}
+
+// isLoopback returns true if the provided host refers to the local machine
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// carriesCredentials returns true if the provided request, or any request redirected to it, was made with
+// credentials. The URLs such requests are redirected to are frequently signed, and so are credentials themselves
+func carriesCredentials(req *http.Request) bool {
+	for ; req != nil; req = redirectedFrom(req) {
+		if req.Header.Get("Authorization") != "" || req.Header.Get("Cookie") != "" || req.URL.User != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// redirectedFrom returns the request which was redirected to the provided request, if any
+func redirectedFrom(req *http.Request) *http.Request {
+	if req.Response == nil {
+		return nil
+	}
+	return req.Response.Request
+}
+
+// transport delegates each request to the current HTTPTransport
+type transport struct{}
+
+// RoundTrip performs the request using HTTPTransport, through the cache server if one is set. Requests made with
+// credentials are always made directly, so that the credentials are never sent to the cache server. Requests failing
+// before a response is received - including those not made because offline mode is enabled - wrap ErrNetwork
+func (transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if Offline {
+		return nil, ErrOffline
+	}
+	if cacheServer == nil || req.URL.Host == cacheServer.Host || carriesCredentials(req) {
+		resp, err := HTTPTransport.RoundTrip(req)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrNetwork, err)
+		}
+		return resp, nil
+	}
+
+	// RoundTrippers must not modify the request they're provided
+	proxied := req.Clone(req.Context())
+	proxied.URL = &url.URL{
+		Scheme: cacheServer.Scheme,
+		Host:   cacheServer.Host,
+		Path:   path.Join("/", cacheServer.Path, CacheServerPath),
+	}
+	proxied.URL.RawQuery = url.Values{"url": []string{req.URL.String()}}.Encode()
+	proxied.Host = ""
+	resp, err := HTTPTransport.RoundTrip(proxied)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to reach cache server '%s': %w", ErrNetwork, cacheServer.Redacted(), err)
+	}
+	// Responses are attributed to the original request, so that redirects are resolved against the upstream URL
+	resp.Request = req
+	return resp, nil
+}
+
+// HTTPClient returns a client whose requests are performed by HTTPTransport
+func HTTPClient() *http.Client {
+	return &http.Client{Transport: transport{}}
+}
+
+// TLSOptions customizes how the TLS connections made by backplane-tools are established
+type TLSOptions struct {
+	// CABundle is the location of a PEM-encoded file containing certificate authorities to trust in addition to
+	// the system's, such as that of a proxy which intercepts TLS connections
+	CABundle string
+
+	// ClientCertificate and ClientKey are the locations of a PEM-encoded certificate and key presented to servers
+	// requiring client authentication
+	ClientCertificate string
+	ClientKey         string
+
+	// InsecureSkipVerify disables verification of servers' certificates. This exposes every download to tampering,
+	// and should only be used for troubleshooting
+	InsecureSkipVerify bool
+}
+
+// TransportSettings tunes the connections made to a server. Zero values are replaced by those in
+// DefaultTransportSettings
+type TransportSettings struct {
+	// ConnectTimeout bounds how long establishing a connection, including the TLS handshake, may take
+	ConnectTimeout time.Duration
+
+	// ReadTimeout bounds how long the server may go without sending any data, whether while waiting for a response
+	// or during a download. Large downloads are not affected, as long as data continues to arrive
+	ReadTimeout time.Duration
+
+	// KeepAlive is the interval between keep-alive probes sent on open connections
+	KeepAlive time.Duration
+
+	// MaxIdleConnsPerHost bounds the number of idle connections kept open for reuse
+	MaxIdleConnsPerHost int
+}
+
+// DefaultTransportSettings are applied to every connection, unless overridden
+var DefaultTransportSettings = TransportSettings{
+	ConnectTimeout:      30 * time.Second,
+	ReadTimeout:         2 * time.Minute,
+	KeepAlive:           30 * time.Second,
+	MaxIdleConnsPerHost: 4,
+}
+
+// merge returns the settings, with any unset values replaced by those in fallback
+func (s TransportSettings) merge(fallback TransportSettings) TransportSettings {
+	if s.ConnectTimeout == 0 {
+		s.ConnectTimeout = fallback.ConnectTimeout
+	}
+	if s.ReadTimeout == 0 {
+		s.ReadTimeout = fallback.ReadTimeout
+	}
+	if s.KeepAlive == 0 {
+		s.KeepAlive = fallback.KeepAlive
+	}
+	if s.MaxIdleConnsPerHost == 0 {
+		s.MaxIdleConnsPerHost = fallback.MaxIdleConnsPerHost
+	}
+	return s
+}
+
+// ConfigureTransport replaces HTTPTransport with a transport establishing TLS connections according to the provided
+// options, and tuning connections using the provided settings. Connections to the hosts in hostSettings use those
+// settings instead, with any unset values taken from settings
+func ConfigureTransport(tlsOpts TLSOptions, settings TransportSettings, hostSettings map[string]TransportSettings) error {
+	tlsConfig, err := newTLSConfig(tlsOpts)
+	if err != nil {
+		return err
+	}
+	settings = settings.merge(DefaultTransportSettings)
+	t := &hostTransport{
+		fallback:   newTransport(tlsConfig, settings),
+		transports: map[string]*http.Transport{},
+	}
+	for host, s := range hostSettings {
+		t.transports[host] = newTransport(tlsConfig, s.merge(settings))
+	}
+	HTTPTransport = t
+	return nil
+}
+
+// newTLSConfig builds the TLS configuration described by the provided options
+func newTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+
+	if opts.CABundle != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		bundle, err := os.ReadFile(opts.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle '%s': %w", opts.CABundle, err)
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no PEM-encoded certificates found in CA bundle '%s'", opts.CABundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.ClientCertificate != "" || opts.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(opts.ClientCertificate, opts.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate '%s' and key '%s': %w", opts.ClientCertificate, opts.ClientKey, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// newTransport creates a transport using the provided TLS configuration and settings
+func newTransport(tlsConfig *tls.Config, settings TransportSettings) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   settings.ConnectTimeout,
+		KeepAlive: settings.KeepAlive,
+	}
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			return &idleTimeoutConn{Conn: conn, timeout: settings.ReadTimeout}, nil
+		},
+		TLSClientConfig:       tlsConfig.Clone(),
+		TLSHandshakeTimeout:   settings.ConnectTimeout,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   settings.MaxIdleConnsPerHost,
+		IdleConnTimeout:       90 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// hostTransport performs each request using the transport tuned for its host
+type hostTransport struct {
+	fallback   *http.Transport
+	transports map[string]*http.Transport
+}
+
+// RoundTrip performs the request using the transport configured for its host, or the fallback transport
+func (t *hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	selected, found := t.transports[req.URL.Hostname()]
+	if !found {
+		selected = t.fallback
+	}
+	return selected.RoundTrip(req)
+}
+
+// idleTimeoutConn fails reads once the server has sent no data for the timeout, so that a stalled connection
+// doesn't block forever
+type idleTimeoutConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+// Read extends the connection's deadline before each read
+func (c *idleTimeoutConn) Read(b []byte) (int, error) {
+	err := c.Conn.SetReadDeadline(time.Now().Add(c.timeout))
+	if err != nil {
+		return 0, err
+	}
+	return c.Conn.Read(b)
+}
@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestParseCacheServer(t *testing.T) {
+	tests := []struct {
+		serverURL string
+		valid     bool
+	}{
+		{serverURL: "https://tools-cache.example.com:8443", valid: true},
+		{serverURL: "http://tools-cache.example.com:8080", valid: false},
+		{serverURL: "http://127.0.0.1:8080", valid: true},
+		{serverURL: "http://localhost:8080", valid: true},
+		{serverURL: "http://[::1]:8080", valid: true},
+		{serverURL: "ftp://tools-cache.example.com", valid: false},
+		{serverURL: "tools-cache.example.com", valid: false},
+	}
+	for _, tt := range tests {
+		_, err := ParseCacheServer(tt.serverURL)
+		if tt.valid && err != nil {
+			t.Errorf("expected '%s' to be accepted, got '%v'", tt.serverURL, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("expected '%s' to be rejected", tt.serverURL)
+		}
+	}
+}
+
+// recorder is an HTTP server recording the Authorization header of each request it receives
+type recorder struct {
+	*httptest.Server
+	mu             sync.Mutex
+	authorizations []string
+}
+
+// newRecorder starts a recorder responding to each request using handler
+func newRecorder(t *testing.T, handler http.HandlerFunc) *recorder {
+	t.Helper()
+	r := &recorder{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mu.Lock()
+		r.authorizations = append(r.authorizations, req.Header.Get("Authorization"))
+		r.mu.Unlock()
+		handler(w, req)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+// requests returns the number of requests the recorder has received
+func (r *recorder) requests() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.authorizations)
+}
+
+func TestCacheServerNeverReceivesCredentials(t *testing.T) {
+	cache := newRecorder(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("cached"))
+	})
+	var upstream *recorder
+	upstream = newRecorder(t, func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/private" {
+			http.Redirect(w, req, upstream.URL+"/signed?X-Amz-Signature=abc", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte("upstream"))
+	})
+	if err := SetCacheServer(cache.URL); err != nil {
+		t.Fatalf("failed to set cache server: %v", err)
+	}
+	t.Cleanup(func() { _ = SetCacheServer("") })
+
+	get := func(path, authorization string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, upstream.URL+path, nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := HTTPClient().Do(req)
+		if err != nil {
+			t.Fatalf("failed to get '%s': %v", path, err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	get("/public", "")
+	if cache.requests() != 1 || upstream.requests() != 0 {
+		t.Errorf("expected the anonymous request to be made through the cache server")
+	}
+
+	// Redirects of requests made with credentials are also made directly, as the signed URLs they lead to grant access
+	get("/private", "Bearer token")
+	if cache.requests() != 1 {
+		t.Errorf("expected the credentialed request and its redirect to bypass the cache server, but it received %d requests", cache.requests()-1)
+	}
+	if upstream.requests() != 2 {
+		t.Errorf("expected the credentialed request and its redirect to be made directly, got %d requests", upstream.requests())
+	}
+	for _, authorization := range cache.authorizations {
+		if authorization != "" {
+			t.Errorf("cache server received credentials '%s'", authorization)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+const (
+	// LibcGlibc identifies the GNU C library, used by most Linux distributions
+	LibcGlibc = "glibc"
+
+	// LibcMusl identifies the musl C library, used by Alpine Linux and other minimal distributions
+	LibcMusl = "musl"
+)
+
+// TargetLibc is the C library tools are installed for on Linux. When empty, it's detected from the local system
+var TargetLibc = ""
+
+// detectLibc determines the local system's C library once, as it can't change while running
+var detectLibc = sync.OnceValue(func() string {
+	// musl's dynamic linker is installed as /lib/ld-musl-<arch>.so.1, whereas glibc systems don't provide one
+	matches, err := filepath.Glob("/lib/ld-musl-*.so.1")
+	if err == nil && len(matches) > 0 {
+		return LibcMusl
+	}
+	return LibcGlibc
+})
+
+// Libc returns the C library tools are installed for: either LibcGlibc or LibcMusl. An empty string is returned
+// when the target OS isn't Linux. Unless TargetLibc is set, the local system's C library is detected; when preparing
+// tools for another system, glibc is assumed
+func Libc() string {
+	if TargetOS != "linux" {
+		return ""
+	}
+	if TargetLibc != "" {
+		return TargetLibc
+	}
+	if TargetOS != runtime.GOOS || TargetArch != runtime.GOARCH {
+		return LibcGlibc
+	}
+	return detectLibc()
+}
+
+// GetLibcAliases returns the names release assets commonly use to denote they're built against the provided C library
+func GetLibcAliases(libc string) []string {
+	switch libc {
+	case LibcGlibc:
+		return []string{"gnu", "glibc"}
+	case LibcMusl:
+		return []string{"musl"}
+	default:
+		return []string{}
+	}
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NetrcPath returns the location of the user's netrc file: $NETRC if set, or ~/.netrc otherwise
+func NetrcPath() string {
+	if path := os.Getenv("NETRC"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".netrc")
+}
+
+// NetrcCredentials returns the login and password stored for the host in the user's netrc file. If the file
+// has no entry for the host, its 'default' entry is used, if any. found is false if no credentials are stored
+func NetrcCredentials(host string) (login, password string, found bool, err error) {
+	path := NetrcPath()
+	if path == "" {
+		return "", "", false, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", "", false, nil
+	}
+	if err != nil {
+		return "", "", false, fmt.Errorf("failed to read netrc file '%s': %w", path, err)
+	}
+	login, password, found = parseNetrc(string(data), host)
+	return login, password, found, nil
+}
+
+// parseNetrc returns the credentials for the host from the contents of a netrc file
+func parseNetrc(contents, host string) (string, string, bool) {
+	type entry struct {
+		login, password string
+	}
+	var machine, fallback *entry
+	var current *entry
+
+	lines := strings.Split(contents, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		tokens := strings.Fields(line)
+		for j := 0; j < len(tokens); j++ {
+			next := func() string {
+				if j+1 < len(tokens) {
+					j++
+					return tokens[j]
+				}
+				return ""
+			}
+			switch tokens[j] {
+			case "machine":
+				current = nil
+				if name := next(); name == host && machine == nil {
+					machine = &entry{}
+					current = machine
+				}
+			case "default":
+				current = nil
+				if fallback == nil {
+					fallback = &entry{}
+					current = fallback
+				}
+			case "login":
+				if value := next(); current != nil {
+					current.login = value
+				}
+			case "password":
+				if value := next(); current != nil {
+					current.password = value
+				}
+			case "account":
+				next()
+			case "macdef":
+				// Macro definitions continue until the next blank line
+				current = nil
+				for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" {
+					i++
+				}
+				j = len(tokens)
+			}
+		}
+	}
+
+	if machine != nil {
+		return machine.login, machine.password, true
+	}
+	if fallback != nil {
+		return fallback.login, fallback.password, true
+	}
+	return "", "", false
+}
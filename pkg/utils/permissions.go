@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+)
+
+const (
+	// ExecutableMode is the mode given to executables and directories
+	ExecutableMode = os.FileMode(0o755)
+
+	// DataMode is the mode given to all other files, such as downloaded archives and checksum files
+	DataMode = os.FileMode(0o644)
+)
+
+// WorldReadable is true when installed files may be read by users other than their owner. When false, files and
+// directories are only accessible by their owner
+var WorldReadable = true
+
+// FileMode returns the permissions a file should be given when the provided mode is requested for it - whether by
+// the caller, or by the archive it's extracted from. Files requesting any executable bit are given ExecutableMode,
+// and all others DataMode. The result never grants more access than was requested, so archives can't create
+// world-writable or setuid files, while files requested to be private stay private. Unless WorldReadable, access is
+// further limited to the file's owner, and the user's umask is applied in either case
+func FileMode(requested os.FileMode) os.FileMode {
+	mode := DataMode
+	allowed := requested.Perm() | 0o200
+	if requested&0o111 != 0 {
+		mode = ExecutableMode
+		allowed |= 0o100
+	}
+	mode &= allowed
+	if !WorldReadable {
+		mode &^= 0o077
+	}
+	return mode &^ umask
+}
+
+// MakeExecutable ensures the file at the provided path can be executed by each user able to read it, such as a binary
+// downloaded without being archived, giving it the permissions determined by FileMode
+func MakeExecutable(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat '%s': %w", path, err)
+	}
+	readable := info.Mode().Perm() & 0o444
+	err = os.Chmod(path, FileMode(info.Mode()|0o100|readable>>2))
+	if err != nil {
+		return fmt.Errorf("failed to set permissions on '%s': %w", path, err)
+	}
+	return nil
+}
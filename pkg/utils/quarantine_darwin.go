@@ -0,0 +1,23 @@
+//go:build darwin
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// quarantineAttr is the extended attribute Gatekeeper uses to flag files downloaded from the internet
+const quarantineAttr = "com.apple.quarantine"
+
+// ClearQuarantine removes the macOS quarantine attribute from the file at the provided path, so that
+// Gatekeeper does not block the file from being executed. Files without the attribute are left untouched
+func ClearQuarantine(path string) error {
+	err := unix.Removexattr(path, quarantineAttr)
+	if err != nil && !errors.Is(err, unix.ENOATTR) {
+		return fmt.Errorf("failed to remove quarantine attribute from '%s': %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !darwin
+
+package utils
+
+// ClearQuarantine is a no-op on systems other than macOS, which do not quarantine downloaded files
+func ClearQuarantine(_ string) error {
+	return nil
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestionDistance is the largest edit distance at which a candidate is still considered a likely typo of the
+// input. Short inputs are held to a tighter bound, so that unrelated short names aren't suggested
+const maxSuggestionDistance = 3
+
+// Suggest returns the candidates the provided input was most likely a typo of, closest match first. Candidates the
+// input is a prefix of are suggested as well
+func Suggest(input string, candidates []string) []string {
+	input = strings.ToLower(input)
+	maxDistance := len(input) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+	if maxDistance > maxSuggestionDistance {
+		maxDistance = maxSuggestionDistance
+	}
+
+	distances := map[string]int{}
+	for _, candidate := range candidates {
+		distance := Levenshtein(input, strings.ToLower(candidate))
+		if distance <= maxDistance || (input != "" && strings.HasPrefix(strings.ToLower(candidate), input)) {
+			distances[candidate] = distance
+		}
+	}
+	suggestions := Keys(distances)
+	sort.Slice(suggestions, func(i, j int) bool {
+		if distances[suggestions[i]] != distances[suggestions[j]] {
+			return distances[suggestions[i]] < distances[suggestions[j]]
+		}
+		return suggestions[i] < suggestions[j]
+	})
+	return suggestions
+}
+
+// Levenshtein returns the minimum number of single-character insertions, deletions, and substitutions needed to
+// change a into b
+func Levenshtein(a, b string) int {
+	source := []rune(a)
+	target := []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(target)]
+}
@@ -0,0 +1,171 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// Conformance installs and removes the provided tool within the Harness, and returns every violation of the
+// contract all Tool implementations are expected to uphold:
+//   - installing creates files only within the tool's directory and the 'latest' directory, aside from state files
+//   - Installed and InstalledVersion agree with the files on disk, before and after installing and removing
+//   - a failed installation leaves the tool's links in the 'latest' directory untouched
+//   - removing leaves no links to the tool behind, and can be repeated without error
+//
+// Before the failed installation is attempted, breakSource is called, and must cause subsequent installations of
+// the tool to fail - such as by routing the tool's source to a server which always responds with an error
+func Conformance(h *Harness, tool tools.Tool, breakSource func()) []error {
+	violations := []error{}
+	violate := func(format string, args ...any) {
+		violations = append(violations, fmt.Errorf("%s: %s", tool.Name(), fmt.Sprintf(format, args...)))
+	}
+	toolDir := filepath.Join(base.InstallDir, tool.Name())
+
+	installed, err := tool.Installed()
+	if err != nil || installed {
+		violate("Installed() returned (%t, %v) before installing, expected (false, nil)", installed, err)
+	}
+
+	before, err := h.Tree()
+	if err != nil {
+		return append(violations, err)
+	}
+	err = tool.Install()
+	if err != nil {
+		return append(violations, fmt.Errorf("%s: failed to install: %w", tool.Name(), err))
+	}
+	after, err := h.Tree()
+	if err != nil {
+		return append(violations, err)
+	}
+	for _, entry := range after {
+		path := filepath.Join(h.Dir, strings.SplitN(entry, " -> ", 2)[0])
+		if utils.Contains(before, entry) || within(path, toolDir) || within(path, base.LatestDir) || isStateFile(path) {
+			continue
+		}
+		violate("installing created '%s', outside of the tool and latest directories, and the state files", entry)
+	}
+
+	installed, err = tool.Installed()
+	if err != nil || !installed {
+		violate("Installed() returned (%t, %v) after installing, expected (true, nil)", installed, err)
+	}
+	links, err := linksTo(toolDir)
+	if err != nil {
+		return append(violations, err)
+	}
+	if len(links) == 0 {
+		violate("installing did not link any executables into '%s'", base.LatestDir)
+	}
+	installedVersion, err := tool.InstalledVersion()
+	if err != nil {
+		violate("InstalledVersion() failed after installing: %v", err)
+	}
+	for linkPath, target := range links {
+		if !within(target, filepath.Join(toolDir, installedVersion)) {
+			violate("'%s' links to '%s', which is not within installed version '%s'", linkPath, target, installedVersion)
+		}
+	}
+
+	breakSource()
+	err = tool.Install()
+	if err == nil {
+		violate("installing succeeded after the source was broken")
+	}
+	relinked, err := linksTo(toolDir)
+	if err != nil {
+		return append(violations, err)
+	}
+	for linkPath, target := range links {
+		if relinked[linkPath] != target {
+			violate("a failed installation changed the target of '%s' from '%s' to '%s'", linkPath, target, relinked[linkPath])
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		err = tool.Remove()
+		if err != nil {
+			violate("removal #%d failed: %v", i+1, err)
+		}
+	}
+	installed, err = tool.Installed()
+	if err != nil || installed {
+		violate("Installed() returned (%t, %v) after removing, expected (false, nil)", installed, err)
+	}
+	for linkPath := range links {
+		_, err = os.Lstat(linkPath)
+		if !errors.Is(err, os.ErrNotExist) {
+			violate("'%s' remains after removing", linkPath)
+		}
+	}
+	return violations
+}
+
+// GithubConformance runs Conformance against the provided tool within a new Harness, after publishing release tag of
+// the tool's repository, owner/repo, containing the provided assets. Any other hosts the tool retrieves files from
+// are routed to the handlers in routes. The tool's source is broken by routing GitHub's API, and every other host,
+// to a handler which always responds with an error
+func GithubConformance(tool tools.Tool, owner, repo, tag string, assets map[string][]byte, routes map[string]http.Handler) ([]error, error) {
+	h, err := NewHarness()
+	if err != nil {
+		return []error{}, err
+	}
+	defer func() {
+		_ = h.Close()
+	}()
+
+	gh := NewGithubServer()
+	gh.AddRelease(owner, repo, tag, assets)
+	h.Route("api.github.com", gh)
+	for host, handler := range routes {
+		h.Route(host, handler)
+	}
+	breakSource := func() {
+		failing := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "unavailable", http.StatusInternalServerError)
+		})
+		h.Route("api.github.com", failing)
+		for host := range routes {
+			h.Route(host, failing)
+		}
+	}
+	return Conformance(h, tool, breakSource), nil
+}
+
+// linksTo returns the recorded links in the 'latest' directory whose targets are within dir
+func linksTo(dir string) (map[string]string, error) {
+	links, err := base.Links()
+	if err != nil {
+		return map[string]string{}, err
+	}
+	owned := map[string]string{}
+	for linkPath, target := range links {
+		if within(target, dir) {
+			owned[linkPath] = target
+		}
+	}
+	return owned, nil
+}
+
+// within returns true if path is dir, or is contained by it
+func within(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(os.PathSeparator))
+}
+
+// isStateFile returns true if path refers to a file directly within the state directory, or to the cache of keys
+// trusted to sign tools within it
+func isStateFile(path string) bool {
+	if within(path, filepath.Join(base.StateDir, "keyrings")) {
+		return true
+	}
+	info, err := os.Lstat(path)
+	return err == nil && info.Mode().IsRegular() && filepath.Dir(path) == base.StateDir
+}
@@ -0,0 +1,171 @@
+package test
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/openshift/backplane-tools/pkg/sources/cloud.google.com/storage"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// GCSEnv pairs a temporary install root with a minimal Google Cloud Storage emulator. The emulator implements
+// the subset of the storage API used by backplane-tools: listing a bucket's objects, and reading an object and
+// its metadata
+type GCSEnv struct {
+	// Dir is the temporary directory tools are installed into
+	Dir string
+
+	// Server serves the emulated storage API
+	Server *httptest.Server
+
+	mu      sync.Mutex
+	buckets map[string]map[string][]byte
+}
+
+// NewGCSEnv creates a temporary install root, and starts an emulator with no buckets. Close must be called to
+// stop the emulator and remove the install root
+func NewGCSEnv() (*GCSEnv, error) {
+	dir, err := newInstallRoot()
+	if err != nil {
+		return nil, err
+	}
+	env := &GCSEnv{
+		Dir:     dir,
+		buckets: map[string]map[string][]byte{},
+	}
+	env.Server = httptest.NewServer(http.HandlerFunc(env.serve))
+	return env, nil
+}
+
+// Source returns a storage source retrieving the named bucket from the emulator
+func (e *GCSEnv) Source(bucketName string) (*storage.Source, error) {
+	return storage.NewSourceWithEndpoint(bucketName, e.Server.URL+"/storage/v1/")
+}
+
+// AddObject stores an object with the provided contents in the named bucket, creating the bucket if needed
+func (e *GCSEnv) AddObject(bucketName, name string, contents []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.buckets[bucketName] == nil {
+		e.buckets[bucketName] = map[string][]byte{}
+	}
+	e.buckets[bucketName][name] = contents
+}
+
+// AddArchive stores a gzip-compressed tarball containing the provided files in the named bucket. Each file is
+// keyed by its path within the archive, and archived as an executable
+func (e *GCSEnv) AddArchive(bucketName, name string, files map[string][]byte) error {
+	archive, err := TarGz(files)
+	if err != nil {
+		return err
+	}
+	e.AddObject(bucketName, name, archive)
+	return nil
+}
+
+// Close stops the emulator and removes the install root
+func (e *GCSEnv) Close() error {
+	e.Server.Close()
+	return os.RemoveAll(e.Dir)
+}
+
+// gcsObject is the representation of an object's metadata returned by the storage API
+type gcsObject struct {
+	Kind   string `json:"kind"`
+	Bucket string `json:"bucket"`
+	Name   string `json:"name"`
+	Size   string `json:"size"`
+	CRC32C string `json:"crc32c"`
+	MD5    string `json:"md5Hash"`
+}
+
+// newGCSObject returns the metadata of the named object with the provided contents
+func newGCSObject(bucketName, name string, contents []byte) gcsObject {
+	checksum := make([]byte, 4)
+	binary.BigEndian.PutUint32(checksum, crc32.Checksum(contents, crc32.MakeTable(crc32.Castagnoli)))
+	digest := md5.Sum(contents)
+	return gcsObject{
+		Kind:   "storage#object",
+		Bucket: bucketName,
+		Name:   name,
+		Size:   strconv.Itoa(len(contents)),
+		CRC32C: base64.StdEncoding.EncodeToString(checksum),
+		MD5:    base64.StdEncoding.EncodeToString(digest[:]),
+	}
+}
+
+// serve responds to list requests ('GET /storage/v1/b/<bucket>/o'), including those grouping objects using a
+// delimiter, metadata requests ('GET /storage/v1/b/<bucket>/o/<object>'), and object reads ('GET /<bucket>/<object>')
+func (e *GCSEnv) serve(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if listPath, found := strings.CutPrefix(r.URL.Path, "/storage/v1/b/"); found {
+		if bucketName, name, found := strings.Cut(listPath, "/o/"); found {
+			contents, exists := e.buckets[bucketName][name]
+			if !exists {
+				http.NotFound(w, r)
+				return
+			}
+			writeJSON(w, newGCSObject(bucketName, name, contents))
+			return
+		}
+		bucketName, found := strings.CutSuffix(listPath, "/o")
+		objects, exists := e.buckets[bucketName]
+		if !found || !exists {
+			http.NotFound(w, r)
+			return
+		}
+		// Objects whose names contain the delimiter after the prefix are grouped into a single prefix
+		prefix := r.URL.Query().Get("prefix")
+		delimiter := r.URL.Query().Get("delimiter")
+		items := []gcsObject{}
+		prefixes := []string{}
+		for name, contents := range objects {
+			rest, found := strings.CutPrefix(name, prefix)
+			if !found {
+				continue
+			}
+			if before, _, grouped := strings.Cut(rest, delimiter); delimiter != "" && grouped {
+				if !utils.Contains(prefixes, prefix+before+delimiter) {
+					prefixes = append(prefixes, prefix+before+delimiter)
+				}
+				continue
+			}
+			items = append(items, newGCSObject(bucketName, name, contents))
+		}
+		sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+		sort.Strings(prefixes)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(struct {
+			Kind     string      `json:"kind"`
+			Items    []gcsObject `json:"items"`
+			Prefixes []string    `json:"prefixes,omitempty"`
+		}{Kind: "storage#objects", Items: items, Prefixes: prefixes})
+		return
+	}
+
+	bucketName, name, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	contents, found := e.buckets[bucketName][name]
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(contents)))
+	_, _ = w.Write(contents)
+}
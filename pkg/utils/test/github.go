@@ -0,0 +1,127 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// GithubServer is a minimal stand-in for the GitHub API, serving the releases, tags, and release assets of
+// repositories. Route "api.github.com" to it using a Harness
+type GithubServer struct {
+	mu       sync.Mutex
+	releases map[string][]githubRelease
+	assets   map[int64][]byte
+	nextID   int64
+}
+
+type githubRelease struct {
+	ID      int64         `json:"id"`
+	TagName string        `json:"tag_name"`
+	Assets  []githubAsset `json:"assets"`
+}
+
+type githubAsset struct {
+	ID                 int64  `json:"id"`
+	Name               string `json:"name"`
+	Size               int    `json:"size"`
+	URL                string `json:"url"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// NewGithubServer creates a GithubServer without any repositories
+func NewGithubServer() *GithubServer {
+	return &GithubServer{
+		releases: map[string][]githubRelease{},
+		assets:   map[int64][]byte{},
+		nextID:   1,
+	}
+}
+
+// AddRelease publishes a release of the repository with the provided assets, keyed by name. The most recently
+// added release is reported as the latest, and its tag listed first
+func (g *GithubServer) AddRelease(owner, repo, tag string, assets map[string][]byte) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	repoPath := owner + "/" + repo
+	release := githubRelease{ID: g.nextID, TagName: tag, Assets: []githubAsset{}}
+	g.nextID++
+	for name, contents := range assets {
+		asset := githubAsset{
+			ID:                 g.nextID,
+			Name:               name,
+			Size:               len(contents),
+			URL:                fmt.Sprintf("https://api.github.com/repos/%s/releases/assets/%d", repoPath, g.nextID),
+			BrowserDownloadURL: fmt.Sprintf("https://github.com/%s/releases/download/%s/%s", repoPath, tag, name),
+		}
+		g.assets[asset.ID] = contents
+		release.Assets = append(release.Assets, asset)
+		g.nextID++
+	}
+	g.releases[repoPath] = append(g.releases[repoPath], release)
+}
+
+// ServeHTTP responds to requests for a repository's latest release, releases, release by tag, tags, and release
+// assets
+func (g *GithubServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 4 || parts[0] != "repos" {
+		http.NotFound(w, r)
+		return
+	}
+	releases := g.releases[parts[1]+"/"+parts[2]]
+	if len(releases) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	resource := strings.Join(parts[3:], "/")
+	switch {
+	case resource == "releases/latest":
+		writeJSON(w, releases[len(releases)-1])
+	case resource == "releases":
+		newestFirst := []githubRelease{}
+		for i := len(releases) - 1; i >= 0; i-- {
+			newestFirst = append(newestFirst, releases[i])
+		}
+		writeJSON(w, newestFirst)
+	case resource == "tags":
+		tags := []map[string]string{}
+		for i := len(releases) - 1; i >= 0; i-- {
+			tags = append(tags, map[string]string{"name": releases[i].TagName})
+		}
+		writeJSON(w, tags)
+	case strings.HasPrefix(resource, "releases/tags/"):
+		tag := strings.TrimPrefix(resource, "releases/tags/")
+		for _, release := range releases {
+			if release.TagName == tag {
+				writeJSON(w, release)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	case strings.HasPrefix(resource, "releases/assets/"):
+		id, err := strconv.ParseInt(strings.TrimPrefix(resource, "releases/assets/"), 10, 64)
+		contents, found := g.assets[id]
+		if err != nil || !found {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(contents)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// writeJSON responds with the JSON encoding of v
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,73 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// UpdateGoldenEnvVar is the environment variable which, when set to "true", causes CompareGolden to replace
+// golden files with the actual output instead of comparing against them
+const UpdateGoldenEnvVar = "BACKPLANE_TOOLS_UPDATE_GOLDEN"
+
+// volatile matches the parts of command output which differ between otherwise identical runs
+var volatile = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`), "<timestamp>"},
+	{regexp.MustCompile(`\d+(\.\d+)?(ns|µs|ms|s)\b`), "<duration>"},
+}
+
+// Normalize replaces the parts of the provided output which differ between runs - the Harness' install root,
+// timestamps, and durations - with fixed placeholders, so that it can be compared against a golden file
+func (h *Harness) Normalize(output string) string {
+	output = strings.ReplaceAll(output, h.Dir, "<root>")
+	for _, v := range volatile {
+		output = v.pattern.ReplaceAllString(output, v.replacement)
+	}
+	return output
+}
+
+// CompareGolden returns an error describing the first difference between actual and the contents of the golden
+// file at path. If UpdateGoldenEnvVar is set to "true", the golden file is replaced with actual instead
+func CompareGolden(path, actual string) error {
+	if os.Getenv(UpdateGoldenEnvVar) == "true" {
+		err := os.MkdirAll(filepath.Dir(path), os.FileMode(0o755))
+		if err != nil {
+			return fmt.Errorf("failed to create directory for golden file '%s': %w", path, err)
+		}
+		err = os.WriteFile(path, []byte(actual), os.FileMode(0o644))
+		if err != nil {
+			return fmt.Errorf("failed to update golden file '%s': %w", path, err)
+		}
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read golden file '%s' (set %s=true to create it): %w", path, UpdateGoldenEnvVar, err)
+	}
+	expected := string(data)
+	if expected == actual {
+		return nil
+	}
+
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+	for i := 0; i < len(expectedLines) || i < len(actualLines); i++ {
+		expectedLine, actualLine := "<end of output>", "<end of output>"
+		if i < len(expectedLines) {
+			expectedLine = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			actualLine = actualLines[i]
+		}
+		if expectedLine != actualLine {
+			return fmt.Errorf("output differs from golden file '%s' at line %d:\n  expected: %q\n  actual:   %q\nSet %s=true to update the golden file", path, i+1, expectedLine, actualLine, UpdateGoldenEnvVar)
+		}
+	}
+	return nil
+}
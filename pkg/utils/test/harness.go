@@ -0,0 +1,204 @@
+package test
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/sources/github"
+	"github.com/openshift/backplane-tools/pkg/tools"
+	"github.com/openshift/backplane-tools/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// Harness runs backplane-tools commands end-to-end against a temporary install root. While the Harness is open,
+// every HTTP request made by backplane-tools is routed to a local server standing in for the requested host;
+// requests to hosts without a route fail, so that no request reaches the network
+type Harness struct {
+	// Dir is the temporary directory tools are installed into
+	Dir string
+
+	mu        sync.Mutex
+	servers   map[string]*httptest.Server
+	transport http.RoundTripper
+	cacheDir  string
+}
+
+// NewHarness creates a temporary install root and configuration file, and begins routing HTTP requests.
+// Close must be called to restore HTTP routing and remove the install root
+func NewHarness() (*Harness, error) {
+	dir, err := newInstallRoot()
+	if err != nil {
+		return nil, err
+	}
+	h := &Harness{
+		Dir:       dir,
+		servers:   map[string]*httptest.Server{},
+		transport: utils.HTTPTransport,
+		cacheDir:  github.CacheDir,
+	}
+	utils.HTTPTransport = h
+	// Responses cached by earlier runs would hide the routed servers' responses
+	github.CacheDir = ""
+	routeAfterInitialize.Do(func() {
+		cobra.OnInitialize(reinstateRouting)
+	})
+	open = h
+	return h, nil
+}
+
+// open is the Harness currently routing HTTP requests, if any
+var open *Harness
+
+// routeAfterInitialize ensures reinstateRouting is only registered with cobra once
+var routeAfterInitialize sync.Once
+
+// reinstateRouting routes HTTP requests through the open Harness again. backplane-tools replaces HTTPTransport as
+// it applies the user's configuration when each command is initialized, which would otherwise send requests to the
+// network
+func reinstateRouting() {
+	if open != nil {
+		utils.HTTPTransport = open
+	}
+}
+
+// Route serves requests for the provided host (ie - "api.github.com") using handler. The servers started by
+// MirrorEnv and GCSEnv can be routed using their Server.Config.Handler
+func (h *Harness) Route(host string, handler http.Handler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if existing, found := h.servers[host]; found {
+		existing.Close()
+	}
+	h.servers[host] = httptest.NewServer(handler)
+}
+
+// RoundTrip sends the request to the server routed for its host
+func (h *Harness) RoundTrip(req *http.Request) (*http.Response, error) {
+	h.mu.Lock()
+	server, found := h.servers[req.URL.Hostname()]
+	h.mu.Unlock()
+	if !found {
+		return nil, fmt.Errorf("no route defined for host '%s'", req.URL.Hostname())
+	}
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		return nil, err
+	}
+	routed := req.Clone(req.Context())
+	routed.URL.Scheme = serverURL.Scheme
+	routed.URL.Host = serverURL.Host
+	routed.Host = serverURL.Host
+	return http.DefaultTransport.RoundTrip(routed)
+}
+
+// WriteConfig replaces the configuration file used by commands run with the Harness
+func (h *Harness) WriteConfig(contents string) error {
+	return os.WriteFile(config.Path, []byte(contents), os.FileMode(0o644))
+}
+
+// Output holds everything a command wrote to its standard streams
+type Output struct {
+	Stdout string
+	Stderr string
+}
+
+// Run executes the provided command with the given arguments, as the backplane-tools binary would, and returns
+// everything it wrote to stdout and stderr. A non-nil error corresponds to the binary exiting with a non-zero status
+func (h *Harness) Run(cmd *cobra.Command, args ...string) (Output, error) {
+	stdout, restoreStdout, err := capture(&os.Stdout)
+	if err != nil {
+		return Output{}, err
+	}
+	stderr, restoreStderr, err := capture(&os.Stderr)
+	if err != nil {
+		restoreStdout()
+		return Output{}, err
+	}
+
+	runErr := config.Load()
+	if runErr == nil {
+		// Tools cache what they've retrieved, such as their latest version, which earlier runs may have changed
+		tools.Reload()
+		cmd.SetArgs(args)
+		cmd.SetOut(os.Stdout)
+		cmd.SetErr(os.Stderr)
+		cmd.SilenceUsage = true
+		runErr = cmd.Execute()
+	}
+
+	restoreStdout()
+	restoreStderr()
+	return Output{Stdout: <-stdout, Stderr: <-stderr}, runErr
+}
+
+// capture redirects the provided stream to a pipe, returning a channel which receives everything written to it
+// once the returned function restores the stream
+func capture(stream **os.File) (<-chan string, func(), error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to capture output: %w", err)
+	}
+	original := *stream
+	*stream = writer
+	output := make(chan string, 1)
+	go func() {
+		data, _ := io.ReadAll(reader)
+		_ = reader.Close()
+		output <- string(data)
+	}()
+	restore := func() {
+		*stream = original
+		_ = writer.Close()
+	}
+	return output, restore, nil
+}
+
+// Tree returns the path of every file and directory within the install root, relative to it, in lexical order.
+// Symlinks are reported as '<path> -> <target>'
+func (h *Harness) Tree() ([]string, error) {
+	entries := []string{}
+	err := filepath.WalkDir(h.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(h.Dir, path)
+		if err != nil || relPath == "." {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			relPath = fmt.Sprintf("%s -> %s", relPath, target)
+		}
+		entries = append(entries, relPath)
+		return nil
+	})
+	sort.Strings(entries)
+	return entries, err
+}
+
+// Close stops all routed servers, restores HTTP routing, and removes the install root
+func (h *Harness) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, server := range h.servers {
+		server.Close()
+	}
+	utils.HTTPTransport = h.transport
+	github.CacheDir = h.cacheDir
+	if open == h {
+		open = nil
+	}
+	return os.RemoveAll(h.Dir)
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// Key is a GPG key generated for a test, standing in for the key a tool's maintainers sign its assets with
+type Key struct {
+	entity *openpgp.Entity
+}
+
+// NewKey generates a new signing key
+func NewKey() (*Key, error) {
+	entity, err := openpgp.NewEntity("backplane-tools test", "", "test@example.com", &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	return &Key{entity: entity}, nil
+}
+
+// Fingerprint returns the key's fingerprint, as pinned by tools trusting it
+func (k *Key) Fingerprint() string {
+	return utils.Fingerprint(k.entity)
+}
+
+// Sign returns a detached binary signature of the provided contents
+func (k *Key) Sign(contents []byte) ([]byte, error) {
+	var signature bytes.Buffer
+	err := openpgp.DetachSign(&signature, k.entity, bytes.NewReader(contents), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+	return signature.Bytes(), nil
+}
+
+// Armored returns the armored public key, as published for tools to retrieve
+func (k *Key) Armored() ([]byte, error) {
+	var buf bytes.Buffer
+	armored, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err == nil {
+		err = k.entity.Serialize(armored)
+	}
+	if err == nil {
+		err = armored.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode public key: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,253 @@
+package test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openshift/backplane-tools/pkg/sources/openshift.com/mirror"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// mirrorKeyPath is where MirrorEnv publishes the public key its checksum files are signed with
+const mirrorKeyPath = "/keys/mirror.asc"
+
+// Signatures describes the detached signatures MirrorEnv publishes alongside each sha256sum.txt
+type Signatures int
+
+const (
+	// SignaturesValid signs each checksum file using the environment's key
+	SignaturesValid Signatures = iota
+	// SignaturesInvalid publishes signatures made by the environment's key which don't match the checksum files
+	// they accompany
+	SignaturesInvalid
+	// SignaturesMissing publishes no signatures
+	SignaturesMissing
+)
+
+// MirrorEnv pairs a temporary install root with a local server standing in for mirror.openshift.com. Each
+// directory the server publishes to is given a release.txt and sha256sum.txt, as mirror.openshift.com does, and
+// requests for directories are answered with a listing of their contents. Each sha256sum.txt is signed by a key
+// generated for the environment, with the signature published as both sha256sum.txt.gpg, as the OpenShift clients'
+// are, and sha256sum.txt.sig, as crc's are
+type MirrorEnv struct {
+	// Dir is the temporary directory tools are installed into
+	Dir string
+
+	// Server serves the published files
+	Server *httptest.Server
+
+	mu         sync.Mutex
+	files      map[string][]byte
+	versions   map[string]string
+	key        *Key
+	signatures Signatures
+}
+
+// NewMirrorEnv creates a temporary install root, and starts a server with no published files. Close must be
+// called to stop the server and remove the install root
+func NewMirrorEnv() (*MirrorEnv, error) {
+	dir, err := newInstallRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := NewKey()
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+
+	env := &MirrorEnv{
+		Dir:      dir,
+		files:    map[string][]byte{},
+		versions: map[string]string{},
+		key:      key,
+	}
+	env.Server = httptest.NewServer(http.HandlerFunc(env.serve))
+	return env, nil
+}
+
+// Source returns a mirror source retrieving files from the environment's server
+func (e *MirrorEnv) Source() *mirror.Source {
+	return mirror.NewSourceWithURL(e.Server.URL)
+}
+
+// Use points the provided tool at the environment, so that it retrieves files from the environment's server, and
+// trusts only the environment's key to sign its checksum files
+func (e *MirrorEnv) Use(t *base.Mirror) {
+	t.Source = e.Source()
+	t.KeyURL = e.Server.URL + mirrorKeyPath
+	t.Fingerprints = []string{e.key.Fingerprint()}
+}
+
+// SetSignatures changes the signatures published alongside each sha256sum.txt. Checksum files are validly signed
+// unless this is called
+func (e *MirrorEnv) SetSignatures(signatures Signatures) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.signatures = signatures
+}
+
+// SetVersion publishes a release.txt in the directory at slug, advertising the provided version
+func (e *MirrorEnv) SetVersion(slug, version string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.versions[clean(slug)] = version
+}
+
+// AddFile publishes a file with the provided contents in the directory at slug, and adds its digest to the
+// directory's sha256sum.txt
+func (e *MirrorEnv) AddFile(slug, name string, contents []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.files[path.Join(clean(slug), name)] = contents
+}
+
+// AddArchive publishes a gzip-compressed tarball in the directory at slug, containing the provided files. Each
+// file is keyed by its path within the archive, and archived as an executable
+func (e *MirrorEnv) AddArchive(slug, name string, files map[string][]byte) error {
+	archive, err := TarGz(files)
+	if err != nil {
+		return err
+	}
+	e.AddFile(slug, name, archive)
+	return nil
+}
+
+// Close stops the server and removes the install root
+func (e *MirrorEnv) Close() error {
+	e.Server.Close()
+	return os.RemoveAll(e.Dir)
+}
+
+// serve responds with the requested file, the environment's public key, or the generated release.txt,
+// sha256sum.txt, or checksum signature for a directory
+func (e *MirrorEnv) serve(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	requested := clean(r.URL.Path)
+	if requested == mirrorKeyPath {
+		armored, err := e.key.Armored()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(armored)
+		return
+	}
+	if strings.HasSuffix(r.URL.Path, "/") {
+		e.list(w, r, requested)
+		return
+	}
+	dir, name := path.Split(requested)
+	dir = clean(dir)
+	switch name {
+	case "release.txt":
+		version, found := e.versions[dir]
+		if !found {
+			break
+		}
+		fmt.Fprintf(w, "Client tools for OpenShift\n--------------------------\n\n  Version:  %s\n", version)
+		return
+	case "sha256sum.txt":
+		sums, found := e.checksums(dir)
+		if !found {
+			break
+		}
+		_, _ = w.Write(sums)
+		return
+	case "sha256sum.txt.gpg", "sha256sum.txt.sig":
+		sums, found := e.checksums(dir)
+		if !found || e.signatures == SignaturesMissing {
+			break
+		}
+		if e.signatures == SignaturesInvalid {
+			sums = append(sums, []byte("tampered\n")...)
+		}
+		signature, err := e.key.Sign(sums)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(signature)
+		return
+	}
+
+	contents, found := e.files[requested]
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	_, _ = w.Write(contents)
+}
+
+// checksums returns the contents of the sha256sum.txt published in dir. found is false if no files are published
+// in dir
+func (e *MirrorEnv) checksums(dir string) (sums []byte, found bool) {
+	lines := []string{}
+	for filePath, contents := range e.files {
+		fileDir, fileName := path.Split(filePath)
+		if clean(fileDir) != dir {
+			continue
+		}
+		sum := sha256.Sum256(contents)
+		lines = append(lines, fmt.Sprintf("%s  %s", hex.EncodeToString(sum[:]), fileName))
+	}
+	if len(lines) == 0 {
+		return nil, false
+	}
+	sort.Strings(lines)
+	return []byte(strings.Join(lines, "\n") + "\n"), true
+}
+
+// list responds with an HTML listing of the entries within the directory at dir
+func (e *MirrorEnv) list(w http.ResponseWriter, r *http.Request, dir string) {
+	published := []string{}
+	for filePath := range e.files {
+		published = append(published, filePath, path.Join(path.Dir(filePath), "sha256sum.txt"))
+	}
+	for versionDir := range e.versions {
+		published = append(published, path.Join(versionDir, "release.txt"))
+	}
+
+	entries := []string{}
+	for _, filePath := range published {
+		rel, found := strings.CutPrefix(filePath, strings.TrimSuffix(dir, "/")+"/")
+		if !found {
+			continue
+		}
+		entry, _, isDir := strings.Cut(rel, "/")
+		if isDir {
+			entry += "/"
+		}
+		if !utils.Contains(entries, entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	sort.Strings(entries)
+	fmt.Fprintln(w, "<html><body><table>")
+	fmt.Fprintln(w, `<tr><td><a href="../">Parent Directory</a></td></tr>`)
+	for _, entry := range entries {
+		fmt.Fprintf(w, "<tr><td><a href=\"%s\">%s</a></td></tr>\n", entry, entry)
+	}
+	fmt.Fprintln(w, "</table></body></html>")
+}
+
+// clean normalizes the provided slug, so that equivalent slugs can be compared
+func clean(slug string) string {
+	return path.Clean("/" + slug)
+}
@@ -0,0 +1,126 @@
+/*
+test provides environments for exercising tools against local stand-ins for the services they install from
+*/
+package test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/openshift/backplane-tools/pkg/config"
+	"github.com/openshift/backplane-tools/pkg/tools/base"
+)
+
+// newInstallRoot creates a temporary directory, and installs tools into it
+func newInstallRoot() (string, error) {
+	dir, err := os.MkdirTemp("", "backplane-tools-test-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary install root: %w", err)
+	}
+	base.SetRoot(dir)
+	// The user's configuration shouldn't change how tools behave under test
+	config.Path = filepath.Join(dir, "config.yaml")
+	err = config.Load()
+	if err != nil {
+		return "", err
+	}
+	for _, d := range []string{base.InstallDir, base.LatestDir, base.StateDir} {
+		err = os.MkdirAll(d, os.FileMode(0o755))
+		if err != nil {
+			return "", fmt.Errorf("failed to create directory '%s': %w", d, err)
+		}
+	}
+	return dir, nil
+}
+
+// TarGz returns a gzip-compressed tarball containing the provided files. Each file is keyed by its path
+// within the archive, and archived as an executable
+func TarGz(files map[string][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o755,
+			Size:     int64(len(files[name])),
+			Typeflag: tar.TypeReg,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to write header for '%s': %w", name, err)
+		}
+		_, err = tarWriter.Write(files[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to write '%s': %w", name, err)
+		}
+	}
+	err := tarWriter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to close archive: %w", err)
+	}
+	err = gzipWriter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Zip returns a zip archive containing the provided files. Each file is keyed by its path within the archive, and
+// archived as an executable
+func Zip(files map[string][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate}
+		header.SetMode(0o755)
+		writer, err := zipWriter.CreateHeader(header)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write header for '%s': %w", name, err)
+		}
+		_, err = writer.Write(files[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to write '%s': %w", name, err)
+		}
+	}
+	err := zipWriter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to close archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// SHA256Sums returns a checksum file in the format written by sha256sum, listing the digest of each of the provided
+// files, keyed by name
+func SHA256Sums(files map[string][]byte) []byte {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var buf bytes.Buffer
+	for _, name := range names {
+		sum := sha256.Sum256(files[name])
+		fmt.Fprintf(&buf, "%s  %s\n", hex.EncodeToString(sum[:]), name)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateUnits maps the suffixes accepted by ParseRate to their multiples. Like curl's --limit-rate, units are powers of
+// 1024
+var rateUnits = map[string]int64{
+	"":  1,
+	"K": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+}
+
+// ParseRate parses a download rate in bytes per second, such as '500K' or '2M'. Rates may be suffixed with 'K', 'M',
+// or 'G' to specify kibibytes, mebibytes, or gibibytes. Empty and zero rates are unlimited, and are returned as 0
+func ParseRate(rate string) (int64, error) {
+	rate = strings.TrimSpace(rate)
+	if rate == "" {
+		return 0, nil
+	}
+	number := strings.TrimRight(rate, "kKmMgG")
+	unit := strings.ToUpper(strings.TrimPrefix(rate, number))
+	multiple, found := rateUnits[unit]
+	if !found {
+		return 0, fmt.Errorf("invalid rate '%s': must be a number of bytes per second, optionally suffixed with 'K', 'M', or 'G'", rate)
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid rate '%s': must be a number of bytes per second, optionally suffixed with 'K', 'M', or 'G'", rate)
+	}
+	return int64(value * float64(multiple)), nil
+}
+
+// downloadLimiter caps the combined throughput of every download made by this process
+var downloadLimiter = &rateLimiter{}
+
+// SetDownloadRateLimit caps the combined throughput of every download made by this process to the provided number of
+// bytes per second. Limits of zero or less remove the cap
+func SetDownloadRateLimit(bytesPerSecond int64) {
+	downloadLimiter.setRate(bytesPerSecond)
+}
+
+// rateLimiter delays reads so that, across all readers sharing it, no more than the configured number of bytes are
+// read each second
+type rateLimiter struct {
+	mu             sync.Mutex
+	bytesPerSecond int64
+	// next is when the bytes reserved so far will have been read at the configured rate
+	next time.Time
+}
+
+// setRate replaces the limiter's rate
+func (l *rateLimiter) setRate(bytesPerSecond int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.bytesPerSecond = bytesPerSecond
+	l.next = time.Time{}
+}
+
+// chunkSize returns the most a single read should request, so that throughput is smoothed over each second rather
+// than arriving in bursts. 0 is returned when the limiter is disabled
+func (l *rateLimiter) chunkSize() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.bytesPerSecond <= 0 {
+		return 0
+	}
+	return int(max(l.bytesPerSecond/10, 1024))
+}
+
+// wait blocks until n bytes can be read without exceeding the limiter's rate
+func (l *rateLimiter) wait(n int) {
+	l.mu.Lock()
+	if l.bytesPerSecond <= 0 || n <= 0 {
+		l.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(time.Duration(int64(n) * int64(time.Second) / l.bytesPerSecond))
+	delay := l.next.Sub(now)
+	l.mu.Unlock()
+	time.Sleep(delay)
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package utils
+
+import (
+	"os"
+)
+
+// umask is always empty on Windows, which doesn't apply a file mode creation mask
+var umask = os.FileMode(0)
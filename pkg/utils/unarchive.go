@@ -3,20 +3,125 @@ package utils
 import (
 	"archive/tar"
 	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
 	"compress/gzip"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 )
 
-// Unarchive decompresses and extracts the contents of .tar.gz bundles to the specified destination
+// ArchiveFormat identifies how a file is packaged
+type ArchiveFormat string
+
+const (
+	// ArchiveFormatUnknown indicates the file is not a recognized archive
+	ArchiveFormatUnknown ArchiveFormat = ""
+	// ArchiveFormatZip is a zip archive
+	ArchiveFormatZip ArchiveFormat = "zip"
+	// ArchiveFormatGzip is a gzip-compressed file, typically a tarball
+	ArchiveFormatGzip ArchiveFormat = "gzip"
+	// ArchiveFormatBzip2 is a bzip2-compressed file, typically a tarball
+	ArchiveFormatBzip2 ArchiveFormat = "bzip2"
+	// ArchiveFormatXz is an xz-compressed file, typically a tarball
+	ArchiveFormatXz ArchiveFormat = "xz"
+	// ArchiveFormatZstd is a zstd-compressed file, typically a tarball
+	ArchiveFormatZstd ArchiveFormat = "zstd"
+	// ArchiveFormatTar is an uncompressed tarball
+	ArchiveFormatTar ArchiveFormat = "tar"
+)
+
+// archiveMagic maps the leading bytes of each archive format to the format itself
+var archiveMagic = []struct {
+	magic  []byte
+	format ArchiveFormat
+}{
+	{[]byte("PK\x03\x04"), ArchiveFormatZip},
+	{[]byte{0x1f, 0x8b}, ArchiveFormatGzip},
+	{[]byte("BZh"), ArchiveFormatBzip2},
+	{[]byte{0xfd, '7', 'z', 'X', 'Z', 0x00}, ArchiveFormatXz},
+	{[]byte{0x28, 0xb5, 0x2f, 0xfd}, ArchiveFormatZstd},
+}
+
+// archiveExtensions maps well-known file extensions to the archive format they indicate, for files whose
+// contents can't be recognized
+var archiveExtensions = map[string]ArchiveFormat{
+	".zip":  ArchiveFormatZip,
+	".gz":   ArchiveFormatGzip,
+	".tgz":  ArchiveFormatGzip,
+	".bz2":  ArchiveFormatBzip2,
+	".tbz2": ArchiveFormatBzip2,
+	".xz":   ArchiveFormatXz,
+	".txz":  ArchiveFormatXz,
+	".zst":  ArchiveFormatZstd,
+	".tar":  ArchiveFormatTar,
+}
+
+// tarMagicOffset is the location of the 'ustar' magic string within a tar header
+const tarMagicOffset = 257
+
+// DetectArchiveFormat determines how the file at the provided path is packaged, first by inspecting its
+// contents, then by its extension
+func DetectArchiveFormat(path string) (ArchiveFormat, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return ArchiveFormatUnknown, fmt.Errorf("failed to open '%s': %w", path, err)
+	}
+	defer func() {
+		closeErr := file.Close()
+		if closeErr != nil {
+			fmt.Printf("WARNING: failed to close '%s': %v\n", path, closeErr)
+		}
+	}()
+
+	header := make([]byte, tarMagicOffset+5)
+	n, err := io.ReadFull(file, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return ArchiveFormatUnknown, fmt.Errorf("failed to read '%s': %w", path, err)
+	}
+	header = header[:n]
+	for _, m := range archiveMagic {
+		if bytes.HasPrefix(header, m.magic) {
+			return m.format, nil
+		}
+	}
+	if isTar(header) {
+		return ArchiveFormatTar, nil
+	}
+	return archiveExtensions[strings.ToLower(filepath.Ext(path))], nil
+}
+
+// isTar returns true if the provided bytes begin with a tar header
+func isTar(header []byte) bool {
+	return len(header) >= tarMagicOffset+5 && string(header[tarMagicOffset:tarMagicOffset+5]) == "ustar"
+}
+
+// Unarchive extracts the contents of the archive at source to the specified destination. The archive's format is
+// detected automatically: zip archives, tarballs (uncompressed, or compressed with gzip, bzip2, xz, or zstd), and
+// compressed single files are supported. Compressed single files are decompressed into the destination, named
+// after the archive with its compression extension removed
 func Unarchive(source string, destination string) error {
+	format, err := DetectArchiveFormat(source)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case ArchiveFormatZip:
+		return Unzip(source, destination)
+	case ArchiveFormatUnknown:
+		return fmt.Errorf("failed to determine the archive format of '%s'", source)
+	}
+
 	src, err := os.Open(source)
 	if err != nil {
-		return fmt.Errorf("failed to open tarball '%s': %w", source, err)
+		return fmt.Errorf("failed to open archive '%s': %w", source, err)
 	}
 	defer func() {
 		err = src.Close()
@@ -24,52 +129,201 @@ func Unarchive(source string, destination string) error {
 			fmt.Printf("WARNING: failed to close '%s': %v\n", src.Name(), err)
 		}
 	}()
-	uncompressed, err := gzip.NewReader(src)
+	uncompressed, err := Decompress(format, src)
 	if err != nil {
-		return fmt.Errorf("failed to read the gzip file '%s': %w", source, err)
+		return fmt.Errorf("failed to decompress '%s': %w", source, err)
 	}
 	defer func() {
 		err = uncompressed.Close()
 		if err != nil {
-			fmt.Printf("WARNING: failed to close gzip file '%s': %s", source, err.Error())
+			fmt.Printf("WARNING: failed to close decompressed file '%s': %s\n", source, err.Error())
 		}
 	}()
-	arc := tar.NewReader(uncompressed)
-	var f *tar.Header
+
+	// Compressed files may contain a tarball or a single file
+	buffered := bufio.NewReaderSize(uncompressed, tarMagicOffset+5)
+	header, _ := buffered.Peek(tarMagicOffset + 5)
+	if !isTar(header) {
+		name := strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
+		err = os.MkdirAll(destination, os.FileMode(0o755))
+		if err != nil {
+			return fmt.Errorf("failed to create destination '%s': %w", destination, err)
+		}
+		return WriteFile(buffered, filepath.Join(destination, name), ExecutableMode)
+	}
+	return untar(source, tar.NewReader(buffered), destination)
+}
+
+// Decompress returns a reader producing the decompressed contents of src, which is compressed using the given format
+func Decompress(format ArchiveFormat, src io.Reader) (io.ReadCloser, error) {
+	switch format {
+	case ArchiveFormatTar:
+		return io.NopCloser(src), nil
+	case ArchiveFormatGzip:
+		return gzip.NewReader(src)
+	case ArchiveFormatBzip2:
+		return io.NopCloser(bzip2.NewReader(src)), nil
+	case ArchiveFormatXz:
+		return execDecompressor("xz", src)
+	case ArchiveFormatZstd:
+		return execDecompressor("zstd", src)
+	default:
+		return nil, fmt.Errorf("unsupported archive format '%s'", format)
+	}
+}
+
+// execDecompressor decompresses src using the named program, which must be installed on the local system
+func execDecompressor(program string, src io.Reader) (io.ReadCloser, error) {
+	path, err := exec.LookPath(program)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' must be installed to decompress this file: %w", program, err)
+	}
+	cmd := exec.Command(path, "-d", "-c")
+	cmd.Stdin = src
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	err = cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run '%s': %w", program, err)
+	}
+	return &cmdReader{ReadCloser: stdout, cmd: cmd}, nil
+}
+
+// cmdReader reads the output of a running command, waiting for the command to exit when closed
+type cmdReader struct {
+	io.ReadCloser
+	cmd *exec.Cmd
+}
+
+func (c *cmdReader) Close() error {
+	// Drain any unread output, so the command isn't blocked writing to a closed pipe
+	_, _ = io.Copy(io.Discard, c.ReadCloser)
+	return c.cmd.Wait()
+}
+
+// untar extracts the contents of the tarball read from arc to the specified destination. Directories, regular
+// files, symlinks, and hardlinks are supported, and each entry's permissions and modification time are preserved
+func untar(source string, arc *tar.Reader, destination string) error {
+	// Directory metadata is applied once extraction completes, so that read-only directories can still be
+	// populated, and their modification times aren't updated by the entries extracted into them
+	dirs := []*tar.Header{}
 	for {
-		f, err = arc.Next()
+		f, err := arc.Next()
 		if errors.Is(err, io.EOF) {
 			break
 		}
 		if err != nil {
 			return fmt.Errorf("failed to read from archive '%s': %w", source, err)
 		}
-		if f.FileInfo().IsDir() {
-			err = os.MkdirAll(filepath.Join(destination, f.Name), f.FileInfo().Mode())
+		path, err := SafeJoin(destination, f.Name)
+		if err != nil {
+			return fmt.Errorf("refusing to extract archive '%s': %w", source, err)
+		}
+
+		if f.Typeflag == tar.TypeDir {
+			// Existing directories may be read-only from a previous extraction
+			err = os.MkdirAll(path, os.FileMode(0o755))
+			if err == nil {
+				err = os.Chmod(path, os.FileMode(0o755))
+			}
 			if err != nil {
 				return fmt.Errorf("failed to create a directory : %w", err)
 			}
-		} else {
-			// Sometimes tarballs don't include dir entries for their subdirectories
-			// (looking at you, gcloud).
-			// We need to make these manually, otherwise the extractFile function attempts to
-			// create a file with '/' in it's name, which causes an error
-			if strings.Contains(f.Name, "/") {
-				fileSubDir := filepath.Dir(f.Name)
-				fileSubDirPath := filepath.Join(destination, fileSubDir)
-				err = os.MkdirAll(fileSubDirPath, os.FileMode(0o755))
-			}
+			dirs = append(dirs, f)
+			continue
+		}
 
-			err = extractFile(destination, f, arc)
-			if err != nil {
-				return fmt.Errorf("failed to extract files: %w", err)
-			}
+		// Sometimes tarballs don't include dir entries for their subdirectories
+		// (looking at you, gcloud).
+		// We need to make these manually, otherwise the extractFile function attempts to
+		// create a file with '/' in it's name, which causes an error
+		err = os.MkdirAll(filepath.Dir(path), os.FileMode(0o755))
+		if err != nil {
+			return fmt.Errorf("failed to create a directory : %w", err)
+		}
+
+		switch f.Typeflag {
+		case tar.TypeReg:
+			err = extractFile(path, f, arc)
+		case tar.TypeSymlink:
+			err = extractSymlink(destination, path, f.Linkname)
+		case tar.TypeLink:
+			err = extractHardlink(destination, path, f.Linkname)
+		default:
+			fmt.Printf("WARNING: skipping unsupported entry '%s' in archive '%s'\n", f.Name, source)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to extract files: %w", err)
+		}
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		path, _ := SafeJoin(destination, dirs[i].Name)
+		err := applyMetadata(path, dirs[i].FileInfo().Mode(), dirs[i].ModTime)
+		if err != nil {
+			return fmt.Errorf("failed to extract files: %w", err)
 		}
 	}
 	return nil
 }
 
-// Unzip extracts files from a zip archive to the specified destination directory.
+// extractSymlink creates a symlink at path referring to target. Symlinks which would resolve to a location outside
+// of the destination are rejected, since subsequent entries could otherwise be written through them. The target is
+// resolved from the link's directory, following any symlinks already extracted, as it would be when the link is used
+func extractSymlink(destination, path, target string) error {
+	if filepath.IsAbs(target) || strings.HasPrefix(target, "/") || strings.HasPrefix(target, "\\") {
+		return fmt.Errorf("symlink '%s' refers to '%s', which is outside of '%s'", path, target, destination)
+	}
+	within, err := withinDestination(destination, filepath.Dir(path)+string(os.PathSeparator)+target)
+	if err != nil {
+		return err
+	}
+	if !within {
+		return fmt.Errorf("symlink '%s' refers to '%s', which is outside of '%s'", path, target, destination)
+	}
+	err = os.RemoveAll(path)
+	if err != nil {
+		return fmt.Errorf("failed to replace '%s': %w", path, err)
+	}
+	return os.Symlink(target, path)
+}
+
+// extractHardlink creates a hardlink at path to the previously extracted entry named target
+func extractHardlink(destination, path, target string) error {
+	targetPath, err := SafeJoin(destination, target)
+	if err != nil {
+		return err
+	}
+	err = os.RemoveAll(path)
+	if err != nil {
+		return fmt.Errorf("failed to replace '%s': %w", path, err)
+	}
+	return os.Link(targetPath, path)
+}
+
+// applyMetadata sets the permissions and modification time of the file at the provided path. Rather than being
+// trusted, the permissions recorded in the archive are limited by FileMode
+func applyMetadata(path string, mode os.FileMode, modTime time.Time) error {
+	err := os.Chmod(path, FileMode(mode))
+	if err != nil {
+		return fmt.Errorf("failed to set permissions on '%s': %w", path, err)
+	}
+	if modTime.IsZero() {
+		return nil
+	}
+	err = os.Chtimes(path, modTime, modTime)
+	if err != nil {
+		return fmt.Errorf("failed to set modification time on '%s': %w", path, err)
+	}
+	return nil
+}
+
+// Unzip extracts files from a zip archive to the specified destination directory. Symlinks, permissions,
+// and modification times are preserved
 func Unzip(source string, destination string) error {
 	// Open the zip archive for reading
 	reader, err := zip.OpenReader(source)
@@ -89,14 +343,22 @@ func Unzip(source string, destination string) error {
 	}
 
 	// Extract each file from the zip archive
+	dirs := []*zip.File{}
 	for _, file := range reader.File {
-		filePath := filepath.Join(destination, file.Name)
+		filePath, err := SafeJoin(destination, file.Name)
+		if err != nil {
+			return fmt.Errorf("refusing to extract archive '%s': %w", source, err)
+		}
 		if file.FileInfo().IsDir() {
-			// Create the directory if it doesn't exist
+			// Create the directory if it doesn't exist. Existing directories may be read-only from a previous extraction
 			err := os.MkdirAll(filePath, os.ModePerm)
+			if err == nil {
+				err = os.Chmod(filePath, os.FileMode(0o755))
+			}
 			if err != nil {
 				return err
 			}
+			dirs = append(dirs, file)
 			continue
 		}
 
@@ -105,28 +367,160 @@ func Unzip(source string, destination string) error {
 			return err
 		}
 
-		// Open the file inside the zip archive
-		inputFile, err := file.Open()
+		err = extractZipFile(destination, filePath, file)
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to extract '%s' from '%s': %w", file.Name, source, err)
 		}
+	}
 
-		// Create the output file
-		outputFile, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.Mode())
+	for i := len(dirs) - 1; i >= 0; i-- {
+		path, _ := SafeJoin(destination, dirs[i].Name)
+		err = applyMetadata(path, dirs[i].Mode(), dirs[i].Modified)
 		if err != nil {
 			return err
 		}
+	}
+	return nil
+}
 
-		// Copy the contents from the input file to the output file
-		if _, err := io.Copy(outputFile, inputFile); err != nil {
+// extractZipFile writes the provided file from a zip archive to filePath
+func extractZipFile(destination, filePath string, file *zip.File) error {
+	// Open the file inside the zip archive
+	inputFile, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := inputFile.Close()
+		if closeErr != nil {
+			fmt.Fprintf(os.Stderr, "failed to close '%s': %v\n", file.Name, closeErr)
+		}
+	}()
+
+	// Symlinks store their target as the file's contents
+	if file.Mode()&os.ModeSymlink != 0 {
+		target, err := io.ReadAll(inputFile)
+		if err != nil {
 			return err
 		}
+		return extractSymlink(destination, filePath, string(target))
 	}
 
-	return nil
+	err = os.RemoveAll(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to replace '%s': %w", filePath, err)
+	}
+	err = WriteFile(inputFile, filePath, file.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	return applyMetadata(filePath, file.Mode(), file.Modified)
 }
 
-func extractFile(destination string, f *tar.Header, arc io.Reader) error {
-	path := filepath.Join(destination, f.Name)
-	return WriteFile(arc, path, os.FileMode(f.Mode))
+// extractFile writes the current entry in the tarball to the provided path
+func extractFile(path string, f *tar.Header, arc io.Reader) error {
+	err := os.RemoveAll(path)
+	if err != nil {
+		return fmt.Errorf("failed to replace '%s': %w", path, err)
+	}
+	err = WriteFile(arc, path, f.FileInfo().Mode().Perm())
+	if err != nil {
+		return err
+	}
+	return applyMetadata(path, f.FileInfo().Mode(), f.ModTime)
+}
+
+// SafeJoin joins an archive entry's name onto the destination directory it is being extracted to. An error is
+// returned if the name is absolute, or would otherwise resolve to a location outside of the destination - including
+// by way of symlinks already present within the destination
+func SafeJoin(destination, name string) (string, error) {
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") || strings.HasPrefix(name, "\\") {
+		return "", fmt.Errorf("entry '%s' has an absolute path", name)
+	}
+	path := filepath.Join(destination, name)
+	rel, err := filepath.Rel(destination, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return "", fmt.Errorf("entry '%s' would be extracted outside of '%s'", name, destination)
+	}
+	within, err := withinDestination(destination, path)
+	if err != nil {
+		return "", err
+	}
+	if !within {
+		return "", fmt.Errorf("entry '%s' would be extracted outside of '%s' through a symlink", name, destination)
+	}
+	return path, nil
+}
+
+// maxSymlinks bounds how many symlinks are followed while resolving a path, so that cycles are rejected
+const maxSymlinks = 255
+
+// withinDestination returns true if path is located within destination once the symlinks present on disk are
+// followed
+func withinDestination(destination, path string) (bool, error) {
+	root, err := resolvePath(destination)
+	if err != nil {
+		return false, err
+	}
+	resolved, err := resolvePath(path)
+	if err != nil {
+		return false, err
+	}
+	rel, err := filepath.Rel(root, resolved)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator)), nil
+}
+
+// resolvePath returns the location path refers to, following each symlink along it the way the operating system
+// would. Unlike filepath.EvalSymlinks, the path needn't exist: components which don't exist yet are resolved
+// lexically. Crucially, the path is never cleaned before its symlinks are followed, since '..' after a symlink
+// refers to the parent of the symlink's target rather than of the symlink
+func resolvePath(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to determine working directory: %w", err)
+		}
+		path = wd + string(os.PathSeparator) + path
+	}
+	volume := filepath.VolumeName(path)
+	resolved := volume + string(os.PathSeparator)
+	pending := strings.Split(path[len(volume):], string(os.PathSeparator))
+	links := 0
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		switch name {
+		case "", ".":
+			continue
+		case "..":
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+
+		next := filepath.Join(resolved, name)
+		info, err := os.Lstat(next)
+		if err != nil && !errors.Is(err, os.ErrNotExist) && !errors.Is(err, syscall.ENOTDIR) {
+			return "", fmt.Errorf("failed to resolve '%s': %w", path, err)
+		}
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		links++
+		if links > maxSymlinks {
+			return "", fmt.Errorf("failed to resolve '%s': too many levels of symbolic links", path)
+		}
+		target, err := os.Readlink(next)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve '%s': %w", path, err)
+		}
+		if filepath.IsAbs(target) {
+			targetVolume := filepath.VolumeName(target)
+			resolved = targetVolume + string(os.PathSeparator)
+			target = target[len(targetVolume):]
+		}
+		pending = append(strings.Split(target, string(os.PathSeparator)), pending...)
+	}
+	return resolved, nil
 }
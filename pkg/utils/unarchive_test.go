@@ -0,0 +1,337 @@
+package utils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// tarEntry describes an entry written to a test tarball
+type tarEntry struct {
+	name     string
+	typeflag byte
+	linkname string
+	contents string
+}
+
+// writeTar writes a tarball containing the provided entries to a file within dir, returning its path
+func writeTar(t testing.TB, dir string, entries []tarEntry) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Typeflag: e.typeflag, Linkname: e.linkname, Mode: 0o755}
+		if e.typeflag == tar.TypeReg {
+			header.Size = int64(len(e.contents))
+		}
+		if err := w.WriteHeader(header); err != nil {
+			t.Fatalf("failed to write header for '%s': %v", e.name, err)
+		}
+		if _, err := w.Write([]byte(e.contents)); err != nil {
+			t.Fatalf("failed to write contents of '%s': %v", e.name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close tarball: %v", err)
+	}
+	path := filepath.Join(dir, "archive.tar")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write tarball: %v", err)
+	}
+	return path
+}
+
+// zipEntry describes an entry written to a test zip archive
+type zipEntry struct {
+	name     string
+	symlink  bool
+	contents string
+}
+
+// writeZip writes a zip archive containing the provided entries to a file within dir, returning its path
+func writeZip(t testing.TB, dir string, entries []zipEntry) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name, Method: zip.Store}
+		header.SetMode(0o755)
+		if e.symlink {
+			header.SetMode(os.ModeSymlink | 0o777)
+		}
+		f, err := w.CreateHeader(header)
+		if err != nil {
+			t.Fatalf("failed to write header for '%s': %v", e.name, err)
+		}
+		if _, err = f.Write([]byte(e.contents)); err != nil {
+			t.Fatalf("failed to write contents of '%s': %v", e.name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close zip archive: %v", err)
+	}
+	path := filepath.Join(dir, "archive.zip")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write zip archive: %v", err)
+	}
+	return path
+}
+
+// assertContained fails the test if anything other than the destination and the archive was written to root
+func assertContained(t *testing.T, root string) {
+	t.Helper()
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatalf("failed to read '%s': %v", root, err)
+	}
+	for _, entry := range entries {
+		if entry.Name() != "dest" && entry.Name() != "archive.tar" && entry.Name() != "archive.zip" {
+			t.Errorf("'%s' was written outside of the destination", entry.Name())
+		}
+	}
+}
+
+func TestUnarchiveRejectsHostileTarballs(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []tarEntry
+	}{
+		{
+			name:    "parent directory entry",
+			entries: []tarEntry{{name: "../evil", typeflag: tar.TypeReg, contents: "evil"}},
+		},
+		{
+			name:    "nested parent directory entry",
+			entries: []tarEntry{{name: "a/../../evil", typeflag: tar.TypeReg, contents: "evil"}},
+		},
+		{
+			name:    "absolute entry",
+			entries: []tarEntry{{name: "/evil", typeflag: tar.TypeReg, contents: "evil"}},
+		},
+		{
+			name:    "symlink to parent directory",
+			entries: []tarEntry{{name: "s", typeflag: tar.TypeSymlink, linkname: ".."}},
+		},
+		{
+			name:    "absolute symlink",
+			entries: []tarEntry{{name: "s", typeflag: tar.TypeSymlink, linkname: "/"}},
+		},
+		{
+			name: "symlink chain",
+			entries: []tarEntry{
+				{name: "s", typeflag: tar.TypeSymlink, linkname: "."},
+				{name: "s/t", typeflag: tar.TypeSymlink, linkname: ".."},
+				{name: "t/evil", typeflag: tar.TypeReg, contents: "evil"},
+			},
+		},
+		{
+			name: "symlink redirected by a later entry",
+			entries: []tarEntry{
+				{name: "y/", typeflag: tar.TypeDir},
+				{name: "b", typeflag: tar.TypeSymlink, linkname: "y/.."},
+				{name: "y", typeflag: tar.TypeSymlink, linkname: "."},
+				{name: "b/evil", typeflag: tar.TypeReg, contents: "evil"},
+			},
+		},
+		{
+			name: "hardlink to parent directory",
+			entries: []tarEntry{
+				{name: "h", typeflag: tar.TypeLink, linkname: "../archive.tar"},
+			},
+		},
+		{
+			name: "absolute hardlink",
+			entries: []tarEntry{
+				{name: "h", typeflag: tar.TypeLink, linkname: "/etc/passwd"},
+			},
+		},
+		{
+			name: "hardlink through symlink",
+			entries: []tarEntry{
+				{name: "s", typeflag: tar.TypeSymlink, linkname: "."},
+				{name: "s/t", typeflag: tar.TypeSymlink, linkname: ".."},
+				{name: "h", typeflag: tar.TypeLink, linkname: "t/archive.tar"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			archive := writeTar(t, root, tt.entries)
+			err := Unarchive(archive, filepath.Join(root, "dest"))
+			if err == nil {
+				t.Errorf("expected extraction to fail")
+			}
+			assertContained(t, root)
+		})
+	}
+}
+
+func TestUnarchiveRejectsHostileZips(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []zipEntry
+	}{
+		{
+			name:    "parent directory entry",
+			entries: []zipEntry{{name: "../evil", contents: "evil"}},
+		},
+		{
+			name:    "absolute entry",
+			entries: []zipEntry{{name: "/evil", contents: "evil"}},
+		},
+		{
+			name: "symlink chain",
+			entries: []zipEntry{
+				{name: "s", symlink: true, contents: "."},
+				{name: "s/t", symlink: true, contents: ".."},
+				{name: "t/evil", contents: "evil"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			archive := writeZip(t, root, tt.entries)
+			err := Unarchive(archive, filepath.Join(root, "dest"))
+			if err == nil {
+				t.Errorf("expected extraction to fail")
+			}
+			assertContained(t, root)
+		})
+	}
+}
+
+func TestUnarchiveExtractsContainedLinks(t *testing.T) {
+	root := t.TempDir()
+	archive := writeTar(t, root, []tarEntry{
+		{name: "dist/bin/", typeflag: tar.TypeDir},
+		{name: "dist/bin/tool", typeflag: tar.TypeReg, contents: "tool"},
+		{name: "dist/current", typeflag: tar.TypeSymlink, linkname: "bin"},
+		{name: "dist/alias", typeflag: tar.TypeSymlink, linkname: "current/../bin/tool"},
+		{name: "dist/hardlink", typeflag: tar.TypeLink, linkname: "dist/bin/tool"},
+	})
+	dest := filepath.Join(root, "dest")
+	err := Unarchive(archive, dest)
+	if err != nil {
+		t.Fatalf("failed to extract archive: %v", err)
+	}
+	for _, name := range []string{"dist/current/tool", "dist/alias", "dist/hardlink"} {
+		contents, err := os.ReadFile(filepath.Join(dest, name))
+		if err != nil {
+			t.Errorf("failed to read '%s': %v", name, err)
+			continue
+		}
+		if string(contents) != "tool" {
+			t.Errorf("'%s' contains '%s', expected 'tool'", name, contents)
+		}
+	}
+}
+
+func FuzzDetectArchiveFormat(f *testing.F) {
+	f.Add([]byte("PK\x03\x04"), "tool.zip")
+	f.Add([]byte{0x1f, 0x8b, 0x08}, "tool")
+	f.Add([]byte("#!/bin/sh"), "tool.tar.xz")
+	f.Fuzz(func(t *testing.T, contents []byte, name string) {
+		// Names are only used for their extension: path separators would place the file elsewhere
+		if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+			return
+		}
+		path := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(path, contents, 0o644); err != nil {
+			return
+		}
+		format, err := DetectArchiveFormat(path)
+		if err != nil {
+			t.Fatalf("failed to detect format of '%s': %v", name, err)
+		}
+		if isTar(contents) && format == ArchiveFormatUnknown {
+			t.Errorf("failed to detect tarball '%s'", name)
+		}
+	})
+}
+
+// syntheticEntries returns the entries of a large synthetic archive, laid out like the SDKs installed by
+// backplane-tools: many small files alongside a few large executables
+func syntheticEntries() []tarEntry {
+	entries := []tarEntry{{name: "sdk/bin/", typeflag: tar.TypeDir}, {name: "sdk/lib/", typeflag: tar.TypeDir}}
+	for i := 0; i < 2; i++ {
+		entries = append(entries, tarEntry{name: fmt.Sprintf("sdk/bin/tool-%d", i), typeflag: tar.TypeReg, contents: string(syntheticData(16<<20, int64(i)))})
+	}
+	for i := 0; i < 1000; i++ {
+		entries = append(entries, tarEntry{name: fmt.Sprintf("sdk/lib/module-%d.py", i), typeflag: tar.TypeReg, contents: string(syntheticData(16<<10, int64(i)))})
+	}
+	entries = append(entries, tarEntry{name: "sdk/current", typeflag: tar.TypeSymlink, linkname: "bin"})
+	return entries
+}
+
+// archiveSize returns the total size of the files within the provided entries
+func archiveSize(entries []tarEntry) int64 {
+	size := int64(0)
+	for _, e := range entries {
+		size += int64(len(e.contents))
+	}
+	return size
+}
+
+// gzipFile compresses the file at path, returning the path of the compressed file
+func gzipFile(tb testing.TB, path string) string {
+	tb.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("failed to read '%s': %v", path, err)
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err = w.Write(data); err != nil {
+		tb.Fatalf("failed to compress '%s': %v", path, err)
+	}
+	if err = w.Close(); err != nil {
+		tb.Fatalf("failed to compress '%s': %v", path, err)
+	}
+	compressed := path + ".gz"
+	if err = os.WriteFile(compressed, buf.Bytes(), 0o644); err != nil {
+		tb.Fatalf("failed to write '%s': %v", compressed, err)
+	}
+	return compressed
+}
+
+func BenchmarkUnarchiveTarGz(b *testing.B) {
+	root := b.TempDir()
+	entries := syntheticEntries()
+	archive := gzipFile(b, writeTar(b, root, entries))
+	b.SetBytes(archiveSize(entries))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := Unarchive(archive, filepath.Join(root, fmt.Sprintf("dest-%d", i)))
+		if err != nil {
+			b.Fatalf("failed to extract archive: %v", err)
+		}
+	}
+}
+
+func BenchmarkUnzip(b *testing.B) {
+	root := b.TempDir()
+	zipEntries := []zipEntry{}
+	entries := syntheticEntries()
+	for _, e := range entries {
+		if e.typeflag == tar.TypeReg {
+			zipEntries = append(zipEntries, zipEntry{name: e.name, contents: e.contents})
+		}
+	}
+	archive := writeZip(b, root, zipEntries)
+	b.SetBytes(archiveSize(entries))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := Unzip(archive, filepath.Join(root, fmt.Sprintf("dest-%d", i)))
+		if err != nil {
+			b.Fatalf("failed to extract archive: %v", err)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"regexp"
 	"runtime"
 	"strings"
@@ -121,7 +122,8 @@ func GetLineInReader(reader io.Reader, match string) (res string, err error) {
 	return "", fmt.Errorf("failed to find matching line for search pattern: '%s'", match)
 }
 
-// WriteFile creates a new file using the contents provided ('from') at the path provided ('to')
+// WriteFile creates a new file using the contents provided ('from') at the path provided ('to'). The file is given
+// the permissions determined by FileMode for those requested
 func WriteFile(from io.Reader, to string, permissions os.FileMode) error {
 	file, err := os.Create(to)
 	if err != nil {
@@ -134,7 +136,7 @@ func WriteFile(from io.Reader, to string, permissions os.FileMode) error {
 		}
 	}()
 
-	err = file.Chmod(permissions)
+	err = file.Chmod(FileMode(permissions))
 	if err != nil {
 		return fmt.Errorf("failed to set permissions on file '%s': %w", to, err)
 	}
@@ -147,27 +149,85 @@ func WriteFile(from io.Reader, to string, permissions os.FileMode) error {
 	return nil
 }
 
+// CreateFileIfNotExist writes the provided content to a new file at the given path, creating any missing parent
+// directories. Existing files are left untouched, so that repeated calls are safe. Returns true if the file was created
+func CreateFileIfNotExist(path string, content []byte, permissions os.FileMode) (bool, error) {
+	err := os.MkdirAll(filepath.Dir(path), os.FileMode(0o755))
+	if err != nil {
+		return false, fmt.Errorf("failed to create directory '%s': %w", filepath.Dir(path), err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, permissions)
+	if os.IsExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to create file '%s': %w", path, err)
+	}
+	defer func() {
+		closeErr := file.Close()
+		if closeErr != nil {
+			fmt.Fprintf(os.Stderr, "failed to close file '%s': %v", path, closeErr)
+		}
+	}()
+
+	_, err = file.Write(content)
+	if err != nil {
+		return true, fmt.Errorf("failed to write to file '%s': %w", path, err)
+	}
+	return true, nil
+}
+
+// TargetOS is the operating system tools are installed for. Defaults to the local system's OS, and only
+// differs when preparing tools for another system, such as when creating a bundle
+var TargetOS = runtime.GOOS
+
+// TargetArch is the architecture tools are installed for. Defaults to the local system's architecture, and only
+// differs when preparing tools for another system, such as when creating a bundle
+var TargetArch = runtime.GOARCH
+
 // GetArchAliases returns all commonly used names for the system's architecture.
 // ie - An 'amd64' system is functionally equivalent to 'x86_64' for our purposes
 // An 'arm64' system is functionally equivalent to 'arm' for our purposes (mainly gcloud)
 func GetArchAliases() []string {
-	switch runtime.GOARCH {
+	switch TargetArch {
 	case "amd64":
 		return []string{"amd64", "x86_64"}
 	case "arm64":
 		return []string{"arm64", "arm"}
 	default:
-		return []string{runtime.GOARCH}
+		return []string{TargetArch}
+	}
+}
+
+// GetExactArchAliases returns the names for the system's architecture which unambiguously refer to it. Unlike
+// GetArchAliases, these exclude names also used for other architectures (ie - 'arm' for 32-bit ARM)
+func GetExactArchAliases() []string {
+	switch TargetArch {
+	case "amd64":
+		return []string{"amd64", "x86_64"}
+	case "arm64":
+		return []string{"arm64", "aarch64"}
+	default:
+		return []string{TargetArch}
+	}
+}
+
+// GetUniversalArchAliases returns the names commonly used to denote an asset built for every architecture supported by
+// the system's OS. Only macOS publishes such universal binaries, so no names are returned for other systems
+func GetUniversalArchAliases() []string {
+	if TargetOS != "darwin" {
+		return []string{}
 	}
+	return []string{"universal", "darwin-all", "darwin_all", "macos-all", "macos_all", "mac-all", "mac_all"}
 }
 
 // GetOSAliases returns all commonly used names for the system's OS.
 // ie - A system running 'darwin' is functionally equivalent to 'mac' for our purposes
 func GetOSAliases() []string {
-	switch runtime.GOOS {
+	switch TargetOS {
 	case "darwin":
 		return []string{"darwin", "mac"}
 	default:
-		return []string{runtime.GOOS}
+		return []string{TargetOS}
 	}
 }
@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func BenchmarkWriteFile(b *testing.B) {
+	dir := b.TempDir()
+	data := syntheticData(64<<20, 1)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := WriteFile(bytes.NewReader(data), filepath.Join(dir, "asset"), DataMode); err != nil {
+			b.Fatalf("failed to write file: %v", err)
+		}
+	}
+}
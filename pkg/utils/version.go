@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionPattern matches version strings consisting of dot-separated numbers, optionally prefixed by 'v' and
+// followed by a pre-release or build suffix (ie - "4.15.3", "v1.2.0-rc.1")
+var versionPattern = regexp.MustCompile(`^v?\d+(\.\d+)*([-+].*)?$`)
+
+// IsVersion returns true if the provided string is formatted as a version
+func IsVersion(version string) bool {
+	return versionPattern.MatchString(version)
+}
+
+// CompareVersions compares two version strings numerically, returning -1 if a is older than b, 1 if a is newer than
+// b, and 0 if they are equivalent. A leading 'v' and any build metadata are ignored, and pre-release versions are
+// older than the release they precede. Strings which aren't versions are compared lexicographically
+func CompareVersions(a, b string) int {
+	aRelease, aPre := splitVersion(a)
+	bRelease, bPre := splitVersion(b)
+	aParts := strings.Split(aRelease, ".")
+	bParts := strings.Split(bRelease, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		aPart, bPart := "0", "0"
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+		if c := compareParts(aPart, bPart); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	aParts = strings.Split(aPre, ".")
+	bParts = strings.Split(bPre, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		if c := compareParts(aParts[i], bParts[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(aParts), len(bParts))
+}
+
+// MajorVersion returns the major component of the provided version, ignoring any leading 'v' (ie - "1" for "v1.2.0")
+func MajorVersion(version string) string {
+	release, _ := splitVersion(version)
+	major, _, _ := strings.Cut(release, ".")
+	return major
+}
+
+// IsPrerelease returns true if the provided version has a pre-release suffix (ie - "v1.2.0-rc.1")
+func IsPrerelease(version string) bool {
+	_, pre := splitVersion(version)
+	return pre != ""
+}
+
+// splitVersion separates a version into its release and pre-release components, discarding any build metadata
+func splitVersion(version string) (string, string) {
+	version = strings.TrimPrefix(version, "v")
+	version, _, _ = strings.Cut(version, "+")
+	release, pre, _ := strings.Cut(version, "-")
+	return release, pre
+}
+
+// compareParts compares a single component of two versions, numerically if both are numbers
+func compareParts(a, b string) int {
+	aNum, aErr := strconv.Atoi(a)
+	bNum, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareInts(aNum, bNum)
+	case aErr == nil:
+		// Numeric identifiers are older than alphanumeric ones
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,21 @@
+package utils
+
+import "testing"
+
+func FuzzCompareVersions(f *testing.F) {
+	f.Add("4.15.3", "4.15.10")
+	f.Add("v1.2.0-rc.1", "1.2.0")
+	f.Add("1.2.0+build.1", "1.2.0")
+	f.Add("1.0.0-alpha", "1.0.0-alpha.1")
+	f.Add("latest", "1.0")
+	f.Fuzz(func(t *testing.T, a, b string) {
+		if c := CompareVersions(a, a); c != 0 {
+			t.Errorf("'%s' compares as %d to itself, expected 0", a, c)
+		}
+		if ab, ba := CompareVersions(a, b), CompareVersions(b, a); ab != -ba {
+			t.Errorf("'%s' compares as %d to '%s', but '%s' compares as %d to '%s'", a, ab, b, b, ba, a)
+		}
+		_ = IsVersion(a)
+		_ = MajorVersion(a)
+	})
+}
@@ -0,0 +1,299 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/zlib"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// xarMagic begins every xar archive: 'xar!'
+	xarMagic = 0x78617221
+
+	// pbzxMagic begins payloads compressed as a series of xz chunks, which are not supported
+	pbzxMagic = "pbzx"
+)
+
+// pkgExpandedEntries names the entries of a macOS installer package which are cpio archives, expanded into a
+// directory of the same name by ExpandPkg
+var pkgExpandedEntries = []string{"Payload", "Scripts"}
+
+// xarHeader is the fixed-size header beginning every xar archive. All fields are big-endian
+type xarHeader struct {
+	Magic                 uint32
+	Size                  uint16
+	Version               uint16
+	TOCLengthCompressed   uint64
+	TOCLengthUncompressed uint64
+	ChecksumAlgorithm     uint32
+}
+
+// xarTOC is the table of contents of a xar archive, describing each file it contains
+type xarTOC struct {
+	Files []xarFile `xml:"toc>file"`
+}
+
+// xarFile describes a file, directory, or symlink within a xar archive
+type xarFile struct {
+	Name  string    `xml:"name"`
+	Type  string    `xml:"type"`
+	Mode  string    `xml:"mode"`
+	Link  string    `xml:"link"`
+	Data  *xarData  `xml:"data"`
+	Files []xarFile `xml:"file"`
+}
+
+// xarData locates a file's contents within the heap following the table of contents
+type xarData struct {
+	Offset            int64       `xml:"offset"`
+	Length            int64       `xml:"length"`
+	Size              int64       `xml:"size"`
+	Encoding          xarEncoding `xml:"encoding"`
+	ExtractedChecksum xarChecksum `xml:"extracted-checksum"`
+}
+
+// xarEncoding describes how a file's contents are compressed within the heap
+type xarEncoding struct {
+	Style string `xml:"style,attr"`
+}
+
+// xarChecksum is the hex-encoded checksum of a file's contents, computed using the named algorithm
+type xarChecksum struct {
+	Style string `xml:"style,attr"`
+	Value string `xml:",chardata"`
+}
+
+// ExpandPkg expands the macOS flat installer package (a xar archive) at source into the specified destination, like
+// 'pkgutil --expand-full': each file in the package is extracted, and the cpio archives within it - such as each
+// component's Payload - are themselves extracted into a directory of the same name
+func ExpandPkg(source, destination string) error {
+	file, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %w", source, err)
+	}
+	defer func() {
+		closeErr := file.Close()
+		if closeErr != nil {
+			fmt.Fprintf(os.Stderr, "failed to close '%s': %v\n", source, closeErr)
+		}
+	}()
+
+	toc, heapOffset, err := readXarTOC(file)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s': %w", source, err)
+	}
+	err = os.MkdirAll(destination, os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create directory '%s': %w", destination, err)
+	}
+	return expandXarFiles(source, file, heapOffset, toc.Files, "", destination)
+}
+
+// readXarTOC reads the header and table of contents of the provided xar archive, returning the table of contents
+// along with the offset of the heap containing each file's contents
+func readXarTOC(file *os.File) (xarTOC, int64, error) {
+	header := xarHeader{}
+	err := binary.Read(file, binary.BigEndian, &header)
+	if err != nil {
+		return xarTOC{}, 0, fmt.Errorf("failed to read xar header: %w", err)
+	}
+	if header.Magic != xarMagic {
+		return xarTOC{}, 0, fmt.Errorf("not a xar archive")
+	}
+
+	compressed := io.NewSectionReader(file, int64(header.Size), int64(header.TOCLengthCompressed))
+	decompressed, err := zlib.NewReader(compressed)
+	if err != nil {
+		return xarTOC{}, 0, fmt.Errorf("failed to decompress xar table of contents: %w", err)
+	}
+	toc := xarTOC{}
+	err = xml.NewDecoder(io.LimitReader(decompressed, int64(header.TOCLengthUncompressed))).Decode(&toc)
+	if err != nil {
+		return xarTOC{}, 0, fmt.Errorf("failed to parse xar table of contents: %w", err)
+	}
+	return toc, int64(header.Size) + int64(header.TOCLengthCompressed), nil
+}
+
+// expandXarFiles extracts the provided files, found at prefix within the archive, into the destination
+func expandXarFiles(source string, file *os.File, heapOffset int64, files []xarFile, prefix, destination string) error {
+	for _, f := range files {
+		name := path.Join(prefix, f.Name)
+		entryPath, err := SafeJoin(destination, name)
+		if err != nil {
+			return fmt.Errorf("refusing to extract archive '%s': %w", source, err)
+		}
+
+		switch f.Type {
+		case "directory":
+			err = os.MkdirAll(entryPath, os.FileMode(0o755))
+			if err != nil {
+				return fmt.Errorf("failed to create a directory : %w", err)
+			}
+			err = expandXarFiles(source, file, heapOffset, f.Files, name, destination)
+		case "symlink":
+			err = extractSymlink(destination, entryPath, f.Link)
+		case "file":
+			err = expandXarFile(source, file, heapOffset, f, entryPath)
+		default:
+			fmt.Printf("WARNING: skipping unsupported entry '%s' in archive '%s'\n", name, source)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to extract '%s' from '%s': %w", name, source, err)
+		}
+	}
+	return nil
+}
+
+// expandXarFile extracts the provided file to the given path. Files which are cpio archives within an installer
+// package are extracted into a directory at the path instead
+func expandXarFile(source string, file *os.File, heapOffset int64, f xarFile, entryPath string) error {
+	if f.Data == nil {
+		return WriteFile(bytes.NewReader(nil), entryPath, xarFileMode(f.Mode))
+	}
+	contents, err := decodeXarData(io.NewSectionReader(file, heapOffset+f.Data.Offset, f.Data.Length), f.Data.Encoding.Style)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := contents.Close()
+		if closeErr != nil {
+			fmt.Fprintf(os.Stderr, "failed to close '%s': %v\n", f.Name, closeErr)
+		}
+	}()
+	verifier, err := newXarVerifier(contents, f.Data.ExtractedChecksum)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(entryPath), os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create a directory : %w", err)
+	}
+	if Contains(pkgExpandedEntries, f.Name) {
+		err = expandPayload(source, verifier, entryPath)
+	} else {
+		err = WriteFile(verifier, entryPath, xarFileMode(f.Mode))
+	}
+	if err != nil {
+		return err
+	}
+	return verifier.verify()
+}
+
+// decodeXarData returns a reader producing the decompressed contents of a file within a xar archive
+func decodeXarData(r io.Reader, style string) (io.ReadCloser, error) {
+	switch style {
+	case "", "application/octet-stream":
+		return io.NopCloser(r), nil
+	case "application/x-gzip":
+		// xar's 'gzip' encoding is in fact zlib
+		return zlib.NewReader(r)
+	case "application/x-bzip2":
+		return io.NopCloser(bzip2.NewReader(r)), nil
+	default:
+		return nil, fmt.Errorf("unsupported xar encoding '%s'", style)
+	}
+}
+
+// expandPayload extracts the cpio archive read from r, which may be compressed with gzip, into the directory at
+// entryPath
+func expandPayload(source string, r io.Reader, entryPath string) error {
+	buffered := bufio.NewReader(r)
+	magic, err := buffered.Peek(len(pbzxMagic))
+	if err != nil {
+		return fmt.Errorf("failed to read payload '%s': %w", entryPath, err)
+	}
+	if string(magic) == pbzxMagic {
+		return fmt.Errorf("payload '%s' is compressed using pbzx, which is not supported", entryPath)
+	}
+	payload := io.Reader(buffered)
+	if magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := Decompress(ArchiveFormatGzip, buffered)
+		if err != nil {
+			return fmt.Errorf("failed to decompress payload '%s': %w", entryPath, err)
+		}
+		defer func() {
+			_ = gz.Close()
+		}()
+		payload = gz
+	}
+
+	err = os.MkdirAll(entryPath, os.FileMode(0o755))
+	if err != nil {
+		return fmt.Errorf("failed to create a directory : %w", err)
+	}
+	err = Uncpio(fmt.Sprintf("%s:%s", source, filepath.Base(entryPath)), payload, entryPath)
+	if err != nil {
+		return err
+	}
+	// Consume the rest of the payload, so that its checksum can be verified
+	_, err = io.Copy(io.Discard, buffered)
+	return err
+}
+
+// xarFileMode parses the octal mode recorded for a file in a xar archive, defaulting to 0644
+func xarFileMode(mode string) os.FileMode {
+	parsed, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return os.FileMode(0o644)
+	}
+	return os.FileMode(parsed).Perm()
+}
+
+// xarVerifier computes the checksum of the contents read through it, for comparison with the checksum recorded in
+// the archive's table of contents
+type xarVerifier struct {
+	io.Reader
+	hash     hash.Hash
+	expected string
+}
+
+// newXarVerifier returns a reader computing the checksum of the contents read from r. Contents without a recorded
+// checksum aren't verified
+func newXarVerifier(r io.Reader, checksum xarChecksum) (*xarVerifier, error) {
+	var h hash.Hash
+	switch strings.ToLower(checksum.Style) {
+	case "", "none":
+		return &xarVerifier{Reader: r}, nil
+	case "md5":
+		h = md5.New()
+	case "sha1":
+		h = sha1.New()
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return nil, fmt.Errorf("unsupported xar checksum algorithm '%s'", checksum.Style)
+	}
+	return &xarVerifier{Reader: io.TeeReader(r, h), hash: h, expected: strings.TrimSpace(checksum.Value)}, nil
+}
+
+// verify compares the checksum of the contents read to the expected checksum
+func (v *xarVerifier) verify() error {
+	if v.hash == nil {
+		return nil
+	}
+	actual := hex.EncodeToString(v.hash.Sum(nil))
+	if !strings.EqualFold(actual, v.expected) {
+		return fmt.Errorf("%w: expected '%s', got '%s'", ErrChecksumMismatch, v.expected, actual)
+	}
+	return nil
+}
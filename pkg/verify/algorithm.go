@@ -0,0 +1,163 @@
+package verify
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Algorithm identifies the hash function a checksum was calculated with
+type Algorithm string
+
+// The algorithms checksums may be calculated with
+const (
+	SHA256     Algorithm = "sha256"
+	SHA384     Algorithm = "sha384"
+	SHA512     Algorithm = "sha512"
+	BLAKE2b256 Algorithm = "blake2b-256"
+	BLAKE2b512 Algorithm = "blake2b-512"
+)
+
+// defaultAlgorithms maps the length of a hex-encoded checksum to the algorithm assumed when nothing else identifies it.
+// blake2b checksums share their lengths with sha256 and sha512, and are only recognized when labelled as such
+var defaultAlgorithms = map[int]Algorithm{
+	64:  SHA256,
+	96:  SHA384,
+	128: SHA512,
+}
+
+// hexLengths maps each supported algorithm to the length of its hex-encoded checksums
+var hexLengths = map[Algorithm]int{
+	SHA256:     64,
+	SHA384:     96,
+	SHA512:     128,
+	BLAKE2b256: 64,
+	BLAKE2b512: 128,
+}
+
+// hexHash matches a hex-encoded checksum
+var hexHash = regexp.MustCompile(`^[0-9A-Fa-f]+$`)
+
+// ParseAlgorithm returns the algorithm with the provided name, as written in checksum files and digests: 'SHA512' and
+// 'sha-512' both identify SHA512, and 'BLAKE2b' - as written by 'b2sum --tag' - identifies BLAKE2b512
+func ParseAlgorithm(name string) (Algorithm, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.Replace(name, "sha-", "sha", 1)
+	if name == "blake2b" || name == "b2" {
+		return BLAKE2b512, true
+	}
+	_, found := hexLengths[Algorithm(name)]
+	return Algorithm(name), found
+}
+
+// algorithmFromName returns the algorithm suggested by the name of a checksum file, such as 'SHA512SUMS',
+// 'tool.tar.gz.sha384', or 'B2SUMS'. An empty Algorithm is returned if the name doesn't suggest one
+func algorithmFromName(name string) Algorithm {
+	name = strings.ToLower(name)
+	switch {
+	case strings.Contains(name, "sha512"):
+		return SHA512
+	case strings.Contains(name, "sha384"):
+		return SHA384
+	case strings.Contains(name, "sha256"):
+		return SHA256
+	case strings.Contains(name, "b2sum") || strings.Contains(name, "blake2") || strings.HasSuffix(name, ".b2"):
+		return BLAKE2b512
+	}
+	return ""
+}
+
+// parseChecksum returns the algorithm and hex-encoded hash of the provided checksum. Checksums may be labelled with
+// their algorithm, as in 'sha512:<hash>'. Otherwise, the hint is used if it produces checksums of the same length,
+// and the algorithm is determined by the checksum's length if not
+func parseChecksum(checksum string, hint Algorithm) (Algorithm, string, error) {
+	checksum = strings.TrimSpace(checksum)
+	if name, sum, labelled := strings.Cut(checksum, ":"); labelled {
+		algorithm, found := ParseAlgorithm(name)
+		if !found {
+			return "", "", fmt.Errorf("unsupported checksum algorithm '%s'", name)
+		}
+		if len(sum) != hexLengths[algorithm] || !hexHash.MatchString(sum) {
+			return "", "", fmt.Errorf("invalid %s checksum '%s'", algorithm, sum)
+		}
+		return algorithm, sum, nil
+	}
+	if !hexHash.MatchString(checksum) {
+		return "", "", fmt.Errorf("invalid checksum '%s'", checksum)
+	}
+	if hint != "" && hexLengths[hint] == len(checksum) {
+		return hint, checksum, nil
+	}
+	algorithm, found := defaultAlgorithms[len(checksum)]
+	if !found {
+		return "", "", fmt.Errorf("unsupported checksum '%s': checksums must be sha256, sha384, sha512, or blake2b", checksum)
+	}
+	return algorithm, checksum, nil
+}
+
+// isSupportedChecksum returns true if the provided checksum was calculated by a supported algorithm
+func isSupportedChecksum(checksum string) bool {
+	_, _, err := parseChecksum(checksum, "")
+	return err == nil
+}
+
+// newHash returns a hash calculating checksums using the provided algorithm
+func newHash(algorithm Algorithm) (hash.Hash, error) {
+	switch algorithm {
+	case SHA256:
+		return sha256.New(), nil
+	case SHA384:
+		return sha512.New384(), nil
+	case SHA512:
+		return sha512.New(), nil
+	case BLAKE2b256:
+		return blake2b.New256(nil)
+	case BLAKE2b512:
+		return blake2b.New512(nil)
+	}
+	return nil, fmt.Errorf("unsupported checksum algorithm '%s'", algorithm)
+}
+
+// hashFile calculates the checksums of the file at the provided path using each of the provided algorithms, in a
+// single pass over the file
+func hashFile(path string, algorithms []Algorithm) (map[Algorithm]string, error) {
+	hashes := map[Algorithm]hash.Hash{}
+	writers := []io.Writer{}
+	for _, algorithm := range algorithms {
+		if _, found := hashes[algorithm]; found {
+			continue
+		}
+		h, err := newHash(algorithm)
+		if err != nil {
+			return nil, err
+		}
+		hashes[algorithm] = h
+		writers = append(writers, h)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open '%s': %w", path, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	_, err = io.Copy(io.MultiWriter(writers...), file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s': %w", path, err)
+	}
+
+	sums := map[Algorithm]string{}
+	for algorithm, h := range hashes {
+		sums[algorithm] = hex.EncodeToString(h.Sum(nil))
+	}
+	return sums, nil
+}
@@ -0,0 +1,80 @@
+package verify
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ErrNotMachO indicates a file isn't a Mach-O executable, such as a shell script, and so can't carry a code signature
+var ErrNotMachO = errors.New("not a Mach-O executable")
+
+// machOMagics are the leading bytes of thin 32- and 64-bit Mach-O executables, in either byte order, and of
+// universal executables containing several architectures
+var machOMagics = []uint32{0xfeedface, 0xfeedfacf, 0xcefaedfe, 0xcffaedfe, 0xcafebabe}
+
+// notarizedSource is reported by 'spctl' as the source of executables whose notarization has been confirmed
+const notarizedSource = "Notarized Developer ID"
+
+// CodeSignature verifies the macOS code signature of the executable at the provided path, and that Apple has
+// notarized it. Verification relies on the 'codesign' and 'spctl' commands, and so is only possible on macOS.
+// ErrNotMachO is returned if the file isn't a Mach-O executable
+func CodeSignature(path string) error {
+	isMachO, err := isMachO(path)
+	if err != nil {
+		return err
+	}
+	if !isMachO {
+		return ErrNotMachO
+	}
+
+	out, err := exec.Command("codesign", "--verify", "--strict", path).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("invalid code signature: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	// Command line executables aren't apps, so they're assessed as if being opened as a document, against their
+	// primary signature
+	out, err = exec.Command("spctl", "--assess", "--type", "open", "--context", "context:primary-signature", "--verbose", path).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("rejected by Gatekeeper: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	if !strings.Contains(string(out), notarizedSource) {
+		return fmt.Errorf("signed, but not notarized: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// isMachO returns true if the file at the provided path begins with a Mach-O magic number
+func isMachO(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open '%s': %w", path, err)
+	}
+	defer func() {
+		closeErr := file.Close()
+		if closeErr != nil {
+			fmt.Fprintf(os.Stderr, "failed to close '%s': %v\n", path, closeErr)
+		}
+	}()
+
+	header := make([]byte, 4)
+	_, err = io.ReadFull(file, header)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read '%s': %w", path, err)
+	}
+	magic := binary.BigEndian.Uint32(header)
+	for _, m := range machOMagics {
+		if magic == m {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,48 @@
+package verify
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// ObjectHashes verifies that the file at assetPath matches the CRC32C checksum and MD5 hash an object store, such as
+// Google Cloud Storage, published for it. Composite objects have no MD5 hash: if md5Sum is empty, only the CRC32C
+// checksum is compared. Both values are calculated in a single pass over the file
+func ObjectHashes(assetPath string, crc32cSum uint32, md5Sum []byte) error {
+	file, err := os.Open(assetPath)
+	if err != nil {
+		return fmt.Errorf("failed to open '%s': %w", assetPath, err)
+	}
+	defer func() {
+		closeErr := file.Close()
+		if closeErr != nil {
+			fmt.Printf("WARNING: failed to close '%s': %v\n", assetPath, closeErr)
+		}
+	}()
+
+	crc32cHash := crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	md5Hash := md5.New()
+	_, err = io.Copy(io.MultiWriter(crc32cHash, md5Hash), file)
+	if err != nil {
+		return fmt.Errorf("failed to calculate hashes for '%s': %w", assetPath, err)
+	}
+
+	if actual := crc32cHash.Sum32(); actual != crc32cSum {
+		return fmt.Errorf("%w for '%s': expected crc32c checksum '%08x', got '%08x'", utils.ErrChecksumMismatch, filepath.Base(assetPath), crc32cSum, actual)
+	}
+	if len(md5Sum) == 0 {
+		return nil
+	}
+	if actual := md5Hash.Sum(nil); !bytes.Equal(actual, md5Sum) {
+		return fmt.Errorf("%w for '%s': expected md5 hash '%s', got '%s'", utils.ErrChecksumMismatch, filepath.Base(assetPath), hex.EncodeToString(md5Sum), hex.EncodeToString(actual))
+	}
+	return nil
+}
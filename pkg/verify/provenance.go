@@ -0,0 +1,182 @@
+package verify
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// SLSAVerifier is the command used to verify the signatures on provenance attestations
+var SLSAVerifier = "slsa-verifier"
+
+// ErrSLSAVerifierMissing indicates the signatures on provenance attestations can't be verified, because
+// SLSAVerifier isn't installed
+var ErrSLSAVerifierMissing = errors.New("'slsa-verifier' is not installed")
+
+// envelope is a DSSE envelope, as published in SLSA provenance (ie - '*.intoto.jsonl') files
+type envelope struct {
+	PayloadType string            `json:"payloadType"`
+	Payload     string            `json:"payload"`
+	Signatures  []json.RawMessage `json:"signatures"`
+}
+
+// statement is an in-toto attestation statement
+type statement struct {
+	Subject []struct {
+		Name   string            `json:"name"`
+		Digest map[string]string `json:"digest"`
+	} `json:"subject"`
+	PredicateType string    `json:"predicateType"`
+	Predicate     predicate `json:"predicate"`
+}
+
+// predicate contains the fields of SLSA v0.2 and v1 provenance predicates used to identify the source repository
+type predicate struct {
+	// SLSA v0.2
+	Invocation struct {
+		ConfigSource struct {
+			URI string `json:"uri"`
+		} `json:"configSource"`
+	} `json:"invocation"`
+
+	// SLSA v1
+	BuildDefinition struct {
+		ExternalParameters struct {
+			Workflow struct {
+				Repository string `json:"repository"`
+			} `json:"workflow"`
+		} `json:"externalParameters"`
+	} `json:"buildDefinition"`
+}
+
+// sourceURI returns the URI of the repository the predicate claims the artifact was built from
+func (p predicate) sourceURI() string {
+	if p.BuildDefinition.ExternalParameters.Workflow.Repository != "" {
+		return p.BuildDefinition.ExternalParameters.Workflow.Repository
+	}
+	return p.Invocation.ConfigSource.URI
+}
+
+// Provenance verifies that the SLSA provenance file at provenancePath attests to the artifact at assetPath, and
+// that the artifact was built from the provided repository (ie - 'github.com/openshift/osdctl') at the given tag.
+//
+// The contents of the attestation are validated first, then its signatures are verified by SLSAVerifier: it checks
+// that the signing certificate was issued by Sigstore to a trusted SLSA builder, and that the signature was recorded
+// in the Rekor transparency log. ErrSLSAVerifierMissing is returned if SLSAVerifier isn't installed
+func Provenance(assetPath, provenancePath, repository, tag string) error {
+	assetSum, err := utils.Sha256sum(assetPath)
+	if err != nil {
+		return fmt.Errorf("failed to calculate checksum for '%s': %w", assetPath, err)
+	}
+	assetName := filepath.Base(assetPath)
+
+	statements, err := readStatements(provenancePath)
+	if err != nil {
+		return fmt.Errorf("failed to read provenance file '%s': %w", provenancePath, err)
+	}
+
+	for _, stmt := range statements {
+		for _, subject := range stmt.Subject {
+			if subject.Name != assetName {
+				continue
+			}
+			if !strings.EqualFold(subject.Digest["sha256"], strings.TrimSpace(assetSum)) {
+				return fmt.Errorf("%w for '%s': expected provenance digest '%s', got '%s'", utils.ErrChecksumMismatch, assetName, subject.Digest["sha256"], assetSum)
+			}
+			source := normalizeRepository(stmt.Predicate.sourceURI())
+			if !strings.EqualFold(source, normalizeRepository(repository)) {
+				return fmt.Errorf("provenance for '%s' reports it was built from '%s', expected '%s'", assetName, source, repository)
+			}
+			return verifySignatures(assetPath, provenancePath, repository, tag)
+		}
+	}
+	return fmt.Errorf("no provenance found for '%s'", assetName)
+}
+
+// verifySignatures runs SLSAVerifier to verify the signatures on the provenance file at provenancePath against the
+// trusted SLSA builders, and that they attest to the artifact at assetPath being built from repository at tag
+func verifySignatures(assetPath, provenancePath, repository, tag string) error {
+	verifier, err := exec.LookPath(SLSAVerifier)
+	if err != nil {
+		return fmt.Errorf("%w: it's required to verify the signatures on provenance attestations", ErrSLSAVerifierMissing)
+	}
+	args := []string{"verify-artifact", assetPath, "--provenance-path", provenancePath, "--source-uri", normalizeRepository(repository)}
+	if tag != "" {
+		args = append(args, "--source-tag", tag)
+	}
+	out, err := exec.Command(verifier, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("invalid provenance signature: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// readStatements decodes all attestation statements contained in the provided provenance file. Envelopes without
+// signatures are rejected outright; the signatures present are verified by verifySignatures
+func readStatements(path string) ([]statement, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return []statement{}, err
+	}
+	defer func() {
+		closeErr := file.Close()
+		if closeErr != nil {
+			fmt.Fprintf(os.Stderr, "failed to close file '%s': %v\n", path, closeErr)
+		}
+	}()
+
+	statements := []statement{}
+	scanner := bufio.NewScanner(file)
+	// Provenance envelopes are single, potentially very long, lines
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		env := envelope{}
+		err = json.Unmarshal([]byte(line), &env)
+		if err != nil {
+			return []statement{}, fmt.Errorf("failed to decode envelope: %w", err)
+		}
+		if len(env.Signatures) == 0 {
+			return []statement{}, errors.New("envelope is not signed")
+		}
+		payload, err := base64.StdEncoding.DecodeString(env.Payload)
+		if err != nil {
+			return []statement{}, fmt.Errorf("failed to decode envelope payload: %w", err)
+		}
+
+		stmt := statement{}
+		err = json.Unmarshal(payload, &stmt)
+		if err != nil {
+			return []statement{}, fmt.Errorf("failed to decode attestation statement: %w", err)
+		}
+		statements = append(statements, stmt)
+	}
+	if scanner.Err() != nil {
+		return []statement{}, scanner.Err()
+	}
+	return statements, nil
+}
+
+// normalizeRepository reduces the various forms a repository URI may take (ie - 'git+https://github.com/org/repo@refs/tags/v1.0.0')
+// to '<host>/<org>/<repo>'
+func normalizeRepository(uri string) string {
+	uri = strings.TrimPrefix(uri, "git+")
+	if _, after, found := strings.Cut(uri, "://"); found {
+		uri = after
+	}
+	uri, _, _ = strings.Cut(uri, "@")
+	uri = strings.TrimSuffix(uri, ".git")
+	return strings.TrimSuffix(uri, "/")
+}
@@ -0,0 +1,164 @@
+package verify
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// writeProvenance writes a provenance file to dir containing a single envelope, attesting that the named asset with
+// the provided contents was built from repository. The envelope carries the provided signatures
+func writeProvenance(t *testing.T, dir, assetName string, contents []byte, repository string, signatures []json.RawMessage) string {
+	t.Helper()
+	digest := sha256.Sum256(contents)
+	stmt := map[string]any{
+		"subject":       []map[string]any{{"name": assetName, "digest": map[string]string{"sha256": hex.EncodeToString(digest[:])}}},
+		"predicateType": "https://slsa.dev/provenance/v0.2",
+		"predicate":     map[string]any{"invocation": map[string]any{"configSource": map[string]string{"uri": "git+https://" + repository + "@refs/tags/v1.0.0"}}},
+	}
+	payload, err := json.Marshal(stmt)
+	if err != nil {
+		t.Fatalf("failed to encode statement: %v", err)
+	}
+	env, err := json.Marshal(envelope{PayloadType: "application/vnd.in-toto+json", Payload: base64.StdEncoding.EncodeToString(payload), Signatures: signatures})
+	if err != nil {
+		t.Fatalf("failed to encode envelope: %v", err)
+	}
+	path := filepath.Join(dir, "multiple.intoto.jsonl")
+	if err = os.WriteFile(path, append(env, '\n'), 0o644); err != nil {
+		t.Fatalf("failed to write provenance: %v", err)
+	}
+	return path
+}
+
+// fakeVerifier installs a script in place of SLSAVerifier for the duration of the test. The script records its
+// arguments to the returned path, prints output, and exits with the provided status
+func fakeVerifier(t *testing.T, output string, status int) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("slsa-verifier is faked using a shell script")
+	}
+	dir := t.TempDir()
+	argsPath := filepath.Join(dir, "args")
+	script := "#!/bin/sh\necho \"$@\" > '" + argsPath + "'\necho '" + output + "'\nexit " + strconv.Itoa(status) + "\n"
+	verifier := filepath.Join(dir, "slsa-verifier")
+	if err := os.WriteFile(verifier, []byte(script), 0o755); err != nil {
+		t.Fatalf("failed to write fake slsa-verifier: %v", err)
+	}
+	original := SLSAVerifier
+	SLSAVerifier = verifier
+	t.Cleanup(func() { SLSAVerifier = original })
+	return argsPath
+}
+
+func TestProvenance(t *testing.T) {
+	signed := []json.RawMessage{json.RawMessage(`{"keyid":"","sig":"MEUCIQ=="}`)}
+	tests := []struct {
+		name              string
+		signatures        []json.RawMessage
+		attestedContents  string
+		repository        string
+		verifierOutput    string
+		verifierStatus    int
+		missingVerifier   bool
+		expectedErr       error
+		expectedErrText   string
+		expectVerifierRun bool
+	}{
+		{
+			name:              "verified",
+			signatures:        signed,
+			attestedContents:  "tool",
+			repository:        "github.com/openshift/osdctl",
+			verifierOutput:    "PASSED: Verified SLSA provenance",
+			expectVerifierRun: true,
+		},
+		{
+			name:             "unsigned envelope",
+			attestedContents: "tool",
+			repository:       "github.com/openshift/osdctl",
+			expectedErrText:  "envelope is not signed",
+		},
+		{
+			name:             "digest mismatch",
+			signatures:       signed,
+			attestedContents: "tampered",
+			repository:       "github.com/openshift/osdctl",
+			expectedErr:      utils.ErrChecksumMismatch,
+		},
+		{
+			name:             "other repository",
+			signatures:       signed,
+			attestedContents: "tool",
+			repository:       "github.com/attacker/osdctl",
+			expectedErrText:  "reports it was built from 'github.com/attacker/osdctl'",
+		},
+		{
+			name:             "slsa-verifier not installed",
+			signatures:       signed,
+			attestedContents: "tool",
+			repository:       "github.com/openshift/osdctl",
+			missingVerifier:  true,
+			expectedErr:      ErrSLSAVerifierMissing,
+		},
+		{
+			name:              "signature rejected",
+			signatures:        signed,
+			attestedContents:  "tool",
+			repository:        "github.com/openshift/osdctl",
+			verifierOutput:    "FAILED: SLSA verification failed: invalid signature",
+			verifierStatus:    1,
+			expectedErrText:   "invalid signature",
+			expectVerifierRun: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			argsPath := fakeVerifier(t, tt.verifierOutput, tt.verifierStatus)
+			if tt.missingVerifier {
+				SLSAVerifier = filepath.Join(t.TempDir(), "slsa-verifier")
+			}
+			dir := t.TempDir()
+			assetPath := filepath.Join(dir, "osdctl_Linux_x86_64.tar.gz")
+			if err := os.WriteFile(assetPath, []byte("tool"), 0o644); err != nil {
+				t.Fatalf("failed to write asset: %v", err)
+			}
+			provenancePath := writeProvenance(t, dir, filepath.Base(assetPath), []byte(tt.attestedContents), tt.repository, tt.signatures)
+
+			err := Provenance(assetPath, provenancePath, "https://github.com/openshift/osdctl", "v1.0.0")
+			switch {
+			case tt.expectedErr != nil && !errors.Is(err, tt.expectedErr):
+				t.Errorf("expected error '%v', got '%v'", tt.expectedErr, err)
+			case tt.expectedErrText != "" && (err == nil || !strings.Contains(err.Error(), tt.expectedErrText)):
+				t.Errorf("expected error containing '%s', got '%v'", tt.expectedErrText, err)
+			case tt.expectedErr == nil && tt.expectedErrText == "" && err != nil:
+				t.Errorf("expected provenance to be verified, got '%v'", err)
+			}
+
+			args, err := os.ReadFile(argsPath)
+			if !tt.expectVerifierRun {
+				if err == nil {
+					t.Errorf("expected slsa-verifier not to be run, but it was run with '%s'", strings.TrimSpace(string(args)))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected slsa-verifier to be run: %v", err)
+			}
+			expectedArgs := "verify-artifact " + assetPath + " --provenance-path " + provenancePath + " --source-uri github.com/openshift/osdctl --source-tag v1.0.0"
+			if strings.TrimSpace(string(args)) != expectedArgs {
+				t.Errorf("slsa-verifier was run with '%s', expected '%s'", strings.TrimSpace(string(args)), expectedArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+/*
+verify provides the capability for tools to validate the integrity of downloaded files
+*/
+package verify
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/openshift/backplane-tools/pkg/utils"
+)
+
+// Format defines how the contents of a checksum file are laid out
+type Format string
+
+const (
+	// FormatGNU is the format produced by GNU coreutils' sha256sum: each line contains a hash,
+	// followed by whitespace and the file's name. The name may be prefixed with '*' to denote binary mode
+	FormatGNU Format = "gnu"
+
+	// FormatBSD is the format produced by BSD's sha256 and 'shasum --tag': each line is formatted as
+	// 'SHA256 (<file name>) = <hash>'
+	FormatBSD Format = "bsd"
+
+	// FormatSingle is used by checksum files published for a single asset (ie - '<asset>.sha256'):
+	// the first field of the file is the hash, optionally followed by the file's name
+	FormatSingle Format = "single"
+
+	// FormatMultiHash is used by checksum files which publish several hashes (of differing algorithms)
+	// for each asset on a single line. Any of the hashes on the line naming the asset may match
+	FormatMultiHash Format = "multi-hash"
+
+	// FormatAuto detects the format of each line in the checksum file, accepting any of the formats above
+	FormatAuto Format = "auto"
+)
+
+var (
+	// bsdLine matches lines in the BSD format, capturing the algorithm, file name, and hash
+	bsdLine = regexp.MustCompile(`^([A-Za-z0-9-]+) ?\((.+)\) ?= ?([0-9A-Fa-f]+)$`)
+
+	// gnuLine matches lines in the GNU format, capturing the hash and file name. File names may contain spaces
+	gnuLine = regexp.MustCompile(`^([0-9A-Fa-f]+)\s[ *]?(.+)$`)
+)
+
+// File verifies that the checksum of the file at assetPath matches the value published for it in the checksum file at
+// checksumPath. The asset is located within the checksum file using its base name. Checksums which aren't labelled
+// with their algorithm use the one suggested by the checksum file's name, such as 'SHA512SUMS' or 'B2SUMS', if any
+func File(assetPath, checksumPath string, format Format) error {
+	expected, err := ChecksumsFromFile(checksumPath, filepath.Base(assetPath), format)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve checksum from file '%s': %w", checksumPath, err)
+	}
+	return sum(assetPath, algorithmFromName(filepath.Base(checksumPath)), expected)
+}
+
+// Sum verifies that the checksum of the file at assetPath matches any of the provided checksums. Checksums may be
+// labelled with their algorithm, as in 'sha512:<hash>'; otherwise the algorithm is determined by their length
+func Sum(assetPath string, expected ...string) error {
+	return sum(assetPath, "", expected)
+}
+
+// sum verifies that the checksum of the file at assetPath matches any of the expected checksums, using the hinted
+// algorithm for those which aren't labelled, if it applies. Checksums of unsupported algorithms are ignored, as long as
+// at least one is supported
+func sum(assetPath string, hint Algorithm, expected []string) error {
+	algorithms := []Algorithm{}
+	hashes := []string{}
+	var parseErr error
+	for _, checksum := range expected {
+		algorithm, hash, err := parseChecksum(checksum, hint)
+		if err != nil {
+			parseErr = err
+			continue
+		}
+		algorithms = append(algorithms, algorithm)
+		hashes = append(hashes, hash)
+	}
+	if len(algorithms) == 0 {
+		if parseErr == nil {
+			parseErr = fmt.Errorf("no checksums provided")
+		}
+		return fmt.Errorf("failed to verify '%s': %w", filepath.Base(assetPath), parseErr)
+	}
+
+	actual, err := hashFile(assetPath, algorithms)
+	if err != nil {
+		return fmt.Errorf("failed to calculate checksum for '%s': %w", assetPath, err)
+	}
+	for i, algorithm := range algorithms {
+		if strings.EqualFold(hashes[i], actual[algorithm]) {
+			return nil
+		}
+	}
+
+	got := []string{}
+	for _, algorithm := range algorithms {
+		sum := actual[algorithm]
+		if len(actual) > 1 || algorithm != SHA256 {
+			sum = fmt.Sprintf("%s:%s", algorithm, sum)
+		}
+		if !utils.Contains(got, sum) {
+			got = append(got, sum)
+		}
+	}
+	return fmt.Errorf("%w for '%s': expected '%s', got '%s'", utils.ErrChecksumMismatch, filepath.Base(assetPath), strings.Join(expected, "' or '"), strings.Join(got, "' or '"))
+}
+
+// ChecksumsFromFile parses the checksum file at the provided path, and returns the checksum(s)
+// published for the named asset. An error is returned if the asset cannot be found
+func ChecksumsFromFile(checksumPath, assetName string, format Format) ([]string, error) {
+	switch format {
+	case FormatGNU, FormatBSD, FormatSingle, FormatMultiHash, FormatAuto:
+	default:
+		return []string{}, fmt.Errorf("unsupported checksum format '%s'", format)
+	}
+
+	file, err := os.Open(checksumPath)
+	if err != nil {
+		return []string{}, err
+	}
+	defer func() {
+		closeErr := file.Close()
+		if closeErr != nil {
+			fmt.Fprintf(os.Stderr, "failed to close file '%s': %v\n", checksumPath, closeErr)
+		}
+	}()
+
+	lines := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if scanner.Err() != nil {
+		return []string{}, fmt.Errorf("failed to read file: %w", scanner.Err())
+	}
+	return checksumsFromLines(lines, assetName, format)
+}
+
+// ChecksumsFromText returns the checksum(s) published for the named asset within free-form text, such as the
+// description of a release. Markdown surrounding the checksums - code spans, list markers, and table delimiters -
+// is ignored, as are lines which don't refer to the asset
+func ChecksumsFromText(text, assetName string, format Format) ([]string, error) {
+	switch format {
+	case FormatGNU, FormatBSD, FormatMultiHash, FormatAuto:
+	default:
+		return []string{}, fmt.Errorf("unsupported checksum format '%s' for text", format)
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(strings.NewReplacer("`", "", "|", " ").Replace(line))
+		line = strings.TrimSpace(strings.TrimLeft(line, "-+*>"))
+		sums, found := parseLine(line, assetName, format)
+		if found {
+			return sums, nil
+		}
+	}
+	return []string{}, fmt.Errorf("no checksum found for '%s'", assetName)
+}
+
+// checksumsFromLines returns the checksum(s) published for the named asset within the provided lines of a checksum
+// file, which have already had comments and blank lines removed
+func checksumsFromLines(lines []string, assetName string, format Format) ([]string, error) {
+	if format == FormatSingle || (format == FormatAuto && isSingle(lines)) {
+		if len(lines) != 1 {
+			return []string{}, fmt.Errorf("expected a single checksum, found %d lines", len(lines))
+		}
+		fields := strings.Fields(lines[0])
+		if len(fields) > 1 && normalizeName(strings.Join(fields[1:], " ")) != assetName {
+			return []string{}, fmt.Errorf("file does not contain a checksum for '%s'", assetName)
+		}
+		return []string{fields[0]}, nil
+	}
+
+	for _, line := range lines {
+		sums, found := parseLine(line, assetName, format)
+		if found {
+			return sums, nil
+		}
+	}
+	return []string{}, fmt.Errorf("no checksum found for '%s'", assetName)
+}
+
+// isSingle returns true if the provided lines consist of a single hash, without a file name
+func isSingle(lines []string) bool {
+	return len(lines) == 1 && len(strings.Fields(lines[0])) == 1
+}
+
+// parseLine extracts the checksum(s) published for the named asset from a single line of a checksum
+// file. If the line does not refer to the asset, false is returned
+func parseLine(line, assetName string, format Format) ([]string, bool) {
+	switch format {
+	case FormatGNU:
+		return parseGNULine(line, assetName)
+	case FormatBSD:
+		return parseBSDLine(line, assetName)
+	case FormatMultiHash:
+		return parseMultiHashLine(line, assetName)
+	case FormatAuto:
+		// Checksum files may publish hashes of several algorithms: only those of supported algorithms are relevant
+		for _, parse := range []func(string, string) ([]string, bool){parseBSDLine, parseGNULine, parseMultiHashLine} {
+			sums, found := parse(line, assetName)
+			if !found {
+				continue
+			}
+			supported := []string{}
+			for _, sum := range sums {
+				if isSupportedChecksum(sum) {
+					supported = append(supported, sum)
+				}
+			}
+			if len(supported) > 0 {
+				return supported, true
+			}
+		}
+	}
+	return []string{}, false
+}
+
+// parseGNULine parses lines formatted as '<hash> <file name>'
+func parseGNULine(line, assetName string) ([]string, bool) {
+	match := gnuLine.FindStringSubmatch(line)
+	if match == nil || normalizeName(strings.TrimSpace(match[2])) != assetName {
+		return []string{}, false
+	}
+	return []string{match[1]}, true
+}
+
+// parseBSDLine parses lines formatted as '<algorithm> (<file name>) = <hash>'. Hashes of supported algorithms are
+// labelled with their algorithm, as in 'sha512:<hash>'
+func parseBSDLine(line, assetName string) ([]string, bool) {
+	match := bsdLine.FindStringSubmatch(line)
+	if match == nil || normalizeName(match[2]) != assetName {
+		return []string{}, false
+	}
+	algorithm, found := ParseAlgorithm(match[1])
+	if !found {
+		return []string{match[3]}, true
+	}
+	return []string{fmt.Sprintf("%s:%s", algorithm, match[3])}, true
+}
+
+// parseMultiHashLine parses lines containing the file name alongside any number of hashes
+func parseMultiHashLine(line, assetName string) ([]string, bool) {
+	sums := []string{}
+	found := false
+	for _, field := range strings.Fields(line) {
+		if normalizeName(field) == assetName {
+			found = true
+			continue
+		}
+		sums = append(sums, field)
+	}
+	return sums, found
+}
+
+// normalizeName strips the decorations commonly applied to file names in checksum files
+func normalizeName(name string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(name, "*"), "./")
+}
@@ -0,0 +1,89 @@
+package verify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func FuzzChecksumsFromText(f *testing.F) {
+	f.Add("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  tool.tar.gz", "tool.tar.gz")
+	f.Add("SHA256 (tool.tar.gz) = e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", "tool.tar.gz")
+	f.Add("| tool.tar.gz | `e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855` |", "tool.tar.gz")
+	f.Add("- abc *./tool.tar.gz\n", "tool.tar.gz")
+	f.Fuzz(func(t *testing.T, text, assetName string) {
+		for _, format := range []Format{FormatGNU, FormatBSD, FormatMultiHash, FormatAuto} {
+			sums, err := ChecksumsFromText(text, assetName, format)
+			if err != nil || format != FormatAuto {
+				continue
+			}
+			for _, sum := range sums {
+				if !isSupportedChecksum(sum) {
+					t.Errorf("returned unsupported checksum '%s' for '%s'", sum, assetName)
+				}
+			}
+		}
+	})
+}
+
+func FuzzChecksumsFromLines(f *testing.F) {
+	f.Add([]byte("contents"), "tool.tar.gz")
+	f.Add([]byte(""), "tool darwin amd64.zip")
+	f.Add([]byte("x"), "ツール.tar.gz")
+	f.Fuzz(func(t *testing.T, contents []byte, assetName string) {
+		digest := sha256.Sum256(contents)
+		sum := hex.EncodeToString(digest[:])
+		if strings.TrimSpace(assetName) != assetName || normalizeName(assetName) != assetName || assetName == "" || strings.ContainsAny(assetName, "\r\n") {
+			return
+		}
+		for _, format := range []Format{FormatGNU, FormatAuto} {
+			sums, err := checksumsFromLines([]string{sum + "  " + assetName}, assetName, format)
+			if err != nil {
+				t.Fatalf("failed to parse %s checksum line for '%s': %v", format, assetName, err)
+			}
+			if len(sums) != 1 || sums[0] != sum {
+				t.Errorf("parsed %s checksums '%s' for '%s', expected '%s'", format, strings.Join(sums, "', '"), assetName, sum)
+			}
+		}
+	})
+}
+
+func FuzzParseChecksum(f *testing.F) {
+	f.Add("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+	f.Add("sha512:abc")
+	f.Add("blake2b:")
+	f.Fuzz(func(t *testing.T, checksum string) {
+		algorithm, sum, err := parseChecksum(checksum, "")
+		if err == nil && len(sum) != hexLengths[algorithm] {
+			t.Errorf("parsed %s checksum '%s' of length %d, expected %d", algorithm, sum, len(sum), hexLengths[algorithm])
+		}
+	})
+}
+
+func BenchmarkFile(b *testing.B) {
+	dir := b.TempDir()
+	data := make([]byte, 64<<20)
+	_, _ = rand.New(rand.NewSource(1)).Read(data)
+	assetPath := filepath.Join(dir, "tool.tar.gz")
+	if err := os.WriteFile(assetPath, data, 0o644); err != nil {
+		b.Fatalf("failed to write '%s': %v", assetPath, err)
+	}
+	digest := sha256.Sum256(data)
+	checksums := fmt.Sprintf("%s  other.tar.gz\n%s  tool.tar.gz\n", strings.Repeat("0", 64), hex.EncodeToString(digest[:]))
+	checksumPath := filepath.Join(dir, "sha256sum.txt")
+	if err := os.WriteFile(checksumPath, []byte(checksums), 0o644); err != nil {
+		b.Fatalf("failed to write '%s': %v", checksumPath, err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := File(assetPath, checksumPath, FormatAuto); err != nil {
+			b.Fatalf("failed to verify '%s': %v", assetPath, err)
+		}
+	}
+}